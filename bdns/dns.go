@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/jmhodges/clock"
@@ -147,7 +148,9 @@ var (
 type DNSClient interface {
 	LookupTXT(context.Context, string) (txts []string, authorities []string, err error)
 	LookupHost(context.Context, string) ([]net.IP, error)
-	LookupCAA(context.Context, string) ([]*dns.CAA, error)
+	// LookupCAA also returns the address of the DNS server queried, so that
+	// CAA decisions can be recorded.
+	LookupCAA(context.Context, string) ([]*dns.CAA, string, error)
 	LookupMX(context.Context, string) ([]string, error)
 }
 
@@ -234,10 +237,10 @@ func NewTestDNSClientImpl(readTimeout time.Duration, servers []string, stats met
 }
 
 // exchangeOne performs a single DNS exchange with a randomly chosen server
-// out of the server list, returning the response, time, and error (if any).
-// We assume that the upstream resolver requests and validates DNSSEC records
-// itself.
-func (dnsClient *DNSClientImpl) exchangeOne(ctx context.Context, hostname string, qtype uint16) (resp *dns.Msg, err error) {
+// out of the server list, returning the response, the server chosen, and
+// error (if any). We assume that the upstream resolver requests and validates
+// DNSSEC records itself.
+func (dnsClient *DNSClientImpl) exchangeOne(ctx context.Context, hostname string, qtype uint16) (resp *dns.Msg, server string, err error) {
 	m := new(dns.Msg)
 	// Set question type
 	m.SetQuestion(dns.Fqdn(hostname), qtype)
@@ -254,11 +257,12 @@ func (dnsClient *DNSClientImpl) exchangeOne(ctx context.Context, hostname string
 	m.SetEdns0(4096, false)
 
 	if len(dnsClient.servers) < 1 {
-		return nil, fmt.Errorf("Not configured with at least one DNS Server")
+		return nil, "", fmt.Errorf("Not configured with at least one DNS Server")
 	}
 
 	// Randomly pick a server
 	chosenServer := dnsClient.servers[rand.Intn(len(dnsClient.servers))]
+	server = chosenServer
 
 	start := dnsClient.clk.Now()
 	client := dnsClient.dnsClient
@@ -335,7 +339,7 @@ type dnsResp struct {
 func (dnsClient *DNSClientImpl) LookupTXT(ctx context.Context, hostname string) ([]string, []string, error) {
 	var txt []string
 	dnsType := dns.TypeTXT
-	r, err := dnsClient.exchangeOne(ctx, hostname, dnsType)
+	r, _, err := dnsClient.exchangeOne(ctx, hostname, dnsType)
 	if err != nil {
 		return nil, nil, &DNSError{dnsType, hostname, err, -1}
 	}
@@ -377,8 +381,33 @@ func isPrivateV6(ip net.IP) bool {
 	return false
 }
 
+// IsPrivateIP returns true if ip is in one of the private, loopback,
+// link-local or otherwise reserved networks that Boulder never connects to on
+// behalf of subscribers.
+func IsPrivateIP(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		return isPrivateV4(v4)
+	}
+	return isPrivateV6(ip)
+}
+
+// PublicOnlyControl can be used as the Control function of a net.Dialer to
+// refuse connections to private addresses. It runs after a hostname has been
+// resolved, so it applies to whichever address is actually dialled.
+func PublicOnlyControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || IsPrivateIP(ip) {
+		return fmt.Errorf("refusing to connect to non-public address %s", host)
+	}
+	return nil
+}
+
 func (dnsClient *DNSClientImpl) lookupIP(ctx context.Context, hostname string, ipType uint16) ([]dns.RR, error) {
-	resp, err := dnsClient.exchangeOne(ctx, hostname, ipType)
+	resp, _, err := dnsClient.exchangeOne(ctx, hostname, ipType)
 	if err != nil {
 		return nil, &DNSError{ipType, hostname, err, -1}
 	}
@@ -436,16 +465,17 @@ func (dnsClient *DNSClientImpl) LookupHost(ctx context.Context, hostname string)
 }
 
 // LookupCAA sends a DNS query to find all CAA records associated with
-// the provided hostname.
-func (dnsClient *DNSClientImpl) LookupCAA(ctx context.Context, hostname string) ([]*dns.CAA, error) {
+// the provided hostname. It returns the records and the address of the DNS
+// server queried.
+func (dnsClient *DNSClientImpl) LookupCAA(ctx context.Context, hostname string) ([]*dns.CAA, string, error) {
 	dnsType := dns.TypeCAA
-	r, err := dnsClient.exchangeOne(ctx, hostname, dnsType)
+	r, server, err := dnsClient.exchangeOne(ctx, hostname, dnsType)
 	if err != nil {
-		return nil, &DNSError{dnsType, hostname, err, -1}
+		return nil, server, &DNSError{dnsType, hostname, err, -1}
 	}
 
 	if r.Rcode == dns.RcodeServerFailure {
-		return nil, &DNSError{dnsType, hostname, nil, r.Rcode}
+		return nil, server, &DNSError{dnsType, hostname, nil, r.Rcode}
 	}
 
 	var CAAs []*dns.CAA
@@ -454,14 +484,14 @@ func (dnsClient *DNSClientImpl) LookupCAA(ctx context.Context, hostname string)
 			CAAs = append(CAAs, caaR)
 		}
 	}
-	return CAAs, nil
+	return CAAs, server, nil
 }
 
 // LookupMX sends a DNS query to find a MX record associated hostname and returns the
 // record target.
 func (dnsClient *DNSClientImpl) LookupMX(ctx context.Context, hostname string) ([]string, error) {
 	dnsType := dns.TypeMX
-	r, err := dnsClient.exchangeOne(ctx, hostname, dnsType)
+	r, _, err := dnsClient.exchangeOne(ctx, hostname, dnsType)
 	if err != nil {
 		return nil, &DNSError{dnsType, hostname, err, -1}
 	}
@@ -279,7 +279,7 @@ func TestDNSLookupsNoServer(t *testing.T) {
 	_, err = obj.LookupHost(context.Background(), "letsencrypt.org")
 	test.AssertError(t, err, "No servers")
 
-	_, err = obj.LookupCAA(context.Background(), "letsencrypt.org")
+	_, _, err = obj.LookupCAA(context.Background(), "letsencrypt.org")
 	test.AssertError(t, err, "No servers")
 }
 
@@ -293,7 +293,7 @@ func TestDNSServFail(t *testing.T) {
 	_, err = obj.LookupHost(context.Background(), bad)
 	test.AssertError(t, err, "LookupHost didn't return an error")
 
-	emptyCaa, err := obj.LookupCAA(context.Background(), bad)
+	emptyCaa, _, err := obj.LookupCAA(context.Background(), bad)
 	test.Assert(t, len(emptyCaa) == 0, "Query returned non-empty list of CAA records")
 	test.AssertError(t, err, "LookupCAA should have returned an error")
 }
@@ -399,15 +399,15 @@ func TestDNSNXDOMAIN(t *testing.T) {
 func TestDNSLookupCAA(t *testing.T) {
 	obj := NewTestDNSClientImpl(time.Second*10, []string{dnsLoopbackAddr}, testStats, clock.NewFake(), 1)
 
-	caas, err := obj.LookupCAA(context.Background(), "bracewel.net")
+	caas, _, err := obj.LookupCAA(context.Background(), "bracewel.net")
 	test.AssertNotError(t, err, "CAA lookup failed")
 	test.Assert(t, len(caas) > 0, "Should have CAA records")
 
-	caas, err = obj.LookupCAA(context.Background(), "nonexistent.letsencrypt.org")
+	caas, _, err = obj.LookupCAA(context.Background(), "nonexistent.letsencrypt.org")
 	test.AssertNotError(t, err, "CAA lookup failed")
 	test.Assert(t, len(caas) == 0, "Shouldn't have CAA records")
 
-	caas, err = obj.LookupCAA(context.Background(), "cname.example.com")
+	caas, _, err = obj.LookupCAA(context.Background(), "cname.example.com")
 	test.AssertNotError(t, err, "CAA lookup failed")
 	test.Assert(t, len(caas) > 0, "Should follow CNAME to find CAA")
 }
@@ -451,6 +451,20 @@ func TestIsPrivateIP(t *testing.T) {
 	test.Assert(t, isPrivateV6(net.ParseIP("0100::")), "should be private")
 	test.Assert(t, isPrivateV6(net.ParseIP("0100::0000:ffff:ffff:ffff:ffff")), "should be private")
 	test.Assert(t, !isPrivateV6(net.ParseIP("0100::0001:0000:0000:0000:0000")), "should be private")
+
+	test.Assert(t, IsPrivateIP(net.ParseIP("169.254.169.254")), "should be private")
+	test.Assert(t, IsPrivateIP(net.ParseIP("::ffff:127.0.0.1")), "should be private")
+	test.Assert(t, IsPrivateIP(net.ParseIP("fe80::1")), "should be private")
+	test.Assert(t, !IsPrivateIP(net.ParseIP("93.184.216.34")), "should not be private")
+	test.Assert(t, !IsPrivateIP(net.ParseIP("2606:2800:220:1::")), "should not be private")
+}
+
+func TestPublicOnlyControl(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:443", "10.0.0.1:443", "[::1]:443", "[fe80::1]:443", "localhost:443"} {
+		test.AssertError(t, PublicOnlyControl("tcp", address, nil), fmt.Sprintf("dialing %s should be refused", address))
+	}
+	test.AssertNotError(t, PublicOnlyControl("tcp", "93.184.216.34:443", nil), "dialing a public address was refused")
+	test.AssertNotError(t, PublicOnlyControl("tcp6", "[2606:2800:220:1::]:443", nil), "dialing a public address was refused")
 }
 
 type testExchanger struct {
@@ -45,6 +45,14 @@ func (mock *MockDNSClient) LookupTXT(_ context.Context, hostname string) ([]stri
 	if hostname == "_acme-challenge.empty-txts.com" {
 		return []string{}, nil, nil
 	}
+	// Names under rbl.invalid mock a DNS blocklist, which only lists
+	// listed.com
+	if hostname == "listed.com.rbl.invalid" {
+		return []string{"phishing"}, nil, nil
+	}
+	if strings.HasSuffix(hostname, ".rbl.invalid") {
+		return nil, nil, &DNSError{dns.TypeTXT, hostname, nil, dns.RcodeNameError}
+	}
 	return []string{"hostname"}, []string{"respect my authority!"}, nil
 }
 
@@ -99,8 +107,8 @@ func (mock *MockDNSClient) LookupHost(_ context.Context, hostname string) ([]net
 }
 
 // LookupCAA returns mock records for use in tests.
-func (mock *MockDNSClient) LookupCAA(_ context.Context, domain string) ([]*dns.CAA, error) {
-	return nil, nil
+func (mock *MockDNSClient) LookupCAA(_ context.Context, domain string) ([]*dns.CAA, string, error) {
+	return nil, "127.0.0.1:53", nil
 }
 
 // LookupMX is a mock
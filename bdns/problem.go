@@ -52,6 +52,11 @@ func (d DNSError) Timeout() bool {
 	return false
 }
 
+// NXDomain returns true if the hostname looked up doesn't exist
+func (d DNSError) NXDomain() bool {
+	return d.underlying == nil && d.rCode == dns.RcodeNameError
+}
+
 const detailDNSTimeout = "query timed out"
 const detailDNSNetFailure = "networking error"
 const detailServerFailure = "server failure at resolver"
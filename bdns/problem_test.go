@@ -37,3 +37,15 @@ func TestDNSError(t *testing.T) {
 		}
 	}
 }
+
+func TestDNSErrorNXDomain(t *testing.T) {
+	if !(&DNSError{dns.TypeTXT, "hostname", nil, dns.RcodeNameError}).NXDomain() {
+		t.Errorf("NXDOMAIN error not reported as NXDOMAIN")
+	}
+	if (&DNSError{dns.TypeTXT, "hostname", nil, dns.RcodeServerFailure}).NXDomain() {
+		t.Errorf("SERVFAIL error reported as NXDOMAIN")
+	}
+	if (&DNSError{dns.TypeTXT, "hostname", MockTimeoutError(), -1}).NXDomain() {
+		t.Errorf("Timeout error reported as NXDOMAIN")
+	}
+}
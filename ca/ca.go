@@ -112,6 +112,9 @@ const (
 type CertificateAuthorityImpl struct {
 	rsaProfile   string
 	ecdsaProfile string
+	// profiles are the named issuance profiles orders may select instead of
+	// the default made up of rsaProfile, ecdsaProfile and validityPeriod
+	profiles map[string]issuanceProfile
 	// A map from issuer cert common name to an internalIssuer struct
 	issuers map[string]*internalIssuer
 	// The common name of the default issuer cert
@@ -130,20 +133,109 @@ type CertificateAuthorityImpl struct {
 	enableMustStaple  bool
 	signatureCount    *prometheus.CounterVec
 	csrExtensionCount *prometheus.CounterVec
+
+	// How long OCSP responses are valid for, and how long a delegated OCSP
+	// responder certificate must have been valid before it is used. See
+	// internalIssuer.ocspSignerAt.
+	lifespanOCSP         time.Duration
+	ocspResponderOverlap time.Duration
 }
 
 // Issuer represents a single issuer certificate, along with its key.
 type Issuer struct {
 	Signer crypto.Signer
 	Cert   *x509.Certificate
+	// OCSPResponders are delegated OCSP responder certificates issued by Cert.
+	// When one is usable, OCSP responses are signed with it instead of Signer.
+	OCSPResponders []OCSPResponder
+}
+
+// issuanceProfile is the cfssl signing profiles and validity period used to
+// issue a certificate.
+type issuanceProfile struct {
+	rsaProfile     string
+	ecdsaProfile   string
+	validityPeriod time.Duration
+}
+
+// OCSPResponder represents a delegated OCSP responder certificate, along with
+// its key. The certificate must be issued by the issuer it signs responses for,
+// have the OCSP signing extended key usage, and carry the id-pkix-ocsp-nocheck
+// extension.
+type OCSPResponder struct {
+	Signer crypto.Signer
+	Cert   *x509.Certificate
 }
 
+// oidOCSPNoCheck is the id-pkix-ocsp-nocheck extension (RFC 6960, Section
+// 4.2.2.2.1).
+var oidOCSPNoCheck = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 5}
+
 // internalIssuer represents the fully initialized internal state for a single
 // issuer, including the cfssl signer and OCSP signer objects.
 type internalIssuer struct {
 	cert       *x509.Certificate
 	eeSigner   *local.Signer
 	ocspSigner ocsp.Signer
+	// delegatedSigners sign OCSP responses with delegated responder
+	// certificates, see ocspSignerAt.
+	delegatedSigners []delegatedSigner
+}
+
+type delegatedSigner struct {
+	cert   *x509.Certificate
+	signer ocsp.Signer
+}
+
+// ocspSignerAt returns the signer to use for OCSP responses produced at now,
+// and whether it is a delegated responder. A delegated responder certificate
+// is only used once it has been valid for the overlap window, giving relying
+// parties and caches time to see the new certificate while the previous one is
+// still in use, and only while it stays valid for the full lifespan of the
+// responses it signs. Of the usable delegated certificates the newest is used.
+// If none are usable the issuer key itself signs the response.
+func (iss *internalIssuer) ocspSignerAt(now time.Time, lifespan, overlap time.Duration) (ocsp.Signer, bool) {
+	var best *delegatedSigner
+	for i, ds := range iss.delegatedSigners {
+		if now.Before(ds.cert.NotBefore.Add(overlap)) || ds.cert.NotAfter.Before(now.Add(lifespan)) {
+			continue
+		}
+		if best == nil || ds.cert.NotBefore.After(best.cert.NotBefore) {
+			best = &iss.delegatedSigners[i]
+		}
+	}
+	if best == nil {
+		return iss.ocspSigner, false
+	}
+	return best.signer, true
+}
+
+// checkOCSPResponder verifies that responder is a delegated OCSP responder
+// certificate issued by issuer.
+func checkOCSPResponder(issuer, responder *x509.Certificate) error {
+	if !bytes.Equal(responder.RawIssuer, issuer.RawSubject) {
+		return fmt.Errorf("OCSP responder cert %q was not issued by issuer %q",
+			responder.Subject.CommonName, issuer.Subject.CommonName)
+	}
+	if err := responder.CheckSignatureFrom(issuer); err != nil {
+		return fmt.Errorf("OCSP responder cert %q is not signed by issuer %q: %s",
+			responder.Subject.CommonName, issuer.Subject.CommonName, err)
+	}
+	var ocspSigning bool
+	for _, eku := range responder.ExtKeyUsage {
+		if eku == x509.ExtKeyUsageOCSPSigning {
+			ocspSigning = true
+		}
+	}
+	if !ocspSigning {
+		return fmt.Errorf("OCSP responder cert %q lacks the OCSP signing extended key usage", responder.Subject.CommonName)
+	}
+	for _, ext := range responder.Extensions {
+		if ext.Id.Equal(oidOCSPNoCheck) {
+			return nil
+		}
+	}
+	return fmt.Errorf("OCSP responder cert %q lacks the id-pkix-ocsp-nocheck extension", responder.Subject.CommonName)
 }
 
 func makeInternalIssuers(
@@ -170,14 +262,32 @@ func makeInternalIssuers(
 		if err != nil {
 			return nil, err
 		}
+		var delegatedSigners []delegatedSigner
+		for _, responder := range iss.OCSPResponders {
+			if responder.Cert == nil || responder.Signer == nil {
+				return nil, errors.New("OCSP responder with nil cert or signer specified.")
+			}
+			if err := checkOCSPResponder(iss.Cert, responder.Cert); err != nil {
+				return nil, err
+			}
+			signer, err := ocsp.NewSigner(iss.Cert, responder.Cert, responder.Signer, lifespanOCSP)
+			if err != nil {
+				return nil, err
+			}
+			delegatedSigners = append(delegatedSigners, delegatedSigner{
+				cert:   responder.Cert,
+				signer: signer,
+			})
+		}
 		cn := iss.Cert.Subject.CommonName
 		if internalIssuers[cn] != nil {
 			return nil, errors.New("Multiple issuer certs with the same CommonName are not supported")
 		}
 		internalIssuers[cn] = &internalIssuer{
-			cert:       iss.Cert,
-			eeSigner:   eeSigner,
-			ocspSigner: ocspSigner,
+			cert:             iss.Cert,
+			eeSigner:         eeSigner,
+			ocspSigner:       ocspSigner,
+			delegatedSigners: delegatedSigners,
 		}
 	}
 	return internalIssuers, nil
@@ -241,6 +351,29 @@ func NewCertificateAuthorityImpl(
 		return nil, errors.New("must specify rsaProfile and ecdsaProfile")
 	}
 
+	profiles := make(map[string]issuanceProfile, len(config.Profiles))
+	for name, pc := range config.Profiles {
+		if name == "" {
+			return nil, errors.New("issuance profile with an empty name configured")
+		}
+		if pc.RSAProfile == "" || pc.ECDSAProfile == "" {
+			return nil, fmt.Errorf("issuance profile %q must specify rsaProfile and ecdsaProfile", name)
+		}
+		for _, cfsslProfile := range []string{pc.RSAProfile, pc.ECDSAProfile} {
+			if _, ok := cfsslConfigObj.Signing.Profiles[cfsslProfile]; !ok {
+				return nil, fmt.Errorf("issuance profile %q uses unknown cfssl profile %q", name, cfsslProfile)
+			}
+		}
+		if pc.Expiry.Duration <= 0 {
+			return nil, fmt.Errorf("issuance profile %q must specify a positive expiry", name)
+		}
+		profiles[name] = issuanceProfile{
+			rsaProfile:     pc.RSAProfile,
+			ecdsaProfile:   pc.ECDSAProfile,
+			validityPeriod: pc.Expiry.Duration,
+		}
+	}
+
 	csrExtensionCount := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "csrExtensions",
@@ -264,6 +397,7 @@ func NewCertificateAuthorityImpl(
 		defaultIssuer:     defaultIssuer,
 		rsaProfile:        rsaProfile,
 		ecdsaProfile:      ecdsaProfile,
+		profiles:          profiles,
 		prefix:            config.SerialPrefix,
 		clk:               clk,
 		log:               logger,
@@ -293,6 +427,9 @@ func NewCertificateAuthorityImpl(
 
 	ca.maxNames = config.MaxNames
 
+	ca.lifespanOCSP = config.LifespanOCSP.Duration
+	ca.ocspResponderOverlap = config.OCSPResponderOverlap.Duration
+
 	return ca, nil
 }
 
@@ -406,10 +543,18 @@ func (ca *CertificateAuthorityImpl) GenerateOCSP(ctx context.Context, xferObj co
 			core.SerialToString(cert.SerialNumber), cn, err)
 	}
 
-	ocspResponse, err := issuer.ocspSigner.Sign(signRequest)
+	signer, delegated := issuer.ocspSignerAt(ca.clk.Now(), ca.lifespanOCSP, ca.ocspResponderOverlap)
+	if !delegated && len(issuer.delegatedSigners) > 0 {
+		ca.log.Warningf("No usable delegated OCSP responder cert for issuer %q, signing with the issuer key", cn)
+	}
+	ocspResponse, err := signer.Sign(signRequest)
 	ca.noteSignError(err)
 	if err == nil {
-		ca.signatureCount.With(prometheus.Labels{"purpose": "ocsp"}).Inc()
+		purpose := "ocsp"
+		if delegated {
+			purpose = "ocsp_delegated"
+		}
+		ca.signatureCount.With(prometheus.Labels{"purpose": purpose}).Inc()
 	}
 	return ocspResponse, err
 }
@@ -433,12 +578,17 @@ func (ca *CertificateAuthorityImpl) IssueCertificate(ctx context.Context, issueR
 		orderID = *issueReq.OrderID
 	}
 
-	serialBigInt, validity, err := ca.generateSerialNumberAndValidity()
+	profile, err := ca.profileFor(issueReq.GetProfile())
 	if err != nil {
 		return emptyCert, err
 	}
 
-	certDER, err := ca.issueCertificateOrPrecertificate(ctx, issueReq, serialBigInt, validity, certType)
+	serialBigInt, validity, err := ca.generateSerialNumberAndValidity(profile.validityPeriod)
+	if err != nil {
+		return emptyCert, err
+	}
+
+	certDER, err := ca.issueCertificateOrPrecertificate(ctx, issueReq, profile, serialBigInt, validity, certType)
 	if err != nil {
 		return emptyCert, err
 	}
@@ -447,12 +597,17 @@ func (ca *CertificateAuthorityImpl) IssueCertificate(ctx context.Context, issueR
 }
 
 func (ca *CertificateAuthorityImpl) IssuePrecertificate(ctx context.Context, issueReq *caPB.IssueCertificateRequest) (*caPB.IssuePrecertificateResponse, error) {
-	serialBigInt, validity, err := ca.generateSerialNumberAndValidity()
+	profile, err := ca.profileFor(issueReq.GetProfile())
+	if err != nil {
+		return nil, err
+	}
+
+	serialBigInt, validity, err := ca.generateSerialNumberAndValidity(profile.validityPeriod)
 	if err != nil {
 		return nil, err
 	}
 
-	precertDER, err := ca.issueCertificateOrPrecertificate(ctx, issueReq, serialBigInt, validity, precertType)
+	precertDER, err := ca.issueCertificateOrPrecertificate(ctx, issueReq, profile, serialBigInt, validity, precertType)
 	if err != nil {
 		return nil, err
 	}
@@ -504,7 +659,26 @@ type validity struct {
 	NotAfter  time.Time
 }
 
-func (ca *CertificateAuthorityImpl) generateSerialNumberAndValidity() (*big.Int, validity, error) {
+// profileFor returns the issuance profile with the given name, or the default
+// profile if the name is empty.
+func (ca *CertificateAuthorityImpl) profileFor(name string) (issuanceProfile, error) {
+	if name == "" {
+		return issuanceProfile{
+			rsaProfile:     ca.rsaProfile,
+			ecdsaProfile:   ca.ecdsaProfile,
+			validityPeriod: ca.validityPeriod,
+		}, nil
+	}
+	profile, ok := ca.profiles[name]
+	if !ok {
+		err := berrors.InternalServerError("unknown issuance profile %q", name)
+		ca.log.AuditErr(err.Error())
+		return issuanceProfile{}, err
+	}
+	return profile, nil
+}
+
+func (ca *CertificateAuthorityImpl) generateSerialNumberAndValidity(validityPeriod time.Duration) (*big.Int, validity, error) {
 	// We want 136 bits of random number, plus an 8-bit instance id prefix.
 	const randBits = 136
 	serialBytes := make([]byte, randBits/8+1)
@@ -521,13 +695,13 @@ func (ca *CertificateAuthorityImpl) generateSerialNumberAndValidity() (*big.Int,
 	notBefore := ca.clk.Now().Add(-1 * ca.backdate)
 	validity := validity{
 		NotBefore: notBefore,
-		NotAfter:  notBefore.Add(ca.validityPeriod),
+		NotAfter:  notBefore.Add(validityPeriod),
 	}
 
 	return serialBigInt, validity, nil
 }
 
-func (ca *CertificateAuthorityImpl) issueCertificateOrPrecertificate(ctx context.Context, issueReq *caPB.IssueCertificateRequest, serialBigInt *big.Int, validity validity, certType certificateType) ([]byte, error) {
+func (ca *CertificateAuthorityImpl) issueCertificateOrPrecertificate(ctx context.Context, issueReq *caPB.IssueCertificateRequest, issuance issuanceProfile, serialBigInt *big.Int, validity validity, certType certificateType) ([]byte, error) {
 	csr, err := x509.ParseCertificateRequest(issueReq.Csr)
 	if err != nil {
 		return nil, err
@@ -567,9 +741,9 @@ func (ca *CertificateAuthorityImpl) issueCertificateOrPrecertificate(ctx context
 	var profile string
 	switch csr.PublicKey.(type) {
 	case *rsa.PublicKey:
-		profile = ca.rsaProfile
+		profile = issuance.rsaProfile
 	case *ecdsa.PublicKey:
-		profile = ca.ecdsaProfile
+		profile = issuance.ecdsaProfile
 	default:
 		err = berrors.InternalServerError("unsupported key type %T", csr.PublicKey)
 		ca.log.AuditErr(err.Error())
@@ -3,11 +3,15 @@ package ca
 import (
 	"bytes"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"sort"
 	"testing"
 	"time"
@@ -262,7 +266,7 @@ func setup(t *testing.T) *testCtx {
 		},
 	}
 
-	issuers := []Issuer{{caKey, caCert}}
+	issuers := []Issuer{{Signer: caKey, Cert: caCert}}
 
 	keyPolicy := goodkey.KeyPolicy{
 		AllowRSA:           true,
@@ -568,6 +572,123 @@ func TestOCSP(t *testing.T) {
 	test.AssertEquals(t, parsedNewCertOcspResp.SerialNumber.Cmp(parsedNewCert.SerialNumber), 0)
 }
 
+// makeOCSPResponder returns a delegated OCSP responder certificate issued by
+// caCert and valid over the given period. If noCheck is false the
+// id-pkix-ocsp-nocheck extension is left out.
+func makeOCSPResponder(t *testing.T, notBefore, notAfter time.Time, noCheck bool) OCSPResponder {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "Failed to generate responder key")
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	test.AssertNotError(t, err, "Failed to generate serial")
+	template := &x509.Certificate{
+		SerialNumber:          serial.Add(serial, big.NewInt(1)),
+		Subject:               pkix.Name{CommonName: "delegated responder"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning},
+	}
+	if noCheck {
+		template.ExtraExtensions = []pkix.Extension{{Id: oidOCSPNoCheck, Value: []byte{5, 0}}}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, key.Public(), caKey)
+	test.AssertNotError(t, err, "Failed to create responder certificate")
+	cert, err := x509.ParseCertificate(der)
+	test.AssertNotError(t, err, "Failed to parse responder certificate")
+	return OCSPResponder{Signer: key, Cert: cert}
+}
+
+func TestDelegatedOCSP(t *testing.T) {
+	testCtx := setup(t)
+	testCtx.caConfig.OCSPResponderOverlap = cmd.ConfigDuration{Duration: time.Hour}
+	now := testCtx.fc.Now()
+	current := makeOCSPResponder(t, now.Add(-2*time.Hour), now.Add(24*time.Hour), true)
+	next := makeOCSPResponder(t, now.Add(-30*time.Minute), now.Add(48*time.Hour), true)
+	issuers := []Issuer{{
+		Signer:         caKey,
+		Cert:           caCert,
+		OCSPResponders: []OCSPResponder{current, next},
+	}}
+	ca, err := NewCertificateAuthorityImpl(
+		testCtx.caConfig,
+		&mockSA{},
+		testCtx.pa,
+		testCtx.fc,
+		testCtx.stats,
+		issuers,
+		testCtx.keyPolicy,
+		testCtx.logger)
+	test.AssertNotError(t, err, "Failed to create CA")
+
+	issued, err := ca.IssueCertificate(ctx, &caPB.IssueCertificateRequest{Csr: CNandSANCSR, RegistrationID: &arbitraryRegID})
+	test.AssertNotError(t, err, "Failed to issue certificate")
+	signedBy := func() *x509.Certificate {
+		ocspResp, err := ca.GenerateOCSP(ctx, core.OCSPSigningRequest{
+			CertDER: issued.DER,
+			Status:  string(core.OCSPStatusGood),
+		})
+		test.AssertNotError(t, err, "Failed to generate OCSP")
+		parsed, err := ocsp.ParseResponse(ocspResp, caCert)
+		test.AssertNotError(t, err, "Failed to parse / validate OCSP response")
+		return parsed.Certificate
+	}
+
+	// The next responder is still within its overlap window, so the current
+	// responder is used and included in the response
+	responder := signedBy()
+	test.Assert(t, responder != nil, "Response did not include the delegated responder cert")
+	test.AssertByteEquals(t, responder.Raw, current.Cert.Raw)
+
+	// Once the overlap window has passed the next responder takes over
+	testCtx.fc.Add(time.Hour)
+	test.AssertByteEquals(t, signedBy().Raw, next.Cert.Raw)
+
+	// A responder that would expire before the responses it signs isn't used,
+	// leaving only the issuer key
+	testCtx.fc.Add(48*time.Hour - 30*time.Minute)
+	test.Assert(t, signedBy() == nil, "Response signed by an expiring delegated responder")
+	test.AssertEquals(t, len(testCtx.logger.(*blog.Mock).GetAllMatching("No usable delegated OCSP responder")), 1)
+}
+
+func TestInvalidOCSPResponder(t *testing.T) {
+	testCtx := setup(t)
+	now := testCtx.fc.Now()
+	issuers := []Issuer{{
+		Signer:         caKey,
+		Cert:           caCert,
+		OCSPResponders: []OCSPResponder{makeOCSPResponder(t, now, now.Add(time.Hour), false)},
+	}}
+	_, err := NewCertificateAuthorityImpl(
+		testCtx.caConfig,
+		&mockSA{},
+		testCtx.pa,
+		testCtx.fc,
+		testCtx.stats,
+		issuers,
+		testCtx.keyPolicy,
+		testCtx.logger)
+	test.AssertError(t, err, "CA accepted an OCSP responder without id-pkix-ocsp-nocheck")
+
+	newIssuerCert, err := core.LoadCert("../test/test-ca2.pem")
+	test.AssertNotError(t, err, "Failed to load new cert")
+	issuers = []Issuer{{
+		Signer:         caKey,
+		Cert:           newIssuerCert,
+		OCSPResponders: []OCSPResponder{makeOCSPResponder(t, now, now.Add(time.Hour), true)},
+	}}
+	_, err = NewCertificateAuthorityImpl(
+		testCtx.caConfig,
+		&mockSA{},
+		testCtx.pa,
+		testCtx.fc,
+		testCtx.stats,
+		issuers,
+		testCtx.keyPolicy,
+		testCtx.logger)
+	test.AssertError(t, err, "CA accepted an OCSP responder issued by another issuer")
+}
+
 func TestInvalidCSRs(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -677,6 +798,83 @@ func TestRejectValidityTooLong(t *testing.T) {
 	test.Assert(t, berrors.Is(err, berrors.InternalServer), "Incorrect error type returned")
 }
 
+func TestIssuanceProfiles(t *testing.T) {
+	testCtx := setup(t)
+	// The short lived profile issues certificates without an OCSP URL
+	shortLived := *testCtx.caConfig.CFSSL.Signing.Profiles[rsaProfileName]
+	shortLived.OCSP = ""
+	shortLived.ExpiryString = "168h"
+	testCtx.caConfig.CFSSL.Signing.Profiles["rsaShortLived"] = &shortLived
+	testCtx.caConfig.Profiles = map[string]ca_config.IssuanceProfileConfig{
+		"shortLived": {
+			RSAProfile:   "rsaShortLived",
+			ECDSAProfile: ecdsaProfileName,
+			Expiry:       cmd.ConfigDuration{Duration: 7 * 24 * time.Hour},
+		},
+	}
+	sa := &mockSA{}
+	ca, err := NewCertificateAuthorityImpl(
+		testCtx.caConfig,
+		sa,
+		testCtx.pa,
+		testCtx.fc,
+		testCtx.stats,
+		testCtx.issuers,
+		testCtx.keyPolicy,
+		testCtx.logger)
+	test.AssertNotError(t, err, "Failed to create CA")
+
+	profile := "shortLived"
+	coreCert, err := ca.IssueCertificate(ctx, &caPB.IssueCertificateRequest{
+		Csr:            CNandSANCSR,
+		RegistrationID: &arbitraryRegID,
+		Profile:        &profile,
+	})
+	test.AssertNotError(t, err, "Failed to issue certificate with the shortLived profile")
+	cert, err := x509.ParseCertificate(coreCert.DER)
+	test.AssertNotError(t, err, "Certificate failed to parse")
+	test.AssertEquals(t, cert.NotAfter, cert.NotBefore.Add(7*24*time.Hour))
+	test.AssertEquals(t, len(cert.OCSPServer), 0)
+
+	// Without a profile the default is used
+	coreCert, err = ca.IssueCertificate(ctx, &caPB.IssueCertificateRequest{
+		Csr:            CNandSANCSR,
+		RegistrationID: &arbitraryRegID,
+	})
+	test.AssertNotError(t, err, "Failed to issue certificate with the default profile")
+	cert, err = x509.ParseCertificate(coreCert.DER)
+	test.AssertNotError(t, err, "Certificate failed to parse")
+	test.AssertEquals(t, cert.NotAfter, cert.NotBefore.Add(ca.validityPeriod))
+	test.AssertDeepEquals(t, cert.OCSPServer, []string{"http://not-example.com/ocsp"})
+
+	unknown := "longLived"
+	_, err = ca.IssuePrecertificate(ctx, &caPB.IssueCertificateRequest{
+		Csr:            CNandSANCSR,
+		RegistrationID: &arbitraryRegID,
+		Profile:        &unknown,
+	})
+	test.AssertError(t, err, "Issued a precertificate with an unknown profile")
+	test.Assert(t, berrors.Is(err, berrors.InternalServer), "Incorrect error type returned")
+
+	for _, pc := range []ca_config.IssuanceProfileConfig{
+		{ECDSAProfile: ecdsaProfileName, Expiry: cmd.ConfigDuration{Duration: time.Hour}},
+		{RSAProfile: "rsaUnknown", ECDSAProfile: ecdsaProfileName, Expiry: cmd.ConfigDuration{Duration: time.Hour}},
+		{RSAProfile: rsaProfileName, ECDSAProfile: ecdsaProfileName},
+	} {
+		testCtx.caConfig.Profiles = map[string]ca_config.IssuanceProfileConfig{"bad": pc}
+		_, err = NewCertificateAuthorityImpl(
+			testCtx.caConfig,
+			sa,
+			testCtx.pa,
+			testCtx.fc,
+			metrics.NewNoopScope(),
+			testCtx.issuers,
+			testCtx.keyPolicy,
+			testCtx.logger)
+		test.AssertError(t, err, fmt.Sprintf("CA accepted issuance profile %#v", pc))
+	}
+}
+
 func TestSingleAIAEnforcement(t *testing.T) {
 	pa, err := policy.New(nil)
 	test.AssertNotError(t, err, "Couldn't create PA")
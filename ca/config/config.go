@@ -30,12 +30,23 @@ type CAConfig struct {
 	// LifespanOCSP is how long OCSP responses are valid for; It should be longer
 	// than the minTimeToExpiry field for the OCSP Updater.
 	LifespanOCSP cmd.ConfigDuration
+	// OCSPResponderOverlap is how long a delegated OCSP responder certificate
+	// must have been valid before the CA starts signing with it. A responder
+	// certificate is retired once it would expire before a response signed
+	// with it, so when rolling over, the previous certificate should remain
+	// valid for at least OCSPResponderOverlap plus LifespanOCSP after its
+	// successor's NotBefore.
+	OCSPResponderOverlap cmd.ConfigDuration
 	// How long issued certificates are valid for, should match expiry field
 	// in cfssl config.
 	Expiry string
 	// How far back certificates should be backdated, should match backdate
 	// field in cfssl config.
 	Backdate cmd.ConfigDuration
+	// Profiles are named issuance profiles that orders may select instead of
+	// the default profile, which is made up of RSAProfile, ECDSAProfile and
+	// Expiry.
+	Profiles map[string]IssuanceProfileConfig
 	// The maximum number of subjectAltNames in a single certificate
 	MaxNames int
 	CFSSL    cfsslConfig.Config
@@ -62,6 +73,18 @@ type CAConfig struct {
 	Features map[string]bool
 }
 
+// IssuanceProfileConfig configures a named issuance profile.
+type IssuanceProfileConfig struct {
+	// RSAProfile and ECDSAProfile are the cfssl signing profiles used for
+	// certificates with RSA and ECDSA keys. A profile with no ocsp_url issues
+	// certificates without an OCSP URL.
+	RSAProfile   string
+	ECDSAProfile string
+	// How long issued certificates are valid for, should match expiry field
+	// in the cfssl profiles.
+	Expiry cmd.ConfigDuration
+}
+
 // IssuerConfig contains info about an issuer: private key and issuer cert.
 // It should contain either a File path to a PEM-format private key,
 // or a PKCS11Config defining how to load a module for an HSM.
@@ -74,4 +97,8 @@ type IssuerConfig struct {
 	// Number of sessions to open with the HSM. For maximum performance,
 	// this should be equal to the number of cores in the HSM. Defaults to 1.
 	NumSessions int
+	// OCSPResponders lists the keys and certificates of delegated OCSP
+	// responders issued by this issuer, generated by gen-ca with an
+	// "ocsp-signer" profile. Their own OCSPResponders field is ignored.
+	OCSPResponders []IssuerConfig
 }
@@ -39,10 +39,11 @@ var _ = math.Inf
 const _ = proto1.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type IssueCertificateRequest struct {
-	Csr              []byte `protobuf:"bytes,1,opt,name=csr" json:"csr,omitempty"`
-	RegistrationID   *int64 `protobuf:"varint,2,opt,name=registrationID" json:"registrationID,omitempty"`
-	OrderID          *int64 `protobuf:"varint,3,opt,name=orderID" json:"orderID,omitempty"`
-	XXX_unrecognized []byte `json:"-"`
+	Csr              []byte  `protobuf:"bytes,1,opt,name=csr" json:"csr,omitempty"`
+	RegistrationID   *int64  `protobuf:"varint,2,opt,name=registrationID" json:"registrationID,omitempty"`
+	OrderID          *int64  `protobuf:"varint,3,opt,name=orderID" json:"orderID,omitempty"`
+	Profile          *string `protobuf:"bytes,4,opt,name=profile" json:"profile,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
 }
 
 func (m *IssueCertificateRequest) Reset()                    { *m = IssueCertificateRequest{} }
@@ -71,6 +72,13 @@ func (m *IssueCertificateRequest) GetOrderID() int64 {
 	return 0
 }
 
+func (m *IssueCertificateRequest) GetProfile() string {
+	if m != nil && m.Profile != nil {
+		return *m.Profile
+	}
+	return ""
+}
+
 type IssuePrecertificateResponse struct {
 	DER              []byte `protobuf:"bytes,1,opt,name=DER,json=dER" json:"DER,omitempty"`
 	XXX_unrecognized []byte `json:"-"`
@@ -404,30 +412,31 @@ var _OCSPGenerator_serviceDesc = grpc.ServiceDesc{
 func init() { proto1.RegisterFile("ca/proto/ca.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 400 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x93, 0xcf, 0xaf, 0xd2, 0x40,
-	0x10, 0xc7, 0x69, 0xcb, 0x13, 0xdf, 0xa4, 0x1a, 0xde, 0x3e, 0xf5, 0x35, 0xc5, 0x44, 0xec, 0xc1,
-	0x34, 0xc6, 0x94, 0x84, 0xab, 0x27, 0x6c, 0xd1, 0x90, 0x98, 0x48, 0x16, 0xbc, 0x78, 0xdb, 0x2c,
-	0x83, 0x36, 0xc6, 0x2e, 0xce, 0x6e, 0x4d, 0x3c, 0xf8, 0x4f, 0x78, 0xf5, 0x9f, 0x35, 0x2d, 0x2d,
-	0x94, 0xa6, 0x3c, 0x6e, 0xf3, 0x83, 0xe5, 0xf3, 0x9d, 0xf9, 0x4e, 0xe1, 0x46, 0x8a, 0xc9, 0x8e,
-	0x94, 0x51, 0x13, 0x29, 0xa2, 0x32, 0x60, 0xb6, 0x14, 0xfe, 0x53, 0xa9, 0x08, 0xeb, 0x86, 0x22,
-	0xdc, 0xb7, 0x82, 0x1f, 0x70, 0xb7, 0xd0, 0x3a, 0xc7, 0x18, 0xc9, 0xa4, 0xdb, 0x54, 0x0a, 0x83,
-	0x1c, 0x7f, 0xe6, 0xa8, 0x0d, 0x1b, 0x82, 0x23, 0x35, 0x79, 0xd6, 0xd8, 0x0a, 0x5d, 0x5e, 0x84,
-	0xec, 0x15, 0x3c, 0x26, 0xfc, 0x9a, 0x6a, 0x43, 0xc2, 0xa4, 0x2a, 0x5b, 0x24, 0x9e, 0x3d, 0xb6,
-	0x42, 0x87, 0xb7, 0xaa, 0xcc, 0x83, 0x81, 0xa2, 0x0d, 0xd2, 0x22, 0xf1, 0x9c, 0xf2, 0x07, 0x75,
-	0x1a, 0x4c, 0x60, 0x54, 0xe2, 0x96, 0x84, 0xb2, 0x49, 0xd4, 0x3b, 0x95, 0x69, 0x2c, 0x90, 0xc9,
-	0x9c, 0xd7, 0xc8, 0xcd, 0x9c, 0x07, 0x7f, 0x2d, 0x08, 0xdb, 0x02, 0xdf, 0x2b, 0x6a, 0xbf, 0x3f,
-	0x28, 0x3e, 0x7d, 0xce, 0x18, 0xf4, 0x57, 0xf1, 0x5a, 0x7b, 0xf6, 0xd8, 0x09, 0x5d, 0xde, 0xd7,
-	0xf1, 0x5a, 0x77, 0x4c, 0xe1, 0x5c, 0x9a, 0xa2, 0x7f, 0x3a, 0xc5, 0x1f, 0xb8, 0xfd, 0x80, 0x19,
-	0x92, 0x30, 0xf8, 0x29, 0x5e, 0x2d, 0x6b, 0xbc, 0x07, 0x83, 0x42, 0xd4, 0x51, 0x42, 0x9d, 0xb2,
-	0x67, 0xf0, 0x40, 0x1b, 0x61, 0x72, 0x5d, 0x2e, 0xec, 0x9a, 0x57, 0x59, 0x51, 0x27, 0x14, 0x5a,
-	0x65, 0xa5, 0x84, 0x2b, 0x5e, 0x65, 0xec, 0x39, 0x5c, 0x13, 0xfe, 0x52, 0xdf, 0x71, 0x33, 0x33,
-	0x15, 0xfc, 0x58, 0x08, 0x5e, 0x83, 0xbb, 0xc7, 0x56, 0x5b, 0xf3, 0xe1, 0x21, 0x55, 0x71, 0x05,
-	0x3e, 0xe4, 0xd3, 0x7f, 0x36, 0x3c, 0x69, 0xac, 0x6e, 0x96, 0x9b, 0x6f, 0x8a, 0x52, 0xf3, 0x9b,
-	0x25, 0x30, 0x6c, 0xef, 0x95, 0x8d, 0x22, 0x29, 0xa2, 0x33, 0xe7, 0xe0, 0xdf, 0x44, 0xe5, 0xd9,
-	0x34, 0x3a, 0x41, 0x8f, 0x7d, 0x86, 0xdb, 0x0e, 0x3f, 0xef, 0xff, 0xa3, 0x17, 0x87, 0x66, 0xf7,
-	0x15, 0x04, 0x3d, 0xb6, 0x85, 0x97, 0x17, 0x4d, 0x67, 0x6f, 0xba, 0x20, 0xe7, 0x6e, 0xa3, 0x53,
-	0xfe, 0xf4, 0x23, 0x3c, 0x2a, 0x36, 0x59, 0x99, 0xa9, 0x88, 0xbd, 0x05, 0xb7, 0xe9, 0x2c, 0xbb,
-	0x2b, 0x18, 0x1d, 0x5e, 0xfb, 0xc3, 0xa2, 0xd1, 0x74, 0x21, 0xe8, 0xbd, 0x1b, 0x7c, 0xb9, 0x2a,
-	0x3f, 0xaa, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0x09, 0x3d, 0x95, 0xee, 0x83, 0x03, 0x00, 0x00,
+	// 413 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x53, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0x8d, 0x63, 0x97, 0x90, 0x91, 0x41, 0xe9, 0x16, 0xa8, 0xe5, 0x22, 0x61, 0xf6, 0x80, 0x2c,
+	0x84, 0x1c, 0xa9, 0x57, 0x4e, 0xc5, 0x2e, 0x28, 0x12, 0x12, 0xd5, 0xb6, 0x5c, 0xb8, 0xad, 0x36,
+	0x13, 0xb0, 0x40, 0xde, 0x30, 0xbb, 0x46, 0xe2, 0xc0, 0x99, 0x3b, 0x57, 0x7e, 0x16, 0xad, 0x6b,
+	0xa7, 0xae, 0xe5, 0x92, 0xdb, 0xbc, 0x79, 0xd9, 0xbc, 0x37, 0xf3, 0xc6, 0x70, 0xa8, 0xe4, 0x72,
+	0x4b, 0xda, 0xea, 0xa5, 0x92, 0x59, 0x53, 0xb0, 0xa9, 0x92, 0xf1, 0x63, 0xa5, 0x09, 0x3b, 0x42,
+	0x13, 0x5e, 0x53, 0xfc, 0xb7, 0x07, 0xc7, 0x2b, 0x63, 0x6a, 0xcc, 0x91, 0x6c, 0xb9, 0x29, 0x95,
+	0xb4, 0x28, 0xf0, 0x7b, 0x8d, 0xc6, 0xb2, 0x05, 0xf8, 0xca, 0x50, 0xe4, 0x25, 0x5e, 0x1a, 0x0a,
+	0x57, 0xb2, 0x17, 0xf0, 0x90, 0xf0, 0x73, 0x69, 0x2c, 0x49, 0x5b, 0xea, 0x6a, 0x55, 0x44, 0xd3,
+	0xc4, 0x4b, 0x7d, 0x31, 0xe8, 0xb2, 0x08, 0x66, 0x9a, 0xd6, 0x48, 0xab, 0x22, 0xf2, 0x9b, 0x1f,
+	0x74, 0xd0, 0x31, 0x5b, 0xd2, 0x9b, 0xf2, 0x1b, 0x46, 0x41, 0xe2, 0xa5, 0x73, 0xd1, 0x41, 0xbe,
+	0x84, 0x93, 0xc6, 0xc8, 0x05, 0xa1, 0xea, 0x7b, 0x31, 0x5b, 0x5d, 0x19, 0x74, 0x66, 0x8a, 0x73,
+	0xd1, 0x99, 0x59, 0x9f, 0x0b, 0xfe, 0xc7, 0x83, 0x74, 0x68, 0xfd, 0xad, 0xa6, 0xe1, 0xfb, 0xdd,
+	0x2c, 0xb7, 0x9f, 0x33, 0x06, 0xc1, 0x65, 0x7e, 0x65, 0xa2, 0x69, 0xe2, 0xa7, 0xa1, 0x08, 0x4c,
+	0x7e, 0x65, 0x46, 0xe6, 0xf3, 0xf7, 0xcd, 0x17, 0xdc, 0x9a, 0x8f, 0xff, 0x82, 0xa3, 0x77, 0x58,
+	0x21, 0x49, 0x8b, 0x1f, 0xf2, 0xcb, 0x8b, 0x4e, 0x3e, 0x82, 0x99, 0x33, 0x75, 0x63, 0xa1, 0x83,
+	0xec, 0x09, 0xdc, 0x33, 0x56, 0xda, 0xda, 0x34, 0xab, 0x9c, 0x8b, 0x16, 0xb9, 0x3e, 0xa1, 0x34,
+	0xba, 0x6a, 0x2c, 0x1c, 0x88, 0x16, 0xb1, 0xa7, 0x30, 0x27, 0xfc, 0xa1, 0xbf, 0xe2, 0xfa, 0xcc,
+	0xb6, 0xe2, 0x37, 0x0d, 0xfe, 0x12, 0xc2, 0x6b, 0xd9, 0x76, 0x6b, 0x31, 0xdc, 0xa7, 0xb6, 0x6e,
+	0x85, 0x77, 0xf8, 0xf4, 0xef, 0x14, 0x1e, 0xf5, 0x56, 0x77, 0x56, 0xdb, 0x2f, 0x9a, 0x4a, 0xfb,
+	0x93, 0x15, 0xb0, 0x18, 0xee, 0x95, 0x9d, 0x64, 0x4a, 0x66, 0x77, 0x1c, 0x4a, 0x7c, 0x98, 0x35,
+	0x17, 0xd5, 0x63, 0xf8, 0x84, 0x7d, 0x84, 0xa3, 0x91, 0x3c, 0xff, 0xff, 0x47, 0xcf, 0x76, 0xe4,
+	0xf8, 0x15, 0xf0, 0x09, 0xdb, 0xc0, 0xf3, 0xbd, 0xa1, 0xb3, 0x57, 0x63, 0x22, 0x77, 0xdd, 0xc6,
+	0xa8, 0xfd, 0xd3, 0xf7, 0xf0, 0xc0, 0x6d, 0xb2, 0x0d, 0x53, 0x13, 0x7b, 0x0d, 0x61, 0x3f, 0x59,
+	0x76, 0xec, 0x34, 0x46, 0xb2, 0x8e, 0x17, 0x8e, 0xe8, 0xa7, 0xc0, 0x27, 0x6f, 0x66, 0x9f, 0x0e,
+	0x9a, 0xef, 0xed, 0xdf, 0x00, 0x1f, 0x54, 0xe5, 0xe0, 0x9e, 0x03, 0x00, 0x00,
 }
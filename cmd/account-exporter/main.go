@@ -0,0 +1,363 @@
+package main
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	cttls "github.com/google/certificate-transparency-go/tls"
+	ctx509 "github.com/google/certificate-transparency-go/x509"
+	"golang.org/x/net/context"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	"github.com/letsencrypt/boulder/features"
+	bgrpc "github.com/letsencrypt/boulder/grpc"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/probs"
+	"github.com/letsencrypt/boulder/revocation"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+const usageString = `usage: account-exporter -config <path> -reg-id <id> [-output <path>] [-redact]
+
+Exports a registration with its history, orders, authorizations (including
+validation records), certificates, revocations and the CT submissions
+embedded in its certificates as JSON, read through the SA's gRPC API.
+
+args:
+`
+
+type config struct {
+	AccountExporter struct {
+		// The exporter needs a TLSConfig to set up its gRPC client certs, but
+		// doesn't get the TLS field from ServiceConfig, so declares its own.
+		TLS cmd.TLSConfig
+
+		SAService *cmd.GRPCClientConfig
+		// SAAdminService is the SA's admin gRPC API, which is served on its
+		// own address.
+		SAAdminService *cmd.GRPCClientConfig
+
+		Features map[string]bool
+	}
+
+	Syslog cmd.SyslogConfig
+}
+
+type order struct {
+	ID                int64                 `json:"id"`
+	Created           time.Time             `json:"created"`
+	Expires           time.Time             `json:"expires"`
+	Status            string                `json:"status"`
+	Names             []string              `json:"names"`
+	Authorizations    []string              `json:"authorizations"`
+	CertificateSerial string                `json:"certificateSerial,omitempty"`
+	Error             *probs.ProblemDetails `json:"error,omitempty"`
+}
+
+// sct is a CT log submission, identified by the log's ID (the SHA-256 hash
+// of its key).
+type sct struct {
+	LogID     string    `json:"logID"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type certificate struct {
+	Serial        string          `json:"serial"`
+	Digest        string          `json:"digest"`
+	Names         []string        `json:"names"`
+	Issued        time.Time       `json:"issued"`
+	Expires       time.Time       `json:"expires"`
+	Status        core.OCSPStatus `json:"status"`
+	RevokedDate   *time.Time      `json:"revokedDate,omitempty"`
+	RevokedReason string          `json:"revokedReason,omitempty"`
+	SCTs          []sct           `json:"scts"`
+	DER           []byte          `json:"der"`
+}
+
+type export struct {
+	Registration   core.Registration         `json:"registration"`
+	History        []core.RegistrationChange `json:"history"`
+	Orders         []order                   `json:"orders"`
+	Authorizations []core.Authorization      `json:"authorizations"`
+	Certificates   []certificate             `json:"certificates"`
+}
+
+type exporter struct {
+	sa  core.StorageGetter
+	saa core.StorageAdmin
+}
+
+func (e exporter) orders(ctx context.Context, regID int64) ([]order, error) {
+	var orders []order
+	req := &sapb.SearchOrdersRequest{RegistrationID: &regID}
+	for {
+		page, err := e.saa.SearchOrders(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			return orders, nil
+		}
+		for _, o := range page {
+			orders = append(orders, orderFromPB(o))
+		}
+		req.AfterID = page[len(page)-1].Id
+	}
+}
+
+func orderFromPB(pb *corepb.Order) order {
+	o := order{
+		ID:             *pb.Id,
+		Expires:        time.Unix(0, *pb.Expires).UTC(),
+		Names:          pb.Names,
+		Authorizations: pb.Authorizations,
+	}
+	if pb.Created != nil {
+		o.Created = time.Unix(0, *pb.Created).UTC()
+	}
+	if pb.Status != nil {
+		o.Status = *pb.Status
+	}
+	if pb.CertificateSerial != nil {
+		o.CertificateSerial = *pb.CertificateSerial
+	}
+	if pb.Error != nil {
+		// A malformed problem is exported without its details rather than
+		// failing the whole export.
+		o.Error, _ = bgrpc.PBToProblemDetails(pb.Error)
+	}
+	return o
+}
+
+func (e exporter) authorizations(ctx context.Context, regID int64) ([]core.Authorization, error) {
+	var authzs []core.Authorization
+	req := &sapb.SearchAuthorizationsRequest{RegistrationID: &regID}
+	for {
+		page, err := e.saa.SearchAuthorizations(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			return authzs, nil
+		}
+		authzs = append(authzs, page...)
+		last := page[len(page)-1].ID
+		req.AfterID = &last
+	}
+}
+
+func (e exporter) certificates(ctx context.Context, regID int64) ([]certificate, error) {
+	var certs []certificate
+	req := &sapb.SearchCertificatesRequest{RegistrationID: &regID}
+	for {
+		page, err := e.saa.SearchCertificates(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			return certs, nil
+		}
+		for _, c := range page {
+			cert, err := e.certificate(ctx, c)
+			if err != nil {
+				return nil, err
+			}
+			certs = append(certs, cert)
+		}
+		last := page[len(page)-1].Serial
+		req.AfterSerial = &last
+	}
+}
+
+func (e exporter) certificate(ctx context.Context, c core.Certificate) (certificate, error) {
+	parsed, err := x509.ParseCertificate(c.DER)
+	if err != nil {
+		return certificate{}, fmt.Errorf("parsing certificate %s: %s", c.Serial, err)
+	}
+	scts, err := embeddedSCTs(parsed)
+	if err != nil {
+		return certificate{}, fmt.Errorf("parsing SCTs of certificate %s: %s", c.Serial, err)
+	}
+	status, err := e.sa.GetCertificateStatus(ctx, c.Serial)
+	if err != nil {
+		return certificate{}, err
+	}
+	cert := certificate{
+		Serial:  c.Serial,
+		Digest:  c.Digest,
+		Names:   parsed.DNSNames,
+		Issued:  c.Issued.UTC(),
+		Expires: c.Expires.UTC(),
+		Status:  status.Status,
+		SCTs:    scts,
+		DER:     c.DER,
+	}
+	if status.Status == core.OCSPStatusRevoked {
+		revokedDate := status.RevokedDate.UTC()
+		cert.RevokedDate = &revokedDate
+		cert.RevokedReason = revocation.ReasonToString[status.RevokedReason]
+	}
+	return cert, nil
+}
+
+// embeddedSCTs returns the SCTs embedded in cert's SCT list extension
+// (RFC 6962 section 3.3), which record its submission to CT logs.
+func embeddedSCTs(cert *x509.Certificate) ([]sct, error) {
+	var scts []sct
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(asn1.ObjectIdentifier(ctx509.OIDExtensionCTSCT)) {
+			continue
+		}
+		var octets []byte
+		_, err := asn1.Unmarshal(ext.Value, &octets)
+		if err != nil {
+			return nil, err
+		}
+		var list ctx509.SignedCertificateTimestampList
+		_, err = cttls.Unmarshal(octets, &list)
+		if err != nil {
+			return nil, err
+		}
+		for _, serialized := range list.SCTList {
+			var s ct.SignedCertificateTimestamp
+			_, err = cttls.Unmarshal(serialized.Val, &s)
+			if err != nil {
+				return nil, err
+			}
+			scts = append(scts, sct{
+				LogID:     base64.StdEncoding.EncodeToString(s.LogID.KeyID[:]),
+				Timestamp: time.Unix(0, int64(s.Timestamp)*int64(time.Millisecond)).UTC(),
+			})
+		}
+	}
+	return scts, nil
+}
+
+func (e exporter) export(ctx context.Context, regID int64) (*export, error) {
+	reg, err := e.sa.GetRegistration(ctx, regID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := e.saa.GetRegistrationHistory(ctx, regID)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := e.orders(ctx, regID)
+	if err != nil {
+		return nil, err
+	}
+	authzs, err := e.authorizations(ctx, regID)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := e.certificates(ctx, regID)
+	if err != nil {
+		return nil, err
+	}
+	return &export{
+		Registration:   reg,
+		History:        history,
+		Orders:         orders,
+		Authorizations: authzs,
+		Certificates:   certs,
+	}, nil
+}
+
+// redact removes the personal data from an export: the registration's
+// contacts, and the IP addresses of its clients and of the hosts contacted
+// to validate its authorizations. Account keys, names and certificates are
+// public and kept.
+func redact(e *export) {
+	redactRegistration(&e.Registration)
+	for i := range e.History {
+		redactRegistration(&e.History[i].Registration)
+		e.History[i].RequestIP = nil
+	}
+	for i := range e.Authorizations {
+		challenges := e.Authorizations[i].Challenges
+		for j := range challenges {
+			records := challenges[j].ValidationRecord
+			for k := range records {
+				records[k].AddressesResolved = nil
+				records[k].AddressUsed = nil
+				records[k].AddressesTried = nil
+			}
+		}
+	}
+}
+
+func redactRegistration(reg *core.Registration) {
+	reg.Contact = nil
+	reg.InitialIP = nil
+}
+
+func writeExport(w io.Writer, e *export) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(e)
+}
+
+func main() {
+	flagSet := flag.NewFlagSet("account-exporter", flag.ExitOnError)
+	flagSet.Usage = func() {
+		fmt.Fprint(os.Stderr, usageString)
+		flagSet.PrintDefaults()
+	}
+	configFile := flagSet.String("config", "", "File path to the configuration file for this service")
+	regID := flagSet.Int64("reg-id", 0, "ID of the registration to export")
+	outFile := flagSet.String("output", "", "File to write the export to. Defaults to stdout")
+	redactFlag := flagSet.Bool("redact", false, "Remove contacts and IP addresses from the export")
+	_ = flagSet.Parse(os.Args[1:])
+	if *configFile == "" || *regID == 0 {
+		flagSet.Usage()
+		os.Exit(1)
+	}
+
+	var c config
+	err := cmd.ReadConfigFile(*configFile, &c)
+	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	err = features.Set(c.AccountExporter.Features)
+	cmd.FailOnError(err, "Failed to set feature flags")
+
+	logger := cmd.NewLogger(c.Syslog)
+	defer logger.AuditPanic()
+
+	tlsConfig, err := c.AccountExporter.TLS.Load()
+	cmd.FailOnError(err, "TLS config")
+
+	clientMetrics := bgrpc.NewClientMetrics(metrics.NewNoopScope())
+	saConn, err := bgrpc.ClientSetup(c.AccountExporter.SAService, tlsConfig, clientMetrics, cmd.Clock())
+	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA")
+	saAdminConn, err := bgrpc.ClientSetup(c.AccountExporter.SAAdminService, tlsConfig, clientMetrics, cmd.Clock())
+	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA admin API")
+	e := exporter{
+		sa:  bgrpc.NewStorageAuthorityClient(sapb.NewStorageAuthorityClient(saConn)),
+		saa: bgrpc.NewStorageAuthorityAdminClient(sapb.NewStorageAuthorityAdminClient(saAdminConn)),
+	}
+
+	result, err := e.export(context.Background(), *regID)
+	cmd.FailOnError(err, fmt.Sprintf("Failed to export registration %d", *regID))
+	if *redactFlag {
+		redact(result)
+	}
+
+	out := os.Stdout
+	if *outFile != "" {
+		out, err = os.Create(*outFile)
+		cmd.FailOnError(err, "Failed to create output file")
+		defer func() { _ = out.Close() }()
+	}
+	err = writeExport(out, result)
+	cmd.FailOnError(err, "Failed to write export")
+	logger.AuditInfof("Exported registration %d (redacted: %t)", *regID, *redactFlag)
+}
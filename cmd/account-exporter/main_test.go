@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	cttls "github.com/google/certificate-transparency-go/tls"
+	ctx509 "github.com/google/certificate-transparency-go/x509"
+	"golang.org/x/net/context"
+
+	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	"github.com/letsencrypt/boulder/revocation"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/sa/satest"
+	"github.com/letsencrypt/boulder/test"
+)
+
+var sctTimestamp = time.Date(2018, 7, 1, 12, 0, 0, 0, time.UTC)
+
+// certWithSCT returns a self-signed certificate for example.com, embedding
+// an SCT from a log whose ID is all 1s.
+func certWithSCT(t *testing.T, serial int64) []byte {
+	t.Helper()
+	s := ct.SignedCertificateTimestamp{
+		LogID:     ct.LogID{KeyID: [32]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+		Timestamp: uint64(sctTimestamp.UnixNano() / int64(time.Millisecond)),
+		Signature: ct.DigitallySigned{Signature: []byte{0}},
+	}
+	serialized, err := cttls.Marshal(s)
+	test.AssertNotError(t, err, "Failed to marshal SCT")
+	list, err := cttls.Marshal(ctx509.SignedCertificateTimestampList{
+		SCTList: []ctx509.SerializedSCT{{Val: serialized}},
+	})
+	test.AssertNotError(t, err, "Failed to marshal SCT list")
+	extValue, err := asn1.Marshal(list)
+	test.AssertNotError(t, err, "Failed to marshal SCT list extension")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "Failed to generate key")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    sctTimestamp,
+		NotAfter:     sctTimestamp.Add(90 * 24 * time.Hour),
+		ExtraExtensions: []pkix.Extension{
+			{Id: asn1.ObjectIdentifier(ctx509.OIDExtensionCTSCT), Value: extValue},
+		},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	test.AssertNotError(t, err, "Failed to create certificate")
+	return der
+}
+
+// mockSA returns a page of one result at a time, to exercise paging.
+type mockSA struct {
+	core.StorageGetter
+	certs []core.Certificate
+}
+
+func (sa *mockSA) GetRegistration(_ context.Context, regID int64) (core.Registration, error) {
+	contact := []string{"mailto:admin@example.com"}
+	return core.Registration{
+		ID:        regID,
+		Key:       satest.GoodJWK(),
+		Contact:   &contact,
+		InitialIP: net.ParseIP("10.0.0.1"),
+		Status:    core.StatusValid,
+	}, nil
+}
+
+func (sa *mockSA) GetCertificateStatus(_ context.Context, serial string) (core.CertificateStatus, error) {
+	if serial == sa.certs[1].Serial {
+		return core.CertificateStatus{
+			Serial:        serial,
+			Status:        core.OCSPStatusRevoked,
+			RevokedDate:   sctTimestamp.Add(time.Hour),
+			RevokedReason: revocation.KeyCompromise,
+		}, nil
+	}
+	return core.CertificateStatus{Serial: serial, Status: core.OCSPStatusGood}, nil
+}
+
+func (sa *mockSA) SearchCertificates(_ context.Context, req *sapb.SearchCertificatesRequest) ([]core.Certificate, error) {
+	for _, cert := range sa.certs {
+		if req.AfterSerial == nil || cert.Serial > *req.AfterSerial {
+			return []core.Certificate{cert}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (sa *mockSA) SearchRegistrations(_ context.Context, _ *sapb.SearchRegistrationsRequest) ([]core.Registration, error) {
+	return nil, nil
+}
+
+func (sa *mockSA) GetRegistrationHistory(ctx context.Context, regID int64) ([]core.RegistrationChange, error) {
+	reg, _ := sa.GetRegistration(ctx, regID)
+	return []core.RegistrationChange{{Registration: reg, ChangedAt: sctTimestamp, RequestIP: reg.InitialIP}}, nil
+}
+
+func (sa *mockSA) SearchOrders(_ context.Context, req *sapb.SearchOrdersRequest) ([]*corepb.Order, error) {
+	if req.AfterID != nil {
+		return nil, nil
+	}
+	id, expires, status := int64(7), sctTimestamp.UnixNano(), string(core.StatusValid)
+	serial := sa.certs[0].Serial
+	return []*corepb.Order{{
+		Id:                &id,
+		RegistrationID:    req.RegistrationID,
+		Expires:           &expires,
+		Status:            &status,
+		Names:             []string{"example.com"},
+		Authorizations:    []string{"a"},
+		CertificateSerial: &serial,
+	}}, nil
+}
+
+func (sa *mockSA) SearchAuthorizations(_ context.Context, req *sapb.SearchAuthorizationsRequest) ([]core.Authorization, error) {
+	if req.AfterID != nil {
+		return nil, nil
+	}
+	return []core.Authorization{{
+		ID:         "a",
+		Identifier: core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "example.com"},
+		Status:     core.StatusValid,
+		Challenges: []core.Challenge{{
+			Type:   core.ChallengeTypeHTTP01,
+			Status: core.StatusValid,
+			ValidationRecord: []core.ValidationRecord{{
+				Hostname:          "example.com",
+				Port:              "80",
+				AddressesResolved: []net.IP{net.ParseIP("192.0.2.1")},
+				AddressUsed:       net.ParseIP("192.0.2.1"),
+				URL:               "http://example.com/.well-known/acme-challenge/a",
+			}},
+		}},
+	}}, nil
+}
+
+func (sa *mockSA) RegistrationsWithCertificates(_ context.Context, _ *sapb.RegistrationsWithCertificatesRequest) ([]*sapb.RegistrationContacts, error) {
+	return nil, nil
+}
+
+// newMockSA returns a mockSA with a good and a revoked certificate.
+func newMockSA(t *testing.T) *mockSA {
+	sa := &mockSA{}
+	for i, serial := range []string{"01", "02"} {
+		sa.certs = append(sa.certs, core.Certificate{
+			Serial:  serial,
+			DER:     certWithSCT(t, int64(i+1)),
+			Issued:  sctTimestamp,
+			Expires: sctTimestamp.Add(90 * 24 * time.Hour),
+		})
+	}
+	return sa
+}
+
+func TestExport(t *testing.T) {
+	sa := newMockSA(t)
+	e := exporter{sa: sa, saa: sa}
+
+	result, err := e.export(context.Background(), 1)
+	test.AssertNotError(t, err, "export failed")
+	test.AssertEquals(t, result.Registration.ID, int64(1))
+	test.AssertEquals(t, len(result.History), 1)
+	test.AssertEquals(t, len(result.Orders), 1)
+	test.AssertEquals(t, result.Orders[0].CertificateSerial, "01")
+	test.AssertEquals(t, len(result.Authorizations), 1)
+
+	test.AssertEquals(t, len(result.Certificates), 2)
+	good, revoked := result.Certificates[0], result.Certificates[1]
+	test.AssertDeepEquals(t, good.Names, []string{"example.com"})
+	test.AssertEquals(t, good.Status, core.OCSPStatusGood)
+	test.Assert(t, good.RevokedDate == nil, "Unexpected revocation date")
+	test.AssertDeepEquals(t, good.SCTs, []sct{{
+		LogID:     "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=",
+		Timestamp: sctTimestamp,
+	}})
+	test.AssertEquals(t, revoked.Status, core.OCSPStatusRevoked)
+	test.AssertEquals(t, *revoked.RevokedDate, sctTimestamp.Add(time.Hour))
+	test.AssertEquals(t, revoked.RevokedReason, "keyCompromise")
+
+	var out bytes.Buffer
+	err = writeExport(&out, result)
+	test.AssertNotError(t, err, "writeExport failed")
+	test.Assert(t, bytes.Contains(out.Bytes(), []byte("mailto:admin@example.com")), "Export is missing contact")
+	test.Assert(t, bytes.Contains(out.Bytes(), []byte("192.0.2.1")), "Export is missing validation address")
+	var decoded map[string]interface{}
+	err = json.Unmarshal(out.Bytes(), &decoded)
+	test.AssertNotError(t, err, "Export isn't valid JSON")
+}
+
+func TestRedact(t *testing.T) {
+	sa := newMockSA(t)
+	e := exporter{sa: sa, saa: sa}
+	result, err := e.export(context.Background(), 1)
+	test.AssertNotError(t, err, "export failed")
+
+	redact(result)
+	var out bytes.Buffer
+	err = writeExport(&out, result)
+	test.AssertNotError(t, err, "writeExport failed")
+	for _, sensitive := range []string{"admin@example.com", "10.0.0.1", "192.0.2.1"} {
+		test.Assert(t, !bytes.Contains(out.Bytes(), []byte(sensitive)), "Redacted export contains "+sensitive)
+	}
+	// Non-personal data is kept
+	test.Assert(t, bytes.Contains(out.Bytes(), []byte("http://example.com/.well-known/acme-challenge/a")), "Redacted export is missing validation URL")
+}
@@ -2,27 +2,26 @@ package main
 
 import (
 	"crypto/x509"
-	"database/sql"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/user"
 	"sort"
 	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"golang.org/x/net/context"
-	"gopkg.in/go-gorp/gorp.v2"
 
 	"github.com/letsencrypt/boulder/cmd"
 	"github.com/letsencrypt/boulder/core"
-	berrors "github.com/letsencrypt/boulder/errors"
 	"github.com/letsencrypt/boulder/features"
 	bgrpc "github.com/letsencrypt/boulder/grpc"
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/metrics"
 	rapb "github.com/letsencrypt/boulder/ra/proto"
 	"github.com/letsencrypt/boulder/revocation"
-	"github.com/letsencrypt/boulder/sa"
 	sapb "github.com/letsencrypt/boulder/sa/proto"
 )
 
@@ -32,12 +31,15 @@ admin-revoker serial-revoke --config <path> <serial> <reason-code>
 admin-revoker reg-revoke --config <path> <registration-id> <reason-code>
 admin-revoker list-reasons --config <path>
 admin-revoker auth-revoke --config <path> <domain>
+admin-revoker reg-history --config <path> <registration-id>
 
 command descriptions:
   serial-revoke   Revoke a single certificate by the hex serial number
   reg-revoke      Revoke all certificates associated with a registration ID
   list-reasons    List all revocation reason codes
   auth-revoke     Revoke all pending/valid authorizations for a domain
+  reg-history     Show the recorded key, contact, agreement and status changes
+                  of a registration
 
 args:
   config    File path to the configuration file for this service
@@ -45,13 +47,15 @@ args:
 
 type config struct {
 	Revoker struct {
-		cmd.DBConfig
 		// Similarly, the Revoker needs a TLSConfig to set up its GRPC client certs,
 		// but doesn't get the TLS field from ServiceConfig, so declares its own.
 		TLS cmd.TLSConfig
 
 		RAService *cmd.GRPCClientConfig
 		SAService *cmd.GRPCClientConfig
+		// SAAdminService is the SA's admin gRPC API, which is served on its
+		// own address.
+		SAAdminService *cmd.GRPCClientConfig
 
 		Features map[string]bool
 	}
@@ -59,7 +63,7 @@ type config struct {
 	Syslog cmd.SyslogConfig
 }
 
-func setupContext(c config) (core.RegistrationAuthority, blog.Logger, *gorp.DbMap, core.StorageAuthority) {
+func setupContext(c config) (core.RegistrationAuthority, blog.Logger, core.StorageAuthority, core.StorageAdmin) {
 	logger := cmd.NewLogger(c.Syslog)
 
 	tlsConfig, err := c.Revoker.TLS.Load()
@@ -72,27 +76,23 @@ func setupContext(c config) (core.RegistrationAuthority, blog.Logger, *gorp.DbMa
 	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to RA")
 	rac := bgrpc.NewRegistrationAuthorityClient(rapb.NewRegistrationAuthorityClient(raConn))
 
-	dbURL, err := c.Revoker.DBConfig.URL()
-	cmd.FailOnError(err, "Couldn't load DB URL")
-	dbMap, err := sa.NewDbMap(dbURL, c.Revoker.DBConfig.MaxDBConns)
-	cmd.FailOnError(err, "Couldn't setup database connection")
-
 	saConn, err := bgrpc.ClientSetup(c.Revoker.SAService, tlsConfig, clientMetrics, clk)
 	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA")
 	sac := bgrpc.NewStorageAuthorityClient(sapb.NewStorageAuthorityClient(saConn))
 
-	return rac, logger, dbMap, sac
+	saAdminConn, err := bgrpc.ClientSetup(c.Revoker.SAAdminService, tlsConfig, clientMetrics, clk)
+	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA admin API")
+	saac := bgrpc.NewStorageAuthorityAdminClient(sapb.NewStorageAuthorityAdminClient(saAdminConn))
+
+	return rac, logger, sac, saac
 }
 
-func revokeBySerial(ctx context.Context, serial string, reasonCode revocation.Reason, rac core.RegistrationAuthority, logger blog.Logger, tx *gorp.Transaction) (err error) {
+func revokeBySerial(ctx context.Context, serial string, reasonCode revocation.Reason, rac core.RegistrationAuthority, logger blog.Logger, sac core.StorageGetter) (err error) {
 	if reasonCode < 0 || reasonCode == 7 || reasonCode > 10 {
 		panic(fmt.Sprintf("Invalid reason code: %d", reasonCode))
 	}
 
-	certObj, err := sa.SelectCertificate(tx, "WHERE serial = ?", serial)
-	if err == sql.ErrNoRows {
-		return berrors.NotFoundError("certificate with serial %q not found", serial)
-	}
+	certObj, err := sac.GetCertificate(ctx, serial)
 	if err != nil {
 		return err
 	}
@@ -111,21 +111,65 @@ func revokeBySerial(ctx context.Context, serial string, reasonCode revocation.Re
 	return
 }
 
-func revokeByReg(ctx context.Context, regID int64, reasonCode revocation.Reason, rac core.RegistrationAuthority, logger blog.Logger, tx *gorp.Transaction) (err error) {
-	var certs []core.Certificate
-	_, err = tx.Select(&certs, "SELECT serial FROM certificates WHERE registrationID = :regID", map[string]interface{}{"regID": regID})
+func revokeByReg(ctx context.Context, regID int64, reasonCode revocation.Reason, rac core.RegistrationAuthority, logger blog.Logger, sac core.StorageGetter, saac core.StorageAdmin) (err error) {
+	req := &sapb.SearchCertificatesRequest{RegistrationID: &regID}
+	for {
+		certs, err := saac.SearchCertificates(ctx, req)
+		if err != nil {
+			return err
+		}
+		if len(certs) == 0 {
+			return nil
+		}
+
+		for _, cert := range certs {
+			err = revokeBySerial(ctx, cert.Serial, reasonCode, rac, logger, sac)
+			if err != nil {
+				return err
+			}
+		}
+		last := certs[len(certs)-1].Serial
+		req.AfterSerial = &last
+	}
+}
+
+// printRegHistory writes a table of the changes recorded for a registration,
+// oldest first, identifying each key by its SHA-256 digest.
+func printRegHistory(ctx context.Context, w io.Writer, regID int64, saac core.StorageAdmin) error {
+	changes, err := saac.GetRegistrationHistory(ctx, regID)
 	if err != nil {
-		return
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "No changes recorded for registration %d\n", regID)
+		return nil
 	}
 
-	for _, cert := range certs {
-		err = revokeBySerial(ctx, cert.Serial, reasonCode, rac, logger, tx)
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHANGED AT\tREQUEST IP\tSTATUS\tKEY SHA256\tAGREEMENT\tCONTACTS")
+	for _, change := range changes {
+		reg := change.Registration
+		digest, err := core.KeyDigest(reg.Key)
 		if err != nil {
-			return
+			return err
 		}
+		requestIP := "unknown"
+		if change.RequestIP != nil {
+			requestIP = change.RequestIP.String()
+		}
+		var contacts string
+		if reg.Contact != nil {
+			contacts = strings.Join(*reg.Contact, ",")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			change.ChangedAt.UTC().Format("2006-01-02 15:04:05"),
+			requestIP,
+			reg.Status,
+			digest,
+			reg.Agreement,
+			contacts)
 	}
-
-	return
+	return tw.Flush()
 }
 
 // This abstraction is needed so that we can use sort.Sort below
@@ -169,20 +213,10 @@ func main() {
 		reasonCode, err := strconv.Atoi(args[1])
 		cmd.FailOnError(err, "Reason code argument must be an integer")
 
-		rac, logger, dbMap, _ := setupContext(c)
+		rac, logger, sac, _ := setupContext(c)
 
-		tx, err := dbMap.Begin()
-		if err != nil {
-			cmd.FailOnError(sa.Rollback(tx, err), "Couldn't begin transaction")
-		}
-
-		err = revokeBySerial(ctx, serial, revocation.Reason(reasonCode), rac, logger, tx)
-		if err != nil {
-			cmd.FailOnError(sa.Rollback(tx, err), "Couldn't revoke certificate")
-		}
-
-		err = tx.Commit()
-		cmd.FailOnError(err, "Couldn't cleanly close transaction")
+		err = revokeBySerial(ctx, serial, revocation.Reason(reasonCode), rac, logger, sac)
+		cmd.FailOnError(err, "Couldn't revoke certificate")
 
 	case command == "reg-revoke" && len(args) == 2:
 		// 1: registration ID,  2: reasonCode
@@ -191,26 +225,16 @@ func main() {
 		reasonCode, err := strconv.Atoi(args[1])
 		cmd.FailOnError(err, "Reason code argument must be an integer")
 
-		rac, logger, dbMap, sac := setupContext(c)
+		rac, logger, sac, saac := setupContext(c)
 		defer logger.AuditPanic()
 
-		tx, err := dbMap.Begin()
-		if err != nil {
-			cmd.FailOnError(sa.Rollback(tx, err), "Couldn't begin transaction")
-		}
-
 		_, err = sac.GetRegistration(ctx, regID)
 		if err != nil {
 			cmd.FailOnError(err, "Couldn't fetch registration")
 		}
 
-		err = revokeByReg(ctx, regID, revocation.Reason(reasonCode), rac, logger, tx)
-		if err != nil {
-			cmd.FailOnError(sa.Rollback(tx, err), "Couldn't revoke certificate")
-		}
-
-		err = tx.Commit()
-		cmd.FailOnError(err, "Couldn't cleanly close transaction")
+		err = revokeByReg(ctx, regID, revocation.Reason(reasonCode), rac, logger, sac, saac)
+		cmd.FailOnError(err, "Couldn't revoke certificate")
 
 	case command == "list-reasons":
 		var codes revocationCodes
@@ -225,13 +249,23 @@ func main() {
 
 	case command == "auth-revoke" && len(args) == 1:
 		domain := args[0]
-		_, logger, _, sac := setupContext(c)
+		_, logger, sac, _ := setupContext(c)
 		ident := core.AcmeIdentifier{Value: domain, Type: core.IdentifierDNS}
 		authsRevoked, pendingAuthsRevoked, err := sac.RevokeAuthorizationsByDomain(ctx, ident)
 		cmd.FailOnError(err, fmt.Sprintf("Failed to revoke authorizations for %s", ident.Value))
 		logger.Infof("Revoked %d pending authorizations and %d final authorizations",
 			pendingAuthsRevoked, authsRevoked)
 
+	case command == "reg-history" && len(args) == 1:
+		regID, err := strconv.ParseInt(args[0], 10, 64)
+		cmd.FailOnError(err, "Registration ID argument must be an integer")
+
+		_, logger, _, saac := setupContext(c)
+		defer logger.AuditPanic()
+
+		err = printRegHistory(ctx, os.Stdout, regID, saac)
+		cmd.FailOnError(err, "Couldn't fetch registration history")
+
 	default:
 		usage()
 	}
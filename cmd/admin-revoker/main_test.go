@@ -1 +1,144 @@
 package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	jose "gopkg.in/square/go-jose.v2"
+
+	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/revocation"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+type mockRA struct {
+	core.RegistrationAuthority
+	revoked int
+}
+
+func (ra *mockRA) AdministrativelyRevokeCertificate(_ context.Context, _ x509.Certificate, _ revocation.Reason, _ string) error {
+	ra.revoked++
+	return nil
+}
+
+// mockSA returns pages of two certificates, for registration 1 only.
+type mockSA struct {
+	core.StorageGetter
+	der      []byte
+	serials  []string
+	searches int
+}
+
+func (sa *mockSA) GetCertificate(_ context.Context, serial string) (core.Certificate, error) {
+	return core.Certificate{Serial: serial, DER: sa.der}, nil
+}
+
+func (sa *mockSA) SearchCertificates(_ context.Context, req *sapb.SearchCertificatesRequest) ([]core.Certificate, error) {
+	sa.searches++
+	if req.RegistrationID == nil || *req.RegistrationID != 1 {
+		return nil, nil
+	}
+	var certs []core.Certificate
+	for _, serial := range sa.serials {
+		if req.AfterSerial != nil && serial <= *req.AfterSerial {
+			continue
+		}
+		if len(certs) == 2 {
+			break
+		}
+		certs = append(certs, core.Certificate{Serial: serial})
+	}
+	return certs, nil
+}
+
+func (sa *mockSA) SearchRegistrations(_ context.Context, _ *sapb.SearchRegistrationsRequest) ([]core.Registration, error) {
+	return nil, nil
+}
+
+func (sa *mockSA) GetRegistrationHistory(_ context.Context, regID int64) ([]core.RegistrationChange, error) {
+	if regID != 1 {
+		return nil, nil
+	}
+	var jwk jose.JSONWebKey
+	err := json.Unmarshal([]byte(`{"kty":"RSA","n":"yNWVhtYEKJR21y9xsHV-PD_bYwbXSeNuFal46xYxVfRL5mqha7vttvjB_vc7Xg2RvgCxHPCqoxgMPTzHrZT75LjCwIW2K_klBYN8oYvTwwmeSkAz6ut7ZxPv-nZaT5TJhGk0NT2kh_zSpdriEJ_3vW-mqxYbbBmpvHqsa1_zx9fSuHYctAZJWzxzUZXykbWMWQZpEiE0J4ajj51fInEzVn7VxV-mzfMyboQjujPh7aNJxAWSq4oQEJJDgWwSh9leyoJoPpONHxh5nEE5AjE01FkGICSxjpZsF-w8hOTI3XXohUdu29Se26k2B0PolDSuj0GIQU6-W9TdLXSjBb2SpQ","e":"AQAB"}`), &jwk)
+	if err != nil {
+		return nil, err
+	}
+	contact := []string{"mailto:admin@example.com"}
+	return []core.RegistrationChange{
+		{
+			Registration: core.Registration{ID: 1, Key: &jwk, Contact: &contact, Status: core.StatusValid},
+			ChangedAt:    time.Date(2018, 7, 1, 12, 0, 0, 0, time.UTC),
+			RequestIP:    net.ParseIP("10.0.0.1"),
+		},
+		{
+			Registration: core.Registration{ID: 1, Key: &jwk, Contact: &contact, Status: core.StatusDeactivated},
+			ChangedAt:    time.Date(2018, 7, 2, 12, 0, 0, 0, time.UTC),
+		},
+	}, nil
+}
+
+func (sa *mockSA) SearchOrders(_ context.Context, _ *sapb.SearchOrdersRequest) ([]*corepb.Order, error) {
+	return nil, nil
+}
+
+func (sa *mockSA) SearchAuthorizations(_ context.Context, _ *sapb.SearchAuthorizationsRequest) ([]core.Authorization, error) {
+	return nil, nil
+}
+
+func (sa *mockSA) RegistrationsWithCertificates(_ context.Context, _ *sapb.RegistrationsWithCertificatesRequest) ([]*sapb.RegistrationContacts, error) {
+	return nil, nil
+}
+
+func TestPrintRegHistory(t *testing.T) {
+	sa := &mockSA{}
+	var out bytes.Buffer
+	err := printRegHistory(context.Background(), &out, 1, sa)
+	test.AssertNotError(t, err, "printRegHistory failed")
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	test.AssertEquals(t, len(lines), 3)
+	test.Assert(t, strings.HasPrefix(lines[0], "CHANGED AT"), "Missing header")
+	// The key is shown by its digest and the empty agreement column is blank
+	first := strings.Fields(lines[1])
+	test.AssertDeepEquals(t, []string{first[0], first[1], first[2], first[3], first[5]},
+		[]string{"2018-07-01", "12:00:00", "10.0.0.1", "valid", "mailto:admin@example.com"})
+	second := strings.Fields(lines[2])
+	test.AssertDeepEquals(t, second[:4], []string{"2018-07-02", "12:00:00", "unknown", "deactivated"})
+	test.AssertEquals(t, second[4], first[4])
+
+	out.Reset()
+	err = printRegHistory(context.Background(), &out, 2, sa)
+	test.AssertNotError(t, err, "printRegHistory failed")
+	test.AssertEquals(t, out.String(), "No changes recorded for registration 2\n")
+}
+
+func TestRevokeByReg(t *testing.T) {
+	cert, err := core.LoadCert("../../test/test-ca.pem")
+	test.AssertNotError(t, err, "Failed to load certificate")
+	sa := &mockSA{der: cert.Raw}
+	for i := 0; i < 5; i++ {
+		sa.serials = append(sa.serials, fmt.Sprintf("%02x", i))
+	}
+	ra := &mockRA{}
+
+	// Every page of the registration's certificates is revoked
+	err = revokeByReg(context.Background(), 1, revocation.KeyCompromise, ra, blog.NewMock(), sa, sa)
+	test.AssertNotError(t, err, "revokeByReg failed")
+	test.AssertEquals(t, ra.revoked, 5)
+	test.AssertEquals(t, sa.searches, 4)
+
+	ra.revoked = 0
+	err = revokeByReg(context.Background(), 2, revocation.KeyCompromise, ra, blog.NewMock(), sa, sa)
+	test.AssertNotError(t, err, "revokeByReg failed")
+	test.AssertEquals(t, ra.revoked, 0)
+}
@@ -37,12 +37,30 @@ func loadIssuers(c config) ([]ca.Issuer, error) {
 	for _, issuerConfig := range c.CA.Issuers {
 		priv, cert, err := loadIssuer(issuerConfig)
 		cmd.FailOnError(err, "Couldn't load private key")
+		responders, err := loadOCSPResponders(issuerConfig)
+		cmd.FailOnError(err, "Couldn't load OCSP responder")
 		issuers = append(issuers, ca.Issuer{
+			Signer:         priv,
+			Cert:           cert,
+			OCSPResponders: responders,
+		})
+	}
+	return issuers, nil
+}
+
+func loadOCSPResponders(issuerConfig ca_config.IssuerConfig) ([]ca.OCSPResponder, error) {
+	var responders []ca.OCSPResponder
+	for _, responderConfig := range issuerConfig.OCSPResponders {
+		priv, cert, err := loadIssuer(responderConfig)
+		if err != nil {
+			return nil, err
+		}
+		responders = append(responders, ca.OCSPResponder{
 			Signer: priv,
 			Cert:   cert,
 		})
 	}
-	return issuers, nil
+	return responders, nil
 }
 
 func loadIssuer(issuerConfig ca_config.IssuerConfig) (crypto.Signer, *x509.Certificate, error) {
@@ -150,6 +168,8 @@ func main() {
 	}
 	err = pa.SetHostnamePolicyFile(c.CA.HostnamePolicyFile)
 	cmd.FailOnError(err, "Couldn't load hostname policy file")
+	err = pa.SetIDNPolicies(c.PA.IDN)
+	cmd.FailOnError(err, "Couldn't set IDN policies")
 
 	issuers, err := loadIssuers(c)
 	cmd.FailOnError(err, "Couldn't load issuers")
@@ -72,6 +72,16 @@ type config struct {
 
 		OrderLifetime cmd.ConfigDuration
 
+		// IssuanceProfiles are the CA issuance profiles, other than the default,
+		// that accounts may request in new orders, and which accounts may
+		// request each.
+		IssuanceProfiles []ra.IssuanceProfile
+
+		// CAAIodefReports enables queueing reports of CAA checks which deny
+		// issuance, for the caa-iodef-reporter to send to the iodef targets in
+		// the domain's CAA records.
+		CAAIodefReports bool
+
 		// CTLogGroups contains groupings of CT logs which we want SCTs from.
 		// When we retrieve SCTs we will submit the certificate to each log
 		// in a group and the first SCT returned will be used. This allows
@@ -134,11 +144,18 @@ func main() {
 	pa, err := policy.New(c.PA.Challenges)
 	cmd.FailOnError(err, "Couldn't create PA")
 
+	if len(c.PA.ContactSchemes) > 0 {
+		err = pa.SetContactSchemes(c.PA.ContactSchemes)
+		cmd.FailOnError(err, "Couldn't set contact schemes")
+	}
+
 	if c.RA.HostnamePolicyFile == "" {
 		cmd.FailOnError(fmt.Errorf("HostnamePolicyFile must be provided."), "")
 	}
 	err = pa.SetHostnamePolicyFile(c.RA.HostnamePolicyFile)
 	cmd.FailOnError(err, "Couldn't load hostname policy file")
+	err = pa.SetIDNPolicies(c.PA.IDN)
+	cmd.FailOnError(err, "Couldn't set IDN policies")
 
 	if c.PA.ChallengesWhitelistFile != "" {
 		err = pa.SetChallengesWhitelistFile(c.PA.ChallengesWhitelistFile)
@@ -230,6 +247,11 @@ func main() {
 
 	policyErr := rai.SetRateLimitPoliciesFile(c.RA.RateLimitPoliciesFilename)
 	cmd.FailOnError(policyErr, "Couldn't load rate limit policies file")
+	err = rai.SetIssuanceProfiles(c.RA.IssuanceProfiles)
+	cmd.FailOnError(err, "Couldn't load issuance profiles")
+	if c.RA.CAAIodefReports {
+		rai.EnableIodefReports()
+	}
 	rai.PA = pa
 
 	raDNSTimeout, err := time.ParseDuration(c.Common.DNSTimeout)
@@ -2,14 +2,18 @@ package main
 
 import (
 	"flag"
+	"net"
 	"os"
 
+	"google.golang.org/grpc"
+
 	"github.com/letsencrypt/boulder/cmd"
 	"github.com/letsencrypt/boulder/features"
 	bgrpc "github.com/letsencrypt/boulder/grpc"
 	"github.com/letsencrypt/boulder/sa"
 	sapb "github.com/letsencrypt/boulder/sa/proto"
 	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/go-gorp/gorp.v2"
 )
 
 type config struct {
@@ -17,6 +21,22 @@ type config struct {
 		cmd.ServiceConfig
 		cmd.DBConfig
 
+		// ReadReplica optionally configures a read-only replica of the
+		// database. Getters that can tolerate replication lag, such as the
+		// rate limit counts, are sent to it instead of the primary.
+		ReadReplica *cmd.DBConfig
+
+		// MigrationsDir is the directory of the migrations this version of the
+		// SA expects to have been applied. If set, the SA refuses to start if
+		// any of them hasn't been. If unset, the schema isn't checked.
+		MigrationsDir string
+
+		// AdminGRPC, if set, serves the StorageAuthorityAdmin API on its own
+		// address. The admin API exposes account contacts and the IP
+		// addresses accounts were used from, so its ClientNames should only
+		// list admin tools. It isn't served on the main gRPC address.
+		AdminGRPC *cmd.GRPCServerConfig
+
 		Features map[string]bool
 
 		// Max simultaneous SQL queries caused by a single RPC.
@@ -27,7 +47,13 @@ type config struct {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrate(os.Args[2:])
+		return
+	}
+
 	grpcAddr := flag.String("addr", "", "gRPC listen address override")
+	adminAddr := flag.String("admin-addr", "", "Admin gRPC listen address override")
 	debugAddr := flag.String("debug-addr", "", "Debug server address override")
 	configFile := flag.String("config", "", "File path to the configuration file for this service")
 	flag.Parse()
@@ -46,6 +72,9 @@ func main() {
 	if *grpcAddr != "" {
 		c.SA.GRPC.Address = *grpcAddr
 	}
+	if *adminAddr != "" && c.SA.AdminGRPC != nil {
+		c.SA.AdminGRPC.Address = *adminAddr
+	}
 	if *debugAddr != "" {
 		c.SA.DebugAddr = *debugAddr
 	}
@@ -62,6 +91,15 @@ func main() {
 	dbMap, err := sa.NewDbMap(dbURL, saConf.DBConfig.MaxDBConns)
 	cmd.FailOnError(err, "Couldn't connect to SA database")
 
+	// Refuse to start against a schema older than the one the SA's queries
+	// are written for.
+	if saConf.MigrationsDir != "" {
+		migrations, err := sa.LoadMigrations(saConf.MigrationsDir)
+		cmd.FailOnError(err, "Failed to load migrations")
+		err = sa.CheckSchemaVersion(dbMap, migrations)
+		cmd.FailOnError(err, "Database schema check failed")
+	}
+
 	// Export the MaxDBConns
 	dbConnStat := prometheus.NewGauge(prometheus.GaugeOpts{
 		Name: "max_db_connections",
@@ -75,6 +113,27 @@ func main() {
 	}
 	go sa.ReportDbConnCount(dbMap, scope)
 
+	var replicaMap *gorp.DbMap
+	if saConf.ReadReplica != nil {
+		replicaURL, err := saConf.ReadReplica.URL()
+		cmd.FailOnError(err, "Couldn't load read replica DB URL")
+
+		replicaMap, err = sa.NewDbMap(replicaURL, saConf.ReadReplica.MaxDBConns)
+		cmd.FailOnError(err, "Couldn't connect to SA read replica database")
+
+		replicaConnStat := prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "max_replica_db_connections",
+			Help: "Maximum number of read replica DB connections allowed.",
+		})
+		scope.MustRegister(replicaConnStat)
+		replicaConnStat.Set(float64(saConf.ReadReplica.MaxDBConns))
+
+		if saConf.ReadReplica.MaxIdleDBConns != 0 {
+			replicaMap.Db.SetMaxIdleConns(saConf.ReadReplica.MaxIdleDBConns)
+		}
+		go sa.ReportDbConnCount(replicaMap, scope.NewScope("ReadReplica"))
+	}
+
 	clk := cmd.Clock()
 
 	parallel := saConf.ParallelismPerRPC
@@ -83,6 +142,9 @@ func main() {
 	}
 	sai, err := sa.NewSQLStorageAuthority(dbMap, clk, logger, scope, parallel)
 	cmd.FailOnError(err, "Failed to create SA impl")
+	if replicaMap != nil {
+		sai.UseReadReplica(replicaMap)
+	}
 
 	tls, err := c.SA.TLS.Load()
 	cmd.FailOnError(err, "TLS config")
@@ -92,7 +154,24 @@ func main() {
 	gw := bgrpc.NewStorageAuthorityServer(sai)
 	sapb.RegisterStorageAuthorityServer(grpcSrv, gw)
 
-	go cmd.CatchSignals(logger, grpcSrv.GracefulStop)
+	var adminSrv *grpc.Server
+	if c.SA.AdminGRPC != nil {
+		var adminListener net.Listener
+		adminSrv, adminListener, err = bgrpc.NewServer(c.SA.AdminGRPC, tls, serverMetrics, clk)
+		cmd.FailOnError(err, "Unable to setup SA admin gRPC server")
+		sapb.RegisterStorageAuthorityAdminServer(adminSrv, bgrpc.NewStorageAuthorityAdminServer(sai))
+		go func() {
+			err := cmd.FilterShutdownErrors(adminSrv.Serve(adminListener))
+			cmd.FailOnError(err, "SA admin gRPC service failed")
+		}()
+	}
+
+	go cmd.CatchSignals(logger, func() {
+		if adminSrv != nil {
+			adminSrv.GracefulStop()
+		}
+		grpcSrv.GracefulStop()
+	})
 
 	err = cmd.FilterShutdownErrors(grpcSrv.Serve(listener))
 	cmd.FailOnError(err, "SA gRPC service failed")
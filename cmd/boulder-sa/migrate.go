@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/sa"
+)
+
+const migrateUsage = `usage: boulder-sa migrate -config <path> [-dir <path>] [-db-connect-file <path>] [-status | -dry-run]
+
+Applies the pending goose-formatted migrations in -dir to the SA database and
+records them in the goose_db_version table, so it can take over databases
+previously migrated with goose. Each migration is applied in its own
+transaction, although MySQL/MariaDB implicitly commit DDL statements.
+
+args:
+`
+
+// defaultMigrationsDir is where the migrations are found, relative to the
+// Boulder source tree, unless configured otherwise.
+const defaultMigrationsDir = "sa/_db/migrations"
+
+// migrate implements the "boulder-sa migrate" subcommand.
+func migrate(args []string) {
+	flagSet := flag.NewFlagSet("migrate", flag.ExitOnError)
+	flagSet.Usage = func() {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		flagSet.PrintDefaults()
+	}
+	configFile := flagSet.String("config", "", "File path to the configuration file for the SA")
+	dir := flagSet.String("dir", defaultMigrationsDir, "Directory containing the migrations to apply")
+	dbConnectFile := flagSet.String("db-connect-file", "", "File containing a connect URL for a DB user that may alter the schema. Defaults to the SA's")
+	status := flagSet.Bool("status", false, "Print which migrations have been applied and exit")
+	dryRun := flagSet.Bool("dry-run", false, "Print the pending migrations' statements without applying them")
+	_ = flagSet.Parse(args)
+	if *configFile == "" {
+		flagSet.Usage()
+		os.Exit(1)
+	}
+
+	var c config
+	err := cmd.ReadConfigFile(*configFile, &c)
+	cmd.FailOnError(err, "Reading JSON config file into config structure")
+
+	dbConfig := c.SA.DBConfig
+	if *dbConnectFile != "" {
+		dbConfig = cmd.DBConfig{DBConnectFile: *dbConnectFile}
+	}
+	dbURL, err := dbConfig.URL()
+	cmd.FailOnError(err, "Couldn't load DB URL")
+	dbMap, err := sa.NewDbMap(dbURL, 1)
+	cmd.FailOnError(err, "Couldn't connect to SA database")
+
+	migrations, err := sa.LoadMigrations(*dir)
+	cmd.FailOnError(err, "Failed to load migrations")
+
+	// -status and -dry-run only read the database, so they treat a missing
+	// migration table as no migrations having been applied rather than
+	// creating it.
+	readOnly := *status || *dryRun
+	if !readOnly {
+		err = sa.EnsureMigrationTable(dbMap)
+		cmd.FailOnError(err, "Failed to create migration table")
+	}
+	applied, err := sa.AppliedMigrations(dbMap)
+	if err != nil {
+		if !readOnly {
+			cmd.FailOnError(err, "Failed to read applied migrations")
+		}
+		fmt.Fprintf(os.Stderr, "Couldn't read applied migrations, treating all as pending: %s\n", err)
+	}
+	pending := sa.PendingMigrations(migrations, applied)
+
+	if *status {
+		for _, m := range migrations {
+			if at, ok := applied[m.Version]; ok {
+				fmt.Printf("%-60s applied %s\n", m.Name, at.UTC().Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Printf("%-60s pending\n", m.Name)
+			}
+		}
+		if len(pending) == 0 {
+			fmt.Println("Schema is up to date")
+		} else {
+			fmt.Printf("Schema is behind: %d pending migrations\n", len(pending))
+		}
+		return
+	}
+
+	for _, m := range pending {
+		if *dryRun {
+			fmt.Printf("-- %s\n%s\n\n", m.Name, strings.Join(m.Up, "\n"))
+			continue
+		}
+		err := sa.ApplyMigration(dbMap, m)
+		cmd.FailOnError(err, "Migration failed")
+		fmt.Printf("Applied %s\n", m.Name)
+	}
+	if len(pending) == 0 {
+		fmt.Println("No pending migrations")
+	}
+}
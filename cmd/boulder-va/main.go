@@ -20,12 +20,24 @@ type config struct {
 
 		UserAgent string
 
-		IssuerDomain string
+		// IssuerDomain is our primary CAA identity. CAAIdentities lists any
+		// others, such as names we operated under before, and identities
+		// scoped to a partner's accounts.
+		IssuerDomain  string
+		CAAIdentities []cmd.CAAIdentityConfig
 
 		PortConfig cmd.PortConfig
 
+		// HTTP01Policy, if set, overrides the default rules for fetching
+		// HTTP-01 challenge responses.
+		HTTP01Policy *cmd.HTTP01PolicyConfig
+
 		GoogleSafeBrowsing *cmd.GoogleSafeBrowsingConfig
 
+		// Reputation, if set, configures domain reputation checks in addition
+		// to Google Safe Browsing, and what happens when a domain is listed.
+		Reputation *cmd.ReputationConfig
+
 		CAADistributedResolver *cmd.CAADistributedResolverConfig
 
 		// The number of times to try a DNS query (that has a temporary error)
@@ -140,6 +152,12 @@ func main() {
 		}
 	}
 
+	var caaIdentities []cmd.CAAIdentityConfig
+	if c.VA.IssuerDomain != "" {
+		caaIdentities = append(caaIdentities, cmd.CAAIdentityConfig{Domain: c.VA.IssuerDomain})
+	}
+	caaIdentities = append(caaIdentities, c.VA.CAAIdentities...)
+
 	vai, err := va.NewValidationAuthorityImpl(
 		pc,
 		sbc,
@@ -147,13 +165,23 @@ func main() {
 		remotes,
 		c.VA.MaxRemoteValidationFailures,
 		c.VA.UserAgent,
-		c.VA.IssuerDomain,
+		caaIdentities,
 		scope,
 		clk,
 		logger,
 		c.VA.AccountURIPrefixes)
 	cmd.FailOnError(err, "Unable to create VA server")
 
+	if c.VA.HTTP01Policy != nil {
+		err = vai.SetHTTP01Policy(*c.VA.HTTP01Policy)
+		cmd.FailOnError(err, "Invalid HTTP-01 policy")
+	}
+
+	if c.VA.Reputation != nil {
+		err = vai.SetReputationChecks(*c.VA.Reputation)
+		cmd.FailOnError(err, "Invalid reputation config")
+	}
+
 	serverMetrics := bgrpc.NewServerMetrics(scope)
 	grpcSrv, l, err := bgrpc.NewServer(c.VA.GRPC, tlsConfig, serverMetrics, clk)
 	cmd.FailOnError(err, "Unable to setup VA gRPC server")
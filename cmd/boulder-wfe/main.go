@@ -37,6 +37,15 @@ type config struct {
 
 		AcceptRevocationReason bool
 		AllowAuthzDeactivation bool
+		// AuthzDebug enables the CAA records and reputation verdicts of an
+		// authz's challenges to be shown when it is requested with the
+		// "debug" query parameter. Anyone can request an authz, so leave it
+		// off outside of testing.
+		AuthzDebug bool
+
+		// Unsubscribe, if set, enables the endpoint for the unsubscribe links
+		// in Boulder's emails. Its URL isn't used.
+		Unsubscribe *cmd.UnsubscribeConfig
 
 		TLS cmd.TLSConfig
 
@@ -47,7 +56,7 @@ type config struct {
 
 		// DirectoryCAAIdentity is used for the /directory response's "meta"
 		// element's "caaIdentities" field. It should match the VA's "issuerDomain"
-		// configuration value (this value is the one used to enforce CAA)
+		// configuration value (this is the primary identity used to enforce CAA)
 		DirectoryCAAIdentity string
 		// DirectoryWebsite is used for the /directory response's "meta" element's
 		// "website" field.
@@ -110,8 +119,14 @@ func main() {
 	wfe.AllowOrigins = c.WFE.AllowOrigins
 	wfe.AcceptRevocationReason = c.WFE.AcceptRevocationReason
 	wfe.AllowAuthzDeactivation = c.WFE.AllowAuthzDeactivation
+	wfe.AuthzDebug = c.WFE.AuthzDebug
 	wfe.DirectoryCAAIdentity = c.WFE.DirectoryCAAIdentity
 	wfe.DirectoryWebsite = c.WFE.DirectoryWebsite
+	if c.WFE.Unsubscribe != nil {
+		wfe.UnsubscribeKey, err = c.WFE.Unsubscribe.Key()
+		cmd.FailOnError(err, "Couldn't load unsubscribe key")
+		wfe.Suppressions = sac
+	}
 
 	wfe.IssuerCert, err = cmd.LoadCert(c.Common.IssuerCert)
 	cmd.FailOnError(err, fmt.Sprintf("Couldn't read issuer cert [%s]", c.Common.IssuerCert))
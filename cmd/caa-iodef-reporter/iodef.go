@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/letsencrypt/boulder/core"
+)
+
+// The structs below are the subset of the RFC 5070 Incident Object
+// Description Exchange Format (IODEF) used to report a denied CAA check, in
+// the element order required by the schema.
+
+type iodefDocument struct {
+	XMLName  xml.Name      `xml:"urn:ietf:params:xml:ns:iodef-1.0 IODEF-Document"`
+	Version  string        `xml:"version,attr"`
+	Lang     string        `xml:"lang,attr"`
+	Incident iodefIncident `xml:"Incident"`
+}
+
+type iodefIncident struct {
+	Purpose     string          `xml:"purpose,attr"`
+	IncidentID  iodefIncidentID `xml:"IncidentID"`
+	DetectTime  string          `xml:"DetectTime"`
+	ReportTime  string          `xml:"ReportTime"`
+	Description string          `xml:"Description"`
+	Assessment  iodefAssessment `xml:"Assessment"`
+	Contact     iodefContact    `xml:"Contact"`
+	EventData   iodefEventData  `xml:"EventData"`
+}
+
+type iodefIncidentID struct {
+	Name string `xml:"name,attr"`
+	ID   string `xml:",chardata"`
+}
+
+type iodefAssessment struct {
+	Impact iodefImpact `xml:"Impact"`
+}
+
+type iodefImpact struct {
+	Type        string `xml:"type,attr"`
+	Completion  string `xml:"completion,attr"`
+	Description string `xml:",chardata"`
+}
+
+type iodefContact struct {
+	Role        string `xml:"role,attr"`
+	Type        string `xml:"type,attr"`
+	ContactName string `xml:"ContactName"`
+}
+
+type iodefEventData struct {
+	Flow           iodefFlow             `xml:"Flow"`
+	AdditionalData []iodefAdditionalData `xml:"AdditionalData"`
+}
+
+type iodefFlow struct {
+	System iodefSystem `xml:"System"`
+}
+
+type iodefSystem struct {
+	Category string    `xml:"category,attr"`
+	Node     iodefNode `xml:"Node"`
+}
+
+type iodefNode struct {
+	NodeName string `xml:"NodeName"`
+}
+
+type iodefAdditionalData struct {
+	DType   string `xml:"dtype,attr"`
+	Meaning string `xml:"meaning,attr"`
+	Value   string `xml:",chardata"`
+}
+
+// iodefReportDocument returns the IODEF document reporting that issuerDomain
+// denied a certificate request for report.Domain because of its CAA records.
+// The incident is identified by the report's ID, namespaced by issuerDomain.
+func iodefReportDocument(issuerDomain string, report core.CAAIodefReport, now time.Time) ([]byte, error) {
+	doc := iodefDocument{
+		Version: "1.00",
+		Lang:    "en",
+		Incident: iodefIncident{
+			Purpose: "reporting",
+			IncidentID: iodefIncidentID{
+				Name: issuerDomain,
+				ID:   strconv.FormatInt(report.ID, 10),
+			},
+			DetectTime: report.CreatedAt.UTC().Format(time.RFC3339),
+			ReportTime: now.UTC().Format(time.RFC3339),
+			Description: fmt.Sprintf(
+				"Certificate issuance for %s was denied because the domain's CAA records do not authorize %s",
+				report.Domain, issuerDomain),
+			Assessment: iodefAssessment{
+				Impact: iodefImpact{
+					Type:        "policy",
+					Completion:  "failed",
+					Description: "No certificate was issued",
+				},
+			},
+			Contact: iodefContact{
+				Role:        "creator",
+				Type:        "organization",
+				ContactName: issuerDomain,
+			},
+			EventData: iodefEventData{
+				Flow: iodefFlow{
+					System: iodefSystem{
+						Category: "target",
+						Node:     iodefNode{NodeName: report.Domain},
+					},
+				},
+				AdditionalData: []iodefAdditionalData{
+					{DType: "integer", Meaning: "account-id", Value: strconv.FormatInt(report.AccountID, 10)},
+					{DType: "string", Meaning: "validation-method", Value: report.ValidationMethod},
+					{DType: "string", Meaning: "caa-records", Value: report.Records},
+				},
+			},
+		},
+	}
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
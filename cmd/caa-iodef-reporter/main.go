@@ -0,0 +1,395 @@
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	netmail "net/mail"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/weppos/publicsuffix-go/publicsuffix"
+	"golang.org/x/net/context"
+
+	"github.com/letsencrypt/boulder/bdns"
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/features"
+	bgrpc "github.com/letsencrypt/boulder/grpc"
+	blog "github.com/letsencrypt/boulder/log"
+	bmail "github.com/letsencrypt/boulder/mail"
+	"github.com/letsencrypt/boulder/metrics"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+type config struct {
+	IodefReporter struct {
+		cmd.ServiceConfig
+		cmd.SMTPConfig
+
+		From string
+
+		// Path to a file containing a list of trusted root certificates for use
+		// during the SMTP connection (as opposed to the gRPC connections).
+		SMTPTrustedRootFile string
+
+		// SAService is used to read and update the queue of reports, and to
+		// check the email suppression list.
+		SAService *cmd.GRPCClientConfig
+
+		// IssuerDomain names us in reports. It should match the VA's.
+		IssuerDomain string
+
+		// Frequency is how often pending reports are processed.
+		Frequency cmd.ConfigDuration
+		// BatchSize is the maximum number of reports processed each time.
+		BatchSize int
+
+		// At most MaxReportsPerRegisteredDomain reports are sent for the
+		// subdomains of a registered domain (the public suffix plus one label)
+		// in any RateLimitWindow, and at most MaxReportsPerTarget are sent to
+		// each target. Reports and targets over the limits are recorded, but
+		// not sent.
+		MaxReportsPerRegisteredDomain int
+		MaxReportsPerTarget           int
+		RateLimitWindow               cmd.ConfigDuration
+
+		// HTTPTimeout is the timeout of each POST to an https: target. Targets
+		// which resolve to private addresses are refused.
+		HTTPTimeout cmd.ConfigDuration
+
+		Features map[string]bool
+	}
+
+	Syslog cmd.SyslogConfig
+}
+
+type reporterStats struct {
+	reports    *prometheus.CounterVec
+	deliveries *prometheus.CounterVec
+}
+
+func initStats(scope metrics.Scope) reporterStats {
+	reports := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reports",
+			Help: "Number of CAA iodef reports processed, by resulting status",
+		},
+		[]string{"status"})
+	scope.MustRegister(reports)
+
+	deliveries := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "deliveries",
+			Help: "Number of deliveries of CAA iodef reports to targets, by channel and result",
+		},
+		[]string{"channel", "result"})
+	scope.MustRegister(deliveries)
+
+	return reporterStats{
+		reports:    reports,
+		deliveries: deliveries,
+	}
+}
+
+type reporter struct {
+	log    blog.Logger
+	sa     core.StorageAuthority
+	mailer bmail.Mailer
+	client *http.Client
+	clk    clock.Clock
+	stats  reporterStats
+
+	issuerDomain           string
+	batchSize              int
+	maxPerRegisteredDomain int
+	maxPerTarget           int
+	rateLimitWindow        time.Duration
+
+	// mailerConnected is set once the mailer is connected during a run, so
+	// that runs without mailto: targets don't connect to the SMTP server.
+	mailerConnected bool
+}
+
+// newHTTPClient returns a client for POSTing to https: targets, which refuses
+// to connect to private, loopback and link-local addresses, since targets come
+// from DNS records anyone can publish.
+func newHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: bdns.PublicOnlyControl,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// registeredDomain returns the registered domain of a report's domain, which
+// may be a wildcard. Domains which are themselves public suffixes are their
+// own registered domain.
+func registeredDomain(domain string) string {
+	domain = strings.TrimPrefix(domain, "*.")
+	registered, err := publicsuffix.Domain(domain)
+	if err != nil {
+		return domain
+	}
+	return registered
+}
+
+// processReports processes up to batchSize pending reports, oldest first. It
+// returns an error, leaving the remaining reports pending, if the SA can't
+// read or update the queue. Failed deliveries don't stop processing.
+func (r *reporter) processReports(ctx context.Context) error {
+	reports, err := r.sa.GetPendingCAAIodefReports(ctx, r.batchSize)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r.mailerConnected {
+			_ = r.mailer.Close()
+			r.mailerConnected = false
+		}
+	}()
+	for _, report := range reports {
+		err := r.process(ctx, report)
+		if err != nil {
+			return fmt.Errorf("processing report %d: %s", report.ID, err)
+		}
+	}
+	return nil
+}
+
+// process sends a report to each of its targets, unless its registered domain
+// or the target has reached its rate limit, and records the outcome with the
+// SA.
+func (r *reporter) process(ctx context.Context, report core.CAAIodefReport) error {
+	now := r.clk.Now()
+	result := core.CAAIodefReportResult{
+		ID:               report.ID,
+		RegisteredDomain: registeredDomain(report.Domain),
+	}
+	sent, err := r.sa.CountCAAIodefReportsSent(ctx, result.RegisteredDomain, now.Add(-r.rateLimitWindow))
+	if err != nil {
+		return err
+	}
+	if sent >= r.maxPerRegisteredDomain {
+		r.log.AuditInfof("Not sending CAA iodef report %d for %s: rate limit of %d reports for %s reached",
+			report.ID, report.Domain, r.maxPerRegisteredDomain, result.RegisteredDomain)
+		result.Status = core.IodefReportRateLimited
+		return r.finish(ctx, result)
+	}
+
+	doc, err := iodefReportDocument(r.issuerDomain, report, now)
+	if err != nil {
+		return err
+	}
+
+	// Deliver to every target even if one fails. The report counts as sent if
+	// any target received it, and as rate limited if every target was.
+	result.Status = core.IodefReportRateLimited
+	results := make(map[string]string, len(report.Targets))
+	for _, target := range report.Targets {
+		delivered, err := r.sa.CountCAAIodefDeliveries(ctx, target, now.Add(-r.rateLimitWindow))
+		if err != nil {
+			return err
+		}
+		if delivered >= r.maxPerTarget {
+			results[target] = core.IodefReportRateLimited
+			continue
+		}
+
+		channel, err := r.deliver(target, report, doc)
+		outcome := "success"
+		if err != nil {
+			outcome = "failure"
+			results[target] = err.Error()
+			if result.Status != core.IodefReportSent {
+				result.Status = core.IodefReportFailed
+			}
+		} else {
+			result.Status = core.IodefReportSent
+			results[target] = "sent"
+			result.Delivered = append(result.Delivered, target)
+		}
+		r.stats.deliveries.With(prometheus.Labels{"channel": channel, "result": outcome}).Inc()
+	}
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	result.Results = string(resultsJSON)
+	r.log.AuditInfof("Processed CAA iodef report %d for %s: status=%s results=%s",
+		report.ID, report.Domain, result.Status, resultsJSON)
+	return r.finish(ctx, result)
+}
+
+func (r *reporter) finish(ctx context.Context, result core.CAAIodefReportResult) error {
+	err := r.sa.FinishCAAIodefReport(ctx, result)
+	if err != nil {
+		return err
+	}
+	r.stats.reports.With(prometheus.Labels{"status": result.Status}).Inc()
+	return nil
+}
+
+// deliver sends doc to a mailto: or https: target, and returns the name of
+// the channel used.
+func (r *reporter) deliver(target string, report core.CAAIodefReport, doc []byte) (string, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "unknown", err
+	}
+	switch u.Scheme {
+	case "mailto":
+		return "smtp", r.email(u.Opaque, report, doc)
+	case "https":
+		return "https", r.post(u.String(), doc)
+	default:
+		return "unknown", fmt.Errorf("unsupported iodef scheme %q", u.Scheme)
+	}
+}
+
+func (r *reporter) email(address string, report core.CAAIodefReport, doc []byte) error {
+	if !r.mailerConnected {
+		err := r.mailer.Connect()
+		if err != nil {
+			return err
+		}
+		r.mailerConnected = true
+	}
+	subject := fmt.Sprintf("CAA prevented certificate issuance for %s", report.Domain)
+	body := fmt.Sprintf(
+		"A request to %s for a certificate for %s was denied because the domain's "+
+			"CAA records don't authorize %s to issue for it. No certificate was issued.\n\n"+
+			"You are receiving this because the domain's CAA records list this address "+
+			"as an iodef contact. The incident report below is an RFC 5070 IODEF document.\n\n%s",
+		r.issuerDomain, report.Domain, r.issuerDomain, doc)
+	return r.mailer.SendMail([]string{address}, subject, body)
+}
+
+func (r *reporter) post(target string, doc []byte) error {
+	resp, err := r.client.Post(target, "application/xml", bytes.NewReader(doc))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected HTTP status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func main() {
+	configFile := flag.String("config", "", "File path to the configuration file for this service")
+	reconnBase := flag.Duration("reconnectBase", 1*time.Second, "Base sleep duration between reconnect attempts")
+	reconnMax := flag.Duration("reconnectMax", 5*60*time.Second, "Max sleep duration between reconnect attempts after exponential backoff")
+	flag.Parse()
+	if *configFile == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	var c config
+	err := cmd.ReadConfigFile(*configFile, &c)
+	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	err = features.Set(c.IodefReporter.Features)
+	cmd.FailOnError(err, "Failed to set feature flags")
+
+	scope, logger := cmd.StatsAndLogging(c.Syslog, c.IodefReporter.DebugAddr)
+	defer logger.AuditPanic()
+	logger.Info(cmd.VersionString())
+
+	rc := c.IodefReporter
+	if rc.IssuerDomain == "" {
+		cmd.Fail("issuerDomain must be set")
+	}
+	if rc.Frequency.Duration == 0 || rc.RateLimitWindow.Duration == 0 {
+		cmd.Fail("frequency and rateLimitWindow must be set")
+	}
+	if rc.BatchSize <= 0 || rc.MaxReportsPerRegisteredDomain <= 0 || rc.MaxReportsPerTarget <= 0 {
+		cmd.Fail("batchSize, maxReportsPerRegisteredDomain and maxReportsPerTarget must be set to non-zero")
+	}
+	httpTimeout := rc.HTTPTimeout.Duration
+	if httpTimeout == 0 {
+		httpTimeout = 10 * time.Second
+	}
+
+	tlsConfig, err := rc.TLS.Load()
+	cmd.FailOnError(err, "TLS config")
+
+	clk := cmd.Clock()
+
+	clientMetrics := bgrpc.NewClientMetrics(scope)
+	conn, err := bgrpc.ClientSetup(rc.SAService, tlsConfig, clientMetrics, clk)
+	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA")
+	sac := bgrpc.NewStorageAuthorityClient(sapb.NewStorageAuthorityClient(conn))
+
+	var smtpRoots *x509.CertPool
+	if rc.SMTPTrustedRootFile != "" {
+		pem, err := ioutil.ReadFile(rc.SMTPTrustedRootFile)
+		cmd.FailOnError(err, "Loading trusted roots file")
+		smtpRoots = x509.NewCertPool()
+		if !smtpRoots.AppendCertsFromPEM(pem) {
+			cmd.FailOnError(nil, "Failed to parse root certs PEM")
+		}
+	}
+
+	fromAddress, err := netmail.ParseAddress(rc.From)
+	cmd.FailOnError(err, fmt.Sprintf("Could not parse from address: %s", rc.From))
+
+	smtpPassword, err := rc.PasswordConfig.Pass()
+	cmd.FailOnError(err, "Failed to load SMTP password")
+	mailClient := bmail.New(
+		rc.Server,
+		rc.Port,
+		rc.Username,
+		smtpPassword,
+		smtpRoots,
+		*fromAddress,
+		logger,
+		scope,
+		*reconnBase,
+		*reconnMax)
+	mailClient.SetSuppressionList(sac)
+
+	r := &reporter{
+		log:                    logger,
+		sa:                     sac,
+		mailer:                 mailClient,
+		client:                 newHTTPClient(httpTimeout),
+		clk:                    clk,
+		stats:                  initStats(scope),
+		issuerDomain:           strings.ToLower(rc.IssuerDomain),
+		batchSize:              rc.BatchSize,
+		maxPerRegisteredDomain: rc.MaxReportsPerRegisteredDomain,
+		maxPerTarget:           rc.MaxReportsPerTarget,
+		rateLimitWindow:        rc.RateLimitWindow.Duration,
+	}
+
+	t := time.NewTicker(rc.Frequency.Duration)
+	for range t.C {
+		err = r.processReports(context.Background())
+		cmd.FailOnError(err, "caa-iodef-reporter has failed")
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/net/context"
+
+	"github.com/letsencrypt/boulder/core"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/mocks"
+	"github.com/letsencrypt/boulder/sa"
+	"github.com/letsencrypt/boulder/test"
+	"github.com/letsencrypt/boulder/test/vars"
+)
+
+var testReport = core.CAAIodefReport{
+	ID:               7,
+	Domain:           "example.com",
+	AccountID:        123,
+	ValidationMethod: "http-01",
+	Records:          `["0 issue \"ca.com\""]`,
+	Targets:          []string{"mailto:security@example.com"},
+	CreatedAt:        time.Date(2018, 7, 13, 1, 2, 3, 0, time.UTC),
+}
+
+func TestIodefReportDocument(t *testing.T) {
+	now := time.Date(2018, 7, 13, 1, 3, 0, 0, time.UTC)
+	body, err := iodefReportDocument("letsencrypt.org", testReport, now)
+	test.AssertNotError(t, err, "iodefReportDocument failed")
+	test.Assert(t, strings.HasPrefix(string(body), xml.Header), "Document is missing the XML header")
+
+	var doc iodefDocument
+	err = xml.Unmarshal(body, &doc)
+	test.AssertNotError(t, err, "Failed to unmarshal document")
+	test.AssertEquals(t, doc.XMLName.Space, "urn:ietf:params:xml:ns:iodef-1.0")
+	test.AssertEquals(t, doc.Version, "1.00")
+	incident := doc.Incident
+	test.AssertEquals(t, incident.IncidentID, iodefIncidentID{Name: "letsencrypt.org", ID: "7"})
+	test.AssertEquals(t, incident.DetectTime, "2018-07-13T01:02:03Z")
+	test.AssertEquals(t, incident.ReportTime, "2018-07-13T01:03:00Z")
+	test.AssertEquals(t, incident.EventData.Flow.System.Node.NodeName, "example.com")
+	test.AssertDeepEquals(t, incident.EventData.AdditionalData, []iodefAdditionalData{
+		{DType: "integer", Meaning: "account-id", Value: "123"},
+		{DType: "string", Meaning: "validation-method", Value: "http-01"},
+		{DType: "string", Meaning: "caa-records", Value: testReport.Records},
+	})
+}
+
+type iodefRequest struct {
+	contentType string
+	body        string
+}
+
+// iodefServer returns an HTTPS server recording the reports it receives. It
+// fails requests to /fail.
+func iodefServer(t *testing.T) (*httptest.Server, *[]iodefRequest) {
+	var requests []iodefRequest
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		test.AssertNotError(t, err, "Failed to read report body")
+		requests = append(requests, iodefRequest{r.Header.Get("Content-Type"), string(body)})
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	return srv, &requests
+}
+
+func newTestReporter(sa core.StorageAuthority, mc *mocks.Mailer, client *http.Client, clk clock.Clock) *reporter {
+	return &reporter{
+		log:                    blog.NewMock(),
+		sa:                     sa,
+		mailer:                 mc,
+		client:                 client,
+		clk:                    clk,
+		stats:                  initStats(metrics.NewNoopScope()),
+		issuerDomain:           "letsencrypt.org",
+		batchSize:              10,
+		maxPerRegisteredDomain: 2,
+		maxPerTarget:           2,
+		rateLimitWindow:        24 * time.Hour,
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	srv, requests := iodefServer(t)
+	defer srv.Close()
+	mc := &mocks.Mailer{}
+	r := newTestReporter(nil, mc, srv.Client(), clock.NewFake())
+	doc := []byte("<IODEF-Document/>")
+
+	channel, err := r.deliver("mailto:security@example.com", testReport, doc)
+	test.AssertNotError(t, err, "Failed to deliver by email")
+	test.AssertEquals(t, channel, "smtp")
+	test.AssertEquals(t, len(mc.Messages), 1)
+	test.AssertEquals(t, mc.Messages[0].To, "security@example.com")
+	test.AssertEquals(t, mc.Messages[0].Subject, "CAA prevented certificate issuance for example.com")
+	test.Assert(t, strings.HasSuffix(mc.Messages[0].Body, "\n\n<IODEF-Document/>"), "Email is missing the report")
+
+	channel, err = r.deliver(srv.URL+"/iodef", testReport, doc)
+	test.AssertNotError(t, err, "Failed to deliver by HTTPS")
+	test.AssertEquals(t, channel, "https")
+	test.AssertDeepEquals(t, *requests, []iodefRequest{{"application/xml", "<IODEF-Document/>"}})
+
+	_, err = r.deliver("http://example.com/iodef", testReport, doc)
+	test.AssertError(t, err, "Delivered to an http: target")
+
+	_, err = r.deliver(srv.URL+"/fail", testReport, doc)
+	test.AssertError(t, err, "Delivery succeeded despite a 500 response")
+}
+
+func TestNewHTTPClientRefusesPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Request reached a loopback server")
+	}))
+	defer srv.Close()
+
+	_, err := newHTTPClient(time.Second).Post(srv.URL, "application/xml", strings.NewReader("<IODEF-Document/>"))
+	test.AssertError(t, err, "POST to a loopback address succeeded")
+	test.Assert(t, strings.Contains(err.Error(), "non-public address"), "Wrong error for a loopback address")
+}
+
+func TestRegisteredDomain(t *testing.T) {
+	testCases := map[string]string{
+		"example.com":        "example.com",
+		"www.example.com":    "example.com",
+		"*.api.example.com":  "example.com",
+		"a.b.example.co.uk":  "example.co.uk",
+		"com":                "com",
+		"*.example.co.uk":    "example.co.uk",
+		"user.github.io":     "user.github.io",
+		"www.user.github.io": "user.github.io",
+	}
+	for domain, expected := range testCases {
+		test.AssertEquals(t, registeredDomain(domain), expected)
+	}
+}
+
+// mockSA serves pending reports and rate limit counts from memory, and
+// records the reports finished.
+type mockSA struct {
+	mocks.StorageAuthority
+	pending    []core.CAAIodefReport
+	sent       map[string]int
+	deliveries map[string]int
+	finished   []core.CAAIodefReportResult
+}
+
+func (m *mockSA) GetPendingCAAIodefReports(_ context.Context, limit int) ([]core.CAAIodefReport, error) {
+	if len(m.pending) > limit {
+		return m.pending[:limit], nil
+	}
+	return m.pending, nil
+}
+
+func (m *mockSA) CountCAAIodefReportsSent(_ context.Context, registeredDomain string, _ time.Time) (int, error) {
+	return m.sent[registeredDomain], nil
+}
+
+func (m *mockSA) CountCAAIodefDeliveries(_ context.Context, target string, _ time.Time) (int, error) {
+	return m.deliveries[target], nil
+}
+
+func (m *mockSA) FinishCAAIodefReport(_ context.Context, result core.CAAIodefReportResult) error {
+	m.finished = append(m.finished, result)
+	return nil
+}
+
+func TestProcess(t *testing.T) {
+	srv, requests := iodefServer(t)
+	defer srv.Close()
+
+	msa := &mockSA{
+		pending: []core.CAAIodefReport{
+			{ID: 1, Domain: "www.example.com", Targets: []string{"mailto:security@example.com", srv.URL + "/a", srv.URL + "/victim"}},
+			{ID: 2, Domain: "limited.com", Targets: []string{srv.URL + "/b"}},
+			{ID: 3, Domain: "example.net", Targets: []string{srv.URL + "/fail"}},
+		},
+		// limited.com and /victim have reached their limits of two
+		sent:       map[string]int{"limited.com": 2},
+		deliveries: map[string]int{srv.URL + "/victim": 2},
+	}
+	mc := &mocks.Mailer{}
+	r := newTestReporter(msa, mc, srv.Client(), clock.NewFake())
+	err := r.processReports(context.Background())
+	test.AssertNotError(t, err, "processReports failed")
+	test.AssertEquals(t, len(mc.Messages), 1)
+	test.AssertEquals(t, len(*requests), 2)
+
+	test.AssertDeepEquals(t, msa.finished, []core.CAAIodefReportResult{
+		{
+			ID:               1,
+			Status:           core.IodefReportSent,
+			RegisteredDomain: "example.com",
+			Results:          `{"` + srv.URL + `/a":"sent","` + srv.URL + `/victim":"ratelimited","mailto:security@example.com":"sent"}`,
+			Delivered:        []string{"mailto:security@example.com", srv.URL + "/a"},
+		},
+		{
+			ID:               2,
+			Status:           core.IodefReportRateLimited,
+			RegisteredDomain: "limited.com",
+		},
+		{
+			ID:               3,
+			Status:           core.IodefReportFailed,
+			RegisteredDomain: "example.net",
+			Results:          `{"` + srv.URL + `/fail":"unexpected HTTP status code 500"}`,
+		},
+	})
+	test.AssertEquals(t, test.CountCounter(r.stats.reports.With(prometheus.Labels{"status": core.IodefReportSent})), 1)
+}
+
+func TestProcessReports(t *testing.T) {
+	dbMap, err := sa.NewDbMap(vars.DBConnSAFullPerms, 0)
+	if err != nil {
+		t.Fatalf("Couldn't connect the database: %s", err)
+	}
+	fc := clock.NewFake()
+	fc.Set(time.Date(2018, 7, 13, 0, 0, 0, 0, time.UTC))
+	ssa, err := sa.NewSQLStorageAuthority(dbMap, fc, blog.NewMock(), metrics.NewNoopScope(), 1)
+	if err != nil {
+		t.Fatalf("unable to create SQLStorageAuthority: %s", err)
+	}
+	cleanUp := test.ResetSATestDatabase(t)
+	defer cleanUp()
+
+	srv, requests := iodefServer(t)
+	defer srv.Close()
+
+	queue := func(domain string, targets ...string) {
+		t.Helper()
+		err := ssa.AddCAAIodefReport(context.Background(), core.CAAIodefReport{
+			Domain:  domain,
+			Records: "[]",
+			Targets: targets,
+		})
+		test.AssertNotError(t, err, "AddCAAIodefReport failed")
+	}
+	// Three reports for subdomains of example.com, which is over the limit of
+	// two per registered domain
+	queue("example.com", "mailto:security@example.com", srv.URL+"/a")
+	queue("www.example.com", srv.URL+"/b")
+	queue("*.api.example.com", srv.URL+"/c")
+	// A report for which every delivery fails
+	queue("example.net", srv.URL+"/fail")
+
+	mc := &mocks.Mailer{}
+	r := newTestReporter(ssa, mc, srv.Client(), fc)
+	err = r.processReports(context.Background())
+	test.AssertNotError(t, err, "processReports failed")
+	test.AssertEquals(t, len(mc.Messages), 1)
+	test.AssertEquals(t, len(*requests), 3)
+
+	var statuses []string
+	_, err = dbMap.Select(&statuses, "SELECT status FROM caaIodefReports ORDER BY id")
+	test.AssertNotError(t, err, "Failed to select statuses")
+	test.AssertDeepEquals(t, statuses, []string{core.IodefReportSent, core.IodefReportSent, core.IodefReportRateLimited, core.IodefReportFailed})
+	test.AssertEquals(t, test.CountCounter(r.stats.reports.With(prometheus.Labels{"status": core.IodefReportSent})), 2)
+
+	results, err := dbMap.SelectStr("SELECT results FROM caaIodefReports ORDER BY id LIMIT 1")
+	test.AssertNotError(t, err, "Failed to select results")
+	test.AssertEquals(t, results, `{"`+srv.URL+`/a":"sent","mailto:security@example.com":"sent"}`)
+
+	registered, err := dbMap.SelectStr("SELECT registeredDomain FROM caaIodefReports WHERE domain = ?", "*.api.example.com")
+	test.AssertNotError(t, err, "Failed to select registeredDomain")
+	test.AssertEquals(t, registered, "example.com")
+
+	// Processed reports aren't processed again
+	err = r.processReports(context.Background())
+	test.AssertNotError(t, err, "processReports failed")
+	test.AssertEquals(t, len(*requests), 3)
+
+	// Once the rate limit window has passed, reports are sent again
+	fc.Add(25 * time.Hour)
+	queue("example.com", srv.URL+"/a")
+	err = r.processReports(context.Background())
+	test.AssertNotError(t, err, "processReports failed")
+	test.AssertEquals(t, len(*requests), 4)
+}
+
+func TestProcessReportsTargetLimit(t *testing.T) {
+	dbMap, err := sa.NewDbMap(vars.DBConnSAFullPerms, 0)
+	if err != nil {
+		t.Fatalf("Couldn't connect the database: %s", err)
+	}
+	fc := clock.NewFake()
+	fc.Set(time.Date(2018, 7, 13, 0, 0, 0, 0, time.UTC))
+	ssa, err := sa.NewSQLStorageAuthority(dbMap, fc, blog.NewMock(), metrics.NewNoopScope(), 1)
+	if err != nil {
+		t.Fatalf("unable to create SQLStorageAuthority: %s", err)
+	}
+	cleanUp := test.ResetSATestDatabase(t)
+	defer cleanUp()
+
+	srv, requests := iodefServer(t)
+	defer srv.Close()
+
+	// Reports for unrelated domains which all name the same target, which is
+	// over the limit of two per target
+	for _, domain := range []string{"example.com", "example.net", "example.org"} {
+		err := ssa.AddCAAIodefReport(context.Background(), core.CAAIodefReport{
+			Domain:  domain,
+			Records: "[]",
+			Targets: []string{srv.URL + "/victim"},
+		})
+		test.AssertNotError(t, err, "AddCAAIodefReport failed")
+	}
+
+	r := newTestReporter(ssa, &mocks.Mailer{}, srv.Client(), fc)
+	err = r.processReports(context.Background())
+	test.AssertNotError(t, err, "processReports failed")
+	test.AssertEquals(t, len(*requests), 2)
+
+	var statuses []string
+	_, err = dbMap.Select(&statuses, "SELECT status FROM caaIodefReports ORDER BY id")
+	test.AssertNotError(t, err, "Failed to select statuses")
+	test.AssertDeepEquals(t, statuses, []string{core.IodefReportSent, core.IodefReportSent, core.IodefReportRateLimited})
+
+	results, err := dbMap.SelectStr("SELECT results FROM caaIodefReports WHERE domain = ?", "example.org")
+	test.AssertNotError(t, err, "Failed to select results")
+	test.AssertEquals(t, results, `{"`+srv.URL+`/victim":"ratelimited"}`)
+}
@@ -100,6 +100,9 @@ type certChecker struct {
 	issuedReport report
 	checkPeriod  time.Duration
 	stats        metrics.Scope
+	// acceptableValidityPeriods are the validity periods of the CA's issuance
+	// profiles, which are accepted as well as expectedValidityPeriod
+	acceptableValidityPeriods []time.Duration
 }
 
 func newChecker(saDbMap certDB, clk clock.Clock, pa core.PolicyAuthority, period time.Duration) certChecker {
@@ -249,10 +252,12 @@ func (c *certChecker) checkCert(cert core.Certificate) (problems []string) {
 		}
 		// Check the cert has the correct validity period
 		validityPeriod := parsedCert.NotAfter.Sub(parsedCert.NotBefore)
-		if validityPeriod > expectedValidityPeriod {
-			problems = append(problems, fmt.Sprintf("Certificate has a validity period longer than %s", expectedValidityPeriod))
-		} else if validityPeriod < expectedValidityPeriod {
-			problems = append(problems, fmt.Sprintf("Certificate has a validity period shorter than %s", expectedValidityPeriod))
+		if !c.acceptableValidityPeriod(validityPeriod) {
+			if validityPeriod > expectedValidityPeriod {
+				problems = append(problems, fmt.Sprintf("Certificate has a validity period longer than %s", expectedValidityPeriod))
+			} else {
+				problems = append(problems, fmt.Sprintf("Certificate has a validity period shorter than %s", expectedValidityPeriod))
+			}
 		}
 		// Check the stored issuance time isn't too far back/forward dated
 		if parsedCert.NotBefore.Before(cert.Issued.Add(-6*time.Hour)) || parsedCert.NotBefore.After(cert.Issued.Add(6*time.Hour)) {
@@ -274,7 +279,7 @@ func (c *certChecker) checkCert(cert core.Certificate) (problems []string) {
 			if features.Enabled(features.WildcardDomains) {
 				checkFunc = c.pa.WillingToIssueWildcard
 			}
-			if err = checkFunc(id); err != nil {
+			if err = checkFunc(id, cert.RegistrationID); err != nil {
 				problems = append(problems, fmt.Sprintf("Policy Authority isn't willing to issue for '%s': %s", name, err))
 			} else {
 				// For defense-in-depth, even if the PA was willing to issue for a name
@@ -307,6 +312,20 @@ func (c *certChecker) checkCert(cert core.Certificate) (problems []string) {
 	return problems
 }
 
+// acceptableValidityPeriod returns whether period is expectedValidityPeriod or
+// the validity period of one of the CA's other issuance profiles.
+func (c *certChecker) acceptableValidityPeriod(period time.Duration) bool {
+	if period == expectedValidityPeriod {
+		return true
+	}
+	for _, p := range c.acceptableValidityPeriods {
+		if period == p {
+			return true
+		}
+	}
+	return false
+}
+
 type config struct {
 	CertChecker struct {
 		cmd.DBConfig
@@ -317,6 +336,9 @@ type config struct {
 		UnexpiredOnly       bool
 		BadResultsOnly      bool
 		CheckPeriod         cmd.ConfigDuration
+		// AcceptableValidityPeriods are the validity periods of the CA's
+		// issuance profiles, other than the default, which aren't problems
+		AcceptableValidityPeriods []cmd.ConfigDuration
 
 		Features map[string]bool
 	}
@@ -380,6 +402,8 @@ func main() {
 	cmd.FailOnError(err, "Failed to create PA")
 	err = pa.SetHostnamePolicyFile(config.CertChecker.HostnamePolicyFile)
 	cmd.FailOnError(err, "Failed to load HostnamePolicyFile")
+	err = pa.SetIDNPolicies(config.PA.IDN)
+	cmd.FailOnError(err, "Failed to set IDN policies")
 
 	checker := newChecker(
 		saDbMap,
@@ -387,6 +411,9 @@ func main() {
 		pa,
 		config.CertChecker.CheckPeriod.Duration,
 	)
+	for _, period := range config.CertChecker.AcceptableValidityPeriods {
+		checker.acceptableValidityPeriods = append(checker.acceptableValidityPeriods, period.Duration)
+	}
 	fmt.Fprintf(os.Stderr, "# Getting certificates issued in the last %s\n", config.CertChecker.CheckPeriod)
 
 	// Since we grab certificates in batches we don't want this to block, when it
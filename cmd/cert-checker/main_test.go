@@ -177,6 +177,9 @@ func TestCheckCert(t *testing.T) {
 			// `dev-myqnapcloud.com` is included because it is an exact private
 			// entry on the public suffix list
 			"dev-myqnapcloud.com",
+			// `xn--ls8h.com` should be flagged because its label isn't valid
+			// IDNA2008
+			"xn--ls8h.com",
 		},
 		SerialNumber:          serial,
 		BasicConstraintsValid: false,
@@ -203,16 +206,17 @@ func TestCheckCert(t *testing.T) {
 	problems := checker.checkCert(cert)
 
 	problemsMap := map[string]int{
-		"Stored digest doesn't match certificate digest":                                                 1,
-		"Stored serial doesn't match certificate serial":                                                 1,
-		"Stored expiration doesn't match certificate NotAfter":                                           1,
-		"Certificate doesn't have basic constraints set":                                                 1,
-		"Certificate has a validity period longer than 2160h0m0s":                                        1,
-		"Stored issuance date is outside of 6 hour window of certificate NotBefore":                      1,
-		"Certificate has incorrect key usage extensions":                                                 1,
-		"Certificate has common name >64 characters long (65)":                                           1,
-		"Policy Authority isn't willing to issue for '*.foodnotbombs.mil': Wildcard names not supported": 1,
-		"Certificate contains an unexpected extension: 1.3.3.7":                                          1,
+		"Stored digest doesn't match certificate digest":                                                                                    1,
+		"Stored serial doesn't match certificate serial":                                                                                    1,
+		"Stored expiration doesn't match certificate NotAfter":                                                                              1,
+		"Certificate doesn't have basic constraints set":                                                                                    1,
+		"Certificate has a validity period longer than 2160h0m0s":                                                                           1,
+		"Stored issuance date is outside of 6 hour window of certificate NotBefore":                                                         1,
+		"Certificate has incorrect key usage extensions":                                                                                    1,
+		"Certificate has common name >64 characters long (65)":                                                                              1,
+		"Policy Authority isn't willing to issue for '*.foodnotbombs.mil': Wildcard names not supported":                                    1,
+		"Certificate contains an unexpected extension: 1.3.3.7":                                                                             1,
+		"Policy Authority isn't willing to issue for 'xn--ls8h.com': DNS label \"xn--ls8h\" contains U+1F4A9, which IDNA2008 doesn't allow": 1,
 	}
 	for _, p := range problems {
 		_, ok := problemsMap[p]
@@ -418,3 +422,12 @@ func TestIsForbiddenDomain(t *testing.T) {
 		test.AssertEquals(t, result, tc.Expected)
 	}
 }
+
+func TestAcceptableValidityPeriods(t *testing.T) {
+	checker := certChecker{
+		acceptableValidityPeriods: []time.Duration{7 * 24 * time.Hour},
+	}
+	test.Assert(t, checker.acceptableValidityPeriod(expectedValidityPeriod), "Default validity period not accepted")
+	test.Assert(t, checker.acceptableValidityPeriod(7*24*time.Hour), "Profile validity period not accepted")
+	test.Assert(t, !checker.acceptableValidityPeriod(30*24*time.Hour), "Unconfigured validity period accepted")
+}
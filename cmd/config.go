@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -71,6 +72,19 @@ type SMTPConfig struct {
 	Username string
 }
 
+// RedisConfig describes how to connect to a Redis server used to store OCSP
+// responses.
+type RedisConfig struct {
+	PasswordConfig
+	// Address is the host:port of the Redis server.
+	Address string
+	// Timeout bounds connecting to Redis and each command sent to it. It is
+	// required.
+	Timeout ConfigDuration
+	// PoolSize is the maximum number of idle connections kept open.
+	PoolSize int
+}
+
 // PAConfig specifies how a policy authority should connect to its
 // database, what policies it should enforce, and what challenges
 // it should offer.
@@ -79,6 +93,37 @@ type PAConfig struct {
 	EnforcePolicyWhitelist  bool
 	Challenges              map[string]bool
 	ChallengesWhitelistFile string
+	// ContactSchemes are the URL schemes accepted for registration contacts.
+	// If empty, only "mailto" is accepted.
+	ContactSchemes []string
+	// IDN configures the optional script checks of internationalized domain
+	// names. Every IDN is checked for IDNA2008 validity regardless.
+	IDN IDNConfig
+}
+
+// IDNConfig specifies the script policies the PA applies to internationalized
+// domain names.
+type IDNConfig struct {
+	// Default is the policy for names under TLDs not listed in TLDs
+	Default IDNPolicyConfig
+	// TLDs maps ICANN TLDs, e.g. "com" or "xn--p1ai", to the policy for names
+	// under them
+	TLDs map[string]IDNPolicyConfig
+}
+
+// IDNPolicyConfig is the script policy for the labels of internationalized
+// domain names under a TLD.
+type IDNPolicyConfig struct {
+	// AllowedScripts, if not empty, lists the Unicode scripts (e.g. "Latin" or
+	// "Cyrillic") labels may be written in. Characters common to all scripts,
+	// like digits and the hyphen, are always allowed.
+	AllowedScripts []string
+	// RejectMixedScripts rejects labels written in more than one script,
+	// other than the combinations used to write Chinese, Japanese and Korean.
+	RejectMixedScripts bool
+	// RejectConfusables rejects labels written only in characters of another
+	// script that are confusable with Latin letters, e.g. a Cyrillic "аррӏе".
+	RejectConfusables bool
 }
 
 // HostnamePolicyConfig specifies a file from which to load a policy regarding
@@ -183,6 +228,19 @@ type OCSPUpdaterConfig struct {
 	AkamaiPurgeRetries      int
 	AkamaiPurgeRetryBackoff ConfigDuration
 
+	// WebhookPurger and SurrogateKeyPurger configure CDN purge backends used
+	// in addition to, or instead of, Akamai.
+	WebhookPurger      *WebhookPurgerConfig
+	SurrogateKeyPurger *SurrogateKeyPurgerConfig
+	// Purges are sent to each backend in batches of up to PurgeBatchSize
+	// certificates, at least every PurgeInterval. A failed batch is retried
+	// with exponential backoff starting at PurgeRetryBackoff, up to
+	// PurgeMaxAttempts attempts in total.
+	PurgeBatchSize    int
+	PurgeInterval     ConfigDuration
+	PurgeMaxAttempts  int
+	PurgeRetryBackoff ConfigDuration
+
 	SignFailureBackoffFactor float64
 	SignFailureBackoffMax    ConfigDuration
 
@@ -190,9 +248,60 @@ type OCSPUpdaterConfig struct {
 	SAService            *GRPCClientConfig
 	OCSPGeneratorService *GRPCClientConfig
 
+	// Redis, if set, is where generated OCSP responses are stored in addition
+	// to the certificateStatus table, with a TTL ending at their nextUpdate.
+	Redis *RedisConfig
+	// When SkipDBResponseStorage is true and Redis is set, generated 'good' OCSP
+	// responses are only written to Redis and the certificateStatus table only
+	// has its ocspLastUpdated column updated. Revoked responses are always
+	// written to the table too, as the responder falls back to it.
+	SkipDBResponseStorage bool
+
 	Features map[string]bool
 }
 
+// WebhookPurgerConfig configures a CDN purge backend that POSTs the serials
+// and OCSP URLs to purge, as JSON, to URL.
+type WebhookPurgerConfig struct {
+	URL string
+	// Headers are added to each request, e.g. for authentication.
+	Headers map[string]string
+	Timeout ConfigDuration
+}
+
+// SurrogateKeyPurgerConfig configures a Fastly-style CDN purge backend, which
+// purges responses by surrogate key through the API at Endpoint. The API key
+// is read from the embedded PasswordConfig.
+type SurrogateKeyPurgerConfig struct {
+	PasswordConfig
+	Endpoint  string
+	ServiceID string
+	Timeout   ConfigDuration
+}
+
+// UnsubscribeConfig configures the signed unsubscribe links in the emails
+// sent by Boulder's mailers. The mailers, which make the links, and the WFE,
+// which verifies them, must have the same key.
+type UnsubscribeConfig struct {
+	// URL of the WFE's unsubscribe endpoint. Only used by mailers.
+	URL string
+	// KeyFile contains the secret key the links are signed with.
+	KeyFile string
+}
+
+// Key reads the signing key from KeyFile.
+func (uc UnsubscribeConfig) Key() ([]byte, error) {
+	contents, err := ioutil.ReadFile(uc.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	key := bytes.TrimRight(contents, "\n")
+	if len(key) == 0 {
+		return nil, fmt.Errorf("unsubscribe key file %q is empty", uc.KeyFile)
+	}
+	return key, nil
+}
+
 // GoogleSafeBrowsingConfig is the JSON config struct for the VA's use of the
 // Google Safe Browsing API.
 type GoogleSafeBrowsingConfig struct {
@@ -201,6 +310,39 @@ type GoogleSafeBrowsingConfig struct {
 	ServerURL string
 }
 
+// ReputationConfig is the JSON config struct for the domain reputation checks
+// the VA makes, in addition to Google Safe Browsing if that is configured.
+type ReputationConfig struct {
+	// Action is what happens when a provider without its own Action, including
+	// Google Safe Browsing, lists a domain: "block" fails the validation and
+	// "log" only records and logs the verdict. It defaults to "block".
+	Action    string
+	Providers []ReputationProviderConfig
+}
+
+// ReputationProviderConfig configures one domain reputation provider.
+type ReputationProviderConfig struct {
+	// Name identifies the provider in verdicts, logs and stats
+	Name string
+	// Type is "blocklist", "http" or "rbl"
+	Type string
+	// Action overrides ReputationConfig.Action for this provider
+	Action string
+	// File is the blocklist file of a "blocklist" provider, which is reloaded
+	// whenever it changes. It lists one domain per line, each of which also
+	// covers its subdomains. Lines starting with "#" are ignored.
+	File string
+	// URL is the lookup service of an "http" provider. The domain is passed
+	// in its "domain" query parameter and the response must be a JSON object
+	// like {"listed": true, "reason": "phishing"}.
+	URL string
+	// Zone is the DNS zone of an "rbl" provider. A domain is listed if there
+	// are TXT records for it under the zone, which give the reason.
+	Zone string
+	// Timeout limits each lookup with the provider
+	Timeout ConfigDuration
+}
+
 // SyslogConfig defines the config for syslogging.
 type SyslogConfig struct {
 	StdoutLevel int
@@ -298,6 +440,48 @@ type PortConfig struct {
 	TLSPort   int
 }
 
+// CAAIdentityConfig is a domain which, found in a CAA issue or issuewild
+// record, authorizes the VA to issue.
+//
+// Identities can't be scoped to particular issuers: CAA is checked before the
+// CA signs, and the CA always signs with its first configured issuer.
+type CAAIdentityConfig struct {
+	Domain string
+	// AccountURIPrefixes, if set, scopes the identity to accounts named by
+	// these prefixes: a record naming this identity only authorizes issuance
+	// if it has an accounturi parameter naming the requesting account under
+	// one of the VA's AccountURIPrefixes that begins with one of these.
+	// Records without accounturi don't authorize issuance for a scoped
+	// identity, and neither does any record unless the CAAAccountURI feature
+	// is enabled.
+	AccountURIPrefixes []string
+}
+
+// HTTP01PolicyConfig configures how the VA fetches HTTP-01 challenge
+// responses. Unset fields take their defaults.
+type HTTP01PolicyConfig struct {
+	// MaxRedirects is the number of redirects followed. Defaults to 10.
+	MaxRedirects *int
+	// AllowedPorts are the ports redirect targets may use, which must be the
+	// VA's HTTP or HTTPS port. Defaults to both.
+	AllowedPorts []int
+	// HTTPSUpgrade controls redirects to https: URLs. "allow" (the default)
+	// follows them, "same-host" only follows them if they are for the
+	// hostname being validated, and "deny" follows none.
+	HTTPSUpgrade string
+	// AddressOrder is the address family dialed first when a host has both
+	// IPv6 and IPv4 addresses, falling back to the other: "ipv6-first" (the
+	// default) or "ipv4-first". The TLS-SNI-01 and TLS-ALPN-01 challenges
+	// dial in the same order.
+	AddressOrder string
+	// MaxResponseSize is the size in bytes at which a response body is
+	// rejected. Defaults to 128.
+	MaxResponseSize int64
+	// MaxTotalTime, if set, limits the time taken by a fetch, including all
+	// of its redirects.
+	MaxTotalTime ConfigDuration
+}
+
 // CAADistributedResolverConfig specifies the HTTP client setup and interfaces
 // needed to resolve CAA addresses over multiple paths
 type CAADistributedResolverConfig struct {
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bytes"
 	"crypto/x509"
 	"errors"
 	"flag"
@@ -28,6 +27,7 @@ import (
 	blog "github.com/letsencrypt/boulder/log"
 	bmail "github.com/letsencrypt/boulder/mail"
 	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/policy"
 	"github.com/letsencrypt/boulder/sa"
 	sapb "github.com/letsencrypt/boulder/sa/proto"
 	"github.com/prometheus/client_golang/prometheus"
@@ -42,27 +42,39 @@ type regStore interface {
 	GetRegistration(context.Context, int64) (core.Registration, error)
 }
 
+// contactPolicy decides which registration contact schemes may be notified.
+type contactPolicy interface {
+	ContactSchemeAllowed(scheme string) bool
+}
+
 type mailer struct {
-	log             blog.Logger
-	dbMap           *gorp.DbMap
-	rs              regStore
-	mailer          bmail.Mailer
-	emailTemplate   *template.Template
-	subjectTemplate *template.Template
-	nagTimes        []time.Duration
-	limit           int
-	clk             clock.Clock
-	stats           mailerStats
+	log       blog.Logger
+	dbMap     *gorp.DbMap
+	rs        regStore
+	pa        contactPolicy
+	notifiers []notifier
+	nagTimes  []time.Duration
+	limit     int
+	clk       clock.Clock
+	stats     mailerStats
 }
 
 type mailerStats struct {
 	nagsAtCapacity    *prometheus.GaugeVec
 	errorCount        *prometheus.CounterVec
 	renewalCount      *prometheus.CounterVec
+	deliveries        *prometheus.CounterVec
 	sendLatency       prometheus.Histogram
 	processingLatency prometheus.Histogram
 }
 
+// sendNags notifies the contacts of a registration about its expiring certs
+// through each notifier with contacts of its scheme. Contacts whose scheme
+// isn't allowed by the PA, or has no notifier, are skipped. It returns an
+// error only if no channel delivered successfully, in which case the nags
+// will be retried on the next run; failures of individual channels are
+// otherwise logged and counted, since retrying would repeat the successful
+// deliveries.
 func (m *mailer) sendNags(contacts []string, certs []*x509.Certificate) error {
 	if len(contacts) == 0 {
 		return nil
@@ -70,18 +82,19 @@ func (m *mailer) sendNags(contacts []string, certs []*x509.Certificate) error {
 	if len(certs) == 0 {
 		return errors.New("no certs given to send nags for")
 	}
-	emails := []string{}
+	byScheme := make(map[string][]*url.URL)
 	for _, contact := range contacts {
 		parsed, err := url.Parse(contact)
 		if err != nil {
-			m.log.AuditErrf("parsing contact email %s: %s", contact, err)
+			m.log.AuditErrf("parsing contact %s: %s", contact, err)
 			continue
 		}
-		if parsed.Scheme == "mailto" {
-			emails = append(emails, parsed.Opaque)
+		if !m.pa.ContactSchemeAllowed(parsed.Scheme) {
+			continue
 		}
+		byScheme[parsed.Scheme] = append(byScheme[parsed.Scheme], parsed)
 	}
-	if len(emails) == 0 {
+	if len(byScheme) == 0 {
 		return nil
 	}
 
@@ -102,46 +115,47 @@ func (m *mailer) sendNags(contacts []string, certs []*x509.Certificate) error {
 	}
 	domains = core.UniqueLowerNames(domains)
 	sort.Strings(domains)
-	m.log.Debugf("Sending mail for %s (%s)", strings.Join(domains, ", "), strings.Join(serials, ", "))
+	m.log.Debugf("Sending nags for %s (%s)", strings.Join(domains, ", "), strings.Join(serials, ", "))
 
-	// Construct the information about the expiring certificates for use in the
-	// subject template
-	expiringSubject := fmt.Sprintf("%q", domains[0])
-	if len(domains) > 1 {
-		expiringSubject += fmt.Sprintf(" (and %d more)", len(domains)-1)
-	}
-
-	// Execute the subjectTemplate by filling in the ExpirationSubject
-	subjBuf := new(bytes.Buffer)
-	err := m.subjectTemplate.Execute(subjBuf, struct {
-		ExpirationSubject string
-	}{
-		ExpirationSubject: expiringSubject,
-	})
-	if err != nil {
-		m.stats.errorCount.With(prometheus.Labels{"type": "SubjectTemplateFailure"}).Inc()
-		return err
-	}
-
-	email := struct {
-		ExpirationDate   string
-		DaysToExpiration int
-		DNSNames         string
-	}{
-		ExpirationDate:   expDate.UTC().Format(time.RFC822Z),
+	n := notification{
+		ExpirationDate:   expDate,
 		DaysToExpiration: int(expiresIn.Hours() / 24),
-		DNSNames:         strings.Join(domains, "\n"),
-	}
-	msgBuf := new(bytes.Buffer)
-	err = m.emailTemplate.Execute(msgBuf, email)
-	if err != nil {
-		m.stats.errorCount.With(prometheus.Labels{"type": "TemplateFailure"}).Inc()
-		return err
+		DNSNames:         domains,
+		Serials:          serials,
 	}
 	startSending := m.clk.Now()
-	err = m.mailer.SendMail(emails, subjBuf.String(), msgBuf.String())
-	if err != nil {
-		return err
+	var delivered int
+	var failures []string
+	for _, nf := range m.notifiers {
+		recipients := byScheme[nf.scheme()]
+		if len(recipients) == 0 {
+			continue
+		}
+		err := nf.notify(recipients, n)
+		if sendErr, ok := err.(*bmail.SendError); ok && len(sendErr.Sent) > 0 {
+			// Some recipients were notified, and retrying would send them a
+			// duplicate, so only log the ones which failed.
+			m.log.AuditErrf("Error sending nags for %s: %s: %s", strings.Join(serials, ", "), nf.channel(), err)
+			err = nil
+		}
+		if err != nil {
+			if _, ok := err.(templateError); ok {
+				m.stats.errorCount.With(prometheus.Labels{"type": "TemplateFailure"}).Inc()
+			}
+			m.stats.deliveries.With(prometheus.Labels{"channel": nf.channel(), "result": "failure"}).Inc()
+			failures = append(failures, fmt.Sprintf("%s: %s", nf.channel(), err))
+			continue
+		}
+		m.stats.deliveries.With(prometheus.Labels{"channel": nf.channel(), "result": "success"}).Inc()
+		delivered++
+	}
+	if len(failures) > 0 {
+		if delivered == 0 {
+			return errors.New(strings.Join(failures, "; "))
+		}
+		for _, failure := range failures {
+			m.log.AuditErrf("Error sending nags for %s: %s", strings.Join(serials, ", "), failure)
+		}
 	}
 	finishSending := m.clk.Now()
 	elapsed := finishSending.Sub(startSending)
@@ -184,14 +198,16 @@ func (m *mailer) processCerts(allCerts []core.Certificate) {
 		regIDToCerts[cert.RegistrationID] = cs
 	}
 
-	err := m.mailer.Connect()
-	if err != nil {
-		m.log.AuditErrf("Error connecting to send nag emails: %s", err)
-		return
+	for _, nf := range m.notifiers {
+		err := nf.connect()
+		if err != nil {
+			m.log.AuditErrf("Error connecting to send %s nags: %s", nf.channel(), err)
+			return
+		}
+		defer func(nf notifier) {
+			_ = nf.close()
+		}(nf)
 	}
-	defer func() {
-		_ = m.mailer.Close()
-	}()
 
 	for regID, certs := range regIDToCerts {
 		reg, err := m.rs.GetRegistration(ctx, regID)
@@ -239,7 +255,7 @@ func (m *mailer) processCerts(allCerts []core.Certificate) {
 		err = m.sendNags(*reg.Contact, parsedCerts)
 		if err != nil {
 			m.stats.errorCount.With(prometheus.Labels{"type": "SendNags"}).Inc()
-			m.log.AuditErrf("Error sending nag emails: %s", err)
+			m.log.AuditErrf("Error sending nags: %s", err)
 			continue
 		}
 		for _, cert := range parsedCerts {
@@ -271,7 +287,8 @@ func (m *mailer) findExpiringCertificates() error {
 		// First we do a query on the certificateStatus table to find certificates
 		// nearing expiry meeting our criteria for email notification. We later
 		// sequentially fetch the certificate details. This avoids an expensive
-		// JOIN.
+		// JOIN. Short-lived certificates are left out, since their whole
+		// validity period can fall within the nag times.
 		var serials []string
 		_, err := m.dbMap.Select(
 			&serials,
@@ -281,6 +298,7 @@ func (m *mailer) findExpiringCertificates() error {
 				WHERE cs.notAfter > :cutoffA
 				AND cs.notAfter <= :cutoffB
 				AND cs.status != "revoked"
+				AND NOT cs.shortLived
 				AND COALESCE(TIMESTAMPDIFF(SECOND, cs.lastExpirationNagSent, cs.notAfter) > :nagCutoff, 1)
 				ORDER BY cs.notAfter ASC
 				LIMIT :limit`,
@@ -380,12 +398,35 @@ type config struct {
 		// during the SMTP connection (as opposed to the gRPC connections).
 		SMTPTrustedRootFile string
 
+		// Webhook, if set, enables notifications to https: contacts.
+		Webhook *webhookConfig
+
+		// Unsubscribe, if set, adds an unsubscribe link to each email.
+		Unsubscribe *cmd.UnsubscribeConfig
+
 		Features map[string]bool
 	}
 
+	// PA decides which contact schemes are notified. Only its ContactSchemes
+	// are used, and they should match the RA's.
+	PA cmd.PAConfig
+
 	Syslog cmd.SyslogConfig
 }
 
+// webhookConfig configures delivery of signed JSON notifications to https:
+// contacts.
+type webhookConfig struct {
+	// Path to the PEM encoded ECDSA P-256 private key requests are signed
+	// with. Its public key must be published for receivers to check the
+	// signatures against.
+	KeyFile string
+	// Path to a text/template for the notification's message, which is given
+	// the same data as the email template. If empty, a short default is used.
+	Template string
+	Timeout  cmd.ConfigDuration
+}
+
 func initStats(scope metrics.Scope) mailerStats {
 	nagsAtCapacity := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -411,6 +452,14 @@ func initStats(scope metrics.Scope) mailerStats {
 		nil)
 	scope.MustRegister(renewalCount)
 
+	deliveries := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "deliveries",
+			Help: "Number of nag deliveries to a registration, by channel and result",
+		},
+		[]string{"channel", "result"})
+	scope.MustRegister(deliveries)
+
 	sendLatency := prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:    "sendLatency",
@@ -430,6 +479,7 @@ func initStats(scope metrics.Scope) mailerStats {
 		nagsAtCapacity:    nagsAtCapacity,
 		errorCount:        errorCount,
 		renewalCount:      renewalCount,
+		deliveries:        deliveries,
 		sendLatency:       sendLatency,
 		processingLatency: processingLatency,
 	}
@@ -525,6 +575,49 @@ func main() {
 		scope,
 		*reconnBase,
 		*reconnMax)
+	mailClient.SetSuppressionList(sac)
+	if c.Mailer.Unsubscribe != nil {
+		key, err := c.Mailer.Unsubscribe.Key()
+		cmd.FailOnError(err, "Couldn't load unsubscribe key")
+		mailClient.SetUnsubscribeURL(c.Mailer.Unsubscribe.URL, key)
+	}
+
+	notifiers := []notifier{&smtpNotifier{
+		mailer:          mailClient,
+		subjectTemplate: subjTmpl,
+		emailTemplate:   tmpl,
+	}}
+
+	pa, err := policy.New(nil)
+	cmd.FailOnError(err, "Couldn't create PA")
+	if len(c.PA.ContactSchemes) > 0 {
+		err = pa.SetContactSchemes(c.PA.ContactSchemes)
+		cmd.FailOnError(err, "Couldn't set contact schemes")
+	}
+
+	if wc := c.Mailer.Webhook; wc != nil {
+		if !pa.ContactSchemeAllowed("https") {
+			cmd.Fail("webhook is configured but the PA doesn't allow https contacts")
+		}
+		key, err := loadWebhookKey(wc.KeyFile)
+		cmd.FailOnError(err, "Failed to load webhook signing key")
+		webhookTmpl := template.New("expiry-webhook")
+		if wc.Template != "" {
+			contents, err := ioutil.ReadFile(wc.Template)
+			cmd.FailOnError(err, fmt.Sprintf("Could not read webhook template file [%s]", wc.Template))
+			webhookTmpl, err = webhookTmpl.Parse(string(contents))
+			cmd.FailOnError(err, "Could not parse webhook template")
+		} else {
+			webhookTmpl = template.Must(webhookTmpl.Parse(defaultWebhookTemplate))
+		}
+		timeout := wc.Timeout.Duration
+		if timeout == 0 {
+			timeout = 10 * time.Second
+		}
+		webhook, err := newWebhookNotifier(key, webhookTmpl, timeout, clk)
+		cmd.FailOnError(err, "Failed to create webhook notifier")
+		notifiers = append(notifiers, webhook)
+	}
 
 	nagCheckInterval := defaultNagCheckInterval
 	if s := c.Mailer.NagCheckInterval; s != "" {
@@ -548,16 +641,15 @@ func main() {
 	sort.Sort(nags)
 
 	m := mailer{
-		log:             logger,
-		dbMap:           dbMap,
-		rs:              sac,
-		mailer:          mailClient,
-		subjectTemplate: subjTmpl,
-		emailTemplate:   tmpl,
-		nagTimes:        nags,
-		limit:           c.Mailer.CertLimit,
-		clk:             clk,
-		stats:           initStats(scope),
+		log:       logger,
+		dbMap:     dbMap,
+		rs:        sac,
+		pa:        pa,
+		notifiers: notifiers,
+		nagTimes:  nags,
+		limit:     c.Mailer.CertLimit,
+		clk:       clk,
+		stats:     initStats(scope),
 	}
 
 	// Prefill this labelled stat with the possible label values, so each value is
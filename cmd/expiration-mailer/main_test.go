@@ -28,6 +28,7 @@ import (
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/metrics"
 	"github.com/letsencrypt/boulder/mocks"
+	"github.com/letsencrypt/boulder/policy"
 	"github.com/letsencrypt/boulder/sa"
 	"github.com/letsencrypt/boulder/sa/satest"
 	"github.com/letsencrypt/boulder/test"
@@ -108,15 +109,19 @@ func TestSendNags(t *testing.T) {
 
 	staticTmpl := template.Must(template.New("expiry-email-subject-static").Parse(testEmailSubject))
 
-	m := mailer{
-		log:           log,
+	smtp := &smtpNotifier{
 		mailer:        &mc,
 		emailTemplate: tmpl,
 		// Explicitly override the default subject to use testEmailSubject
 		subjectTemplate: staticTmpl,
-		rs:              rs,
-		clk:             fc,
-		stats:           initStats(metrics.NewNoopScope()),
+	}
+	m := mailer{
+		log:       log,
+		pa:        newPA(t),
+		notifiers: []notifier{smtp},
+		rs:        rs,
+		clk:       fc,
+		stats:     initStats(metrics.NewNoopScope()),
 	}
 
 	cert := &x509.Certificate{
@@ -159,7 +164,7 @@ func TestSendNags(t *testing.T) {
 	templates, err := template.ParseGlob("../../data/*.template")
 	test.AssertNotError(t, err, "Failed to parse templates")
 	for _, template := range templates.Templates() {
-		m.emailTemplate = template
+		smtp.emailTemplate = template
 		err = m.sendNags(nil, []*x509.Certificate{cert})
 		test.AssertNotError(t, err, "failed to send nag")
 	}
@@ -717,6 +722,58 @@ func TestDontFindRevokedCert(t *testing.T) {
 	}
 }
 
+func TestDontFindShortLivedCert(t *testing.T) {
+	expiresIn := 7 * 24 * time.Hour
+	testCtx := setup(t, []time.Duration{expiresIn})
+
+	var keyA jose.JSONWebKey
+	err := json.Unmarshal(jsonKeyA, &keyA)
+	test.AssertNotError(t, err, "Failed to unmarshal public JWK")
+
+	regA := core.Registration{
+		ID: 1,
+		Contact: &[]string{
+			emailA,
+		},
+		Key:       &keyA,
+		InitialIP: net.ParseIP("6.5.5.6"),
+	}
+	regA, err = testCtx.ssa.NewRegistration(ctx, regA)
+	if err != nil {
+		t.Fatalf("Couldn't store regA: %s", err)
+	}
+	// A 7 day certificate falls within the 7 day nag from its issuance
+	rawCertA := x509.Certificate{
+		Subject: pkix.Name{
+			CommonName: "happy A",
+		},
+		NotBefore:    testCtx.fc.Now(),
+		NotAfter:     testCtx.fc.Now().Add(expiresIn),
+		DNSNames:     []string{"example-a.com"},
+		SerialNumber: serial1,
+	}
+	certDerA, _ := x509.CreateCertificate(rand.Reader, &rawCertA, &rawCertA, &testKey.PublicKey, &testKey)
+	certA := &core.Certificate{
+		RegistrationID: regA.ID,
+		Serial:         serial1String,
+		Expires:        rawCertA.NotAfter,
+		DER:            certDerA,
+	}
+
+	setupDBMap, err := sa.NewDbMap(vars.DBConnSAFullPerms, 0)
+	err = setupDBMap.Insert(certA)
+	test.AssertNotError(t, err, "unable to insert Certificate")
+	_, err = setupDBMap.Exec("INSERT INTO certificateStatus (serial, status, notAfter, lastExpirationNagSent, ocspLastUpdated, revokedDate, revokedReason, LockCol, subscriberApproved, shortLived) VALUES (?,?,?,?,?,?,?,?,?,?)", serial1String, string(core.OCSPStatusGood), rawCertA.NotAfter, time.Time{}, time.Time{}, time.Time{}, 0, 0, false, true)
+	test.AssertNotError(t, err, "unable to insert CertificateStatus")
+
+	err = testCtx.m.findExpiringCertificates()
+	test.AssertNotError(t, err, "err from findExpiringCertificates")
+
+	if len(testCtx.mc.Messages) != 0 {
+		t.Errorf("no emails should have been sent, but sent %d", len(testCtx.mc.Messages))
+	}
+}
+
 func TestDedupOnRegistration(t *testing.T) {
 	expiresIn := 96 * time.Hour
 	testCtx := setup(t, []time.Duration{expiresIn})
@@ -804,6 +861,15 @@ type testCtx struct {
 	cleanUp func()
 }
 
+// newPA returns a PA which allows mailto: and https: contacts.
+func newPA(t *testing.T) *policy.AuthorityImpl {
+	pa, err := policy.New(nil)
+	test.AssertNotError(t, err, "Couldn't create PA")
+	err = pa.SetContactSchemes([]string{"mailto", "https"})
+	test.AssertNotError(t, err, "SetContactSchemes failed")
+	return pa
+}
+
 func setup(t *testing.T, nagTimes []time.Duration) *testCtx {
 	// We use the test_setup user (which has full permissions to everything)
 	// because the SA we return is used for inserting data to set up the test.
@@ -826,16 +892,19 @@ func setup(t *testing.T, nagTimes []time.Duration) *testCtx {
 	}
 
 	m := &mailer{
-		log:             log,
-		mailer:          mc,
-		emailTemplate:   tmpl,
-		subjectTemplate: subjTmpl,
-		dbMap:           dbMap,
-		rs:              ssa,
-		nagTimes:        offsetNags,
-		limit:           100,
-		clk:             fc,
-		stats:           initStats(metrics.NewNoopScope()),
+		log: log,
+		pa:  newPA(t),
+		notifiers: []notifier{&smtpNotifier{
+			mailer:          mc,
+			emailTemplate:   tmpl,
+			subjectTemplate: subjTmpl,
+		}},
+		dbMap:    dbMap,
+		rs:       ssa,
+		nagTimes: offsetNags,
+		limit:    100,
+		clk:      fc,
+		stats:    initStats(metrics.NewNoopScope()),
 	}
 	return &testCtx{
 		dbMap:   dbMap,
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/letsencrypt/boulder/bdns"
+	bmail "github.com/letsencrypt/boulder/mail"
+)
+
+const defaultWebhookTemplate = "Your certificate for {{.DNSNames}} expires in {{.DaysToExpiration}} days ({{.ExpirationDate}})."
+
+// notification describes the expiring certificates of one registration.
+type notification struct {
+	// ExpirationDate is the earliest expiry of the certificates.
+	ExpirationDate   time.Time
+	DaysToExpiration int
+	// DNSNames are the unique, sorted names of the certificates.
+	DNSNames []string
+	Serials  []string
+}
+
+// templateData is the data passed to each channel's message template. It is
+// kept unchanged so that existing email templates keep working.
+type templateData struct {
+	ExpirationDate   string
+	DaysToExpiration int
+	DNSNames         string
+}
+
+func (n notification) templateData() templateData {
+	return templateData{
+		ExpirationDate:   n.ExpirationDate.UTC().Format(time.RFC822Z),
+		DaysToExpiration: n.DaysToExpiration,
+		DNSNames:         strings.Join(n.DNSNames, "\n"),
+	}
+}
+
+// notifier delivers notifications to the registration contacts with one URL
+// scheme, e.g. email to mailto: contacts. Each notifier is a separate
+// delivery channel with its own templates.
+type notifier interface {
+	// channel names the notifier in logs and stats.
+	channel() string
+	// scheme is the contact URL scheme the notifier delivers to.
+	scheme() string
+	// connect and close are called around each batch of notifications.
+	connect() error
+	close() error
+	// notify delivers n to each of the contacts, which all use scheme().
+	notify(contacts []*url.URL, n notification) error
+}
+
+// smtpNotifier emails notifications to mailto: contacts.
+type smtpNotifier struct {
+	mailer          bmail.Mailer
+	subjectTemplate *template.Template
+	emailTemplate   *template.Template
+}
+
+func (s *smtpNotifier) channel() string { return "smtp" }
+func (s *smtpNotifier) scheme() string  { return "mailto" }
+func (s *smtpNotifier) connect() error  { return s.mailer.Connect() }
+func (s *smtpNotifier) close() error    { return s.mailer.Close() }
+
+func (s *smtpNotifier) notify(contacts []*url.URL, n notification) error {
+	emails := make([]string, len(contacts))
+	for i, contact := range contacts {
+		emails[i] = contact.Opaque
+	}
+
+	// Construct the information about the expiring certificates for use in the
+	// subject template
+	expiringSubject := fmt.Sprintf("%q", n.DNSNames[0])
+	if len(n.DNSNames) > 1 {
+		expiringSubject += fmt.Sprintf(" (and %d more)", len(n.DNSNames)-1)
+	}
+
+	// Execute the subjectTemplate by filling in the ExpirationSubject
+	subjBuf := new(bytes.Buffer)
+	err := s.subjectTemplate.Execute(subjBuf, struct {
+		ExpirationSubject string
+	}{
+		ExpirationSubject: expiringSubject,
+	})
+	if err != nil {
+		return templateError{err}
+	}
+
+	msgBuf := new(bytes.Buffer)
+	err = s.emailTemplate.Execute(msgBuf, n.templateData())
+	if err != nil {
+		return templateError{err}
+	}
+	return s.mailer.SendMail(emails, subjBuf.String(), msgBuf.String())
+}
+
+// templateError is returned by a notifier when its templates fail to execute,
+// as opposed to when delivery fails.
+type templateError struct {
+	err error
+}
+
+func (e templateError) Error() string {
+	return fmt.Sprintf("executing template: %s", e.err)
+}
+
+// webhookPayload is the JSON body POSTed by a webhookNotifier.
+type webhookPayload struct {
+	Type             string    `json:"type"`
+	ExpirationDate   time.Time `json:"expirationDate"`
+	DaysToExpiration int       `json:"daysToExpiration"`
+	DNSNames         []string  `json:"dnsNames"`
+	Serials          []string  `json:"serials"`
+	// Message is the notification rendered by the webhook template, for
+	// receivers that only display text.
+	Message string `json:"message"`
+}
+
+// webhookNotifier POSTs notifications as JSON to https: contacts. Each request
+// is signed with an ECDSA P-256 key whose public key is published, so that
+// receivers can check it came from us without holding a secret; see
+// signWebhook. Any 2xx response is treated as success, and redirects are not
+// followed.
+type webhookNotifier struct {
+	client   *http.Client
+	key      *ecdsa.PrivateKey
+	keyID    string
+	template *template.Template
+	clk      clock.Clock
+}
+
+// loadWebhookKey reads the PEM encoded ECDSA P-256 private key webhook requests
+// are signed with from filename.
+func loadWebhookKey(filename string) (*ecdsa.PrivateKey, error) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(contents)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, fmt.Errorf("no EC PRIVATE KEY block in %s", filename)
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if key.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("webhook signing key in %s is not a P-256 key", filename)
+	}
+	return key, nil
+}
+
+// webhookKeyID returns the ID receivers know a webhook signing key by: the
+// hex-encoded SHA-256 of its DER encoded public key.
+func webhookKeyID(key *ecdsa.PrivateKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(der)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// newWebhookNotifier returns a webhookNotifier whose client refuses to connect
+// to private, loopback and link-local addresses, whatever the contact's
+// hostname resolves to.
+func newWebhookNotifier(key *ecdsa.PrivateKey, tmpl *template.Template, timeout time.Duration, clk clock.Clock) (*webhookNotifier, error) {
+	keyID, err := webhookKeyID(key)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: bdns.PublicOnlyControl,
+	}
+	return &webhookNotifier{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		key:      key,
+		keyID:    keyID,
+		template: tmpl,
+		clk:      clk,
+	}, nil
+}
+
+func (w *webhookNotifier) channel() string { return "webhook" }
+func (w *webhookNotifier) scheme() string  { return "https" }
+func (w *webhookNotifier) connect() error  { return nil }
+func (w *webhookNotifier) close() error    { return nil }
+
+// signWebhook returns the signature of a webhook request body sent at
+// timestamp (in Unix seconds): the hex-encoded ECDSA P-256 signature, as the
+// 32-byte big-endian r followed by the 32-byte big-endian s, of the SHA-256 of
+// the timestamp, a ".", and the body. Including the timestamp lets receivers
+// reject replayed requests.
+func signWebhook(key *ecdsa.PrivateKey, timestamp string, body []byte) (string, error) {
+	digest := sha256.Sum256(append([]byte(timestamp+"."), body...))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	sig := make([]byte, 64)
+	rBytes, sBytes := r.Bytes(), s.Bytes()
+	copy(sig[32-len(rBytes):32], rBytes)
+	copy(sig[64-len(sBytes):], sBytes)
+	return hex.EncodeToString(sig), nil
+}
+
+func (w *webhookNotifier) notify(contacts []*url.URL, n notification) error {
+	msgBuf := new(bytes.Buffer)
+	err := w.template.Execute(msgBuf, n.templateData())
+	if err != nil {
+		return templateError{err}
+	}
+	body, err := json.Marshal(webhookPayload{
+		Type:             "certificate-expiration",
+		ExpirationDate:   n.ExpirationDate.UTC(),
+		DaysToExpiration: n.DaysToExpiration,
+		DNSNames:         n.DNSNames,
+		Serials:          n.Serials,
+		Message:          msgBuf.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Deliver to every contact even if one fails, and report the failures
+	// together.
+	var failures []string
+	for _, contact := range contacts {
+		err := w.post(contact.String(), body)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", contact, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("delivering webhooks: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+func (w *webhookNotifier) post(target string, body []byte) error {
+	req, err := http.NewRequest("POST", target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(w.clk.Now().Unix(), 10)
+	signature, err := signWebhook(w.key, timestamp, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Boulder-Timestamp", timestamp)
+	req.Header.Set("Boulder-Signature", signature)
+	req.Header.Set("Boulder-Signature-Key", w.keyID)
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected HTTP status code %d", resp.StatusCode)
+	}
+	return nil
+}
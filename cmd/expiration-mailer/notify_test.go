@@ -0,0 +1,294 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	bmail "github.com/letsencrypt/boulder/mail"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/mocks"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func loadTestWebhookKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := loadWebhookKey("../../test/secrets/webhook_signing_key")
+	test.AssertNotError(t, err, "Failed to load webhook signing key")
+	return key
+}
+
+// verifyWebhook checks a webhook signature the way a receiver would, against
+// the published public key.
+func verifyWebhook(pub *ecdsa.PublicKey, timestamp string, body []byte, signature string) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) != 64 {
+		return false
+	}
+	digest := sha256.Sum256(append([]byte(timestamp+"."), body...))
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	return ecdsa.Verify(pub, digest[:], r, s)
+}
+
+type webhookRequest struct {
+	timestamp string
+	signature string
+	payload   webhookPayload
+}
+
+// webhookServer returns an HTTPS server recording the webhook requests it
+// receives, and responding with status.
+func webhookServer(t *testing.T, status int) (*httptest.Server, *[]webhookRequest) {
+	key := loadTestWebhookKey(t)
+	keyID, err := webhookKeyID(key)
+	test.AssertNotError(t, err, "Failed to compute key ID")
+	var requests []webhookRequest
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		test.AssertNotError(t, err, "Failed to read webhook body")
+		timestamp := r.Header.Get("Boulder-Timestamp")
+		signature := r.Header.Get("Boulder-Signature")
+		test.Assert(t, verifyWebhook(&key.PublicKey, timestamp, body, signature), "Webhook signature doesn't verify")
+		test.AssertEquals(t, r.Header.Get("Boulder-Signature-Key"), keyID)
+		var payload webhookPayload
+		err = json.Unmarshal(body, &payload)
+		test.AssertNotError(t, err, "Failed to unmarshal webhook body")
+		requests = append(requests, webhookRequest{timestamp, signature, payload})
+		w.WriteHeader(status)
+	}))
+	return srv, &requests
+}
+
+func testWebhookNotifier(t *testing.T, srv *httptest.Server) *webhookNotifier {
+	w, err := newWebhookNotifier(
+		loadTestWebhookKey(t),
+		template.Must(template.New("webhook").Parse(defaultWebhookTemplate)),
+		time.Second,
+		newFakeClock(t))
+	test.AssertNotError(t, err, "Failed to create webhook notifier")
+	w.client = srv.Client()
+	return w
+}
+
+func TestSignWebhook(t *testing.T) {
+	key := loadTestWebhookKey(t)
+	signature, err := signWebhook(key, "1500000000", []byte("{}"))
+	test.AssertNotError(t, err, "signWebhook failed")
+	test.Assert(t, verifyWebhook(&key.PublicKey, "1500000000", []byte("{}"), signature), "Signature doesn't verify")
+	test.Assert(t, !verifyWebhook(&key.PublicKey, "1500000001", []byte("{}"), signature), "Signature verifies for another timestamp")
+	test.Assert(t, !verifyWebhook(&key.PublicKey, "1500000000", []byte("[]"), signature), "Signature verifies for another body")
+}
+
+func TestLoadWebhookKey(t *testing.T) {
+	key := loadTestWebhookKey(t)
+	// The test key matches the public key published for receivers
+	contents, err := ioutil.ReadFile("../../test/webhook-signing-key.pub.pem")
+	test.AssertNotError(t, err, "Failed to read public key")
+	block, _ := pem.Decode(contents)
+	test.Assert(t, block != nil, "No PEM block in public key file")
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	test.AssertNotError(t, err, "Failed to marshal public key")
+	test.AssertByteEquals(t, der, block.Bytes)
+
+	// Only P-256 keys are accepted
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	test.AssertNotError(t, err, "Failed to generate key")
+	der, err = x509.MarshalECPrivateKey(p384)
+	test.AssertNotError(t, err, "Failed to marshal key")
+	f, err := ioutil.TempFile("", "webhook-key")
+	test.AssertNotError(t, err, "Failed to create temp file")
+	defer os.Remove(f.Name())
+	err = pem.Encode(f, &pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	test.AssertNotError(t, err, "Failed to write key")
+	_ = f.Close()
+	_, err = loadWebhookKey(f.Name())
+	test.AssertError(t, err, "loadWebhookKey accepted a P-384 key")
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	srv, requests := webhookServer(t, http.StatusNoContent)
+	defer srv.Close()
+	w := testWebhookNotifier(t, srv)
+
+	contact, err := url.Parse(srv.URL + "/hook")
+	test.AssertNotError(t, err, "Failed to parse URL")
+	expires := time.Date(2015, 3, 9, 0, 0, 0, 0, time.UTC)
+	err = w.notify([]*url.URL{contact}, notification{
+		ExpirationDate:   expires,
+		DaysToExpiration: 5,
+		DNSNames:         []string{"example.com"},
+		Serials:          []string{"01"},
+	})
+	test.AssertNotError(t, err, "notify failed")
+	test.AssertEquals(t, len(*requests), 1)
+	req := (*requests)[0]
+	test.AssertEquals(t, req.timestamp, "1136214245")
+	test.AssertDeepEquals(t, req.payload, webhookPayload{
+		Type:             "certificate-expiration",
+		ExpirationDate:   expires,
+		DaysToExpiration: 5,
+		DNSNames:         []string{"example.com"},
+		Serials:          []string{"01"},
+		Message:          "Your certificate for example.com expires in 5 days (09 Mar 15 00:00 +0000).",
+	})
+}
+
+func TestWebhookNotifierFailure(t *testing.T) {
+	srv, requests := webhookServer(t, http.StatusInternalServerError)
+	defer srv.Close()
+	w := testWebhookNotifier(t, srv)
+
+	contactA, _ := url.Parse(srv.URL + "/a")
+	contactB, _ := url.Parse(srv.URL + "/b")
+	err := w.notify([]*url.URL{contactA, contactB}, notification{DNSNames: []string{"example.com"}})
+	test.AssertError(t, err, "notify succeeded despite a 500 response")
+	// Every contact is tried
+	test.AssertEquals(t, len(*requests), 2)
+}
+
+func TestWebhookNotifierRefusesPrivateAddresses(t *testing.T) {
+	srv, requests := webhookServer(t, http.StatusNoContent)
+	defer srv.Close()
+	// Unlike testWebhookNotifier, keep the notifier's own client, which must
+	// refuse to connect to the test server's loopback address.
+	w, err := newWebhookNotifier(
+		loadTestWebhookKey(t),
+		template.Must(template.New("webhook").Parse(defaultWebhookTemplate)),
+		time.Second,
+		newFakeClock(t))
+	test.AssertNotError(t, err, "Failed to create webhook notifier")
+
+	contact, _ := url.Parse(srv.URL + "/hook")
+	err = w.notify([]*url.URL{contact}, notification{DNSNames: []string{"example.com"}})
+	test.AssertError(t, err, "notify succeeded to a loopback address")
+	test.AssertEquals(t, len(*requests), 0)
+}
+
+func countDeliveries(m *mailer, channel, result string) int {
+	return test.CountCounter(m.stats.deliveries.With(prometheus.Labels{"channel": channel, "result": result}))
+}
+
+type mailtoOnly struct{}
+
+func (mailtoOnly) ContactSchemeAllowed(scheme string) bool {
+	return scheme == "mailto"
+}
+
+func TestSendNagsChannels(t *testing.T) {
+	fc := newFakeClock(t)
+	cert := &x509.Certificate{
+		NotAfter:     fc.Now().AddDate(0, 0, 2),
+		DNSNames:     []string{"example.com"},
+		SerialNumber: serial1,
+	}
+
+	okSrv, okRequests := webhookServer(t, http.StatusOK)
+	defer okSrv.Close()
+	failSrv, _ := webhookServer(t, http.StatusInternalServerError)
+	defer failSrv.Close()
+
+	newMailer := func(srv *httptest.Server, pa contactPolicy) (*mailer, *mocks.Mailer) {
+		mc := &mocks.Mailer{}
+		return &mailer{
+			log: log,
+			pa:  pa,
+			notifiers: []notifier{
+				&smtpNotifier{mailer: mc, emailTemplate: tmpl, subjectTemplate: subjTmpl},
+				testWebhookNotifier(t, srv),
+			},
+			clk:   fc,
+			stats: initStats(metrics.NewNoopScope()),
+		}, mc
+	}
+
+	// Each channel gets the contacts of its scheme
+	m, mc := newMailer(okSrv, newPA(t))
+	err := m.sendNags([]string{emailA, okSrv.URL}, []*x509.Certificate{cert})
+	test.AssertNotError(t, err, "sendNags failed")
+	test.AssertEquals(t, len(mc.Messages), 1)
+	test.AssertEquals(t, len(*okRequests), 1)
+	test.AssertEquals(t, countDeliveries(m, "webhook", "success"), 1)
+	test.AssertEquals(t, countDeliveries(m, "smtp", "success"), 1)
+
+	// https: contacts are skipped if the PA doesn't allow them
+	m, mc = newMailer(okSrv, mailtoOnly{})
+	err = m.sendNags([]string{emailA, okSrv.URL}, []*x509.Certificate{cert})
+	test.AssertNotError(t, err, "sendNags failed")
+	test.AssertEquals(t, len(mc.Messages), 1)
+	test.AssertEquals(t, len(*okRequests), 1)
+
+	// A failing channel doesn't fail the nag if another channel delivered it,
+	// so that it isn't repeated on the next run
+	m, mc = newMailer(failSrv, newPA(t))
+	err = m.sendNags([]string{emailA, failSrv.URL}, []*x509.Certificate{cert})
+	test.AssertNotError(t, err, "sendNags failed despite the email being sent")
+	test.AssertEquals(t, len(mc.Messages), 1)
+	test.AssertEquals(t, countDeliveries(m, "smtp", "success"), 1)
+	test.AssertEquals(t, countDeliveries(m, "webhook", "failure"), 1)
+
+	// But it does if it was the only channel
+	m, _ = newMailer(failSrv, newPA(t))
+	err = m.sendNags([]string{failSrv.URL}, []*x509.Certificate{cert})
+	test.AssertError(t, err, "sendNags succeeded without delivering")
+}
+
+// partialMailer sends to the first recipient and fails for the rest, like a
+// mailer sending each recipient a separate message.
+type partialMailer struct {
+	mocks.Mailer
+}
+
+func (m *partialMailer) SendMail(to []string, subject, msg string) error {
+	err := m.Mailer.SendMail(to[:1], subject, msg)
+	if err != nil || len(to) == 1 {
+		return err
+	}
+	sendErr := &bmail.SendError{Sent: to[:1], Failed: make(map[string]error)}
+	for _, rcpt := range to[1:] {
+		sendErr.Failed[rcpt] = errors.New("mailbox full")
+	}
+	return sendErr
+}
+
+func TestSendNagsPartialSend(t *testing.T) {
+	fc := newFakeClock(t)
+	cert := &x509.Certificate{
+		NotAfter:     fc.Now().AddDate(0, 0, 2),
+		DNSNames:     []string{"example.com"},
+		SerialNumber: serial1,
+	}
+	mc := &partialMailer{}
+	m := &mailer{
+		log: log,
+		pa:  newPA(t),
+		notifiers: []notifier{
+			&smtpNotifier{mailer: mc, emailTemplate: tmpl, subjectTemplate: subjTmpl},
+		},
+		clk:   fc,
+		stats: initStats(metrics.NewNoopScope()),
+	}
+
+	// Some recipients were sent the nag, so it isn't retried
+	err := m.sendNags([]string{emailA, emailB}, []*x509.Certificate{cert})
+	test.AssertNotError(t, err, "sendNags failed after a partial send")
+	test.AssertEquals(t, len(mc.Messages), 1)
+	test.AssertEquals(t, countDeliveries(m, "smtp", "success"), 1)
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"gopkg.in/go-gorp/gorp.v2"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/features"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/sa"
+)
+
+type config struct {
+	ExpiredArchiver struct {
+		cmd.DBConfig
+
+		Syslog cmd.SyslogConfig
+
+		// Certificates and authorizations which expired longer than
+		// ArchiveAfter ago are moved to the archive tables.
+		ArchiveAfter cmd.ConfigDuration
+		// BatchSize is the number of rows of a main table moved in each
+		// transaction, along with their dependent rows.
+		BatchSize int
+		// MaxRows is the maximum number of rows of each main table moved in
+		// one run.
+		MaxRows int
+
+		Features map[string]bool
+	}
+}
+
+// dependent is a table whose rows are archived along with the main table row
+// they reference through column.
+type dependent struct {
+	table   string
+	column  string
+	columns string
+}
+
+// archiveSet is a main table whose expired rows are archived, identified by
+// key, along with the rows of its dependent tables. Each table is archived to
+// the table with the same name suffixed with "Archive". The main table's
+// archive table is partitioned by month of expiry.
+//
+// Only the listed columns are copied. A migration adding a column to a main
+// table must add it to the archive table too, and the column must be added to
+// the list here, or it is lost when rows are archived.
+type archiveSet struct {
+	table      string
+	key        string
+	columns    string
+	dependents []dependent
+}
+
+var archiveSets = []archiveSet{
+	{
+		table:   "certificates",
+		key:     "serial",
+		columns: "id, registrationID, serial, digest, der, issued, expires",
+		dependents: []dependent{
+			{
+				table:   "certificateStatus",
+				column:  "serial",
+				columns: "id, serial, subscriberApproved, status, ocspLastUpdated, revokedDate, revokedReason, lastExpirationNagSent, LockCol, ocspResponse, notAfter, isExpired, shortLived",
+			},
+			{
+				table:   "issuedNames",
+				column:  "serial",
+				columns: "id, reversedName, notBefore, serial, renewal",
+			},
+		},
+	},
+	{
+		table:   "authz",
+		key:     "id",
+		columns: "id, identifier, registrationID, status, expires, combinations",
+		dependents: []dependent{
+			{
+				table:   "challenges",
+				column:  "authorizationID",
+				columns: "id, authorizationID, LockCol, type, status, error, validated, token, validationRecord, keyAuthorization, caaRecord, reputation",
+			},
+		},
+	},
+}
+
+type expiredArchiver struct {
+	log blog.Logger
+	clk clock.Clock
+	db  *gorp.DbMap
+
+	batchSize int
+}
+
+const partitionLayout = "p200601"
+
+// partitionsToAdd returns the first day of each month which needs a partition
+// before rows expiring before `before` can be archived, given the names of
+// the existing partitions. Partition pYYYYMM holds the rows expiring in that
+// month, and the first partition also holds all earlier rows, so months are
+// only ever added after the last existing one.
+func partitionsToAdd(existing []string, before time.Time) ([]time.Time, error) {
+	before = before.UTC()
+	// The month of the instant just before `before`, which is the last month
+	// that can hold rows to archive.
+	last := before.Add(-time.Nanosecond)
+	last = time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	next := last
+	var months []time.Time
+	for _, name := range existing {
+		if name == "pMax" {
+			continue
+		}
+		month, err := time.Parse(partitionLayout, name)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected partition %q", name)
+		}
+		months = append(months, month)
+	}
+	if len(months) > 0 {
+		sort.Slice(months, func(i, j int) bool { return months[i].Before(months[j]) })
+		next = months[len(months)-1].AddDate(0, 1, 0)
+	}
+
+	var add []time.Time
+	for month := next; !month.After(last); month = month.AddDate(0, 1, 0) {
+		add = append(add, month)
+	}
+	return add, nil
+}
+
+// reorganizeStatement returns the statement splitting the pMax partition of
+// table into a partition for each of months, followed by pMax.
+func reorganizeStatement(table string, months []time.Time) string {
+	var partitions []string
+	for _, month := range months {
+		partitions = append(partitions, fmt.Sprintf(
+			"PARTITION %s VALUES LESS THAN (TO_DAYS('%s'))",
+			month.Format(partitionLayout),
+			month.AddDate(0, 1, 0).Format("2006-01-02")))
+	}
+	partitions = append(partitions, "PARTITION pMax VALUES LESS THAN MAXVALUE")
+	return fmt.Sprintf("ALTER TABLE %s REORGANIZE PARTITION pMax INTO (%s)",
+		table, strings.Join(partitions, ", "))
+}
+
+// ensurePartitions adds the monthly partitions of table needed to archive the
+// rows expiring before `before`. Since partitions are always added before
+// rows are archived into them, pMax is empty and splitting it is cheap.
+func (a *expiredArchiver) ensurePartitions(table string, before time.Time) error {
+	var existing []string
+	_, err := a.db.Select(
+		&existing,
+		`SELECT PARTITION_NAME FROM information_schema.PARTITIONS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?`,
+		table)
+	if err != nil {
+		return err
+	}
+	months, err := partitionsToAdd(existing, before)
+	if err != nil {
+		return fmt.Errorf("%s: %s", table, err)
+	}
+	if len(months) == 0 {
+		return nil
+	}
+	_, err = a.db.Exec(reorganizeStatement(table, months))
+	if err != nil {
+		return err
+	}
+	a.log.Infof("Added %d partitions to %s", len(months), table)
+	return nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// moveRows copies the given columns of the rows of table where column is one
+// of keys to the table's archive table and deletes the rows, returning the
+// number of rows moved. It returns an error if the number of rows deleted
+// doesn't match the number copied.
+func moveRows(tx *gorp.Transaction, table, column, columns string, keys []interface{}) (int64, error) {
+	where := fmt.Sprintf("WHERE %s IN (%s)", column, placeholders(len(keys)))
+	result, err := tx.Exec(
+		fmt.Sprintf("INSERT INTO %sArchive (%s) SELECT %s FROM %s %s", table, columns, columns, table, where),
+		keys...)
+	if err != nil {
+		return 0, err
+	}
+	copied, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	result, err = tx.Exec(fmt.Sprintf("DELETE FROM %s %s", table, where), keys...)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if copied != deleted {
+		return 0, fmt.Errorf("copied %d rows of %s to the archive but deleted %d", copied, table, deleted)
+	}
+	return copied, nil
+}
+
+// archiveBatch moves the main table rows with the given keys, and their
+// dependent rows, to the archive tables in a single transaction. Dependent
+// rows are moved first so that they are never left without their main row.
+func (a *expiredArchiver) archiveBatch(set archiveSet, batch []string) error {
+	keys := make([]interface{}, len(batch))
+	for i, key := range batch {
+		keys[i] = key
+	}
+
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, dep := range set.dependents {
+		_, err := moveRows(tx, dep.table, dep.column, dep.columns, keys)
+		if err != nil {
+			return sa.Rollback(tx, err)
+		}
+	}
+	moved, err := moveRows(tx, set.table, set.key, set.columns, keys)
+	if err != nil {
+		return sa.Rollback(tx, err)
+	}
+	if moved != int64(len(keys)) {
+		return sa.Rollback(tx, fmt.Errorf("moved %d rows of %s to the archive, expected %d", moved, set.table, len(keys)))
+	}
+	return tx.Commit()
+}
+
+// archive moves up to max rows of the set's main table that expired before
+// `before`, with their dependent rows, to the archive tables in batches. Like
+// the expired-authz-purger it iterates through the table by key, since neither
+// main table has an index on `expires` by itself. It stops at the first
+// failed batch, leaving that batch's rows in the main tables.
+func (a *expiredArchiver) archive(set archiveSet, before time.Time, max int) (int, error) {
+	err := a.ensurePartitions(set.table+"Archive", before)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE %s > :key AND expires < :expires ORDER BY %s LIMIT :limit",
+		set.key, set.table, set.key, set.key)
+	// key starts as "", which is smaller than all other keys.
+	var key string
+	var count int
+	for count < max {
+		limit := a.batchSize
+		if max-count < limit {
+			limit = max - count
+		}
+		var batch []string
+		_, err := a.db.Select(
+			&batch,
+			query,
+			map[string]interface{}{
+				"key":     key,
+				"expires": before,
+				"limit":   limit,
+			},
+		)
+		if err != nil && err != sql.ErrNoRows {
+			return count, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		err = a.archiveBatch(set, batch)
+		if err != nil {
+			return count, fmt.Errorf("archiving %s from %s to %s: %s", set.table, batch[0], batch[len(batch)-1], err)
+		}
+		count += len(batch)
+		// Start the next query after the highest key in this batch.
+		key = batch[len(batch)-1]
+		a.log.Infof("Archived %d rows from %s so far", count, set.table)
+		if len(batch) < limit {
+			break
+		}
+	}
+	a.log.Infof("Archived a total of %d expired rows from %s", count, set.table)
+	return count, nil
+}
+
+func main() {
+	configFile := flag.String("config", "", "File path to the configuration file for this service")
+	flag.Parse()
+	if *configFile == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	var c config
+	err := cmd.ReadConfigFile(*configFile, &c)
+	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	err = features.Set(c.ExpiredArchiver.Features)
+	cmd.FailOnError(err, "Failed to set feature flags")
+
+	logger := cmd.NewLogger(c.ExpiredArchiver.Syslog)
+	logger.Info(cmd.VersionString())
+
+	defer logger.AuditPanic()
+
+	if c.ExpiredArchiver.ArchiveAfter.Duration == 0 {
+		fmt.Fprintln(os.Stderr, "archiveAfter is 0, refusing to archive unexpired rows")
+		os.Exit(1)
+	}
+	if c.ExpiredArchiver.BatchSize <= 0 || c.ExpiredArchiver.MaxRows <= 0 {
+		fmt.Fprintln(os.Stderr, "batchSize and maxRows in config must be set to non-zero")
+		os.Exit(1)
+	}
+
+	// Configure DB
+	dbURL, err := c.ExpiredArchiver.DBConfig.URL()
+	cmd.FailOnError(err, "Couldn't load DB URL")
+	dbMap, err := sa.NewDbMap(dbURL, c.ExpiredArchiver.DBConfig.MaxDBConns)
+	cmd.FailOnError(err, "Could not connect to database")
+	sa.SetSQLDebug(dbMap, logger)
+
+	archiver := &expiredArchiver{
+		log:       logger,
+		clk:       cmd.Clock(),
+		db:        dbMap,
+		batchSize: c.ExpiredArchiver.BatchSize,
+	}
+
+	before := archiver.clk.Now().Add(-c.ExpiredArchiver.ArchiveAfter.Duration)
+	logger.Infof("Archiving rows which expired before %s", before)
+	for _, set := range archiveSets {
+		_, err := archiver.archive(set, before, c.ExpiredArchiver.MaxRows)
+		cmd.FailOnError(err, fmt.Sprintf("Failed to archive %s", set.table))
+	}
+}
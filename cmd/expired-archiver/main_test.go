@@ -0,0 +1,159 @@
+package main
+
+import (
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"golang.org/x/net/context"
+
+	"github.com/letsencrypt/boulder/core"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/sa"
+	"github.com/letsencrypt/boulder/sa/satest"
+	"github.com/letsencrypt/boulder/test"
+	"github.com/letsencrypt/boulder/test/vars"
+)
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPartitionsToAdd(t *testing.T) {
+	// With no partitions, the first covers the month of the last rows to
+	// archive and everything before it
+	months, err := partitionsToAdd([]string{"pMax"}, time.Date(2018, 6, 15, 0, 0, 0, 0, time.UTC))
+	test.AssertNotError(t, err, "partitionsToAdd failed")
+	test.AssertDeepEquals(t, months, []time.Time{month(2018, 6)})
+
+	// Rows expiring before midnight on the first belong to the previous month
+	months, err = partitionsToAdd([]string{"pMax"}, month(2018, 6))
+	test.AssertNotError(t, err, "partitionsToAdd failed")
+	test.AssertDeepEquals(t, months, []time.Time{month(2018, 5)})
+
+	// Months are added after the last existing partition, across years
+	months, err = partitionsToAdd([]string{"p201711", "p201710", "pMax"}, time.Date(2018, 2, 3, 0, 0, 0, 0, time.UTC))
+	test.AssertNotError(t, err, "partitionsToAdd failed")
+	test.AssertDeepEquals(t, months, []time.Time{month(2017, 12), month(2018, 1), month(2018, 2)})
+
+	months, err = partitionsToAdd([]string{"p201806", "pMax"}, time.Date(2018, 2, 3, 0, 0, 0, 0, time.UTC))
+	test.AssertNotError(t, err, "partitionsToAdd failed")
+	test.AssertEquals(t, len(months), 0)
+
+	_, err = partitionsToAdd([]string{"p1", "pMax"}, month(2018, 6))
+	test.AssertError(t, err, "partitionsToAdd accepted an unexpected partition name")
+}
+
+func TestReorganizeStatement(t *testing.T) {
+	test.AssertEquals(t,
+		reorganizeStatement("certificatesArchive", []time.Time{month(2017, 12), month(2018, 1)}),
+		"ALTER TABLE certificatesArchive REORGANIZE PARTITION pMax INTO ("+
+			"PARTITION p201712 VALUES LESS THAN (TO_DAYS('2018-01-01')), "+
+			"PARTITION p201801 VALUES LESS THAN (TO_DAYS('2018-02-01')), "+
+			"PARTITION pMax VALUES LESS THAN MAXVALUE)")
+}
+
+func TestArchive(t *testing.T) {
+	dbMap, err := sa.NewDbMap(vars.DBConnSAFullPerms, 0)
+	if err != nil {
+		t.Fatalf("Couldn't connect the database: %s", err)
+	}
+	log := blog.UseMock()
+	fc := clock.NewFake()
+	fc.Set(time.Date(2015, 3, 4, 5, 0, 0, 0, time.UTC))
+	ssa, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NewNoopScope(), 1)
+	if err != nil {
+		t.Fatalf("unable to create SQLStorageAuthority: %s", err)
+	}
+	cleanUp := test.ResetSATestDatabase(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, ssa)
+	// Expires 2016-04-14
+	effDER, err := ioutil.ReadFile("../../sa/www.eff.org.der")
+	test.AssertNotError(t, err, "Couldn't read example cert DER")
+	effSerial := "000000000000000000000000000000021bd4"
+	_, err = ssa.AddCertificate(context.Background(), effDER, reg.ID, nil, nil)
+	test.AssertNotError(t, err, "Couldn't add www.eff.org.der")
+
+	expires := fc.Now().Add(time.Hour)
+	pa, err := ssa.NewPendingAuthorization(context.Background(), core.Authorization{
+		RegistrationID: reg.ID,
+		Identifier:     core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "eff.org"},
+		Expires:        &expires,
+		Status:         core.StatusPending,
+		Challenges:     []core.Challenge{{Type: core.ChallengeTypeHTTP01, Token: "a", Status: core.StatusPending}},
+	})
+	test.AssertNotError(t, err, "NewPendingAuthorization failed")
+	pa.Status = core.StatusValid
+	err = ssa.FinalizeAuthorization(context.Background(), pa)
+	test.AssertNotError(t, err, "FinalizeAuthorization failed")
+
+	count := func(table string) int64 {
+		t.Helper()
+		n, err := dbMap.SelectInt("SELECT COUNT(1) FROM " + table)
+		test.AssertNotError(t, err, "dbMap.SelectInt failed")
+		return n
+	}
+
+	a := &expiredArchiver{log: log, clk: fc, db: dbMap, batchSize: 1}
+
+	// Nothing has expired yet
+	for _, set := range archiveSets {
+		n, err := a.archive(set, fc.Now(), 100)
+		test.AssertNotError(t, err, "archive failed")
+		test.AssertEquals(t, n, 0)
+	}
+
+	for _, set := range archiveSets {
+		n, err := a.archive(set, time.Date(2016, 5, 1, 0, 0, 0, 0, time.UTC), 100)
+		test.AssertNotError(t, err, "archive failed")
+		test.AssertEquals(t, n, 1)
+	}
+	for _, table := range []string{"certificates", "certificateStatus", "issuedNames", "authz", "challenges"} {
+		test.AssertEquals(t, count(table), int64(0))
+	}
+	test.AssertEquals(t, count("certificatesArchive"), int64(1))
+	test.AssertEquals(t, count("certificateStatusArchive"), int64(1))
+	test.AssertEquals(t, count("issuedNamesArchive"), int64(3))
+	test.AssertEquals(t, count("authzArchive"), int64(1))
+	test.AssertEquals(t, count("challengesArchive"), int64(1))
+
+	// Archived certificates can still be fetched through the SA
+	cert, err := ssa.GetCertificate(context.Background(), effSerial)
+	test.AssertNotError(t, err, "GetCertificate failed for an archived certificate")
+	test.AssertByteEquals(t, cert.DER, effDER)
+}
+
+func TestArchiveSetsListEveryColumn(t *testing.T) {
+	dbMap, err := sa.NewDbMap(vars.DBConnSAFullPerms, 0)
+	if err != nil {
+		t.Fatalf("Couldn't connect the database: %s", err)
+	}
+
+	// Every column of the archived tables must be copied, or its values are
+	// lost when rows are archived
+	lists := make(map[string]string)
+	for _, set := range archiveSets {
+		lists[set.table] = set.columns
+		for _, dep := range set.dependents {
+			lists[dep.table] = dep.columns
+		}
+	}
+	for table, list := range lists {
+		var columns []string
+		_, err := dbMap.Select(&columns,
+			`SELECT COLUMN_NAME FROM information_schema.COLUMNS
+			 WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?`,
+			table)
+		test.AssertNotError(t, err, "Couldn't read columns of "+table)
+		sort.Strings(columns)
+		listed := strings.Split(list, ", ")
+		sort.Strings(listed)
+		test.AssertDeepEquals(t, listed, columns)
+	}
+}
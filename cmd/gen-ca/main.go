@@ -170,9 +170,27 @@ var AllowedSigAlgs = map[string]x509.SignatureAlgorithm{
 	"ECDSAWithSHA512": x509.ECDSAWithSHA512,
 }
 
+const (
+	// caProfile is the profile type for root and intermediate certificates.
+	caProfile = "ca"
+	// ocspSignerProfile is the profile type for delegated OCSP responder
+	// certificates, which can only sign OCSP responses for their issuer.
+	ocspSignerProfile = "ocsp-signer"
+)
+
+// oidOCSPNoCheck is the id-pkix-ocsp-nocheck extension (RFC 6960, Section
+// 4.2.2.2.1), telling clients not to check the revocation status of a delegated
+// OCSP responder certificate.
+var oidOCSPNoCheck = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 5}
+
 // CertProfile contains the information required to generate a certificate
 // for signing
 type CertProfile struct {
+	// Type is the type of certificate to generate, either "ca" for root and
+	// intermediate certificates or "ocsp-signer" for a delegated OCSP
+	// responder certificate. Defaults to "ca".
+	Type string
+
 	// SignatureAlgorithm should contain one of the allowed signature algorithms
 	// in AllowedSigAlgs
 	SignatureAlgorithm string
@@ -241,6 +259,21 @@ func verifyProfile(profile CertProfile, root bool) error {
 	if profile.Country == "" {
 		return errors.New("Country in profile is required")
 	}
+	switch profile.Type {
+	case "", caProfile:
+	case ocspSignerProfile:
+		if root {
+			return errors.New("ocsp-signer profiles require an issuer")
+		}
+		// The certificate carries id-pkix-ocsp-nocheck, so clients won't
+		// look for revocation information
+		if profile.OCSPURL != "" || profile.CRLURL != "" {
+			return errors.New("OCSPURL and CRLURL are not allowed in ocsp-signer profiles")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown profile type %q", profile.Type)
+	}
 	if !root && profile.OCSPURL == "" {
 		return errors.New("OCSPURL in profile is required for intermediates")
 	}
@@ -319,6 +352,14 @@ func makeTemplate(ctx pkcs11helpers.PKCtx, profile *CertProfile, pubKey []byte,
 		SubjectKeyId:          subjectKeyID[:],
 	}
 
+	if profile.Type == ocspSignerProfile {
+		cert.IsCA = false
+		cert.KeyUsage = x509.KeyUsageDigitalSignature
+		cert.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning}
+		// The extension value is an ASN.1 NULL
+		cert.ExtraExtensions = []pkix.Extension{{Id: oidOCSPNoCheck, Value: []byte{5, 0}}}
+	}
+
 	return cert, nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/x509"
 	"encoding/asn1"
 	"errors"
 	"math/big"
@@ -277,6 +278,35 @@ func TestMakeTemplate(t *testing.T) {
 	test.AssertEquals(t, cert.CRLDistributionPoints[0], profile.CRLURL)
 	test.AssertEquals(t, len(cert.IssuingCertificateURL), 1)
 	test.AssertEquals(t, cert.IssuingCertificateURL[0], profile.IssuerURL)
+	test.Assert(t, cert.IsCA, "CA certificate template is not a CA")
+}
+
+func TestMakeOCSPSignerTemplate(t *testing.T) {
+	ctx := pkcs11helpers.MockCtx{}
+	ctx.GenerateRandomFunc = func(_ pkcs11.SessionHandle, length int) ([]byte, error) {
+		r := make([]byte, length)
+		_, err := rand.Read(r)
+		return r, err
+	}
+	profile := &CertProfile{
+		Type:               "ocsp-signer",
+		SignatureAlgorithm: "SHA256WithRSA",
+		CommonName:         "common name",
+		Organization:       "organization",
+		Country:            "country",
+		NotBefore:          "2018-05-18 11:31:00",
+		NotAfter:           "2018-06-18 11:31:00",
+	}
+	cert, err := makeTemplate(ctx, profile, nil, 0)
+	test.AssertNotError(t, err, "makeTemplate failed for ocsp-signer profile")
+	test.Assert(t, !cert.IsCA, "OCSP signer certificate template is a CA")
+	test.AssertEquals(t, cert.KeyUsage, x509.KeyUsageDigitalSignature)
+	test.AssertDeepEquals(t, cert.ExtKeyUsage, []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning})
+	test.AssertEquals(t, len(cert.ExtraExtensions), 1)
+	test.Assert(t, cert.ExtraExtensions[0].Id.Equal(oidOCSPNoCheck), "Missing id-pkix-ocsp-nocheck extension")
+	test.AssertByteEquals(t, cert.ExtraExtensions[0].Value, []byte{5, 0})
+	test.AssertEquals(t, len(cert.OCSPServer), 0)
+	test.AssertEquals(t, len(cert.CRLDistributionPoints), 0)
 }
 
 func TestVerifyProfile(t *testing.T) {
@@ -385,6 +415,58 @@ func TestVerifyProfile(t *testing.T) {
 			},
 			root: true,
 		},
+		{
+			profile: CertProfile{
+				Type:               "nope",
+				NotBefore:          "a",
+				NotAfter:           "b",
+				SignatureAlgorithm: "c",
+				CommonName:         "d",
+				Organization:       "e",
+				Country:            "f",
+			},
+			root:        true,
+			expectedErr: "unknown profile type \"nope\"",
+		},
+		{
+			profile: CertProfile{
+				Type:               "ocsp-signer",
+				NotBefore:          "a",
+				NotAfter:           "b",
+				SignatureAlgorithm: "c",
+				CommonName:         "d",
+				Organization:       "e",
+				Country:            "f",
+			},
+			root:        true,
+			expectedErr: "ocsp-signer profiles require an issuer",
+		},
+		{
+			profile: CertProfile{
+				Type:               "ocsp-signer",
+				NotBefore:          "a",
+				NotAfter:           "b",
+				SignatureAlgorithm: "c",
+				CommonName:         "d",
+				Organization:       "e",
+				Country:            "f",
+				OCSPURL:            "g",
+			},
+			root:        false,
+			expectedErr: "OCSPURL and CRLURL are not allowed in ocsp-signer profiles",
+		},
+		{
+			profile: CertProfile{
+				Type:               "ocsp-signer",
+				NotBefore:          "a",
+				NotAfter:           "b",
+				SignatureAlgorithm: "c",
+				CommonName:         "d",
+				Organization:       "e",
+				Country:            "f",
+			},
+			root: false,
+		},
 	} {
 		err := verifyProfile(tc.profile, tc.root)
 		if err != nil {
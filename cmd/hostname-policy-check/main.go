@@ -0,0 +1,67 @@
+// hostname-policy-check validates a hostname policy file before it is
+// deployed, and optionally reports whether the PA would issue for names under
+// it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/policy"
+)
+
+func main() {
+	policyFile := flag.String("policy", "", "path to the hostname policy file to check")
+	names := flag.String("names", "", "optional comma separated list of names to check against the policy")
+	account := flag.Int64("account", 0, "registration ID to check the names for")
+	flag.Parse()
+
+	if *policyFile == "" {
+		fmt.Fprintln(os.Stderr, "--policy is required")
+		os.Exit(1)
+	}
+
+	b, err := ioutil.ReadFile(*policyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reading %s: %s\n", *policyFile, err)
+		os.Exit(1)
+	}
+	warnings, err := policy.CheckHostnamePolicy(b, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s is not a valid hostname policy: %s\n", *policyFile, err)
+		os.Exit(1)
+	}
+	for _, warning := range warnings {
+		fmt.Printf("warning: %s\n", warning)
+	}
+	fmt.Printf("%s is a valid hostname policy\n", *policyFile)
+
+	if *names == "" {
+		return
+	}
+	pa, err := policy.New(nil)
+	cmd.FailOnError(err, "creating policy authority")
+	err = pa.SetHostnamePolicyFile(*policyFile)
+	cmd.FailOnError(err, fmt.Sprintf("loading %s", *policyFile))
+
+	rejected := false
+	for _, name := range strings.Split(*names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		ident := core.AcmeIdentifier{Type: core.IdentifierDNS, Value: name}
+		if err := pa.WillingToIssueWildcard(ident, *account); err != nil {
+			fmt.Printf("%s: rejected: %s\n", name, err)
+			rejected = true
+		} else {
+			fmt.Printf("%s: allowed\n", name)
+		}
+	}
+	if rejected {
+		os.Exit(1)
+	}
+}
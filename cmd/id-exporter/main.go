@@ -1,7 +1,6 @@
 package main
 
 import (
-	"database/sql"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -10,18 +9,21 @@ import (
 	"strings"
 	"time"
 
-	"gopkg.in/go-gorp/gorp.v2"
+	"golang.org/x/net/context"
 
 	"github.com/jmhodges/clock"
 	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
 	"github.com/letsencrypt/boulder/features"
+	bgrpc "github.com/letsencrypt/boulder/grpc"
 	blog "github.com/letsencrypt/boulder/log"
-	"github.com/letsencrypt/boulder/sa"
+	"github.com/letsencrypt/boulder/metrics"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
 )
 
 type idExporter struct {
 	log   blog.Logger
-	dbMap *gorp.DbMap
+	saa   core.StorageAdmin
 	clk   clock.Clock
 	grace time.Duration
 }
@@ -30,57 +32,57 @@ type id struct {
 	ID int64 `json:"id"`
 }
 
+// registrations returns the registrations with certificates which are
+// unexpired, or expired within the grace period, optionally limited to the
+// certificates for names.
+func (c idExporter) registrations(ctx context.Context, names []string) ([]*sapb.RegistrationContacts, error) {
+	cutoff := c.clk.Now().Add(-c.grace).UnixNano()
+	return c.saa.RegistrationsWithCertificates(ctx, &sapb.RegistrationsWithCertificatesRequest{
+		ExpiresAfter: &cutoff,
+		Names:        names,
+	})
+}
+
 // Find all registration IDs with unexpired certificates.
-func (c idExporter) findIDs() ([]id, error) {
-	var idsList []id
-	_, err := c.dbMap.Select(
-		&idsList,
-		`SELECT id
-		FROM registrations
-		WHERE contact != 'null' AND
-			id IN (
-				SELECT registrationID
-				FROM certificates
-				WHERE expires >= :expireCutoff
-			);`,
-		map[string]interface{}{
-			"expireCutoff": c.clk.Now().Add(-c.grace),
-		})
+func (c idExporter) findIDs(ctx context.Context) ([]id, error) {
+	regs, err := c.registrations(ctx, nil)
 	if err != nil {
 		c.log.AuditErrf("Error finding IDs: %s", err)
 		return nil, err
 	}
 
+	var idsList []id
+	for _, reg := range regs {
+		// Only registrations with contacts can be notified.
+		if len(reg.Contact) == 0 {
+			continue
+		}
+		idsList = append(idsList, id{ID: *reg.Id})
+	}
 	return idsList, nil
 }
 
-func (c idExporter) findIDsForDomains(domains []string) ([]id, error) {
-	var idsList []id
+func (c idExporter) findIDsForDomains(ctx context.Context, domains []string) ([]id, error) {
+	var names []string
 	for _, domain := range domains {
-		// Pass the same list in each time, gorp will happily just append to the slice
-		// instead of overwriting it each time
-		// https://github.com/go-gorp/gorp/blob/2ae7d174a4cf270240c4561092402affba25da5e/select.go#L348-L355
-		_, err := c.dbMap.Select(
-			&idsList,
-			`SELECT registrationID AS id FROM certificates
-                         WHERE expires >= :expireCutoff AND
-                         serial IN (
-                           SELECT serial FROM issuedNames
-                            WHERE reversedName = :reversedName
-                         )`,
-			map[string]interface{}{
-				"expireCutoff": c.clk.Now().Add(-c.grace),
-				"reversedName": sa.ReverseName(domain),
-			},
-		)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				continue
-			}
-			return nil, err
+		domain := strings.TrimSpace(domain)
+		if domain != "" {
+			names = append(names, domain)
 		}
 	}
+	if len(names) == 0 {
+		return nil, nil
+	}
 
+	regs, err := c.registrations(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	var idsList []id
+	for _, reg := range regs {
+		idsList = append(idsList, id{ID: *reg.Id})
+	}
 	return idsList, nil
 }
 
@@ -147,8 +149,15 @@ func main() {
 	domainsFile := flag.String("domains", "", "If provided only output contacts for certificates that contain at least one of the domains in the provided file. Provided file should contain one domain per line")
 	type config struct {
 		ContactExporter struct {
-			cmd.DBConfig
-			cmd.PasswordConfig
+			// The exporter needs a TLSConfig to set up its gRPC client certs,
+			// but doesn't get the TLS field from ServiceConfig, so declares
+			// its own.
+			TLS cmd.TLSConfig
+
+			// SAAdminService is the SA's admin gRPC API, which is served on its
+			// own address.
+			SAAdminService *cmd.GRPCClientConfig
+
 			Features map[string]bool
 		}
 	}
@@ -176,15 +185,18 @@ func main() {
 	err = features.Set(cfg.ContactExporter.Features)
 	cmd.FailOnError(err, "Failed to set feature flags")
 
-	dbURL, err := cfg.ContactExporter.DBConfig.URL()
-	cmd.FailOnError(err, "Couldn't load DB URL")
-	dbMap, err := sa.NewDbMap(dbURL, 10)
-	cmd.FailOnError(err, "Could not connect to database")
+	tlsConfig, err := cfg.ContactExporter.TLS.Load()
+	cmd.FailOnError(err, "TLS config")
+
+	clk := cmd.Clock()
+	clientMetrics := bgrpc.NewClientMetrics(metrics.NewNoopScope())
+	saAdminConn, err := bgrpc.ClientSetup(cfg.ContactExporter.SAAdminService, tlsConfig, clientMetrics, clk)
+	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA admin API")
 
 	exporter := idExporter{
 		log:   log,
-		dbMap: dbMap,
-		clk:   cmd.Clock(),
+		saa:   bgrpc.NewStorageAuthorityAdminClient(sapb.NewStorageAuthorityAdminClient(saAdminConn)),
+		clk:   clk,
 		grace: *grace,
 	}
 
@@ -192,10 +204,10 @@ func main() {
 	if *domainsFile != "" {
 		df, err := ioutil.ReadFile(*domainsFile)
 		cmd.FailOnError(err, fmt.Sprintf("Could not read domains file %q", *domainsFile))
-		ids, err = exporter.findIDsForDomains(strings.Split(string(df), "\n"))
+		ids, err = exporter.findIDsForDomains(context.Background(), strings.Split(string(df), "\n"))
 		cmd.FailOnError(err, "Could not find IDs")
 	} else {
-		ids, err = exporter.findIDs()
+		ids, err = exporter.findIDs(context.Background())
 		cmd.FailOnError(err, "Could not find IDs")
 	}
 
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"golang.org/x/net/context"
+	"gopkg.in/go-gorp/gorp.v2"
 
 	"github.com/jmhodges/clock"
 	"github.com/letsencrypt/boulder/core"
@@ -50,7 +51,7 @@ func TestFindIDs(t *testing.T) {
 
 	// Run findIDs - since no certificates have been added corresponding to
 	// the above registrations, no IDs should be found.
-	ids, err := testCtx.c.findIDs()
+	ids, err := testCtx.c.findIDs(context.Background())
 	test.AssertNotError(t, err, "findIDs() produced error")
 	test.AssertEquals(t, len(ids), 0)
 
@@ -62,7 +63,7 @@ func TestFindIDs(t *testing.T) {
 	// *not* be present since their certificate has already expired. Unlike
 	// previous versions of this test RegD is not filtered out for having a `tel:`
 	// contact field anymore - this is the duty of the notify-mailer.
-	ids, err = testCtx.c.findIDs()
+	ids, err = testCtx.c.findIDs(context.Background())
 	test.AssertNotError(t, err, "findIDs() produced error")
 	test.AssertEquals(t, len(ids), 3)
 	test.AssertEquals(t, ids[0].ID, regA.ID)
@@ -71,7 +72,7 @@ func TestFindIDs(t *testing.T) {
 
 	// Allow a 1 year grace period
 	testCtx.c.grace = 360 * 24 * time.Hour
-	ids, err = testCtx.c.findIDs()
+	ids, err = testCtx.c.findIDs(context.Background())
 	test.AssertNotError(t, err, "findIDs() produced error")
 	// Now all four registration should be returned, including RegB since its
 	// certificate expired within the grace period
@@ -91,14 +92,14 @@ func TestFindIDsForDomains(t *testing.T) {
 
 	// Run findIDsForDomains - since no certificates have been added corresponding to
 	// the above registrations, no IDs should be found.
-	ids, err := testCtx.c.findIDsForDomains([]string{"example-a.com", "example-b.com", "example-c.com", "example-d.com"})
+	ids, err := testCtx.c.findIDsForDomains(context.Background(), []string{"example-a.com", "example-b.com", "example-c.com", "example-d.com"})
 	test.AssertNotError(t, err, "findIDs() produced error")
 	test.AssertEquals(t, len(ids), 0)
 
 	// Now add some certificates
 	testCtx.addCertificates(t)
 
-	ids, err = testCtx.c.findIDsForDomains([]string{"example-a.com", "example-b.com", "example-c.com", "example-d.com"})
+	ids, err = testCtx.c.findIDsForDomains(context.Background(), []string{"example-a.com", "example-b.com", "example-c.com", "example-d.com"})
 	test.AssertNotError(t, err, "findIDsForDomains() failed")
 	test.AssertEquals(t, len(ids), 3)
 	test.AssertEquals(t, ids[0].ID, regA.ID)
@@ -145,6 +146,7 @@ func TestWriteOutput(t *testing.T) {
 type testCtx struct {
 	c       idExporter
 	ssa     core.StorageAdder
+	dbMap   *gorp.DbMap
 	cleanUp func()
 }
 
@@ -283,9 +285,9 @@ func (ctx testCtx) addCertificates(t *testing.T) {
 		Expires:        rawCertA.NotAfter,
 		DER:            certDerA,
 	}
-	err := ctx.c.dbMap.Insert(certA)
+	err := ctx.dbMap.Insert(certA)
 	test.AssertNotError(t, err, "Couldn't add certA")
-	_, err = ctx.c.dbMap.Exec(
+	_, err = ctx.dbMap.Exec(
 		"INSERT INTO issuedNames (reversedName, serial, notBefore) VALUES (?,?,0)",
 		"com.example-a",
 		serial1String,
@@ -308,9 +310,9 @@ func (ctx testCtx) addCertificates(t *testing.T) {
 		Expires:        rawCertB.NotAfter,
 		DER:            certDerB,
 	}
-	err = ctx.c.dbMap.Insert(certB)
+	err = ctx.dbMap.Insert(certB)
 	test.AssertNotError(t, err, "Couldn't add certB")
-	_, err = ctx.c.dbMap.Exec(
+	_, err = ctx.dbMap.Exec(
 		"INSERT INTO issuedNames (reversedName, serial, notBefore) VALUES (?,?,0)",
 		"com.example-b",
 		serial2String,
@@ -333,9 +335,9 @@ func (ctx testCtx) addCertificates(t *testing.T) {
 		Expires:        rawCertC.NotAfter,
 		DER:            certDerC,
 	}
-	err = ctx.c.dbMap.Insert(certC)
+	err = ctx.dbMap.Insert(certC)
 	test.AssertNotError(t, err, "Couldn't add certC")
-	_, err = ctx.c.dbMap.Exec(
+	_, err = ctx.dbMap.Exec(
 		"INSERT INTO issuedNames (reversedName, serial, notBefore) VALUES (?,?,0)",
 		"com.example-c",
 		serial3String,
@@ -358,9 +360,9 @@ func (ctx testCtx) addCertificates(t *testing.T) {
 		Expires:        rawCertD.NotAfter,
 		DER:            certDerD,
 	}
-	err = ctx.c.dbMap.Insert(certD)
+	err = ctx.dbMap.Insert(certD)
 	test.AssertNotError(t, err, "Couldn't add certD")
-	_, err = ctx.c.dbMap.Exec(
+	_, err = ctx.dbMap.Exec(
 		"INSERT INTO issuedNames (reversedName, serial, notBefore) VALUES (?,?,0)",
 		"com.example-d",
 		serial4String,
@@ -386,11 +388,12 @@ func setup(t *testing.T) testCtx {
 
 	return testCtx{
 		c: idExporter{
-			dbMap: dbMap,
-			log:   log,
-			clk:   fc,
+			saa: ssa,
+			log: log,
+			clk: fc,
 		},
 		ssa:     ssa,
+		dbMap:   dbMap,
 		cleanUp: cleanUp,
 	}
 }
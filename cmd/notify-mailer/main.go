@@ -1,7 +1,6 @@
 package main
 
 import (
-	"database/sql"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -12,18 +11,23 @@ import (
 	"time"
 
 	"github.com/jmhodges/clock"
+	"golang.org/x/net/context"
+
 	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	berrors "github.com/letsencrypt/boulder/errors"
 	"github.com/letsencrypt/boulder/features"
+	bgrpc "github.com/letsencrypt/boulder/grpc"
 	blog "github.com/letsencrypt/boulder/log"
 	bmail "github.com/letsencrypt/boulder/mail"
 	"github.com/letsencrypt/boulder/metrics"
-	"github.com/letsencrypt/boulder/sa"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
 )
 
 type mailer struct {
 	clk           clock.Clock
 	log           blog.Logger
-	dbMap         dbSelector
+	sa            regGetter
 	mailer        bmail.Mailer
 	subject       string
 	emailTemplate string
@@ -41,11 +45,6 @@ type regID struct {
 	ID int
 }
 
-type contactJSON struct {
-	ID      int
-	Contact []byte
-}
-
 func (i *interval) ok() error {
 	if i.start < 0 || i.end < 0 {
 		return fmt.Errorf(
@@ -147,7 +146,7 @@ func (m *mailer) resolveDestinations() ([]string, error) {
 	var contactsList []string
 	for _, c := range regs[m.checkpoint.start:m.checkpoint.end] {
 		// Get the email address for the reg ID
-		emails, err := emailsForReg(c.ID, m.dbMap)
+		emails, err := emailsForReg(context.Background(), c.ID, m.sa)
 		if err != nil {
 			return nil, err
 		}
@@ -162,38 +161,28 @@ func (m *mailer) resolveDestinations() ([]string, error) {
 	return contactsList, nil
 }
 
-// Since the only thing we use from gorp is the SelectOne method on the
-// gorp.DbMap object, we just define an interface with that method
-// instead of importing all of gorp. This facilitates mock implementations for
-// unit tests
-type dbSelector interface {
-	SelectOne(holder interface{}, query string, args ...interface{}) error
+// Since the only thing we use from the SA is the GetRegistration method, we
+// just define an interface with that method instead of requiring all of
+// core.StorageGetter. This facilitates mock implementations for unit tests
+type regGetter interface {
+	GetRegistration(ctx context.Context, regID int64) (core.Registration, error)
 }
 
 // Finds the email addresses associated with a reg ID
-func emailsForReg(id int, dbMap dbSelector) ([]string, error) {
-	var contact contactJSON
-	err := dbMap.SelectOne(&contact,
-		`SELECT id, contact
-		FROM registrations
-		WHERE contact != 'null' AND id = :id;`,
-		map[string]interface{}{
-			"id": id,
-		})
-	if err == sql.ErrNoRows {
+func emailsForReg(ctx context.Context, id int, sa regGetter) ([]string, error) {
+	reg, err := sa.GetRegistration(ctx, int64(id))
+	if berrors.Is(err, berrors.NotFound) {
 		return []string{}, nil
 	}
 	if err != nil {
 		return nil, err
 	}
+	if reg.Contact == nil {
+		return []string{}, nil
+	}
 
-	var contactFields []string
 	var addresses []string
-	err = json.Unmarshal(contact.Contact, &contactFields)
-	if err != nil {
-		return nil, err
-	}
-	for _, entry := range contactFields {
+	for _, entry := range *reg.Contact {
 		if strings.HasPrefix(entry, "mailto:") {
 			addresses = append(addresses, strings.TrimPrefix(entry, "mailto:"))
 		}
@@ -284,10 +273,17 @@ func main() {
 	reconnMax := flag.Duration("reconnectMax", 5*60*time.Second, "Max sleep duration between reconnect attempts after exponential backoff")
 	type config struct {
 		NotifyMailer struct {
-			cmd.DBConfig
 			cmd.PasswordConfig
 			cmd.SMTPConfig
 			Features map[string]bool
+
+			// The SA resolves registration IDs to contacts and holds the
+			// suppression list.
+			TLS       cmd.TLSConfig
+			SAService *cmd.GRPCClientConfig
+
+			// Unsubscribe, if set, adds an unsubscribe link to each email.
+			Unsubscribe *cmd.UnsubscribeConfig
 		}
 		Syslog cmd.SyslogConfig
 	}
@@ -316,10 +312,15 @@ func main() {
 	log := cmd.NewLogger(cfg.Syslog)
 	defer log.AuditPanic()
 
-	dbURL, err := cfg.NotifyMailer.DBConfig.URL()
-	cmd.FailOnError(err, "Couldn't load DB URL")
-	dbMap, err := sa.NewDbMap(dbURL, 10)
-	cmd.FailOnError(err, "Could not connect to database")
+	if cfg.NotifyMailer.SAService == nil {
+		cmd.Fail("saService must be configured")
+	}
+	tlsConfig, err := cfg.NotifyMailer.TLS.Load()
+	cmd.FailOnError(err, "TLS config")
+	clk := cmd.Clock()
+	conn, err := bgrpc.ClientSetup(cfg.NotifyMailer.SAService, tlsConfig, bgrpc.NewClientMetrics(metrics.NewNoopScope()), clk)
+	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA")
+	sac := bgrpc.NewStorageAuthorityClient(sapb.NewStorageAuthorityClient(conn))
 
 	// Load email body
 	body, err := ioutil.ReadFile(*bodyFile)
@@ -336,7 +337,7 @@ func main() {
 		end:   *end,
 	}
 
-	var mailClient bmail.Mailer
+	var mailClient *bmail.MailerImpl
 	if *dryRun {
 		mailClient = bmail.NewDryRun(*address, log)
 	} else {
@@ -353,12 +354,18 @@ func main() {
 			metrics.NewNoopScope(),
 			*reconnBase,
 			*reconnMax)
+		mailClient.SetSuppressionList(sac)
+	}
+	if cfg.NotifyMailer.Unsubscribe != nil {
+		key, err := cfg.NotifyMailer.Unsubscribe.Key()
+		cmd.FailOnError(err, "Couldn't load unsubscribe key")
+		mailClient.SetUnsubscribeURL(cfg.NotifyMailer.Unsubscribe.URL, key)
 	}
 
 	m := mailer{
-		clk:           cmd.Clock(),
+		clk:           clk,
 		log:           log,
-		dbMap:         dbMap,
+		sa:            sac,
 		mailer:        mailClient,
 		subject:       *subject,
 		destinations:  toBody,
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -9,7 +8,10 @@ import (
 	"time"
 
 	"github.com/jmhodges/clock"
+	"golang.org/x/net/context"
 
+	"github.com/letsencrypt/boulder/core"
+	berrors "github.com/letsencrypt/boulder/errors"
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/mocks"
 	"github.com/letsencrypt/boulder/test"
@@ -53,7 +55,7 @@ func TestSleepInterval(t *testing.T) {
 	const sleepLen = 10
 	const numMessages = 3
 	mc := &mocks.Mailer{}
-	dbMap := mockEmailResolver{}
+	sa := mockEmailResolver{}
 
 	testDestinationsBody, err := ioutil.ReadFile("testdata/test_msg_recipients.txt")
 	test.AssertNotError(t, err, "failed to read testdata/test_msg_recipients.txt")
@@ -67,7 +69,7 @@ func TestSleepInterval(t *testing.T) {
 		checkpoint:    interval{start: 0, end: numMessages},
 		clk:           newFakeClock(t),
 		destinations:  testDestinationsBody,
-		dbMap:         dbMap,
+		sa:            sa,
 	}
 
 	// Call run() - this should sleep `sleepLen` per destination address
@@ -88,7 +90,7 @@ func TestSleepInterval(t *testing.T) {
 		checkpoint:    interval{start: 0, end: 3},
 		clk:           newFakeClock(t),
 		destinations:  testDestinationsBody,
-		dbMap:         dbMap,
+		sa:            sa,
 	}
 
 	// Call run() - this should blast through all destinations without sleep
@@ -101,7 +103,7 @@ func TestSleepInterval(t *testing.T) {
 
 func TestMailCheckpointing(t *testing.T) {
 	const testSubject = "Test Subject"
-	dbMap := mockEmailResolver{}
+	sa := mockEmailResolver{}
 
 	testDestinationsBody, err := ioutil.ReadFile("testdata/test_msg_recipients.txt")
 	test.AssertNotError(t, err, "failed to read testdata/test_msg_recipients.txt")
@@ -115,7 +117,7 @@ func TestMailCheckpointing(t *testing.T) {
 	m := &mailer{
 		log:           blog.UseMock(),
 		mailer:        mc,
-		dbMap:         dbMap,
+		sa:            sa,
 		subject:       testSubject,
 		destinations:  testDestinationsBody,
 		emailTemplate: string(testBody),
@@ -134,7 +136,7 @@ func TestMailCheckpointing(t *testing.T) {
 	m = &mailer{
 		log:           blog.UseMock(),
 		mailer:        mc,
-		dbMap:         dbMap,
+		sa:            sa,
 		subject:       testSubject,
 		destinations:  testDestinationsBody,
 		emailTemplate: string(testBody),
@@ -154,7 +156,7 @@ func TestMailCheckpointing(t *testing.T) {
 	m = &mailer{
 		log:           blog.UseMock(),
 		mailer:        mc,
-		dbMap:         dbMap,
+		sa:            sa,
 		subject:       testSubject,
 		destinations:  testDestinationsBody,
 		emailTemplate: string(testBody),
@@ -190,7 +192,7 @@ func TestMailCheckpointing(t *testing.T) {
 	m = &mailer{
 		log:           blog.UseMock(),
 		mailer:        mc,
-		dbMap:         dbMap,
+		sa:            sa,
 		subject:       testSubject,
 		destinations:  testDestinationsBody,
 		emailTemplate: string(testBody),
@@ -227,7 +229,7 @@ func TestMailCheckpointing(t *testing.T) {
 	m = &mailer{
 		log:           blog.UseMock(),
 		mailer:        mc,
-		dbMap:         dbMap,
+		sa:            sa,
 		subject:       testSubject,
 		destinations:  testDestinationsBody,
 		emailTemplate: string(testBody),
@@ -268,12 +270,12 @@ func TestMessageContent(t *testing.T) {
 	testBody, err := ioutil.ReadFile("testdata/test_msg_body.txt")
 	test.AssertNotError(t, err, "failed to read testdata/test_msg_body.txt")
 
-	dbMap := mockEmailResolver{}
+	sa := mockEmailResolver{}
 	mc := &mocks.Mailer{}
 	m := &mailer{
 		log:           blog.UseMock(),
 		mailer:        mc,
-		dbMap:         dbMap,
+		sa:            sa,
 		subject:       testSubject,
 		destinations:  testDestinationsBody,
 		emailTemplate: string(testBody),
@@ -294,76 +296,36 @@ func TestMessageContent(t *testing.T) {
 	}, mc.Messages[0])
 }
 
-// the `mockEmailResolver` implements the `dbSelector` interface from
+// the `mockEmailResolver` implements the `regGetter` interface from
 // `notify-mailer/main.go` to allow unit testing without using a backing
-// database
+// SA
 type mockEmailResolver struct{}
 
-// the `mockEmailResolver` select method treats the requested reg ID as an index
-// into a list of anonymous structs
-func (bs mockEmailResolver) SelectOne(output interface{}, _ string, args ...interface{}) error {
+// the `mockEmailResolver` GetRegistration method treats the requested reg ID
+// as an index into a list of contacts
+func (bs mockEmailResolver) GetRegistration(_ context.Context, id int64) (core.Registration, error) {
 	// The "db" is just a list in memory
-	db := []contactJSON{
-		{
-			ID:      1,
-			Contact: []byte(`["mailto:example@example.com"]`),
-		},
-		{
-			ID:      2,
-			Contact: []byte(`["mailto:test-example-updated@example.com"]`),
-		},
-		{
-			ID:      3,
-			Contact: []byte(`["mailto:test-test-test@example.com"]`),
-		},
-		{
-			ID:      4,
-			Contact: []byte(`["mailto:example-example-example@example.com"]`),
-		},
-		{
-			ID:      5,
-			Contact: []byte(`["mailto:youve.got.mail@example.com"]`),
-		},
-		{
-			ID:      6,
-			Contact: []byte(`["mailto:mail@example.com"]`),
-		},
-	}
-
-	// Play the type cast game so that we can dig into the arguments map and get
-	// out an integer "id" parameter
-	argsRaw := args[0]
-	argsMap, ok := argsRaw.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("incorrect args type %T", args)
-	}
-	idRaw := argsMap["id"]
-	id, ok := idRaw.(int)
-	if !ok {
-		return fmt.Errorf("incorrect args ID type %T", id)
-	}
-
-	// Play the type cast game to get a pointer to the output `contactJSON`
-	// pointer so we can write the result from the db list
-	outputPtr, ok := output.(*contactJSON)
-	if !ok {
-		return fmt.Errorf("incorrect output type %T", output)
+	db := []string{
+		"mailto:example@example.com",
+		"mailto:test-example-updated@example.com",
+		"mailto:test-test-test@example.com",
+		"mailto:example-example-example@example.com",
+		"mailto:youve.got.mail@example.com",
+		"mailto:mail@example.com",
 	}
 
 	// If the ID (shifted by 1 to account for zero indexing) is within the range
-	// of the DB list, return the DB entry by assigning to the `outputPtr`.
-	// Otherwise, return that no rows were found
+	// of the DB list, return a registration with that contact. Otherwise,
+	// return that the registration wasn't found
 	if (id-1) >= 0 && int(id-1) < len(db) {
-		*outputPtr = db[id-1]
-	} else {
-		return sql.ErrNoRows
+		return core.Registration{ID: id, Contact: &[]string{db[id-1]}}, nil
 	}
-	return nil
+	return core.Registration{}, berrors.NotFoundError("no registration with ID %d", id)
 }
 
 func TestResolveEmails(t *testing.T) {
 	// Start with three reg. IDs. Note: the IDs have been matched with fake
-	// results in the `db` slice in `mockEmailResolver`'s `SelectOne`. If you add
+	// results in the `db` slice in `mockEmailResolver`'s `GetRegistration`. If you add
 	// more test cases here you must also add the corresponding DB result in the
 	// mock.
 	regs := []regID{
@@ -385,12 +347,12 @@ func TestResolveEmails(t *testing.T) {
 	contactsJSON, err := json.Marshal(regs)
 	test.AssertNotError(t, err, "failed to marshal test regs")
 
-	dbMap := mockEmailResolver{}
+	sa := mockEmailResolver{}
 	mc := &mocks.Mailer{}
 	m := &mailer{
 		log:           blog.UseMock(),
 		mailer:        mc,
-		dbMap:         dbMap,
+		sa:            sa,
 		subject:       "Test",
 		destinations:  contactsJSON,
 		emailTemplate: "Hi",
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/letsencrypt/boulder/cmd"
+)
+
+// requestHashes are the hash algorithms an OCSP request may use to identify
+// its issuer by IssuerNameHash and IssuerKeyHash.
+var requestHashes = []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA384, crypto.SHA512}
+
+type issuerHashes struct {
+	nameHash []byte
+	keyHash  []byte
+}
+
+// issuer is a CA certificate the responder serves OCSP responses for. It holds
+// the name and key hashes of the certificate for each supported hash algorithm
+// so that requests can be routed without hashing on every request.
+type issuer struct {
+	// name identifies the issuer in logs and metrics.
+	name   string
+	hashes map[crypto.Hash]issuerHashes
+	cert   *x509.Certificate
+}
+
+func newIssuer(cert *x509.Certificate) (*issuer, error) {
+	// The key hash is computed over the subjectPublicKey BIT STRING only, not
+	// the whole SubjectPublicKeyInfo (RFC 6960, Section 4.1.1).
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(cert.RawSubjectPublicKeyInfo, &spki); err != nil {
+		return nil, fmt.Errorf("parsing issuer public key: %s", err)
+	}
+
+	iss := &issuer{
+		name:   cert.Subject.CommonName,
+		hashes: make(map[crypto.Hash]issuerHashes),
+		cert:   cert,
+	}
+	for _, h := range requestHashes {
+		nameHash := h.New()
+		nameHash.Write(cert.RawSubject)
+		keyHash := h.New()
+		keyHash.Write(spki.PublicKey.RightAlign())
+		iss.hashes[h] = issuerHashes{
+			nameHash: nameHash.Sum(nil),
+			keyHash:  keyHash.Sum(nil),
+		}
+	}
+	if iss.name == "" {
+		iss.name = hex.EncodeToString(iss.hashes[crypto.SHA1].keyHash)
+	}
+	return iss, nil
+}
+
+// matches returns true if the request's IssuerNameHash and IssuerKeyHash both
+// identify this issuer.
+func (iss *issuer) matches(req *ocsp.Request) bool {
+	hashes, ok := iss.hashes[req.HashAlgorithm]
+	if !ok {
+		return false
+	}
+	return bytes.Equal(req.IssuerKeyHash, hashes.keyHash) &&
+		bytes.Equal(req.IssuerNameHash, hashes.nameHash)
+}
+
+// signed returns true if the OCSP response was signed by this issuer, directly
+// or by a delegated responder certificate it issued. Responses are looked up by
+// serial alone, so this stops a response for another issuer's certificate from
+// being served to a request naming this issuer.
+func (iss *issuer) signed(der []byte) bool {
+	resp, err := ocsp.ParseResponse(der, iss.cert)
+	if err != nil {
+		return false
+	}
+	// Issuers may share a key, so the signature alone doesn't tell them
+	// apart. Also check the responder is named by, or was issued by, this
+	// issuer.
+	if resp.Certificate != nil {
+		return bytes.Equal(resp.Certificate.RawIssuer, iss.cert.RawSubject)
+	}
+	if resp.RawResponderName != nil {
+		return bytes.Equal(resp.RawResponderName, iss.cert.RawSubject)
+	}
+	return bytes.Equal(resp.ResponderKeyHash, iss.hashes[crypto.SHA1].keyHash)
+}
+
+// loadIssuers reads the issuer certificates at the given paths.
+func loadIssuers(paths []string) ([]*issuer, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no issuer certificates configured")
+	}
+	var issuers []*issuer
+	for _, path := range paths {
+		der, err := cmd.LoadCert(path)
+		if err != nil {
+			return nil, fmt.Errorf("Could not read issuer cert %s: %s", path, err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse issuer cert %s: %s", path, err)
+		}
+		iss, err := newIssuer(cert)
+		if err != nil {
+			return nil, fmt.Errorf("Could not load issuer cert %s: %s", path, err)
+		}
+		issuers = append(issuers, iss)
+	}
+	return issuers, nil
+}
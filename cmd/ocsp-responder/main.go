@@ -1,9 +1,9 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"crypto/x509"
+	"crypto/sha256"
+	"crypto/tls"
 	"database/sql"
 	"encoding/hex"
 	"flag"
@@ -15,15 +15,20 @@ import (
 	"time"
 
 	cfocsp "github.com/cloudflare/cfssl/ocsp"
+	"github.com/jmhodges/clock"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/crypto/ocsp"
 
+	capb "github.com/letsencrypt/boulder/ca/proto"
 	"github.com/letsencrypt/boulder/cmd"
 	"github.com/letsencrypt/boulder/core"
 	"github.com/letsencrypt/boulder/features"
+	bgrpc "github.com/letsencrypt/boulder/grpc"
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/metrics"
 	"github.com/letsencrypt/boulder/metrics/measured_http"
+	"github.com/letsencrypt/boulder/ocspstore"
+	"github.com/letsencrypt/boulder/revocation"
 	"github.com/letsencrypt/boulder/sa"
 )
 
@@ -43,9 +48,19 @@ serialNumber field, since we will always query on it.
 
 */
 type DBSource struct {
-	dbMap     dbSelector
-	caKeyHash []byte
-	log       blog.Logger
+	dbMap   dbSelector
+	issuers []*issuer
+	log     blog.Logger
+	clk     clock.Clock
+	// maxAge, if non-zero, caps the max-age of the Cache-Control header.
+	maxAge time.Duration
+
+	// signer, if not nil, is used to get a fresh response from the CA when the
+	// stored response is missing or past its nextUpdate.
+	signer *onDemandSigner
+
+	staleServed prometheus.Counter
+	responses   *prometheus.CounterVec
 }
 
 // Since the only thing we use from gorp is the SelectOne method on the
@@ -57,70 +72,192 @@ type dbSelector interface {
 }
 
 // NewSourceFromDatabase produces a DBSource representing the binding of a
-// given DB schema to a CA key.
-func NewSourceFromDatabase(dbMap dbSelector, caKeyHash []byte, log blog.Logger) (src *DBSource, err error) {
-	src = &DBSource{dbMap: dbMap, caKeyHash: caKeyHash, log: log}
+// given DB schema to a set of issuers.
+func NewSourceFromDatabase(
+	dbMap dbSelector,
+	issuers []*issuer,
+	maxAge time.Duration,
+	signer *onDemandSigner,
+	scope metrics.Scope,
+	clk clock.Clock,
+	log blog.Logger,
+) (src *DBSource, err error) {
+	staleServed := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "ocsp_served_stale",
+		Help: "Number of OCSP responses served after their nextUpdate had passed",
+	})
+	scope.MustRegister(staleServed)
+	responses := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ocsp_responses",
+			Help: "Number of OCSP requests answered, by issuer and result",
+		},
+		[]string{"issuer", "result"})
+	scope.MustRegister(responses)
+	src = &DBSource{
+		dbMap:       dbMap,
+		issuers:     issuers,
+		maxAge:      maxAge,
+		log:         log,
+		clk:         clk,
+		signer:      signer,
+		staleServed: staleServed,
+		responses:   responses,
+	}
 	return
 }
 
 type dbResponse struct {
 	OCSPResponse    []byte
 	OCSPLastUpdated time.Time
+	Status          core.OCSPStatus
+	RevokedReason   revocation.Reason
+	RevokedDate     time.Time
+}
+
+// isExpired returns true if the given OCSP response's nextUpdate is at or
+// before now. Responses that can't be parsed or that have no nextUpdate are
+// not considered expired, so they are served as they were before.
+func isExpired(der []byte, now time.Time) bool {
+	parsed, err := ocsp.ParseResponse(der, nil)
+	if err != nil || parsed.NextUpdate.IsZero() {
+		return false
+	}
+	return !now.Before(parsed.NextUpdate)
+}
+
+// findIssuer returns the issuer the request is for, or nil if it is for an
+// issuer this responder doesn't serve.
+func (src *DBSource) findIssuer(req *ocsp.Request) *issuer {
+	for _, iss := range src.issuers {
+		if iss.matches(req) {
+			return iss
+		}
+	}
+	return nil
+}
+
+// cacheHeaders returns the Cache-Control, Expires, Last-Modified and ETag
+// headers for an OCSP response, computed from its thisUpdate and nextUpdate,
+// and a Surrogate-Key header with the certificate serial so CDNs that support
+// it can purge every cached copy of the response at once. The same headers are
+// used for GET and POST requests. Responses that can't be parsed get no
+// headers, leaving the responder's defaults in place.
+func (src *DBSource) cacheHeaders(der []byte) http.Header {
+	parsed, err := ocsp.ParseResponse(der, nil)
+	if err != nil {
+		return nil
+	}
+	maxAge := parsed.NextUpdate.Sub(src.clk.Now())
+	if src.maxAge > 0 && maxAge > src.maxAge {
+		maxAge = src.maxAge
+	}
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	headers := make(http.Header)
+	headers.Set("Cache-Control", fmt.Sprintf("max-age=%d, public, no-transform, must-revalidate", maxAge/time.Second))
+	headers.Set("Last-Modified", parsed.ThisUpdate.UTC().Format(http.TimeFormat))
+	headers.Set("Expires", parsed.NextUpdate.UTC().Format(http.TimeFormat))
+	headers.Set("ETag", fmt.Sprintf("\"%X\"", sha256.Sum256(der)))
+	headers.Set("Surrogate-Key", core.SerialToString(parsed.SerialNumber))
+	return headers
 }
 
 // Response is called by the HTTP server to handle a new OCSP request.
 func (src *DBSource) Response(req *ocsp.Request) ([]byte, http.Header, error) {
-	// Check that this request is for the proper CA
-	if bytes.Compare(req.IssuerKeyHash, src.caKeyHash) != 0 {
-		src.log.Debugf("Request intended for CA Cert ID: %s", hex.EncodeToString(req.IssuerKeyHash))
+	// Check that this request is for one of our issuers. The responder replies
+	// to requests for any other issuer with "unauthorized".
+	iss := src.findIssuer(req)
+	if iss == nil {
+		src.log.Debugf("Request intended for unknown CA Cert ID: %s", hex.EncodeToString(req.IssuerKeyHash))
+		src.responses.With(prometheus.Labels{"issuer": "unknown", "result": "unknown_issuer"}).Inc()
 		return nil, nil, cfocsp.ErrNotFound
 	}
 
 	serialString := core.SerialToString(req.SerialNumber)
-	src.log.Debugf("Searching for OCSP issued by us for serial %s", serialString)
+	src.log.Debugf("Searching for OCSP issued by %s for serial %s", iss.name, serialString)
+
+	der, err := src.response(iss, serialString)
+	if err == nil && !iss.signed(der) {
+		src.log.Debugf("OCSP Response for Serial=%s not signed by CA=%s", serialString, iss.name)
+		src.responses.With(prometheus.Labels{"issuer": iss.name, "result": "wrong_issuer"}).Inc()
+		return nil, nil, cfocsp.ErrNotFound
+	}
+	switch err {
+	case nil:
+		src.responses.With(prometheus.Labels{"issuer": iss.name, "result": "found"}).Inc()
+		src.log.Debugf("OCSP Response sent for CA=%s, Serial=%s", iss.name, serialString)
+		return der, src.cacheHeaders(der), nil
+	case cfocsp.ErrNotFound:
+		src.responses.With(prometheus.Labels{"issuer": iss.name, "result": "not_found"}).Inc()
+	default:
+		src.responses.With(prometheus.Labels{"issuer": iss.name, "result": "error"}).Inc()
+	}
+	return nil, nil, err
+}
 
+// response looks up the OCSP response for a serial issued by iss.
+func (src *DBSource) response(iss *issuer, serialString string) ([]byte, error) {
 	var response dbResponse
-	defer func() {
-		if len(response.OCSPResponse) != 0 {
-			src.log.Debugf("OCSP Response sent for CA=%s, Serial=%s", hex.EncodeToString(src.caKeyHash), serialString)
-		}
-	}()
 	err := src.dbMap.SelectOne(
 		&response,
-		"SELECT ocspResponse, ocspLastUpdated FROM certificateStatus WHERE serial = :serial",
+		"SELECT ocspResponse, ocspLastUpdated, status, revokedReason, revokedDate FROM certificateStatus WHERE serial = :serial",
 		map[string]interface{}{"serial": serialString},
 	)
 	if err == sql.ErrNoRows {
-		return nil, nil, cfocsp.ErrNotFound
+		return nil, cfocsp.ErrNotFound
 	}
 	if err != nil {
 		src.log.AuditErrf("Looking up OCSP response: %s", err)
-		return nil, nil, err
+		return nil, err
 	}
 	if response.OCSPLastUpdated.IsZero() {
-		src.log.Debugf("OCSP Response not sent (ocspLastUpdated is zero) for CA=%s, Serial=%s", hex.EncodeToString(src.caKeyHash), serialString)
-		return nil, nil, cfocsp.ErrNotFound
+		if src.signer != nil {
+			der, err := src.signer.sign(serialString, response)
+			if err == nil {
+				return der, nil
+			}
+			src.log.Warningf("Signing missing OCSP response on demand for Serial=%s: %s", serialString, err)
+		}
+		src.log.Debugf("OCSP Response not sent (ocspLastUpdated is zero) for CA=%s, Serial=%s", iss.name, serialString)
+		return nil, cfocsp.ErrNotFound
+	}
+
+	if isExpired(response.OCSPResponse, src.clk.Now()) {
+		if src.signer != nil {
+			der, err := src.signer.sign(serialString, response)
+			if err == nil {
+				return der, nil
+			}
+			src.log.Warningf("Signing expired OCSP response on demand for Serial=%s: %s", serialString, err)
+		}
+		// Serving the expired response is no worse than serving nothing, and
+		// keeps clients that don't check nextUpdate working until the updater
+		// catches up.
+		src.staleServed.Inc()
 	}
 
-	return response.OCSPResponse, nil, nil
+	return response.OCSPResponse, nil
 }
 
-func makeDBSource(dbMap dbSelector, issuerCert string, log blog.Logger) (*DBSource, error) {
-	// Load the CA's key so we can store its SubjectKey in the DB
-	caCertDER, err := cmd.LoadCert(issuerCert)
+func makeDBSource(
+	dbMap dbSelector,
+	issuerCerts []string,
+	maxAge time.Duration,
+	signer *onDemandSigner,
+	scope metrics.Scope,
+	clk clock.Clock,
+	log blog.Logger,
+) (*DBSource, error) {
+	// Load the CA certificates so we can recognize requests for them
+	issuers, err := loadIssuers(issuerCerts)
 	if err != nil {
-		return nil, fmt.Errorf("Could not read issuer cert %s: %s", issuerCert, err)
-	}
-	caCert, err := x509.ParseCertificate(caCertDER)
-	if err != nil {
-		return nil, fmt.Errorf("Could not parse issuer cert %s: %s", issuerCert, err)
-	}
-	if len(caCert.SubjectKeyId) == 0 {
-		return nil, fmt.Errorf("Empty subjectKeyID")
+		return nil, err
 	}
 
 	// Construct source from DB
-	return NewSourceFromDatabase(dbMap, caCert.SubjectKeyId, log)
+	return NewSourceFromDatabase(dbMap, issuers, maxAge, signer, scope, clk, log)
 }
 
 type config struct {
@@ -137,11 +274,32 @@ type config struct {
 		Path          string
 		ListenAddress string
 		// MaxAge is the max-age to set in the Cache-Control response
-		// header. It is a time.Duration formatted string.
+		// header. It is a time.Duration formatted string. Responses are
+		// never cached past their nextUpdate, so the max-age is lower for
+		// responses that expire sooner.
 		MaxAge cmd.ConfigDuration
 
+		// IssuerCerts lists the issuer certificates whose OCSP requests
+		// this responder answers. Requests for other issuers get an
+		// "unauthorized" response. If empty, Common.IssuerCert is used.
+		IssuerCerts []string
+
 		ShutdownStopTimeout cmd.ConfigDuration
 
+		// When OCSPGeneratorService is configured, responses that are missing
+		// or past their nextUpdate are signed on demand by the CA instead of
+		// being served stale. At most MaxInFlightSigning requests are sent to
+		// the CA at once, and up to SigningCacheSize signed responses are
+		// kept in memory until their nextUpdate.
+		TLS                  cmd.TLSConfig
+		OCSPGeneratorService *cmd.GRPCClientConfig
+		MaxInFlightSigning   int
+		SigningCacheSize     int
+
+		// Redis, if set, is checked for a response written by the
+		// ocsp-updater before falling back to the database.
+		Redis *cmd.RedisConfig
+
 		Features map[string]bool
 	}
 
@@ -152,6 +310,20 @@ type config struct {
 	}
 }
 
+// setupOCSPGenerator returns a CA client that is only capable of signing OCSP.
+func setupOCSPGenerator(c *cmd.GRPCClientConfig, tlsConfig cmd.TLSConfig, scope metrics.Scope, clk clock.Clock) ocspGenerator {
+	var tls *tls.Config
+	var err error
+	if tlsConfig.CertFile != nil {
+		tls, err = tlsConfig.Load()
+		cmd.FailOnError(err, "TLS config")
+	}
+	clientMetrics := bgrpc.NewClientMetrics(scope)
+	caConn, err := bgrpc.ClientSetup(c, tls, clientMetrics, clk)
+	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to CA")
+	return bgrpc.NewCertificateAuthorityClient(nil, capb.NewOCSPGeneratorClient(caConn))
+}
+
 func main() {
 	configFile := flag.String("config", "", "File path to the configuration file for this service")
 	flag.Parse()
@@ -196,13 +368,44 @@ as generated by Boulder's single-ocsp command.
 		if dbConnect == "" {
 			dbConnect = config.Source
 		}
-		logger.Infof("Loading OCSP Database for CA Cert: %s", c.Common.IssuerCert)
+		issuerCerts := config.IssuerCerts
+		if len(issuerCerts) == 0 {
+			issuerCerts = []string{c.Common.IssuerCert}
+		}
+		logger.Infof("Loading OCSP Database for CA Certs: %s", strings.Join(issuerCerts, ", "))
 		dbMap, err := sa.NewDbMap(dbConnect, config.DBConfig.MaxDBConns)
 		cmd.FailOnError(err, "Could not connect to database")
 		sa.SetSQLDebug(dbMap, logger)
 		go sa.ReportDbConnCount(dbMap, scope)
-		source, err = makeDBSource(dbMap, c.Common.IssuerCert, logger)
+		clk := cmd.Clock()
+		var signer *onDemandSigner
+		if config.OCSPGeneratorService != nil {
+			signer = newOnDemandSigner(
+				setupOCSPGenerator(config.OCSPGeneratorService, config.TLS, scope, clk),
+				dbMap,
+				config.MaxInFlightSigning,
+				config.SigningCacheSize,
+				config.OCSPGeneratorService.Timeout.Duration,
+				clk,
+				scope,
+			)
+		}
+		dbSource, err := makeDBSource(dbMap, issuerCerts, config.MaxAge.Duration, signer, scope, clk, logger)
 		cmd.FailOnError(err, "Couldn't load OCSP DB")
+		source = dbSource
+		if config.Redis != nil {
+			password, err := config.Redis.Pass()
+			cmd.FailOnError(err, "Failed to load Redis password")
+			store, err := ocspstore.NewRedisStore(
+				config.Redis.Address,
+				password,
+				config.Redis.Timeout.Duration,
+				config.Redis.PoolSize,
+				scope,
+			)
+			cmd.FailOnError(err, "Failed to configure Redis")
+			source = newStoreSource(store, dbSource, scope, logger)
+		}
 		// Export the MaxDBConns
 		dbConnStat := prometheus.NewGauge(prometheus.GaugeOpts{
 			Name: "max_db_connections",
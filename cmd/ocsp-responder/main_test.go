@@ -2,6 +2,9 @@ package main
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
@@ -11,17 +14,24 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/crypto/ocsp"
 
 	cfocsp "github.com/cloudflare/cfssl/ocsp"
+	"github.com/letsencrypt/boulder/core"
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/metrics"
 	"github.com/letsencrypt/boulder/test"
 )
 
 var (
-	req   = mustRead("./testdata/ocsp.req")
-	resp  = dbResponse{mustRead("./testdata/ocsp.resp"), time.Now()}
+	req  = mustRead("./testdata/ocsp.req")
+	resp = dbResponse{
+		OCSPResponse:    mustRead("./testdata/ocsp.resp"),
+		OCSPLastUpdated: time.Now(),
+		Status:          core.OCSPStatusGood,
+	}
 	stats = metrics.NewNoopScope()
 )
 
@@ -71,7 +81,7 @@ func TestMux(t *testing.T) {
 }
 
 func TestDBHandler(t *testing.T) {
-	src, err := makeDBSource(mockSelector{}, "./testdata/test-ca.der.pem", blog.NewMock())
+	src, err := makeDBSource(mockSelector{}, []string{"./testdata/test-ca.der.pem"}, 0, nil, stats, clock.NewFake(), blog.NewMock())
 	if err != nil {
 		t.Fatalf("makeDBSource: %s", err)
 	}
@@ -127,7 +137,7 @@ func (bs brokenSelector) SelectOne(_ interface{}, _ string, _ ...interface{}) er
 
 func TestErrorLog(t *testing.T) {
 	mockLog := blog.NewMock()
-	src, err := makeDBSource(brokenSelector{}, "./testdata/test-ca.der.pem", mockLog)
+	src, err := makeDBSource(brokenSelector{}, []string{"./testdata/test-ca.der.pem"}, 0, nil, stats, clock.NewFake(), mockLog)
 	test.AssertNotError(t, err, "Failed to create broken dbMap")
 
 	ocspReq, err := ocsp.ParseRequest(req)
@@ -150,3 +160,113 @@ func mustRead(path string) []byte {
 	}
 	return b
 }
+
+func TestMultipleIssuers(t *testing.T) {
+	src, err := makeDBSource(
+		mockSelector{},
+		[]string{"./testdata/test-ca.der.pem", "../../test/test-ca2.pem"},
+		0, nil, metrics.NewNoopScope(), clock.NewFake(), blog.NewMock())
+	test.AssertNotError(t, err, "makeDBSource failed")
+	test.AssertEquals(t, len(src.issuers), 2)
+
+	ocspReq, err := ocsp.ParseRequest(req)
+	test.AssertNotError(t, err, "Failed to parse OCSP request")
+	test.AssertEquals(t, src.findIssuer(ocspReq), src.issuers[0])
+
+	issuer2, err := core.LoadCert("../../test/test-ca2.pem")
+	test.AssertNotError(t, err, "Failed to load second issuer")
+	leaf := &x509.Certificate{SerialNumber: ocspReq.SerialNumber}
+	for i, h := range []crypto.Hash{crypto.SHA1, crypto.SHA256} {
+		reqDER, err := ocsp.CreateRequest(leaf, issuer2, &ocsp.RequestOptions{Hash: h})
+		test.AssertNotError(t, err, "Failed to create OCSP request")
+		issuer2Req, err := ocsp.ParseRequest(reqDER)
+		test.AssertNotError(t, err, "Failed to parse OCSP request")
+		test.AssertEquals(t, src.findIssuer(issuer2Req), src.issuers[1])
+
+		// The stored response was signed by the first issuer, so it isn't
+		// served to a request naming the second, even though they share a key
+		_, _, err = src.Response(issuer2Req)
+		test.AssertEquals(t, err, cfocsp.ErrNotFound)
+		wrongIssuer := src.responses.With(prometheus.Labels{"issuer": src.issuers[1].name, "result": "wrong_issuer"})
+		test.AssertEquals(t, test.CountCounter(wrongIssuer), i+1)
+	}
+
+	// A response signed by the second issuer is served for it
+	key, err := x509.ParsePKCS8PrivateKey(mustReadPEM("./testdata/test-ca.key"))
+	test.AssertNotError(t, err, "Failed to parse test CA key")
+	issuer2Resp, err := ocsp.CreateResponse(issuer2, issuer2, ocsp.Response{
+		SerialNumber: ocspReq.SerialNumber,
+		Status:       ocsp.Good,
+		ThisUpdate:   time.Now(),
+		NextUpdate:   time.Now().Add(time.Hour),
+	}, key.(crypto.Signer))
+	test.AssertNotError(t, err, "Failed to sign OCSP response")
+	src.dbMap = statusSelector{status: dbResponse{
+		OCSPResponse:    issuer2Resp,
+		OCSPLastUpdated: time.Now(),
+		Status:          core.OCSPStatusGood,
+	}}
+	reqDER, err := ocsp.CreateRequest(&x509.Certificate{SerialNumber: ocspReq.SerialNumber}, issuer2, nil)
+	test.AssertNotError(t, err, "Failed to create OCSP request")
+	issuer2Req, err := ocsp.ParseRequest(reqDER)
+	test.AssertNotError(t, err, "Failed to parse OCSP request")
+	der, _, err := src.Response(issuer2Req)
+	test.AssertNotError(t, err, "Response for second issuer failed")
+	test.AssertByteEquals(t, der, issuer2Resp)
+	// and not for the first
+	_, _, err = src.Response(ocspReq)
+	test.AssertEquals(t, err, cfocsp.ErrNotFound)
+
+	// A request matching an issuer's key but not its name is not routed to it
+	ocspReq.IssuerNameHash = []byte("not the right name hash")
+	test.Assert(t, src.findIssuer(ocspReq) == nil, "Request with wrong name hash was routed")
+	_, _, err = src.Response(ocspReq)
+	test.AssertEquals(t, err, cfocsp.ErrNotFound)
+	unknown := src.responses.With(prometheus.Labels{"issuer": "unknown", "result": "unknown_issuer"})
+	test.AssertEquals(t, test.CountCounter(unknown), 1)
+}
+
+func TestCacheHeaders(t *testing.T) {
+	now := time.Date(2018, 3, 4, 5, 0, 0, 0, time.UTC)
+	fc := clock.NewFake()
+	fc.Set(now)
+	response := signResponse(t, ocsp.Good, now.Add(-time.Hour), 72*time.Hour)
+	selector := statusSelector{status: dbResponse{
+		OCSPResponse:    response,
+		OCSPLastUpdated: now.Add(-time.Hour),
+		Status:          core.OCSPStatusGood,
+	}}
+	src, err := makeDBSource(selector, []string{"./testdata/test-ca.der.pem"}, 0, nil, metrics.NewNoopScope(), fc, blog.NewMock())
+	test.AssertNotError(t, err, "makeDBSource failed")
+
+	parsedResponse, err := ocsp.ParseResponse(response, nil)
+	test.AssertNotError(t, err, "Failed to parse response")
+
+	h := cfocsp.NewResponder(src)
+	for _, method := range []string{"GET", "POST"} {
+		w := httptest.NewRecorder()
+		var r *http.Request
+		if method == "GET" {
+			r, err = http.NewRequest("GET", "/"+base64.StdEncoding.EncodeToString(req), nil)
+		} else {
+			r, err = http.NewRequest("POST", "/", bytes.NewReader(req))
+		}
+		test.AssertNotError(t, err, "Failed to create request")
+		h.ServeHTTP(w, r)
+		test.AssertEquals(t, w.Code, http.StatusOK)
+		test.AssertByteEquals(t, w.Body.Bytes(), response)
+		test.AssertEquals(t, w.Header().Get("Cache-Control"), "max-age=255600, public, no-transform, must-revalidate")
+		test.AssertEquals(t, w.Header().Get("Last-Modified"), "Sun, 04 Mar 2018 04:00:00 GMT")
+		test.AssertEquals(t, w.Header().Get("Expires"), "Wed, 07 Mar 2018 04:00:00 GMT")
+		test.AssertEquals(t, w.Header().Get("ETag"), fmt.Sprintf("\"%X\"", sha256.Sum256(response)))
+		test.AssertEquals(t, w.Header().Get("Surrogate-Key"), core.SerialToString(parsedResponse.SerialNumber))
+	}
+
+	// The configured max age caps the Cache-Control max-age
+	src.maxAge = time.Hour
+	test.AssertEquals(t, src.cacheHeaders(response).Get("Cache-Control"), "max-age=3600, public, no-transform, must-revalidate")
+
+	// Expired responses must not be cached
+	fc.Add(100 * time.Hour)
+	test.AssertEquals(t, src.cacheHeaders(response).Get("Cache-Control"), "max-age=0, public, no-transform, must-revalidate")
+}
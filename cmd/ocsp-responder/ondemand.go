@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/metrics"
+)
+
+// errSigningThrottled is returned by the onDemandSigner when the maximum
+// number of in-flight signing requests has been reached.
+var errSigningThrottled = errors.New("too many on-demand OCSP signing requests in flight")
+
+// ocspGenerator is the subset of core.CertificateAuthority used to sign OCSP
+// responses on demand.
+type ocspGenerator interface {
+	GenerateOCSP(ctx context.Context, req core.OCSPSigningRequest) ([]byte, error)
+}
+
+// onDemandSigner asks the CA for a fresh OCSP response when the pre-signed
+// response the ocsp-updater stored for a certificate is missing or past its
+// nextUpdate. The number of concurrent signing requests is bounded so that a
+// stalled updater can't turn the responder's request load into a flood of
+// signing requests, and signed responses are kept in memory until they expire.
+type onDemandSigner struct {
+	ca      ocspGenerator
+	dbMap   dbSelector
+	clk     clock.Clock
+	timeout time.Duration
+	sem     chan struct{}
+	cache   *responseCache
+
+	signings *prometheus.CounterVec
+}
+
+func newOnDemandSigner(
+	ca ocspGenerator,
+	dbMap dbSelector,
+	maxInFlight int,
+	cacheSize int,
+	timeout time.Duration,
+	clk clock.Clock,
+	scope metrics.Scope,
+) *onDemandSigner {
+	if maxInFlight <= 0 {
+		maxInFlight = 1
+	}
+	signings := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ocsp_signed_on_demand",
+			Help: "Number of OCSP responses the responder requested from the CA on demand, by result",
+		},
+		[]string{"result"})
+	scope.MustRegister(signings)
+
+	return &onDemandSigner{
+		ca:       ca,
+		dbMap:    dbMap,
+		clk:      clk,
+		timeout:  timeout,
+		sem:      make(chan struct{}, maxInFlight),
+		cache:    newResponseCache(cacheSize),
+		signings: signings,
+	}
+}
+
+// sign returns a fresh OCSP response for the given serial and certificate
+// status, either from the in-memory cache or by asking the CA to sign one.
+func (s *onDemandSigner) sign(serial string, status dbResponse) ([]byte, error) {
+	now := s.clk.Now()
+	if der := s.cache.get(serial, status.Status, now); der != nil {
+		s.signings.With(prometheus.Labels{"result": "cached"}).Inc()
+		return der, nil
+	}
+
+	select {
+	case s.sem <- struct{}{}:
+		defer func() { <-s.sem }()
+	default:
+		s.signings.With(prometheus.Labels{"result": "throttled"}).Inc()
+		return nil, errSigningThrottled
+	}
+
+	var cert struct {
+		DER []byte
+	}
+	err := s.dbMap.SelectOne(
+		&cert,
+		"SELECT der FROM certificates WHERE serial = :serial",
+		map[string]interface{}{"serial": serial},
+	)
+	if err != nil {
+		s.signings.With(prometheus.Labels{"result": "failed"}).Inc()
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	der, err := s.ca.GenerateOCSP(ctx, core.OCSPSigningRequest{
+		CertDER:   cert.DER,
+		Status:    string(status.Status),
+		Reason:    status.RevokedReason,
+		RevokedAt: status.RevokedDate,
+	})
+	if err != nil {
+		s.signings.With(prometheus.Labels{"result": "failed"}).Inc()
+		return nil, err
+	}
+	s.signings.With(prometheus.Labels{"result": "signed"}).Inc()
+
+	if parsed, err := ocsp.ParseResponse(der, nil); err == nil && !parsed.NextUpdate.IsZero() {
+		s.cache.put(serial, status.Status, der, parsed.NextUpdate, now)
+	}
+	return der, nil
+}
+
+type cachedResponse struct {
+	status     core.OCSPStatus
+	der        []byte
+	nextUpdate time.Time
+}
+
+// responseCache is a size-bounded in-memory store of OCSP responses signed on
+// demand. Entries are keyed by serial and only returned while they are fresh
+// and were signed for the certificate's current status, so a revocation is
+// never masked by a cached "good" response.
+type responseCache struct {
+	sync.Mutex
+	maxEntries int
+	entries    map[string]cachedResponse
+}
+
+func newResponseCache(maxEntries int) *responseCache {
+	return &responseCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]cachedResponse),
+	}
+}
+
+func (c *responseCache) get(serial string, status core.OCSPStatus, now time.Time) []byte {
+	c.Lock()
+	defer c.Unlock()
+	entry, present := c.entries[serial]
+	if !present {
+		return nil
+	}
+	if entry.status != status || !now.Before(entry.nextUpdate) {
+		delete(c.entries, serial)
+		return nil
+	}
+	return entry.der
+}
+
+func (c *responseCache) put(serial string, status core.OCSPStatus, der []byte, nextUpdate, now time.Time) {
+	if c.maxEntries <= 0 {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	if _, present := c.entries[serial]; !present && len(c.entries) >= c.maxEntries {
+		for k, v := range c.entries {
+			if !now.Before(v.nextUpdate) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			return
+		}
+	}
+	c.entries[serial] = cachedResponse{status: status, der: der, nextUpdate: nextUpdate}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	cfocsp "github.com/cloudflare/cfssl/ocsp"
+	"github.com/jmhodges/clock"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/letsencrypt/boulder/core"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/test"
+)
+
+// signResponse signs an OCSP response for the serial in ./testdata/ocsp.req
+// with the test CA, valid from thisUpdate for the given duration.
+func signResponse(t *testing.T, status int, thisUpdate time.Time, validity time.Duration) []byte {
+	issuer, err := x509.ParseCertificate(mustReadPEM("./testdata/test-ca.der.pem"))
+	test.AssertNotError(t, err, "Failed to parse test CA certificate")
+	key, err := x509.ParsePKCS8PrivateKey(mustReadPEM("./testdata/test-ca.key"))
+	test.AssertNotError(t, err, "Failed to parse test CA key")
+	ocspReq, err := ocsp.ParseRequest(req)
+	test.AssertNotError(t, err, "Failed to parse OCSP request")
+
+	der, err := ocsp.CreateResponse(issuer, issuer, ocsp.Response{
+		SerialNumber: ocspReq.SerialNumber,
+		Status:       status,
+		ThisUpdate:   thisUpdate,
+		NextUpdate:   thisUpdate.Add(validity),
+	}, key.(crypto.Signer))
+	test.AssertNotError(t, err, "Failed to sign OCSP response")
+	return der
+}
+
+func mustReadPEM(path string) []byte {
+	block, _ := pem.Decode(mustRead(path))
+	if block == nil {
+		panic(fmt.Sprintf("no PEM data in %#v", path))
+	}
+	return block.Bytes
+}
+
+// statusSelector returns a fixed certificateStatus row and certificate DER.
+type statusSelector struct {
+	status dbResponse
+}
+
+func (s statusSelector) SelectOne(output interface{}, query string, _ ...interface{}) error {
+	switch o := output.(type) {
+	case *dbResponse:
+		*o = s.status
+	case *struct{ DER []byte }:
+		o.DER = []byte{1, 2, 3}
+	default:
+		return fmt.Errorf("incorrect output type %T for query %q", output, query)
+	}
+	return nil
+}
+
+type mockOCSPGenerator struct {
+	response []byte
+	err      error
+	calls    int
+	block    chan struct{}
+}
+
+func (m *mockOCSPGenerator) GenerateOCSP(_ context.Context, req core.OCSPSigningRequest) ([]byte, error) {
+	m.calls++
+	if m.block != nil {
+		<-m.block
+	}
+	return m.response, m.err
+}
+
+func setupOnDemand(t *testing.T, status dbResponse, ca *mockOCSPGenerator) (*DBSource, clock.FakeClock) {
+	fc := clock.NewFake()
+	fc.Set(time.Date(2018, 3, 4, 5, 0, 0, 0, time.UTC))
+	selector := statusSelector{status: status}
+	var signer *onDemandSigner
+	if ca != nil {
+		signer = newOnDemandSigner(ca, selector, 1, 10, time.Second, fc, stats)
+	}
+	src, err := makeDBSource(selector, []string{"./testdata/test-ca.der.pem"}, 0, signer, stats, fc, blog.NewMock())
+	test.AssertNotError(t, err, "makeDBSource failed")
+	return src, fc
+}
+
+func TestExpiredResponseSignedOnDemand(t *testing.T) {
+	now := time.Date(2018, 3, 4, 5, 0, 0, 0, time.UTC)
+	expired := signResponse(t, ocsp.Good, now.Add(-96*time.Hour), 72*time.Hour)
+	fresh := signResponse(t, ocsp.Good, now, 72*time.Hour)
+	ca := &mockOCSPGenerator{response: fresh}
+	src, _ := setupOnDemand(t, dbResponse{
+		OCSPResponse:    expired,
+		OCSPLastUpdated: now.Add(-96 * time.Hour),
+		Status:          core.OCSPStatusGood,
+	}, ca)
+
+	ocspReq, err := ocsp.ParseRequest(req)
+	test.AssertNotError(t, err, "Failed to parse OCSP request")
+
+	body, _, err := src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, fresh)
+	test.AssertEquals(t, ca.calls, 1)
+	test.AssertEquals(t, test.CountCounterVec("result", "signed", src.signer.signings), 1)
+
+	// A second request should be served from the cache
+	body, _, err = src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, fresh)
+	test.AssertEquals(t, ca.calls, 1)
+	test.AssertEquals(t, test.CountCounterVec("result", "cached", src.signer.signings), 1)
+	test.AssertEquals(t, test.CountCounter(src.staleServed), 0)
+}
+
+func TestMissingResponseSignedOnDemand(t *testing.T) {
+	now := time.Date(2018, 3, 4, 5, 0, 0, 0, time.UTC)
+	fresh := signResponse(t, ocsp.Good, now, 72*time.Hour)
+	ca := &mockOCSPGenerator{response: fresh}
+	src, _ := setupOnDemand(t, dbResponse{Status: core.OCSPStatusGood}, ca)
+
+	ocspReq, err := ocsp.ParseRequest(req)
+	test.AssertNotError(t, err, "Failed to parse OCSP request")
+
+	body, _, err := src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, fresh)
+	test.AssertEquals(t, ca.calls, 1)
+
+	// When the CA can't sign, a missing response is still not found
+	ca = &mockOCSPGenerator{err: errors.New("broken CA")}
+	src, _ = setupOnDemand(t, dbResponse{Status: core.OCSPStatusGood}, ca)
+	_, _, err = src.Response(ocspReq)
+	test.AssertEquals(t, err, cfocsp.ErrNotFound)
+}
+
+func TestExpiredResponseServedStale(t *testing.T) {
+	now := time.Date(2018, 3, 4, 5, 0, 0, 0, time.UTC)
+	expired := signResponse(t, ocsp.Good, now.Add(-96*time.Hour), 72*time.Hour)
+	status := dbResponse{
+		OCSPResponse:    expired,
+		OCSPLastUpdated: now.Add(-96 * time.Hour),
+		Status:          core.OCSPStatusGood,
+	}
+
+	ocspReq, err := ocsp.ParseRequest(req)
+	test.AssertNotError(t, err, "Failed to parse OCSP request")
+
+	// Without a signer the expired response is served as-is
+	src, _ := setupOnDemand(t, status, nil)
+	body, _, err := src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, expired)
+	test.AssertEquals(t, test.CountCounter(src.staleServed), 1)
+
+	// Same for when the CA fails to sign
+	ca := &mockOCSPGenerator{err: errors.New("broken CA")}
+	src, _ = setupOnDemand(t, status, ca)
+	body, _, err = src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, expired)
+	test.AssertEquals(t, test.CountCounter(src.staleServed), 1)
+	test.AssertEquals(t, test.CountCounterVec("result", "failed", src.signer.signings), 1)
+}
+
+func TestOnDemandSigningThrottled(t *testing.T) {
+	now := time.Date(2018, 3, 4, 5, 0, 0, 0, time.UTC)
+	expired := signResponse(t, ocsp.Good, now.Add(-96*time.Hour), 72*time.Hour)
+	fresh := signResponse(t, ocsp.Good, now, 72*time.Hour)
+	ca := &mockOCSPGenerator{response: fresh, block: make(chan struct{})}
+	src, _ := setupOnDemand(t, dbResponse{
+		OCSPResponse:    expired,
+		OCSPLastUpdated: now.Add(-96 * time.Hour),
+		Status:          core.OCSPStatusGood,
+	}, ca)
+
+	ocspReq, err := ocsp.ParseRequest(req)
+	test.AssertNotError(t, err, "Failed to parse OCSP request")
+
+	// Occupy the only signing slot
+	done := make(chan struct{})
+	go func() {
+		_, _, _ = src.Response(ocspReq)
+		close(done)
+	}()
+	for len(src.signer.sem) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	body, _, err := src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, expired)
+	test.AssertEquals(t, test.CountCounterVec("result", "throttled", src.signer.signings), 1)
+
+	close(ca.block)
+	<-done
+}
+
+func TestResponseCache(t *testing.T) {
+	now := time.Date(2018, 3, 4, 5, 0, 0, 0, time.UTC)
+	cache := newResponseCache(1)
+	cache.put("serial", core.OCSPStatusGood, []byte{1}, now.Add(time.Hour), now)
+
+	test.AssertByteEquals(t, cache.get("serial", core.OCSPStatusGood, now), []byte{1})
+	// A cached response for a different status must not be used
+	test.Assert(t, cache.get("serial", core.OCSPStatusRevoked, now) == nil, "Got cached response for wrong status")
+	test.Assert(t, cache.get("serial", core.OCSPStatusGood, now) == nil, "Mismatched entry was not evicted")
+
+	cache.put("serial", core.OCSPStatusGood, []byte{1}, now.Add(time.Hour), now)
+	// The cache is full, so this entry is dropped
+	cache.put("other", core.OCSPStatusGood, []byte{2}, now.Add(time.Hour), now)
+	test.Assert(t, cache.get("other", core.OCSPStatusGood, now) == nil, "Cache grew beyond its maximum size")
+
+	// Once the first entry has expired there is room again
+	later := now.Add(2 * time.Hour)
+	cache.put("other", core.OCSPStatusGood, []byte{2}, later.Add(time.Hour), later)
+	test.AssertByteEquals(t, cache.get("other", core.OCSPStatusGood, later), []byte{2})
+	test.Assert(t, cache.get("serial", core.OCSPStatusGood, later) == nil, "Got expired cached response")
+}
+
+func TestIsExpired(t *testing.T) {
+	now := time.Date(2018, 3, 4, 5, 0, 0, 0, time.UTC)
+	der := signResponse(t, ocsp.Good, now, time.Hour)
+	test.Assert(t, !isExpired(der, now), "Fresh response considered expired")
+	test.Assert(t, isExpired(der, now.Add(time.Hour)), "Response at nextUpdate not considered expired")
+	test.Assert(t, !isExpired([]byte("not OCSP"), now), "Unparseable response considered expired")
+}
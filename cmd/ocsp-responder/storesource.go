@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/letsencrypt/boulder/core"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/ocspstore"
+)
+
+// storeSource serves OCSP responses from an ocspstore.Getter, such as Redis,
+// and falls back to the wrapped DBSource when the store has no response for
+// the requested serial or can't be reached. This keeps the bulk of OCSP
+// traffic away from the database.
+type storeSource struct {
+	store ocspstore.Getter
+	db    *DBSource
+	log   blog.Logger
+
+	lookups *prometheus.CounterVec
+}
+
+func newStoreSource(store ocspstore.Getter, db *DBSource, scope metrics.Scope, log blog.Logger) *storeSource {
+	lookups := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ocsp_store_lookups",
+			Help: "Number of OCSP responses looked up in the response store, by result",
+		},
+		[]string{"result"})
+	scope.MustRegister(lookups)
+
+	return &storeSource{
+		store:   store,
+		db:      db,
+		log:     log,
+		lookups: lookups,
+	}
+}
+
+// Response is called by the HTTP server to handle a new OCSP request.
+func (src *storeSource) Response(req *ocsp.Request) ([]byte, http.Header, error) {
+	// Requests for other CAs are rejected by the DBSource without touching
+	// the database, so there is no point in looking them up in the store.
+	iss := src.db.findIssuer(req)
+	if iss == nil {
+		return src.db.Response(req)
+	}
+
+	serialString := core.SerialToString(req.SerialNumber)
+	der, err := src.store.GetResponse(context.Background(), serialString)
+	if err == nil && !iss.signed(der) {
+		// The stored response was signed by another issuer than the one
+		// requested. The DBSource would reject the request, so let it answer.
+		src.lookups.With(prometheus.Labels{"result": "wrong_issuer"}).Inc()
+		return src.db.Response(req)
+	}
+	switch err {
+	case nil:
+		src.lookups.With(prometheus.Labels{"result": "hit"}).Inc()
+		src.db.responses.With(prometheus.Labels{"issuer": iss.name, "result": "found"}).Inc()
+		return der, src.db.cacheHeaders(der), nil
+	case ocspstore.ErrNotFound:
+		src.lookups.With(prometheus.Labels{"result": "miss"}).Inc()
+	default:
+		src.lookups.With(prometheus.Labels{"result": "error"}).Inc()
+		src.log.Warningf("Looking up OCSP response for Serial=%s in response store: %s", serialString, err)
+	}
+	return src.db.Response(req)
+}
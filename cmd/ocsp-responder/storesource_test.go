@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/ocspstore"
+	"github.com/letsencrypt/boulder/test"
+)
+
+type mockGetter struct {
+	response []byte
+	err      error
+	serials  []string
+}
+
+func (m *mockGetter) GetResponse(_ context.Context, serial string) ([]byte, error) {
+	m.serials = append(m.serials, serial)
+	return m.response, m.err
+}
+
+func TestStoreSource(t *testing.T) {
+	now := time.Date(2018, 3, 4, 5, 0, 0, 0, time.UTC)
+	fromDB := signResponse(t, ocsp.Good, now, 72*time.Hour)
+	fromStore := signResponse(t, ocsp.Good, now.Add(time.Hour), 72*time.Hour)
+	dbSource, _ := setupOnDemand(t, dbResponse{
+		OCSPResponse:    fromDB,
+		OCSPLastUpdated: now,
+		Status:          core.OCSPStatusGood,
+	}, nil)
+
+	ocspReq, err := ocsp.ParseRequest(req)
+	test.AssertNotError(t, err, "Failed to parse OCSP request")
+
+	getter := &mockGetter{response: fromStore}
+	src := newStoreSource(getter, dbSource, metrics.NewNoopScope(), dbSource.log)
+	body, _, err := src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, fromStore)
+	test.AssertEquals(t, getter.serials[0], core.SerialToString(ocspReq.SerialNumber))
+	test.AssertEquals(t, test.CountCounterVec("result", "hit", src.lookups), 1)
+
+	// A miss falls back to the database
+	getter = &mockGetter{err: ocspstore.ErrNotFound}
+	src = newStoreSource(getter, dbSource, metrics.NewNoopScope(), dbSource.log)
+	body, _, err = src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, fromDB)
+	test.AssertEquals(t, test.CountCounterVec("result", "miss", src.lookups), 1)
+
+	// So does a store failure
+	getter = &mockGetter{err: errors.New("connection refused")}
+	src = newStoreSource(getter, dbSource, metrics.NewNoopScope(), dbSource.log)
+	body, _, err = src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, fromDB)
+	test.AssertEquals(t, test.CountCounterVec("result", "error", src.lookups), 1)
+
+	// A stored response signed by another issuer isn't served
+	issuer2, err := core.LoadCert("../../test/test-ca2.pem")
+	test.AssertNotError(t, err, "Failed to load second issuer")
+	key, err := x509.ParsePKCS8PrivateKey(mustReadPEM("./testdata/test-ca.key"))
+	test.AssertNotError(t, err, "Failed to parse test CA key")
+	otherIssuer, err := ocsp.CreateResponse(issuer2, issuer2, ocsp.Response{
+		SerialNumber: ocspReq.SerialNumber,
+		Status:       ocsp.Good,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(72 * time.Hour),
+	}, key.(crypto.Signer))
+	test.AssertNotError(t, err, "Failed to sign OCSP response")
+	getter = &mockGetter{response: otherIssuer}
+	src = newStoreSource(getter, dbSource, metrics.NewNoopScope(), dbSource.log)
+	body, _, err = src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, fromDB)
+	test.AssertEquals(t, test.CountCounterVec("result", "wrong_issuer", src.lookups), 1)
+
+	// Requests for another issuer never reach the store
+	getter = &mockGetter{response: fromStore}
+	src = newStoreSource(getter, dbSource, metrics.NewNoopScope(), dbSource.log)
+	ocspReq.IssuerKeyHash = []byte("some other issuer")
+	_, _, err = src.Response(ocspReq)
+	test.AssertError(t, err, "Response for another issuer succeeded")
+	test.AssertEquals(t, len(getter.serials), 0)
+}
@@ -25,6 +25,8 @@ import (
 	bgrpc "github.com/letsencrypt/boulder/grpc"
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/ocspstore"
+	"github.com/letsencrypt/boulder/purger"
 	"github.com/letsencrypt/boulder/sa"
 	sapb "github.com/letsencrypt/boulder/sa/proto"
 )
@@ -61,8 +63,16 @@ type OCSPUpdater struct {
 
 	loops []*looper
 
-	ccu    *akamai.CachePurgeClient
-	issuer *x509.Certificate
+	// If set, generated responses are also written to responseStore. When
+	// skipDBResponseStorage is true 'good' responses are only written there.
+	responseStore         ocspstore.Storer
+	skipDBResponseStorage bool
+
+	// purgeQueues send purge requests for updated OCSP responses to each
+	// configured CDN. issuer is used to construct the OCSP requests whose
+	// cached responses are purged.
+	purgeQueues []*purger.Queue
+	issuer      *x509.Certificate
 }
 
 func newUpdater(
@@ -141,9 +151,38 @@ func newUpdater(
 		})
 	}
 
-	// TODO(#1050): Remove this gate and the nil ccu checks below
+	if config.Redis != nil {
+		password, err := config.Redis.Pass()
+		if err != nil {
+			return nil, err
+		}
+		updater.responseStore, err = ocspstore.NewRedisStore(
+			config.Redis.Address,
+			password,
+			config.Redis.Timeout.Duration,
+			config.Redis.PoolSize,
+			stats,
+		)
+		if err != nil {
+			return nil, err
+		}
+		updater.skipDBResponseStorage = config.SkipDBResponseStorage
+	}
+
+	purgeQueue := func(name string, p purger.CachePurger) *purger.Queue {
+		return purger.NewQueue(
+			name,
+			p,
+			config.PurgeBatchSize,
+			config.PurgeInterval.Duration,
+			config.PurgeMaxAttempts,
+			config.PurgeRetryBackoff.Duration,
+			clk,
+			log,
+			stats,
+		)
+	}
 	if config.AkamaiBaseURL != "" {
-		issuer, err := core.LoadCert(issuerPath)
 		ccu, err := akamai.NewCachePurgeClient(
 			config.AkamaiBaseURL,
 			config.AkamaiClientToken,
@@ -158,7 +197,34 @@ func newUpdater(
 		if err != nil {
 			return nil, err
 		}
-		updater.ccu = ccu
+		updater.purgeQueues = append(updater.purgeQueues, purgeQueue("akamai", purger.NewAkamaiPurger(ccu)))
+	}
+	if config.WebhookPurger != nil {
+		webhook := purger.NewWebhookPurger(
+			config.WebhookPurger.URL,
+			config.WebhookPurger.Headers,
+			config.WebhookPurger.Timeout.Duration,
+		)
+		updater.purgeQueues = append(updater.purgeQueues, purgeQueue("webhook", webhook))
+	}
+	if config.SurrogateKeyPurger != nil {
+		apiKey, err := config.SurrogateKeyPurger.Pass()
+		if err != nil {
+			return nil, err
+		}
+		surrogateKey := purger.NewSurrogateKeyPurger(
+			config.SurrogateKeyPurger.Endpoint,
+			config.SurrogateKeyPurger.ServiceID,
+			apiKey,
+			config.SurrogateKeyPurger.Timeout.Duration,
+		)
+		updater.purgeQueues = append(updater.purgeQueues, purgeQueue("surrogate_key", surrogateKey))
+	}
+	if len(updater.purgeQueues) > 0 {
+		issuer, err := core.LoadCert(issuerPath)
+		if err != nil {
+			return nil, err
+		}
 		updater.issuer = issuer
 	}
 
@@ -196,8 +262,8 @@ func generateOCSPCacheKeys(req []byte, ocspServer string) []string {
 	}
 }
 
-// sendPurge should only be called as a Goroutine as it will block until the purge
-// request is successful
+// sendPurge queues the cached OCSP responses for the given certificate to be
+// purged from each configured CDN. It doesn't block.
 func (updater *OCSPUpdater) sendPurge(der []byte) {
 	cert, err := x509.ParseCertificate(der)
 	if err != nil {
@@ -218,12 +284,15 @@ func (updater *OCSPUpdater) sendPurge(der []byte) {
 			ocspServer += "/"
 		}
 		// Generate GET url
-		urls = append(generateOCSPCacheKeys(req, ocspServer))
+		urls = append(urls, generateOCSPCacheKeys(req, ocspServer)...)
 	}
 
-	err = updater.ccu.Purge(urls)
-	if err != nil {
-		updater.log.AuditErrf("Failed to purge OCSP response from CDN: %s", err)
+	target := purger.Target{
+		Serial: core.SerialToString(cert.SerialNumber),
+		URLs:   urls,
+	}
+	for _, q := range updater.purgeQueues {
+		q.Add(target)
 	}
 }
 
@@ -245,6 +314,7 @@ func (updater *OCSPUpdater) findStaleOCSPResponses(oldestLastUpdatedTime time.Ti
 				WHERE cs.ocspLastUpdated > :maxAge
 				AND cs.ocspLastUpdated < :lastUpdate
 				AND NOT cs.isExpired
+				AND NOT cs.shortLived
 				ORDER BY cs.ocspLastUpdated ASC
 				LIMIT :limit`,
 		map[string]interface{}{
@@ -259,8 +329,11 @@ func (updater *OCSPUpdater) findStaleOCSPResponses(oldestLastUpdatedTime time.Ti
 	return statuses, err
 }
 
+// getCertificatesWithMissingResponses finds certificates which have never had
+// an OCSP response. Like the other queries for certificates needing responses,
+// it leaves out short-lived certificates, which have no OCSP URL.
 func (updater *OCSPUpdater) getCertificatesWithMissingResponses(batchSize int) ([]core.CertificateStatus, error) {
-	const query = "WHERE ocspLastUpdated = 0 LIMIT ?"
+	const query = "WHERE ocspLastUpdated = 0 AND NOT shortLived LIMIT ?"
 	statuses, err := sa.SelectCertificateStatuses(
 		updater.dbMap,
 		query,
@@ -327,30 +400,82 @@ func (updater *OCSPUpdater) generateRevokedResponse(ctx context.Context, status
 	status.OCSPLastUpdated = now
 	status.OCSPResponse = ocspResponse
 
-	// Purge OCSP response from CDN, gated on client having been initialized
-	if updater.ccu != nil {
-		go updater.sendPurge(cert.DER)
+	// Purge OCSP response from CDNs, gated on a purger having been configured
+	if len(updater.purgeQueues) > 0 {
+		updater.sendPurge(cert.DER)
 	}
 
 	return &status, nil
 }
 
-func (updater *OCSPUpdater) storeResponse(status *core.CertificateStatus) error {
+func (updater *OCSPUpdater) storeResponse(ctx context.Context, status *core.CertificateStatus) error {
+	// Write to the response store before the database, so that a failed write
+	// leaves the certificateStatus row stale and the response is generated
+	// again. Otherwise the store would keep serving the previous response,
+	// which for a newly revoked certificate is a 'good' one.
+	if updater.responseStore != nil {
+		parsed, err := ocsp.ParseResponse(status.OCSPResponse, nil)
+		if err != nil {
+			return fmt.Errorf("parsing OCSP response for %s: %s", status.Serial, err)
+		}
+		ttl := parsed.NextUpdate.Sub(updater.clk.Now())
+		if ttl <= 0 {
+			return fmt.Errorf("OCSP response for %s expired at %s", status.Serial, parsed.NextUpdate)
+		}
+		err = updater.responseStore.StoreResponse(ctx, status.Serial, status.OCSPResponse, ttl)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Update the certificateStatus table with the new OCSP response, the status
 	// WHERE is used make sure we don't overwrite a revoked response with a one
 	// containing a 'good' status and that we don't do the inverse when the OCSP
 	// status should be 'good'.
-	_, err := updater.dbMap.Exec(
-		`UPDATE certificateStatus
-		 SET ocspResponse=?,ocspLastUpdated=?
-		 WHERE serial=?
-		 AND status=?`,
-		status.OCSPResponse,
-		status.OCSPLastUpdated,
-		status.Serial,
-		string(status.Status),
-	)
-	return err
+	//
+	// Responses that aren't 'good' are always stored in the database: the
+	// responder falls back to it when the response store misses, and it must
+	// never find the 'good' response from before a revocation there.
+	var result sql.Result
+	var err error
+	if updater.responseStore != nil && updater.skipDBResponseStorage && status.Status == core.OCSPStatusGood {
+		result, err = updater.dbMap.Exec(
+			`UPDATE certificateStatus
+			 SET ocspLastUpdated=?
+			 WHERE serial=?
+			 AND status=?`,
+			status.OCSPLastUpdated,
+			status.Serial,
+			string(status.Status),
+		)
+	} else {
+		result, err = updater.dbMap.Exec(
+			`UPDATE certificateStatus
+			 SET ocspResponse=?,ocspLastUpdated=?
+			 WHERE serial=?
+			 AND status=?`,
+			status.OCSPResponse,
+			status.OCSPLastUpdated,
+			status.Serial,
+			string(status.Status),
+		)
+	}
+	if err != nil || updater.responseStore == nil {
+		return err
+	}
+
+	// If the status guard above stopped the update the certificate was revoked
+	// after the response was generated, so the 'good' response just stored
+	// must not be served. Without it the responder falls back to the
+	// database.
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return updater.responseStore.DeleteResponse(ctx, status.Serial)
+	}
+	return nil
 }
 
 // markExpired updates a given CertificateStatus to have `isExpired` set.
@@ -380,7 +505,7 @@ func (updater *OCSPUpdater) newCertificateTick(ctx context.Context, batchSize in
 }
 
 func (updater *OCSPUpdater) findRevokedCertificatesToUpdate(batchSize int) ([]core.CertificateStatus, error) {
-	const query = "WHERE status = ? AND ocspLastUpdated <= revokedDate LIMIT ?"
+	const query = "WHERE status = ? AND ocspLastUpdated <= revokedDate AND NOT shortLived LIMIT ?"
 	statuses, err := sa.SelectCertificateStatuses(
 		updater.dbMap,
 		query,
@@ -405,7 +530,7 @@ func (updater *OCSPUpdater) revokedCertificatesTick(ctx context.Context, batchSi
 			updater.stats.Inc("Errors.RevokedResponseGeneration", 1)
 			return err
 		}
-		err = updater.storeResponse(meta)
+		err = updater.storeResponse(ctx, meta)
 		if err != nil {
 			updater.stats.Inc("Errors.StoreRevokedResponse", 1)
 			updater.log.AuditErrf("Failed to store OCSP response: %s", err)
@@ -439,7 +564,7 @@ func (updater *OCSPUpdater) generateOCSPResponses(ctx context.Context, statuses
 			return
 		}
 		stats.Inc("GeneratedResponses", 1)
-		err = updater.storeResponse(meta)
+		err = updater.storeResponse(ctx, meta)
 		if err != nil {
 			updater.log.AuditErrf("Failed to store OCSP response: %s", err)
 			stats.Inc("Errors.StoreResponse", 1)
@@ -615,6 +740,11 @@ func main() {
 
 	cmd.FailOnError(err, "Failed to create updater")
 
+	for _, q := range updater.purgeQueues {
+		// Purges are sent until the process exits
+		go q.Run(nil)
+	}
+
 	for _, l := range updater.loops {
 		go func(loop *looper) {
 			err = loop.loop()
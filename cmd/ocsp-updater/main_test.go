@@ -1,12 +1,24 @@
 package main
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/jmhodges/clock"
+	"golang.org/x/crypto/ocsp"
 	"golang.org/x/net/context"
 	"gopkg.in/go-gorp/gorp.v2"
 
@@ -16,6 +28,7 @@ import (
 	"github.com/letsencrypt/boulder/features"
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/purger"
 	"github.com/letsencrypt/boulder/revocation"
 	"github.com/letsencrypt/boulder/sa"
 	"github.com/letsencrypt/boulder/sa/satest"
@@ -111,12 +124,12 @@ func TestGenerateAndStoreOCSPResponse(t *testing.T) {
 
 	meta, err := updater.generateResponse(ctx, status)
 	test.AssertNotError(t, err, "Couldn't generate OCSP response")
-	err = updater.storeResponse(meta)
+	err = updater.storeResponse(ctx, meta)
 	test.AssertNotError(t, err, "Couldn't store certificate status")
 
 	secondMeta, err := updater.generateRevokedResponse(ctx, status)
 	test.AssertNotError(t, err, "Couldn't generate revoked OCSP response")
-	err = updater.storeResponse(secondMeta)
+	err = updater.storeResponse(ctx, secondMeta)
 	test.AssertNotError(t, err, "Couldn't store certificate status")
 
 	newStatus, err := sa.GetCertificateStatus(ctx, status.Serial)
@@ -204,7 +217,7 @@ func TestFindStaleOCSPResponses(t *testing.T) {
 
 	meta, err := updater.generateResponse(ctx, status)
 	test.AssertNotError(t, err, "Couldn't generate OCSP response")
-	err = updater.storeResponse(meta)
+	err = updater.storeResponse(ctx, meta)
 	test.AssertNotError(t, err, "Couldn't store OCSP response")
 
 	certs, err = updater.findStaleOCSPResponses(earliest, 10)
@@ -269,6 +282,48 @@ func TestGetCertificatesWithMissingResponses(t *testing.T) {
 	test.AssertEquals(t, len(statuses), 1)
 }
 
+func TestShortLivedCertificatesSkipped(t *testing.T) {
+	updater, sa, _, fc, cleanUp := setup(t)
+	defer cleanUp()
+
+	// A certificate without an OCSP URL, as issued by a short-lived profile
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "Couldn't generate key")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1337),
+		DNSNames:     []string{"short.example.com"},
+		NotBefore:    fc.Now(),
+		NotAfter:     fc.Now().Add(7 * 24 * time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	test.AssertNotError(t, err, "Couldn't create certificate")
+	reg := satest.CreateWorkingRegistration(t, sa)
+	issued := fc.Now()
+	_, err = sa.AddCertificate(ctx, certDER, reg.ID, []byte("first response"), &issued)
+	test.AssertNotError(t, err, "Couldn't add short-lived certificate")
+
+	// Its response isn't updated once stale
+	fc.Add(2 * time.Hour)
+	statuses, err := updater.findStaleOCSPResponses(fc.Now().Add(-time.Hour), 10)
+	test.AssertNotError(t, err, "Couldn't find stale responses")
+	test.AssertEquals(t, len(statuses), 0)
+
+	// Nor is a response signed after it's revoked
+	err = sa.MarkCertificateRevoked(ctx, core.SerialToString(template.SerialNumber), revocation.KeyCompromise)
+	test.AssertNotError(t, err, "Failed to revoke certificate")
+	statuses, err = updater.findRevokedCertificatesToUpdate(10)
+	test.AssertNotError(t, err, "Failed to find revoked certificates")
+	test.AssertEquals(t, len(statuses), 0)
+
+	// Nor is one signed if it never had one
+	_, err = updater.dbMap.Exec("UPDATE certificateStatus SET ocspLastUpdated = 0 WHERE serial = ?",
+		core.SerialToString(template.SerialNumber))
+	test.AssertNotError(t, err, "Couldn't clear ocspLastUpdated")
+	statuses, err = updater.getCertificatesWithMissingResponses(10)
+	test.AssertNotError(t, err, "Couldn't get status")
+	test.AssertEquals(t, len(statuses), 0)
+}
+
 func TestFindRevokedCertificatesToUpdate(t *testing.T) {
 	updater, sa, _, fc, cleanUp := setup(t)
 	defer cleanUp()
@@ -435,7 +490,7 @@ func TestStoreResponseGuard(t *testing.T) {
 	// Attempt to update OCSP response where status.Status is good but stored status
 	// is revoked, this should fail silently
 	status.OCSPResponse = []byte{0, 1, 1}
-	err = updater.storeResponse(&status)
+	err = updater.storeResponse(ctx, &status)
 	test.AssertNotError(t, err, "Failed to update certificate status")
 
 	// Make sure the OCSP response hasn't actually changed
@@ -445,7 +500,7 @@ func TestStoreResponseGuard(t *testing.T) {
 
 	// Changing the status to the stored status should allow the update to occur
 	status.Status = core.OCSPStatusRevoked
-	err = updater.storeResponse(&status)
+	err = updater.storeResponse(ctx, &status)
 	test.AssertNotError(t, err, "Failed to updated certificate status")
 
 	// Make sure the OCSP response has been updated
@@ -514,3 +569,165 @@ func TestGenerateOCSPCacheKeys(t *testing.T) {
 		},
 	)
 }
+
+// rowsAffectedDB is an ocspDB whose Exec reports a fixed number of affected
+// rows and records the queries it was given.
+type rowsAffectedDB struct {
+	rows    int64
+	queries []string
+}
+
+func (db *rowsAffectedDB) Select(_ interface{}, _ string, _ ...interface{}) ([]interface{}, error) {
+	return nil, errors.New("Select is not implemented by rowsAffectedDB")
+}
+
+func (db *rowsAffectedDB) SelectOne(_ interface{}, _ string, _ ...interface{}) error {
+	return errors.New("SelectOne is not implemented by rowsAffectedDB")
+}
+
+func (db *rowsAffectedDB) Exec(query string, _ ...interface{}) (sql.Result, error) {
+	db.queries = append(db.queries, query)
+	return driver.RowsAffected(db.rows), nil
+}
+
+type memoryStore struct {
+	responses map[string][]byte
+	ttls      map[string]time.Duration
+	err       error
+}
+
+func (m *memoryStore) StoreResponse(_ context.Context, serial string, response []byte, ttl time.Duration) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.responses[serial] = response
+	m.ttls[serial] = ttl
+	return nil
+}
+
+func (m *memoryStore) DeleteResponse(_ context.Context, serial string) error {
+	delete(m.responses, serial)
+	delete(m.ttls, serial)
+	return nil
+}
+
+func signTestResponse(t *testing.T, thisUpdate, nextUpdate time.Time) []byte {
+	issuer, err := core.LoadCert("../../test/test-ca.pem")
+	test.AssertNotError(t, err, "Failed to load test CA certificate")
+	keyPEM, err := ioutil.ReadFile("../../test/test-ca.key")
+	test.AssertNotError(t, err, "Failed to read test CA key")
+	block, _ := pem.Decode(keyPEM)
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	test.AssertNotError(t, err, "Failed to parse test CA key")
+
+	der, err := ocsp.CreateResponse(issuer, issuer, ocsp.Response{
+		SerialNumber: big.NewInt(1),
+		Status:       ocsp.Good,
+		ThisUpdate:   thisUpdate,
+		NextUpdate:   nextUpdate,
+	}, key.(crypto.Signer))
+	test.AssertNotError(t, err, "Failed to sign OCSP response")
+	return der
+}
+
+func TestStoreResponseInResponseStore(t *testing.T) {
+	fc := clock.NewFake()
+	db := &rowsAffectedDB{rows: 1}
+	store := &memoryStore{responses: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+	updater := &OCSPUpdater{clk: fc, dbMap: db, responseStore: store}
+
+	response := signTestResponse(t, fc.Now(), fc.Now().Add(72*time.Hour))
+	status := &core.CertificateStatus{
+		Serial:          "00ff",
+		Status:          core.OCSPStatusGood,
+		OCSPResponse:    response,
+		OCSPLastUpdated: fc.Now(),
+	}
+	err := updater.storeResponse(ctx, status)
+	test.AssertNotError(t, err, "Failed to store response")
+	test.AssertByteEquals(t, store.responses["00ff"], response)
+	test.AssertEquals(t, store.ttls["00ff"], 72*time.Hour)
+	test.Assert(t, strings.Contains(db.queries[0], "ocspResponse=?"), "Response was not written to the DB")
+
+	// When the status guard stops the DB update, the stored response is
+	// removed again
+	db.rows = 0
+	status.Serial = "00fe"
+	err = updater.storeResponse(ctx, status)
+	test.AssertNotError(t, err, "Failed to store response")
+	_, present := store.responses["00fe"]
+	test.Assert(t, !present, "Response stored despite status mismatch")
+
+	// With skipDBResponseStorage only ocspLastUpdated is written to the DB
+	db.rows = 1
+	db.queries = nil
+	updater.skipDBResponseStorage = true
+	err = updater.storeResponse(ctx, status)
+	test.AssertNotError(t, err, "Failed to store response")
+	test.AssertByteEquals(t, store.responses["00fe"], response)
+	test.Assert(t, !strings.Contains(db.queries[0], "ocspResponse"), "Response was written to the DB")
+
+	// When the response store can't be written to, the DB isn't updated so
+	// the response is generated again
+	db.queries = nil
+	store.err = errors.New("store unavailable")
+	status.Status = core.OCSPStatusRevoked
+	err = updater.storeResponse(ctx, status)
+	test.AssertError(t, err, "Store failure wasn't returned")
+	test.AssertEquals(t, len(db.queries), 0)
+	store.err = nil
+
+	// Responses for revoked certificates are still written to the DB
+	err = updater.storeResponse(ctx, status)
+	test.AssertNotError(t, err, "Failed to store response")
+	test.Assert(t, strings.Contains(db.queries[0], "ocspResponse=?"), "Revoked response was not written to the DB")
+
+	// Expired responses are rejected
+	fc.Add(73 * time.Hour)
+	err = updater.storeResponse(ctx, status)
+	test.AssertError(t, err, "Stored an expired response")
+}
+
+// chanPurger is a purger.CachePurger that sends each purged target on a
+// channel.
+type chanPurger chan purger.Target
+
+func (cp chanPurger) Purge(targets []purger.Target) error {
+	for _, t := range targets {
+		cp <- t
+	}
+	return nil
+}
+
+func TestSendPurge(t *testing.T) {
+	fc := clock.NewFake()
+	issuer, err := core.LoadCert("../../test/test-ca2.pem")
+	test.AssertNotError(t, err, "Failed to load issuer")
+	cert, err := core.LoadCert("test-cert.pem")
+	test.AssertNotError(t, err, "Failed to load certificate")
+
+	purgers := []chanPurger{make(chanPurger, 1), make(chanPurger, 1)}
+	updater := &OCSPUpdater{log: log, issuer: issuer}
+	stop := make(chan struct{})
+	defer close(stop)
+	for i, p := range purgers {
+		q := purger.NewQueue(fmt.Sprintf("mock%d", i), p, 1, time.Hour, 1, time.Minute, fc, log, metrics.NewNoopScope())
+		updater.purgeQueues = append(updater.purgeQueues, q)
+		go q.Run(stop)
+	}
+
+	updater.sendPurge(cert.Raw)
+
+	req, err := ocsp.CreateRequest(cert, issuer, nil)
+	test.AssertNotError(t, err, "Failed to create OCSP request")
+	// Every configured CDN is asked to purge the certificate
+	for _, p := range purgers {
+		select {
+		case target := <-p:
+			test.AssertEquals(t, target.Serial, core.SerialToString(cert.SerialNumber))
+			test.AssertDeepEquals(t, target.URLs, generateOCSPCacheKeys(req, "http://127.0.0.1:4002/"))
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for purge")
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package core
+
+import (
+	"net"
+
+	"golang.org/x/net/context"
+)
+
+type requestIPKey struct{}
+
+// WithRequestIP returns a copy of ctx carrying the IP address of the client
+// whose request is being handled. The gRPC interceptors forward it between
+// services, so that e.g. the SA can record where a change came from.
+func WithRequestIP(ctx context.Context, ip net.IP) context.Context {
+	return context.WithValue(ctx, requestIPKey{}, ip)
+}
+
+// RequestIP returns the client IP address carried by ctx, or nil if there is
+// none.
+func RequestIP(ctx context.Context) net.IP {
+	ip, _ := ctx.Value(requestIPKey{}).(net.IP)
+	return ip
+}
@@ -106,10 +106,12 @@ type CertificateAuthority interface {
 
 // PolicyAuthority defines the public interface for the Boulder PA
 type PolicyAuthority interface {
-	WillingToIssue(domain AcmeIdentifier) error
-	WillingToIssueWildcard(domain AcmeIdentifier) error
-	ChallengesFor(domain AcmeIdentifier, registrationID int64, revalidation bool) (challenges []Challenge, validCombinations [][]int, err error)
+	WillingToIssue(domain AcmeIdentifier, registrationID int64) error
+	WillingToIssueWildcard(domain AcmeIdentifier, registrationID int64) error
+	ChallengesFor(domain AcmeIdentifier, reg Registration, revalidation bool) (challenges []Challenge, validCombinations [][]int, err error)
 	ChallengeTypeEnabled(t string, registrationID int64) bool
+	AccountAllowsChallengeType(t string, reg Registration) bool
+	ContactSchemeAllowed(scheme string) bool
 }
 
 // StorageGetter are the Boulder SA's read-only methods
@@ -135,6 +137,10 @@ type StorageGetter interface {
 	GetValidOrderAuthorizations(ctx context.Context, req *sapb.GetValidOrderAuthorizationsRequest) (map[string]*Authorization, error)
 	CountInvalidAuthorizations(ctx context.Context, req *sapb.CountInvalidAuthorizationsRequest) (count *sapb.Count, err error)
 	GetAuthorizations(ctx context.Context, req *sapb.GetAuthorizationsRequest) (*sapb.Authorizations, error)
+	GetSuppressedEmails(ctx context.Context, emails []string) ([]string, error)
+	GetPendingCAAIodefReports(ctx context.Context, limit int) ([]CAAIodefReport, error)
+	CountCAAIodefReportsSent(ctx context.Context, registeredDomain string, since time.Time) (int, error)
+	CountCAAIodefDeliveries(ctx context.Context, target string, since time.Time) (int, error)
 }
 
 // StorageAdder are the Boulder SA's write/update methods
@@ -154,6 +160,9 @@ type StorageAdder interface {
 	FinalizeOrder(ctx context.Context, order *corepb.Order) error
 	AddPendingAuthorizations(ctx context.Context, req *sapb.AddPendingAuthorizationsRequest) (*sapb.AuthorizationIDs, error)
 	SetOrderError(ctx context.Context, order *corepb.Order) error
+	AddEmailSuppression(ctx context.Context, email, reason string) error
+	AddCAAIodefReport(ctx context.Context, report CAAIodefReport) error
+	FinishCAAIodefReport(ctx context.Context, result CAAIodefReportResult) error
 }
 
 // StorageAuthority interface represents a simple key/value
@@ -164,6 +173,16 @@ type StorageAuthority interface {
 	StorageAdder
 }
 
+// StorageAdmin are the Boulder SA's read-only search methods for admin tools
+type StorageAdmin interface {
+	SearchCertificates(ctx context.Context, req *sapb.SearchCertificatesRequest) ([]Certificate, error)
+	SearchRegistrations(ctx context.Context, req *sapb.SearchRegistrationsRequest) ([]Registration, error)
+	GetRegistrationHistory(ctx context.Context, regID int64) ([]RegistrationChange, error)
+	SearchOrders(ctx context.Context, req *sapb.SearchOrdersRequest) ([]*corepb.Order, error)
+	SearchAuthorizations(ctx context.Context, req *sapb.SearchAuthorizationsRequest) ([]Authorization, error)
+	RegistrationsWithCertificates(ctx context.Context, req *sapb.RegistrationsWithCertificatesRequest) ([]*sapb.RegistrationContacts, error)
+}
+
 // Publisher defines the public interface for the Boulder Publisher
 type Publisher interface {
 	SubmitToSingleCTWithResult(ctx context.Context, req *pubpb.Request) (*pubpb.Result, error)
@@ -162,6 +162,24 @@ type Registration struct {
 	CreatedAt time.Time `json:"createdAt"`
 
 	Status AcmeStatus `json:"status"`
+
+	// ChallengeTypes, if present, are the only challenge types the account is
+	// willing to use for its authorizations. Like Contact it is a pointer so
+	// that an update can clear it by providing an empty list.
+	ChallengeTypes *[]string `json:"challengeTypes,omitempty"`
+}
+
+// RegistrationChange is an entry in a registration's history, recording the
+// registration's key, contacts, agreement and status after a change.
+type RegistrationChange struct {
+	Registration Registration `json:"registration"`
+
+	// ChangedAt is the time the change was made.
+	ChangedAt time.Time `json:"changedAt"`
+
+	// RequestIP is the IP address of the client which made the change, if
+	// known.
+	RequestIP net.IP `json:"requestIp,omitempty"`
 }
 
 // ValidationRecord represents a validation attempt against a specific URL/hostname
@@ -195,6 +213,48 @@ type ValidationRecord struct {
 	AddressesTried []net.IP `json:"addressesTried,omitempty"`
 }
 
+// CAAValidationRecord records a CAA check in enough detail to reconstruct the
+// decision later.
+type CAAValidationRecord struct {
+	// QueryName is the name whose CAA records were relevant: the checked
+	// name or its closest ancestor with CAA records. If there were none, it
+	// is the checked name.
+	QueryName string `json:"queryName"`
+	// RRSet is the relevant CAA records in presentation format, e.g.
+	// `0 issue "letsencrypt.org"`.
+	RRSet []string `json:"rrset,omitempty"`
+	// MatchedRecord is the record which authorized issuance, if any, and
+	// Parameters are its parsed parameters.
+	MatchedRecord string            `json:"matchedRecord,omitempty"`
+	Parameters    map[string]string `json:"parameters,omitempty"`
+	// Identity is our CAA identity named by MatchedRecord.
+	Identity string `json:"identity,omitempty"`
+	// Iodef is the mailto: and https: iodef targets of RRSet, which are told
+	// when the check denies issuance. It is only set for denials.
+	Iodef []string `json:"iodef,omitempty"`
+	// Resolver is the address of the DNS server queried for QueryName.
+	Resolver  string    `json:"resolver,omitempty"`
+	Present   bool      `json:"present"`
+	Valid     bool      `json:"valid"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// ReputationVerdict records the result of checking a domain with one
+// reputation provider, such as Google Safe Browsing or a local blocklist.
+type ReputationVerdict struct {
+	// Provider is the configured name of the provider
+	Provider string `json:"provider"`
+	// Reason is why the provider listed the domain, empty if it didn't
+	Reason string `json:"reason,omitempty"`
+	// Blocked is true if the listing failed the validation, rather than only
+	// being logged
+	Blocked bool `json:"blocked,omitempty"`
+	// Error is set if the provider couldn't be checked, in which case the
+	// domain is treated as unlisted
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
 func looksLikeKeyAuthorization(str string) error {
 	parts := strings.Split(str, ".")
 	if len(parts) != 2 {
@@ -246,6 +306,14 @@ type Challenge struct {
 	// Contains information about URLs used or redirected to and IPs resolved and
 	// used
 	ValidationRecord []ValidationRecord `json:"validationRecord,omitempty"`
+
+	// Records the CAA check made when the challenge was validated. It is only
+	// shown to clients in debug output.
+	CAARecord *CAAValidationRecord `json:"caaRecord,omitempty"`
+
+	// Records the domain reputation checks made when the challenge was
+	// validated. It is only shown to clients in debug output.
+	Reputation []ReputationVerdict `json:"reputation,omitempty"`
 }
 
 // ExpectedKeyAuthorization computes the expected KeyAuthorization value for
@@ -601,6 +669,47 @@ type Order struct {
 	Status            AcmeStatus
 }
 
+// CAAIodefReport records a CAA check which denied issuance for a domain whose
+// CAA records name iodef targets, so that they can be told about it.
+type CAAIodefReport struct {
+	// Domain is the identifier that was checked, which may be a wildcard.
+	Domain string
+	// AccountID is the ID of the requesting account, or 0 if unknown.
+	AccountID        int64
+	ValidationMethod string
+	// Records is the JSON encoding of the CAA records that denied issuance.
+	Records string
+	// Targets are the mailto: and https: iodef URLs to report to.
+	Targets []string
+	// ID and CreatedAt are set by the SA when the report is queued.
+	ID        int64
+	CreatedAt time.Time
+}
+
+// The statuses of a CAA iodef report. Reports are queued as pending and moved
+// to one of the other statuses once processed.
+const (
+	IodefReportPending     = "pending"
+	IodefReportSent        = "sent"
+	IodefReportFailed      = "failed"
+	IodefReportRateLimited = "ratelimited"
+)
+
+// CAAIodefReportResult is the outcome of processing a pending CAA iodef report.
+type CAAIodefReportResult struct {
+	ID int64
+	// Status is the report's new status, one of IodefReportSent,
+	// IodefReportFailed or IodefReportRateLimited.
+	Status string
+	// RegisteredDomain is the registered domain of the report's domain, by
+	// which reports are rate limited.
+	RegisteredDomain string
+	// Results is the JSON encoding of the outcome of delivery to each target.
+	Results string
+	// Delivered are the targets the report was delivered to.
+	Delivered []string
+}
+
 // SCTDER is a convenience type
 type SCTDERs [][]byte
 
@@ -44,7 +44,13 @@ type Challenge struct {
 	KeyAuthorization  *string             `protobuf:"bytes,5,opt,name=keyAuthorization" json:"keyAuthorization,omitempty"`
 	Validationrecords []*ValidationRecord `protobuf:"bytes,10,rep,name=validationrecords" json:"validationrecords,omitempty"`
 	Error             *ProblemDetails     `protobuf:"bytes,7,opt,name=error" json:"error,omitempty"`
-	XXX_unrecognized  []byte              `json:"-"`
+	// JSON encoding of the core.CAAValidationRecord of the CAA check made
+	// when the challenge was validated
+	CaaRecord []byte `protobuf:"bytes,11,opt,name=caaRecord" json:"caaRecord,omitempty"`
+	// JSON encoding of the []core.ReputationVerdict of the domain reputation
+	// checks made when the challenge was validated
+	Reputation       []byte `protobuf:"bytes,12,opt,name=reputation" json:"reputation,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
 }
 
 func (m *Challenge) Reset()                    { *m = Challenge{} }
@@ -108,6 +114,20 @@ func (m *Challenge) GetError() *ProblemDetails {
 	return nil
 }
 
+func (m *Challenge) GetCaaRecord() []byte {
+	if m != nil {
+		return m.CaaRecord
+	}
+	return nil
+}
+
+func (m *Challenge) GetReputation() []byte {
+	if m != nil {
+		return m.Reputation
+	}
+	return nil
+}
+
 type ValidationRecord struct {
 	Hostname          *string  `protobuf:"bytes,1,opt,name=hostname" json:"hostname,omitempty"`
 	Port              *string  `protobuf:"bytes,2,opt,name=port" json:"port,omitempty"`
@@ -267,15 +287,17 @@ func (m *Certificate) GetExpires() int64 {
 }
 
 type Registration struct {
-	Id               *int64   `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
-	Key              []byte   `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
-	Contact          []string `protobuf:"bytes,3,rep,name=contact" json:"contact,omitempty"`
-	ContactsPresent  *bool    `protobuf:"varint,4,opt,name=contactsPresent" json:"contactsPresent,omitempty"`
-	Agreement        *string  `protobuf:"bytes,5,opt,name=agreement" json:"agreement,omitempty"`
-	InitialIP        []byte   `protobuf:"bytes,6,opt,name=initialIP" json:"initialIP,omitempty"`
-	CreatedAt        *int64   `protobuf:"varint,7,opt,name=createdAt" json:"createdAt,omitempty"`
-	Status           *string  `protobuf:"bytes,8,opt,name=status" json:"status,omitempty"`
-	XXX_unrecognized []byte   `json:"-"`
+	Id                    *int64   `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Key                   []byte   `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+	Contact               []string `protobuf:"bytes,3,rep,name=contact" json:"contact,omitempty"`
+	ContactsPresent       *bool    `protobuf:"varint,4,opt,name=contactsPresent" json:"contactsPresent,omitempty"`
+	Agreement             *string  `protobuf:"bytes,5,opt,name=agreement" json:"agreement,omitempty"`
+	InitialIP             []byte   `protobuf:"bytes,6,opt,name=initialIP" json:"initialIP,omitempty"`
+	CreatedAt             *int64   `protobuf:"varint,7,opt,name=createdAt" json:"createdAt,omitempty"`
+	Status                *string  `protobuf:"bytes,8,opt,name=status" json:"status,omitempty"`
+	ChallengeTypes        []string `protobuf:"bytes,9,rep,name=challengeTypes" json:"challengeTypes,omitempty"`
+	ChallengeTypesPresent *bool    `protobuf:"varint,10,opt,name=challengeTypesPresent" json:"challengeTypesPresent,omitempty"`
+	XXX_unrecognized      []byte   `json:"-"`
 }
 
 func (m *Registration) Reset()                    { *m = Registration{} }
@@ -339,6 +361,20 @@ func (m *Registration) GetStatus() string {
 	return ""
 }
 
+func (m *Registration) GetChallengeTypes() []string {
+	if m != nil {
+		return m.ChallengeTypes
+	}
+	return nil
+}
+
+func (m *Registration) GetChallengeTypesPresent() bool {
+	if m != nil && m.ChallengeTypesPresent != nil {
+		return *m.ChallengeTypesPresent
+	}
+	return false
+}
+
 type Authorization struct {
 	Id               *string      `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 	Identifier       *string      `protobuf:"bytes,2,opt,name=identifier" json:"identifier,omitempty"`
@@ -415,6 +451,7 @@ type Order struct {
 	Names             []string        `protobuf:"bytes,8,rep,name=names" json:"names,omitempty"`
 	BeganProcessing   *bool           `protobuf:"varint,9,opt,name=beganProcessing" json:"beganProcessing,omitempty"`
 	Created           *int64          `protobuf:"varint,10,opt,name=created" json:"created,omitempty"`
+	Profile           *string         `protobuf:"bytes,11,opt,name=profile" json:"profile,omitempty"`
 	XXX_unrecognized  []byte          `json:"-"`
 }
 
@@ -493,6 +530,13 @@ func (m *Order) GetCreated() int64 {
 	return 0
 }
 
+func (m *Order) GetProfile() string {
+	if m != nil && m.Profile != nil {
+		return *m.Profile
+	}
+	return ""
+}
+
 type Empty struct {
 	XXX_unrecognized []byte `json:"-"`
 }
@@ -516,51 +560,54 @@ func init() {
 func init() { proto1.RegisterFile("core/proto/core.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 723 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0x41, 0x6e, 0xdb, 0x3a,
-	0x10, 0x85, 0x2c, 0x2b, 0xb6, 0xc6, 0xfe, 0x89, 0x43, 0xe4, 0x07, 0xc2, 0xc7, 0x47, 0x20, 0x68,
-	0x51, 0x08, 0x41, 0x91, 0x00, 0xb9, 0x41, 0x9a, 0x74, 0x91, 0x55, 0x0d, 0x26, 0xed, 0xa2, 0x3b,
-	0x45, 0x9a, 0xda, 0x6c, 0x64, 0xc9, 0x20, 0xe9, 0xa0, 0xee, 0x1d, 0x7a, 0x90, 0x1e, 0xa6, 0xd7,
-	0xe8, 0xb2, 0x67, 0x28, 0x66, 0x28, 0xdb, 0xb2, 0x9d, 0xa2, 0xbb, 0x99, 0x47, 0xca, 0x9c, 0x79,
-	0xef, 0xcd, 0x18, 0xfe, 0xcd, 0x6b, 0x8d, 0x97, 0x73, 0x5d, 0xdb, 0xfa, 0x92, 0xc2, 0x0b, 0x0e,
-	0x45, 0x97, 0xe2, 0xe4, 0x5b, 0x07, 0xc2, 0x9b, 0x69, 0x56, 0x96, 0x58, 0x4d, 0x50, 0x1c, 0x42,
-	0x47, 0x15, 0x91, 0x17, 0x7b, 0xa9, 0x2f, 0x3b, 0xaa, 0x10, 0x02, 0xba, 0x76, 0x39, 0xc7, 0xa8,
-	0x13, 0x7b, 0x69, 0x28, 0x39, 0x16, 0xa7, 0x70, 0x60, 0x6c, 0x66, 0x17, 0x26, 0x3a, 0x60, 0xb4,
-	0xc9, 0xc4, 0x08, 0xfc, 0x85, 0x56, 0x51, 0xc8, 0x20, 0x85, 0xe2, 0x04, 0x02, 0x5b, 0x3f, 0x61,
-	0x15, 0xf9, 0x8c, 0xb9, 0x44, 0x9c, 0xc3, 0xe8, 0x09, 0x97, 0xd7, 0x0b, 0x3b, 0xad, 0xb5, 0xfa,
-	0x9a, 0x59, 0x55, 0x57, 0x51, 0xc0, 0x17, 0xf6, 0x70, 0x71, 0x0b, 0xc7, 0xcf, 0x59, 0xa9, 0x0a,
-	0xce, 0x34, 0xe6, 0xb5, 0x2e, 0x4c, 0x04, 0xb1, 0x9f, 0x0e, 0xae, 0x4e, 0x2f, 0xb8, 0x97, 0x0f,
-	0xeb, 0x63, 0xc9, 0xc7, 0x72, 0xff, 0x03, 0x71, 0x0e, 0x01, 0x6a, 0x5d, 0xeb, 0xa8, 0x17, 0x7b,
-	0xe9, 0xe0, 0xea, 0xc4, 0x7d, 0x39, 0xd6, 0xf5, 0x63, 0x89, 0xb3, 0x5b, 0xb4, 0x99, 0x2a, 0x8d,
-	0x74, 0x57, 0x92, 0x5f, 0x1e, 0x8c, 0x76, 0x7f, 0x53, 0xfc, 0x07, 0xfd, 0x69, 0x6d, 0x6c, 0x95,
-	0xcd, 0x90, 0xc9, 0x09, 0xe5, 0x3a, 0x27, 0x8a, 0xe6, 0xb5, 0xb6, 0x2b, 0x8a, 0x28, 0x16, 0xaf,
-	0xe1, 0x38, 0x2b, 0x0a, 0x8d, 0xc6, 0xa0, 0x91, 0x68, 0xea, 0xf2, 0x19, 0x8b, 0xc8, 0x8f, 0xfd,
-	0x74, 0x28, 0xf7, 0x0f, 0x44, 0x0c, 0x83, 0x06, 0x7c, 0x6f, 0xb0, 0x88, 0xba, 0xb1, 0x97, 0x0e,
-	0x65, 0x1b, 0xe2, 0x1b, 0x8e, 0x17, 0xab, 0xd0, 0x44, 0x41, 0xec, 0xa7, 0xa1, 0x6c, 0x43, 0x8e,
-	0xfc, 0xb2, 0x51, 0x84, 0x42, 0xf1, 0x0a, 0x0e, 0xd7, 0x4f, 0x3d, 0x68, 0x85, 0x45, 0xd4, 0xe3,
-	0x02, 0x76, 0xd0, 0xe4, 0x33, 0x1c, 0x6e, 0x33, 0x41, 0xaf, 0xcd, 0x1d, 0xf2, 0x40, 0xda, 0xbb,
-	0x86, 0xdb, 0x10, 0x59, 0xa0, 0xe0, 0xcb, 0x4d, 0xd7, 0x4d, 0x26, 0xce, 0x00, 0xa6, 0xd6, 0xce,
-	0xef, 0x9d, 0x3d, 0x48, 0xf5, 0x40, 0xb6, 0x90, 0xe4, 0xbb, 0x07, 0x83, 0x1b, 0xd4, 0x56, 0x7d,
-	0x52, 0x79, 0x66, 0x91, 0x6a, 0xd4, 0x38, 0x51, 0xc6, 0x6a, 0x66, 0xfb, 0xee, 0xb6, 0xb1, 0xde,
-	0x0e, 0xca, 0x96, 0x43, 0xad, 0xb2, 0xf5, 0x7b, 0x2e, 0xe3, 0x3a, 0xd4, 0x04, 0x8d, 0x6d, 0x1c,
-	0xd6, 0x64, 0xc4, 0x46, 0x81, 0xba, 0x61, 0x92, 0x42, 0xba, 0xa9, 0x8c, 0x59, 0x60, 0xc1, 0x56,
-	0xf3, 0x65, 0x93, 0x89, 0x08, 0x7a, 0xf8, 0x65, 0xae, 0x34, 0x3a, 0x37, 0xfb, 0x72, 0x95, 0x26,
-	0x3f, 0x3d, 0x18, 0xca, 0x56, 0x19, 0x7b, 0xb3, 0x31, 0x02, 0xff, 0x09, 0x97, 0x5c, 0xd1, 0x50,
-	0x52, 0x48, 0x3f, 0x96, 0xd7, 0x95, 0xcd, 0x72, 0xcb, 0x62, 0x87, 0x72, 0x95, 0x8a, 0x14, 0x8e,
-	0x9a, 0xd0, 0x8c, 0x35, 0x1a, 0xac, 0x2c, 0x17, 0xd7, 0x97, 0xbb, 0xb0, 0xf8, 0x1f, 0xc2, 0x6c,
-	0xa2, 0x11, 0x67, 0x74, 0xc7, 0x8d, 0xc5, 0x06, 0xa0, 0x53, 0x55, 0x29, 0xab, 0xb2, 0xf2, 0x6e,
-	0xcc, 0x05, 0x0f, 0xe5, 0x06, 0xa0, 0xd3, 0x5c, 0x63, 0x66, 0xb1, 0xb8, 0xb6, 0xec, 0x75, 0x5f,
-	0x6e, 0x80, 0xd6, 0xdc, 0xf6, 0xdb, 0x73, 0x4b, 0x8e, 0xff, 0x67, 0x7b, 0xea, 0x36, 0x9d, 0x86,
-	0xdc, 0xe9, 0x19, 0x80, 0x2a, 0xb0, 0x22, 0xd9, 0x50, 0x37, 0x12, 0xb4, 0x90, 0x17, 0x64, 0xf4,
-	0xff, 0x28, 0xa3, 0xab, 0xa0, 0xbb, 0xb5, 0x39, 0x5a, 0x22, 0x04, 0x5b, 0x22, 0x88, 0x4b, 0x80,
-	0x7c, 0xb5, 0x9c, 0x48, 0x21, 0x1a, 0xfc, 0x23, 0x37, 0xbe, 0xeb, 0xa5, 0x25, 0x5b, 0x57, 0x44,
-	0x02, 0xc3, 0xbc, 0x9e, 0x3d, 0xaa, 0x8a, 0xdf, 0x34, 0xcc, 0xc2, 0x50, 0x6e, 0x61, 0xc9, 0x8f,
-	0x0e, 0x04, 0xef, 0x34, 0xb9, 0x62, 0x57, 0xd2, 0xfd, 0x46, 0x3a, 0x2f, 0x36, 0xd2, 0x2a, 0xd8,
-	0xdf, 0x2e, 0x78, 0xbd, 0x6a, 0xba, 0x7f, 0x5d, 0x35, 0xb4, 0x25, 0xf2, 0xcd, 0x30, 0xdc, 0x3b,
-	0x83, 0x3b, 0xc9, 0xf7, 0x0f, 0x78, 0x9e, 0xdb, 0x2a, 0x39, 0x3a, 0x42, 0xb9, 0x83, 0xb6, 0x48,
-	0xee, 0x6d, 0x91, 0x7c, 0x02, 0x01, 0xed, 0x2b, 0x52, 0x9f, 0x3e, 0x73, 0x09, 0x19, 0xf3, 0x11,
-	0x27, 0x59, 0x35, 0xd6, 0x75, 0x8e, 0xc6, 0xa8, 0x6a, 0xc2, 0x0b, 0xbc, 0x2f, 0x77, 0x61, 0x36,
-	0xb7, 0xf3, 0x52, 0x04, 0xae, 0xe7, 0x26, 0x4d, 0x7a, 0x10, 0xbc, 0x9d, 0xcd, 0xed, 0xf2, 0x4d,
-	0xef, 0x63, 0xc0, 0x7f, 0x2d, 0xbf, 0x03, 0x00, 0x00, 0xff, 0xff, 0xf6, 0x75, 0x46, 0x28, 0x72,
-	0x06, 0x00, 0x00,
+	// 780 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0xc1, 0x8e, 0xdb, 0x46,
+	0x0c, 0x85, 0x2d, 0x2b, 0xb6, 0x68, 0xd5, 0xf1, 0x0e, 0x36, 0x81, 0x50, 0x14, 0x81, 0xa0, 0x43,
+	0x20, 0x04, 0x41, 0x16, 0x08, 0xfa, 0x03, 0x69, 0xb6, 0x87, 0x9c, 0x6a, 0x4c, 0xd2, 0x1e, 0x7a,
+	0x9b, 0x95, 0xb8, 0xf6, 0x74, 0x65, 0x8d, 0x30, 0x33, 0x5e, 0xd4, 0xfd, 0xa3, 0xfe, 0x47, 0x6f,
+	0xfd, 0x90, 0xde, 0xfa, 0x0d, 0xc5, 0x70, 0x24, 0x5b, 0x92, 0xb7, 0xc8, 0x8d, 0x7c, 0xa4, 0x3c,
+	0xe4, 0xe3, 0x23, 0x0d, 0x2f, 0x0a, 0xa5, 0xf1, 0xa6, 0xd1, 0xca, 0xaa, 0x1b, 0x67, 0xbe, 0x23,
+	0x93, 0xcd, 0x9c, 0x9d, 0xfd, 0x3d, 0x85, 0xe8, 0xe3, 0x4e, 0x54, 0x15, 0xd6, 0x5b, 0x64, 0x2b,
+	0x98, 0xca, 0x32, 0x99, 0xa4, 0x93, 0x3c, 0xe0, 0x53, 0x59, 0x32, 0x06, 0x33, 0x7b, 0x6c, 0x30,
+	0x99, 0xa6, 0x93, 0x3c, 0xe2, 0x64, 0xb3, 0x97, 0xf0, 0xcc, 0x58, 0x61, 0x0f, 0x26, 0x79, 0x46,
+	0x68, 0xeb, 0xb1, 0x35, 0x04, 0x07, 0x2d, 0x93, 0x88, 0x40, 0x67, 0xb2, 0x6b, 0x08, 0xad, 0x7a,
+	0xc0, 0x3a, 0x09, 0x08, 0xf3, 0x0e, 0x7b, 0x03, 0xeb, 0x07, 0x3c, 0x7e, 0x38, 0xd8, 0x9d, 0xd2,
+	0xf2, 0x0f, 0x61, 0xa5, 0xaa, 0x93, 0x90, 0x12, 0x2e, 0x70, 0x76, 0x0b, 0x57, 0x8f, 0xa2, 0x92,
+	0x25, 0x79, 0x1a, 0x0b, 0xa5, 0x4b, 0x93, 0x40, 0x1a, 0xe4, 0xcb, 0xf7, 0x2f, 0xdf, 0x51, 0x2f,
+	0xbf, 0x9c, 0xc2, 0x9c, 0xc2, 0xfc, 0xf2, 0x03, 0xf6, 0x06, 0x42, 0xd4, 0x5a, 0xe9, 0x64, 0x9e,
+	0x4e, 0xf2, 0xe5, 0xfb, 0x6b, 0xff, 0xe5, 0x46, 0xab, 0xbb, 0x0a, 0xf7, 0xb7, 0x68, 0x85, 0xac,
+	0x0c, 0xf7, 0x29, 0xec, 0x3b, 0x88, 0x0a, 0x21, 0xfc, 0x6f, 0x25, 0xcb, 0x74, 0x92, 0xc7, 0xfc,
+	0x0c, 0xb0, 0x57, 0x00, 0x1a, 0x9b, 0x83, 0xf5, 0x55, 0xc7, 0x14, 0xee, 0x21, 0xd9, 0xbf, 0x13,
+	0x58, 0x8f, 0x2b, 0x62, 0xdf, 0xc2, 0x62, 0xa7, 0x8c, 0xad, 0xc5, 0x1e, 0x89, 0xda, 0x88, 0x9f,
+	0x7c, 0x47, 0x70, 0xa3, 0xb4, 0xed, 0x08, 0x76, 0x36, 0x7b, 0x0b, 0x57, 0xa2, 0x2c, 0x35, 0x1a,
+	0x83, 0x86, 0xa3, 0x51, 0xd5, 0x23, 0x96, 0x49, 0x90, 0x06, 0x79, 0xcc, 0x2f, 0x03, 0x2c, 0x85,
+	0x65, 0x0b, 0xfe, 0x6c, 0xb0, 0x4c, 0x66, 0x54, 0x53, 0x1f, 0xa2, 0x0c, 0xcf, 0xaa, 0x95, 0x68,
+	0x92, 0x30, 0x0d, 0xf2, 0x88, 0xf7, 0x21, 0x3f, 0xba, 0xaa, 0x9d, 0xa7, 0x33, 0xd9, 0x6b, 0x58,
+	0x9d, 0x9e, 0xfa, 0xa2, 0x25, 0x96, 0xc9, 0x9c, 0x0a, 0x18, 0xa1, 0xd9, 0x6f, 0xb0, 0x1a, 0xf2,
+	0xe8, 0x5e, 0x6b, 0x3c, 0xf2, 0xe5, 0xd8, 0x74, 0x0d, 0xf7, 0x21, 0x27, 0xa0, 0x92, 0x92, 0xdb,
+	0xae, 0x5b, 0xcf, 0x91, 0xbb, 0xb3, 0xb6, 0xf9, 0xec, 0xc5, 0xe5, 0x34, 0x13, 0xf2, 0x1e, 0x92,
+	0xfd, 0x39, 0x81, 0xe5, 0x47, 0xd4, 0x56, 0xde, 0xcb, 0x42, 0x58, 0x74, 0x35, 0x6a, 0xdc, 0x4a,
+	0x63, 0x35, 0xb1, 0xfd, 0xe9, 0xb6, 0x15, 0xee, 0x08, 0x25, 0xc1, 0xa2, 0x96, 0xe2, 0xf4, 0x9e,
+	0xf7, 0xa8, 0x0e, 0xb9, 0x45, 0x63, 0x5b, 0x7d, 0xb6, 0x9e, 0x63, 0xa3, 0x44, 0xdd, 0x32, 0xe9,
+	0x4c, 0x97, 0x29, 0x8d, 0x39, 0x60, 0x49, 0x42, 0x0d, 0x78, 0xeb, 0xb1, 0x04, 0xe6, 0xf8, 0x7b,
+	0x23, 0x35, 0xfa, 0x5d, 0x08, 0x78, 0xe7, 0x66, 0x7f, 0x4d, 0x21, 0xe6, 0xbd, 0x32, 0x2e, 0x36,
+	0x6b, 0x0d, 0xc1, 0x03, 0x1e, 0xa9, 0xa2, 0x98, 0x3b, 0xd3, 0xfd, 0x58, 0xa1, 0x6a, 0x2b, 0x0a,
+	0x4b, 0xc3, 0x8e, 0x78, 0xe7, 0xb2, 0x1c, 0x9e, 0xb7, 0xa6, 0xd9, 0x68, 0x34, 0x58, 0x5b, 0x2a,
+	0x6e, 0xc1, 0xc7, 0xb0, 0x53, 0xaf, 0xd8, 0x6a, 0xc4, 0xbd, 0xcb, 0xf1, 0x4b, 0x75, 0x06, 0x5c,
+	0x54, 0xd6, 0xd2, 0x4a, 0x51, 0x7d, 0xda, 0x50, 0xc1, 0x31, 0x3f, 0x03, 0xa4, 0x7c, 0x8d, 0xc2,
+	0x62, 0xf9, 0xc1, 0xd2, 0xa6, 0x04, 0xfc, 0x0c, 0xf4, 0xb6, 0x7e, 0x31, 0xd8, 0xfa, 0xd7, 0xb0,
+	0x2a, 0xba, 0xf3, 0xe1, 0xa6, 0x6b, 0x92, 0x88, 0x8a, 0x1f, 0xa1, 0xec, 0x7b, 0x78, 0x31, 0x44,
+	0xba, 0x4e, 0x80, 0x3a, 0x79, 0x3a, 0xe8, 0xf6, 0xe9, 0x9b, 0xe1, 0x45, 0x38, 0xf3, 0x18, 0x11,
+	0x8f, 0xaf, 0x00, 0x64, 0x89, 0xb5, 0x13, 0x05, 0xea, 0x76, 0xc0, 0x3d, 0xe4, 0x09, 0x91, 0x04,
+	0xff, 0x2b, 0x12, 0xdf, 0xdf, 0x6c, 0xd0, 0x5f, 0x6f, 0xc4, 0xe1, 0x60, 0xc4, 0xec, 0x06, 0xe0,
+	0x54, 0xb4, 0x9b, 0xbf, 0x3b, 0x4a, 0xcf, 0xfd, 0x69, 0x39, 0x1d, 0x54, 0xde, 0x4b, 0x61, 0x19,
+	0xc4, 0x85, 0xda, 0xdf, 0xc9, 0x9a, 0xde, 0x34, 0xc4, 0x71, 0xcc, 0x07, 0x58, 0xf6, 0xcf, 0x14,
+	0xc2, 0x9f, 0xb4, 0xd3, 0xdc, 0x58, 0x30, 0x97, 0x8d, 0x4c, 0x9f, 0x6c, 0xa4, 0x57, 0x70, 0x30,
+	0x2c, 0xf8, 0x74, 0x06, 0x67, 0x5f, 0x3f, 0x83, 0x6f, 0xe1, 0xaa, 0x38, 0xaf, 0xda, 0x67, 0xbf,
+	0x3e, 0x5e, 0x50, 0x97, 0x01, 0xba, 0x16, 0xfd, 0x29, 0x79, 0x3a, 0x22, 0x3e, 0x42, 0x7b, 0x24,
+	0xcf, 0x07, 0x24, 0x5f, 0x43, 0xe8, 0xae, 0xa1, 0xd3, 0x96, 0xfb, 0xcc, 0x3b, 0x4e, 0xf6, 0x77,
+	0xb8, 0x15, 0xf5, 0x46, 0xab, 0x02, 0x8d, 0x91, 0xf5, 0x96, 0xfe, 0x5c, 0x16, 0x7c, 0x0c, 0xd3,
+	0xea, 0x78, 0xa5, 0x92, 0x9c, 0x02, 0xde, 0xb9, 0x2e, 0xd2, 0x68, 0x75, 0x2f, 0x2b, 0xa4, 0x63,
+	0x1e, 0xf1, 0xce, 0xcd, 0xe6, 0x10, 0xfe, 0xb8, 0x6f, 0xec, 0xf1, 0x87, 0xf9, 0xaf, 0x21, 0xfd,
+	0x21, 0xfe, 0x37, 0x00, 0x8a, 0x00, 0x92, 0x80, 0x28, 0x07, 0x00, 0x00,
 }
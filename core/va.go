@@ -9,12 +9,14 @@ import (
 // ValidationAuthority defines the public interface for the Boulder VA
 type ValidationAuthority interface {
 	// PerformValidation checks the challenge with the given index in the
-	// given Authorization and returns the updated ValidationRecords.
+	// given Authorization and returns the updated ValidationRecords, along
+	// with a record of the CAA check if one was made and the verdicts of the
+	// domain reputation checks.
 	//
 	// A failure to validate the Challenge will result in a error of type
 	// *probs.ProblemDetails.
 	//
 	// TODO(#1626): remove authz parameter
-	PerformValidation(ctx context.Context, domain string, challenge Challenge, authz Authorization) ([]ValidationRecord, error)
+	PerformValidation(ctx context.Context, domain string, challenge Challenge, authz Authorization) ([]ValidationRecord, *CAAValidationRecord, []ReputationVerdict, error)
 	IsSafeDomain(ctx context.Context, req *vaPB.IsSafeDomainRequest) (resp *vaPB.IsDomainSafe, err error)
 }
@@ -83,10 +83,10 @@ func VerifyCSR(csr *x509.CertificateRequest, maxNames int, keyPolicy *goodkey.Ke
 		var err error
 		// If wildcard names are enabled then use WillingToIssueWildcard
 		if features.Enabled(features.WildcardDomains) {
-			err = pa.WillingToIssueWildcard(ident)
+			err = pa.WillingToIssueWildcard(ident, regID)
 		} else {
 			// Otherwise use WillingToIssue
-			err = pa.WillingToIssue(ident)
+			err = pa.WillingToIssue(ident, regID)
 		}
 		if err != nil {
 			badNames = append(badNames, fmt.Sprintf("%q", name))
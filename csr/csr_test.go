@@ -23,18 +23,18 @@ var testingPolicy = &goodkey.KeyPolicy{
 
 type mockPA struct{}
 
-func (pa *mockPA) ChallengesFor(identifier core.AcmeIdentifier, registrationID int64, revalidation bool) (challenges []core.Challenge, combinations [][]int, err error) {
+func (pa *mockPA) ChallengesFor(identifier core.AcmeIdentifier, reg core.Registration, revalidation bool) (challenges []core.Challenge, combinations [][]int, err error) {
 	return
 }
 
-func (pa *mockPA) WillingToIssue(id core.AcmeIdentifier) error {
+func (pa *mockPA) WillingToIssue(id core.AcmeIdentifier, regID int64) error {
 	if id.Value == "bad-name.com" || id.Value == "other-bad-name.com" {
 		return errors.New("")
 	}
 	return nil
 }
 
-func (pa *mockPA) WillingToIssueWildcard(id core.AcmeIdentifier) error {
+func (pa *mockPA) WillingToIssueWildcard(id core.AcmeIdentifier, regID int64) error {
 	return nil
 }
 
@@ -42,6 +42,14 @@ func (pa *mockPA) ChallengeTypeEnabled(t string, registrationID int64) bool {
 	return true
 }
 
+func (pa *mockPA) AccountAllowsChallengeType(t string, reg core.Registration) bool {
+	return true
+}
+
+func (pa *mockPA) ContactSchemeAllowed(scheme string) bool {
+	return scheme == "mailto"
+}
+
 func TestVerifyCSR(t *testing.T) {
 	private, err := rsa.GenerateKey(rand.Reader, 2048)
 	test.AssertNotError(t, err, "error generating test key")
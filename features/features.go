@@ -15,6 +15,8 @@ const (
 	// authz, return that instead of creating a new one.
 	ReusePendingAuthz
 	CountCertificatesExact
+	// IPv6First is deprecated, the VA tries IPv6 addresses first and falls
+	// back to IPv4 unless its HTTP-01 policy says otherwise
 	IPv6First
 	AllowRenewalFirstRL
 	// Allow issuance of wildcard domains for ACMEv2
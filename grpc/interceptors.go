@@ -1,6 +1,7 @@
 package grpc
 
 import (
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/letsencrypt/boulder/core"
 	berrors "github.com/letsencrypt/boulder/errors"
 	"github.com/letsencrypt/boulder/features"
 )
@@ -21,6 +23,7 @@ const (
 	meaningfulWorkOverhead = 100 * time.Millisecond
 	clientRequestTimeKey   = "client-request-time"
 	serverLatencyKey       = "server-latency"
+	requestIPKey           = "request-ip"
 )
 
 // serverInterceptor is a gRPC interceptor that adds Prometheus
@@ -52,6 +55,14 @@ func (si *serverInterceptor) intercept(ctx context.Context, req interface{}, inf
 		}
 	}
 
+	// Restore the IP of the client whose request led to this RPC, if the
+	// caller forwarded one.
+	if md, ok := metadata.FromContext(ctx); ok && len(md[requestIPKey]) > 0 {
+		if ip := net.ParseIP(md[requestIPKey][0]); ip != nil {
+			ctx = core.WithRequestIP(ctx, ip)
+		}
+	}
+
 	if features.Enabled(features.RPCHeadroom) {
 		// Shave 20 milliseconds off the deadline to ensure that if the RPC server times
 		// out any sub-calls it makes (like DNS lookups, or onwards RPCs), it has a
@@ -156,6 +167,10 @@ func (ci *clientInterceptor) intercept(
 	// Create a grpc/metadata.Metadata instance for the request metadata.
 	// Initialize it with the request time.
 	reqMD := metadata.New(map[string]string{clientRequestTimeKey: nowTS})
+	// Forward the IP of the client whose request led to this RPC, if known.
+	if ip := core.RequestIP(ctx); ip != nil {
+		reqMD[requestIPKey] = []string{ip.String()}
+	}
 	// Configure the localCtx with the metadata so it gets sent along in the request
 	localCtx = metadata.NewContext(localCtx, reqMD)
 
@@ -17,6 +17,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/letsencrypt/boulder/core"
 	"github.com/letsencrypt/boulder/features"
 	"github.com/letsencrypt/boulder/grpc/test_proto"
 	"github.com/letsencrypt/boulder/metrics"
@@ -74,6 +75,39 @@ func TestClientInterceptor(t *testing.T) {
 	test.AssertError(t, err, "ci.intercept didn't fail when handler returned a error")
 }
 
+func TestRequestIPForwarding(t *testing.T) {
+	ci := clientInterceptor{
+		timeout: time.Second,
+		metrics: NewClientMetrics(metrics.NewNoopScope()),
+		clk:     clock.NewFake(),
+	}
+	var sent metadata.MD
+	invoker := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		sent, _ = metadata.FromContext(ctx)
+		return nil
+	}
+	ctx := core.WithRequestIP(context.Background(), net.ParseIP("10.0.0.1"))
+	err := ci.intercept(ctx, "-service-test", nil, nil, nil, invoker)
+	test.AssertNotError(t, err, "ci.intercept failed")
+	test.AssertDeepEquals(t, sent[requestIPKey], []string{"10.0.0.1"})
+
+	// Without a request IP, none is sent
+	err = ci.intercept(context.Background(), "-service-test", nil, nil, nil, invoker)
+	test.AssertNotError(t, err, "ci.intercept failed")
+	test.AssertEquals(t, len(sent[requestIPKey]), 0)
+
+	si := newServerInterceptor(NewServerMetrics(metrics.NewNoopScope()), clock.NewFake())
+	var received net.IP
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		received = core.RequestIP(ctx)
+		return nil, nil
+	}
+	md := metadata.New(map[string]string{requestIPKey: "10.0.0.1"})
+	_, err = si.intercept(metadata.NewContext(context.Background(), md), nil, &grpc.UnaryServerInfo{FullMethod: "-service-test"}, handler)
+	test.AssertNotError(t, err, "si.intercept failed")
+	test.AssertEquals(t, received.String(), "10.0.0.1")
+}
+
 // TestFailFastFalse sends a gRPC request to a backend that is
 // unavailable, and ensures that the request doesn't error out until the
 // timeout is reached, i.e. that FailFast is set to false.
@@ -100,6 +100,14 @@ func ChallengeToPB(challenge core.Challenge) (*corepb.Challenge, error) {
 			return nil, err
 		}
 	}
+	caaRecord, err := caaRecordToPB(challenge.CAARecord)
+	if err != nil {
+		return nil, err
+	}
+	reputation, err := reputationToPB(challenge.Reputation)
+	if err != nil {
+		return nil, err
+	}
 	return &corepb.Challenge{
 		Id:                &challenge.ID,
 		Type:              &challenge.Type,
@@ -108,6 +116,8 @@ func ChallengeToPB(challenge core.Challenge) (*corepb.Challenge, error) {
 		KeyAuthorization:  &challenge.ProvidedKeyAuthorization,
 		Error:             prob,
 		Validationrecords: recordAry,
+		CaaRecord:         caaRecord,
+		Reputation:        reputation,
 	}, nil
 }
 
@@ -132,6 +142,14 @@ func pbToChallenge(in *corepb.Challenge) (challenge core.Challenge, err error) {
 	if err != nil {
 		return core.Challenge{}, err
 	}
+	caaRecord, err := pbToCAARecord(in.CaaRecord)
+	if err != nil {
+		return core.Challenge{}, err
+	}
+	reputation, err := pbToReputation(in.Reputation)
+	if err != nil {
+		return core.Challenge{}, err
+	}
 	return core.Challenge{
 		ID:     *in.Id,
 		Type:   *in.Type,
@@ -140,9 +158,53 @@ func pbToChallenge(in *corepb.Challenge) (challenge core.Challenge, err error) {
 		ProvidedKeyAuthorization: *in.KeyAuthorization,
 		Error:            prob,
 		ValidationRecord: recordAry,
+		CAARecord:        caaRecord,
+		Reputation:       reputation,
 	}, nil
 }
 
+// caaRecordToPB JSON-encodes a CAA validation record. A nil record is encoded
+// as nil.
+func caaRecordToPB(record *core.CAAValidationRecord) ([]byte, error) {
+	if record == nil {
+		return nil, nil
+	}
+	return json.Marshal(record)
+}
+
+func pbToCAARecord(in []byte) (*core.CAAValidationRecord, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	var record core.CAAValidationRecord
+	err := json.Unmarshal(in, &record)
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// reputationToPB JSON-encodes reputation verdicts. No verdicts are encoded as
+// nil.
+func reputationToPB(verdicts []core.ReputationVerdict) ([]byte, error) {
+	if len(verdicts) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(verdicts)
+}
+
+func pbToReputation(in []byte) ([]core.ReputationVerdict, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	var verdicts []core.ReputationVerdict
+	err := json.Unmarshal(in, &verdicts)
+	if err != nil {
+		return nil, err
+	}
+	return verdicts, nil
+}
+
 func validationRecordToPB(record core.ValidationRecord) (*corepb.ValidationRecord, error) {
 	addrs := make([][]byte, len(record.AddressesResolved))
 	addrsTried := make([][]byte, len(record.AddressesTried))
@@ -199,7 +261,7 @@ func pbToValidationRecord(in *corepb.ValidationRecord) (record core.ValidationRe
 	}, nil
 }
 
-func validationResultToPB(records []core.ValidationRecord, prob *probs.ProblemDetails) (*vapb.ValidationResult, error) {
+func validationResultToPB(records []core.ValidationRecord, caaRecord *core.CAAValidationRecord, reputation []core.ReputationVerdict, prob *probs.ProblemDetails) (*vapb.ValidationResult, error) {
 	recordAry := make([]*corepb.ValidationRecord, len(records))
 	var err error
 	for i, v := range records {
@@ -212,29 +274,47 @@ func validationResultToPB(records []core.ValidationRecord, prob *probs.ProblemDe
 	if err != nil {
 		return nil, err
 	}
+	marshalledCAARecord, err := caaRecordToPB(caaRecord)
+	if err != nil {
+		return nil, err
+	}
+	marshalledReputation, err := reputationToPB(reputation)
+	if err != nil {
+		return nil, err
+	}
 	return &vapb.ValidationResult{
-		Records:  recordAry,
-		Problems: marshalledProbs,
+		Records:    recordAry,
+		Problems:   marshalledProbs,
+		CaaRecord:  marshalledCAARecord,
+		Reputation: marshalledReputation,
 	}, nil
 }
 
-func pbToValidationResult(in *vapb.ValidationResult) ([]core.ValidationRecord, *probs.ProblemDetails, error) {
+func pbToValidationResult(in *vapb.ValidationResult) ([]core.ValidationRecord, *core.CAAValidationRecord, []core.ReputationVerdict, *probs.ProblemDetails, error) {
 	if in == nil {
-		return nil, nil, ErrMissingParameters
+		return nil, nil, nil, nil, ErrMissingParameters
 	}
 	recordAry := make([]core.ValidationRecord, len(in.Records))
 	var err error
 	for i, v := range in.Records {
 		recordAry[i], err = pbToValidationRecord(v)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 	}
 	prob, err := PBToProblemDetails(in.Problems)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, nil, err
+	}
+	caaRecord, err := pbToCAARecord(in.CaaRecord)
+	if err != nil {
+		return nil, nil, nil, nil, err
 	}
-	return recordAry, prob, nil
+	reputation, err := pbToReputation(in.Reputation)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return recordAry, caaRecord, reputation, prob, nil
 }
 
 func performValidationReqToArgs(in *vapb.PerformValidationRequest) (domain string, challenge core.Challenge, authz core.Authorization, err error) {
@@ -295,15 +375,24 @@ func registrationToPB(reg core.Registration) (*corepb.Registration, error) {
 	if reg.Contact != nil {
 		contacts = *reg.Contact
 	}
+	// ChallengeTypes needs the same indicator, since an empty list clears the
+	// account's challenge preferences.
+	var challengeTypes []string
+	challengeTypesPresent := reg.ChallengeTypes != nil
+	if reg.ChallengeTypes != nil {
+		challengeTypes = *reg.ChallengeTypes
+	}
 	return &corepb.Registration{
-		Id:              &reg.ID,
-		Key:             keyBytes,
-		Contact:         contacts,
-		ContactsPresent: &contactsPresent,
-		Agreement:       &reg.Agreement,
-		InitialIP:       ipBytes,
-		CreatedAt:       &createdAt,
-		Status:          &status,
+		Id:                    &reg.ID,
+		Key:                   keyBytes,
+		Contact:               contacts,
+		ContactsPresent:       &contactsPresent,
+		Agreement:             &reg.Agreement,
+		InitialIP:             ipBytes,
+		CreatedAt:             &createdAt,
+		Status:                &status,
+		ChallengeTypes:        challengeTypes,
+		ChallengeTypesPresent: &challengeTypesPresent,
 	}, nil
 }
 
@@ -332,14 +421,23 @@ func pbToRegistration(pb *corepb.Registration) (core.Registration, error) {
 			contacts = &empty
 		}
 	}
+	var challengeTypes *[]string
+	if pb.GetChallengeTypesPresent() {
+		types := pb.ChallengeTypes
+		if types == nil {
+			types = []string{}
+		}
+		challengeTypes = &types
+	}
 	return core.Registration{
-		ID:        *pb.Id,
-		Key:       &key,
-		Contact:   contacts,
-		Agreement: *pb.Agreement,
-		InitialIP: initialIP,
-		CreatedAt: time.Unix(0, *pb.CreatedAt),
-		Status:    core.AcmeStatus(*pb.Status),
+		ID:             *pb.Id,
+		Key:            &key,
+		Contact:        contacts,
+		Agreement:      *pb.Agreement,
+		InitialIP:      initialIP,
+		CreatedAt:      time.Unix(0, *pb.CreatedAt),
+		Status:         core.AcmeStatus(*pb.Status),
+		ChallengeTypes: challengeTypes,
 	}, nil
 }
 
@@ -127,6 +127,16 @@ func TestChallenge(t *testing.T) {
 		},
 	}
 	chall.Error = &probs.ProblemDetails{Type: probs.TLSProblem, Detail: "asd", HTTPStatus: 200}
+	chall.CAARecord = &core.CAAValidationRecord{
+		QueryName: "example.com",
+		RRSet:     []string{`0 issue "example.net"`},
+		Resolver:  "127.0.0.1:53",
+		Present:   true,
+		CheckedAt: time.Date(2018, 7, 14, 1, 2, 3, 0, time.UTC),
+	}
+	chall.Reputation = []core.ReputationVerdict{
+		{Provider: "gsb", CheckedAt: time.Date(2018, 7, 15, 1, 2, 3, 0, time.UTC)},
+	}
 	pb, err = ChallengeToPB(chall)
 	test.AssertNotError(t, err, "ChallengeToPB failed")
 	test.Assert(t, pb != nil, "Returned corepb.Challenge is nil")
@@ -187,14 +197,51 @@ func TestValidationResult(t *testing.T) {
 	result := []core.ValidationRecord{vrA, vrB}
 	prob := &probs.ProblemDetails{Type: probs.TLSProblem, Detail: "asd", HTTPStatus: 200}
 
-	pb, err := validationResultToPB(result, prob)
+	caaRecord := &core.CAAValidationRecord{
+		QueryName:     "example.com",
+		RRSet:         []string{`0 issue "letsencrypt.org; validationmethods=http-01"`},
+		MatchedRecord: `0 issue "letsencrypt.org; validationmethods=http-01"`,
+		Parameters:    map[string]string{"validationmethods": "http-01"},
+		Identity:      "letsencrypt.org",
+		Resolver:      "127.0.0.1:53",
+		Present:       true,
+		Valid:         true,
+		CheckedAt:     time.Date(2018, 7, 14, 1, 2, 3, 0, time.UTC),
+	}
+
+	reputation := []core.ReputationVerdict{
+		{
+			Provider:  "blocklist",
+			Reason:    "listed in blocklist as example.com",
+			Blocked:   true,
+			CheckedAt: time.Date(2018, 7, 15, 1, 2, 3, 0, time.UTC),
+		},
+		{
+			Provider:  "rbl",
+			Error:     "DNS problem: SERVFAIL looking up TXT for example.com.rbl.example.net",
+			CheckedAt: time.Date(2018, 7, 15, 1, 2, 3, 0, time.UTC),
+		},
+	}
+
+	pb, err := validationResultToPB(result, caaRecord, reputation, prob)
 	test.AssertNotError(t, err, "validationResultToPB failed")
 	test.Assert(t, pb != nil, "Returned vapb.ValidationResult is nil")
 
-	reconResult, reconProb, err := pbToValidationResult(pb)
+	reconResult, reconCAARecord, reconReputation, reconProb, err := pbToValidationResult(pb)
 	test.AssertNotError(t, err, "pbToValidationResult failed")
 	test.AssertDeepEquals(t, reconResult, result)
+	test.AssertDeepEquals(t, reconCAARecord, caaRecord)
+	test.AssertDeepEquals(t, reconReputation, reputation)
 	test.AssertDeepEquals(t, reconProb, prob)
+
+	// A result without a CAA record or reputation verdicts round trips
+	// without them
+	pb, err = validationResultToPB(result, nil, nil, nil)
+	test.AssertNotError(t, err, "validationResultToPB failed")
+	_, reconCAARecord, reconReputation, _, err = pbToValidationResult(pb)
+	test.AssertNotError(t, err, "pbToValidationResult failed")
+	test.Assert(t, reconCAARecord == nil, "Expected a nil CAA record")
+	test.Assert(t, reconReputation == nil, "Expected nil reputation verdicts")
 }
 
 func TestPerformValidationReq(t *testing.T) {
@@ -263,6 +310,27 @@ func TestRegistration(t *testing.T) {
 	outReg, err = pbToRegistration(pbReg)
 	test.AssertNotError(t, err, "pbToRegistration failed")
 	test.Assert(t, *outReg.Contact != nil, "Empty slice was converted to a nil slice")
+
+	inReg.ChallengeTypes = &[]string{core.ChallengeTypeDNS01}
+	pbReg, err = registrationToPB(inReg)
+	test.AssertNotError(t, err, "registrationToPB failed")
+	outReg, err = pbToRegistration(pbReg)
+	test.AssertNotError(t, err, "pbToRegistration failed")
+	test.AssertDeepEquals(t, *outReg.ChallengeTypes, []string{core.ChallengeTypeDNS01})
+
+	// An empty list of challenge types is distinct from there being none
+	inReg.ChallengeTypes = &[]string{}
+	pbReg, err = registrationToPB(inReg)
+	test.AssertNotError(t, err, "registrationToPB failed")
+	outReg, err = pbToRegistration(pbReg)
+	test.AssertNotError(t, err, "pbToRegistration failed")
+	test.Assert(t, outReg.ChallengeTypes != nil, "Empty challenge types were converted to nil")
+	test.AssertEquals(t, len(*outReg.ChallengeTypes), 0)
+
+	pbReg.ChallengeTypesPresent = nil
+	outReg, err = pbToRegistration(pbReg)
+	test.AssertNotError(t, err, "pbToRegistration failed")
+	test.Assert(t, outReg.ChallengeTypes == nil, "Challenge types weren't nil")
 }
 
 func TestAuthz(t *testing.T) {
@@ -318,6 +318,110 @@ func (sac StorageAuthorityClientWrapper) FQDNSetExists(ctx context.Context, doma
 	return *response.Exists, nil
 }
 
+func (sac StorageAuthorityClientWrapper) GetSuppressedEmails(ctx context.Context, emails []string) ([]string, error) {
+	response, err := sac.inner.GetSuppressedEmails(ctx, &sapb.Emails{Emails: emails})
+	if err != nil {
+		return nil, err
+	}
+
+	if response == nil {
+		return nil, errIncompleteResponse
+	}
+
+	return response.Emails, nil
+}
+
+func (sac StorageAuthorityClientWrapper) AddEmailSuppression(ctx context.Context, email, reason string) error {
+	_, err := sac.inner.AddEmailSuppression(ctx, &sapb.EmailSuppression{Email: &email, Reason: &reason})
+	return err
+}
+
+func (sac StorageAuthorityClientWrapper) AddCAAIodefReport(ctx context.Context, report core.CAAIodefReport) error {
+	_, err := sac.inner.AddCAAIodefReport(ctx, &sapb.CAAIodefReport{
+		Domain:           &report.Domain,
+		AccountID:        &report.AccountID,
+		ValidationMethod: &report.ValidationMethod,
+		Records:          &report.Records,
+		Targets:          report.Targets,
+	})
+	return err
+}
+
+func (sac StorageAuthorityClientWrapper) FinishCAAIodefReport(ctx context.Context, result core.CAAIodefReportResult) error {
+	_, err := sac.inner.FinishCAAIodefReport(ctx, &sapb.FinishCAAIodefReportRequest{
+		Id:               &result.ID,
+		Status:           &result.Status,
+		RegisteredDomain: &result.RegisteredDomain,
+		Results:          &result.Results,
+		Delivered:        result.Delivered,
+	})
+	return err
+}
+
+func (sac StorageAuthorityClientWrapper) GetPendingCAAIodefReports(ctx context.Context, limit int) ([]core.CAAIodefReport, error) {
+	limit64 := int64(limit)
+	response, err := sac.inner.GetPendingCAAIodefReports(ctx, &sapb.GetPendingCAAIodefReportsRequest{Limit: &limit64})
+	if err != nil {
+		return nil, err
+	}
+
+	if response == nil {
+		return nil, errIncompleteResponse
+	}
+
+	reports := make([]core.CAAIodefReport, len(response.Reports))
+	for i, report := range response.Reports {
+		if report == nil || report.Id == nil || report.Domain == nil || report.AccountID == nil ||
+			report.ValidationMethod == nil || report.Records == nil || report.CreatedAt == nil {
+			return nil, errIncompleteResponse
+		}
+		reports[i] = core.CAAIodefReport{
+			ID:               *report.Id,
+			Domain:           *report.Domain,
+			AccountID:        *report.AccountID,
+			ValidationMethod: *report.ValidationMethod,
+			Records:          *report.Records,
+			Targets:          report.Targets,
+			CreatedAt:        time.Unix(0, *report.CreatedAt),
+		}
+	}
+	return reports, nil
+}
+
+func (sac StorageAuthorityClientWrapper) CountCAAIodefReportsSent(ctx context.Context, registeredDomain string, since time.Time) (int, error) {
+	sinceNano := since.UnixNano()
+	response, err := sac.inner.CountCAAIodefReportsSent(ctx, &sapb.CountCAAIodefReportsSentRequest{
+		RegisteredDomain: &registeredDomain,
+		Since:            &sinceNano,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if response == nil || response.Count == nil {
+		return 0, errIncompleteResponse
+	}
+
+	return int(*response.Count), nil
+}
+
+func (sac StorageAuthorityClientWrapper) CountCAAIodefDeliveries(ctx context.Context, target string, since time.Time) (int, error) {
+	sinceNano := since.UnixNano()
+	response, err := sac.inner.CountCAAIodefDeliveries(ctx, &sapb.CountCAAIodefDeliveriesRequest{
+		Target: &target,
+		Since:  &sinceNano,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if response == nil || response.Count == nil {
+		return 0, errIncompleteResponse
+	}
+
+	return int(*response.Count), nil
+}
+
 func (sac StorageAuthorityClientWrapper) NewRegistration(ctx context.Context, reg core.Registration) (core.Registration, error) {
 	regPB, err := registrationToPB(reg)
 	if err != nil {
@@ -843,6 +947,127 @@ func (sas StorageAuthorityServerWrapper) FQDNSetExists(ctx context.Context, requ
 	return &sapb.Exists{Exists: &exists}, nil
 }
 
+func (sas StorageAuthorityServerWrapper) GetSuppressedEmails(ctx context.Context, request *sapb.Emails) (*sapb.Emails, error) {
+	if request == nil {
+		return nil, errIncompleteRequest
+	}
+
+	suppressed, err := sas.inner.GetSuppressedEmails(ctx, request.Emails)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sapb.Emails{Emails: suppressed}, nil
+}
+
+func (sas StorageAuthorityServerWrapper) AddEmailSuppression(ctx context.Context, request *sapb.EmailSuppression) (*corepb.Empty, error) {
+	if request == nil || request.Email == nil || request.Reason == nil {
+		return nil, errIncompleteRequest
+	}
+
+	err := sas.inner.AddEmailSuppression(ctx, *request.Email, *request.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corepb.Empty{}, nil
+}
+
+func (sas StorageAuthorityServerWrapper) AddCAAIodefReport(ctx context.Context, request *sapb.CAAIodefReport) (*corepb.Empty, error) {
+	if request == nil || request.Domain == nil || request.AccountID == nil ||
+		request.ValidationMethod == nil || request.Records == nil {
+		return nil, errIncompleteRequest
+	}
+
+	err := sas.inner.AddCAAIodefReport(ctx, core.CAAIodefReport{
+		Domain:           *request.Domain,
+		AccountID:        *request.AccountID,
+		ValidationMethod: *request.ValidationMethod,
+		Records:          *request.Records,
+		Targets:          request.Targets,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &corepb.Empty{}, nil
+}
+
+func (sas StorageAuthorityServerWrapper) FinishCAAIodefReport(ctx context.Context, request *sapb.FinishCAAIodefReportRequest) (*corepb.Empty, error) {
+	if request == nil || request.Id == nil || request.Status == nil ||
+		request.RegisteredDomain == nil || request.Results == nil {
+		return nil, errIncompleteRequest
+	}
+
+	err := sas.inner.FinishCAAIodefReport(ctx, core.CAAIodefReportResult{
+		ID:               *request.Id,
+		Status:           *request.Status,
+		RegisteredDomain: *request.RegisteredDomain,
+		Results:          *request.Results,
+		Delivered:        request.Delivered,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &corepb.Empty{}, nil
+}
+
+func (sas StorageAuthorityServerWrapper) GetPendingCAAIodefReports(ctx context.Context, request *sapb.GetPendingCAAIodefReportsRequest) (*sapb.CAAIodefReports, error) {
+	if request == nil || request.Limit == nil {
+		return nil, errIncompleteRequest
+	}
+
+	reports, err := sas.inner.GetPendingCAAIodefReports(ctx, int(*request.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &sapb.CAAIodefReports{}
+	for _, report := range reports {
+		report := report
+		createdAt := report.CreatedAt.UnixNano()
+		resp.Reports = append(resp.Reports, &sapb.CAAIodefReport{
+			Id:               &report.ID,
+			Domain:           &report.Domain,
+			AccountID:        &report.AccountID,
+			ValidationMethod: &report.ValidationMethod,
+			Records:          &report.Records,
+			Targets:          report.Targets,
+			CreatedAt:        &createdAt,
+		})
+	}
+	return resp, nil
+}
+
+func (sas StorageAuthorityServerWrapper) CountCAAIodefReportsSent(ctx context.Context, request *sapb.CountCAAIodefReportsSentRequest) (*sapb.Count, error) {
+	if request == nil || request.RegisteredDomain == nil || request.Since == nil {
+		return nil, errIncompleteRequest
+	}
+
+	count, err := sas.inner.CountCAAIodefReportsSent(ctx, *request.RegisteredDomain, time.Unix(0, *request.Since))
+	if err != nil {
+		return nil, err
+	}
+
+	castedCount := int64(count)
+	return &sapb.Count{Count: &castedCount}, nil
+}
+
+func (sas StorageAuthorityServerWrapper) CountCAAIodefDeliveries(ctx context.Context, request *sapb.CountCAAIodefDeliveriesRequest) (*sapb.Count, error) {
+	if request == nil || request.Target == nil || request.Since == nil {
+		return nil, errIncompleteRequest
+	}
+
+	count, err := sas.inner.CountCAAIodefDeliveries(ctx, *request.Target, time.Unix(0, *request.Since))
+	if err != nil {
+		return nil, err
+	}
+
+	castedCount := int64(count)
+	return &sapb.Count{Count: &castedCount}, nil
+}
+
 func (sac StorageAuthorityServerWrapper) PreviousCertificateExists(
 	ctx context.Context,
 	req *sapb.PreviousCertificateExistsRequest,
@@ -1111,3 +1336,261 @@ func (sas StorageAuthorityServerWrapper) AddPendingAuthorizations(ctx context.Co
 
 	return sas.inner.AddPendingAuthorizations(ctx, request)
 }
+
+// StorageAuthorityAdminClientWrapper is the gRPC version of a core.StorageAdmin client
+type StorageAuthorityAdminClientWrapper struct {
+	inner sapb.StorageAuthorityAdminClient
+}
+
+func NewStorageAuthorityAdminClient(inner sapb.StorageAuthorityAdminClient) *StorageAuthorityAdminClientWrapper {
+	return &StorageAuthorityAdminClientWrapper{inner}
+}
+
+func (sac StorageAuthorityAdminClientWrapper) SearchCertificates(ctx context.Context, req *sapb.SearchCertificatesRequest) ([]core.Certificate, error) {
+	response, err := sac.inner.SearchCertificates(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		return nil, errIncompleteResponse
+	}
+
+	certs := make([]core.Certificate, len(response.Certificates))
+	for i, certPB := range response.Certificates {
+		certs[i], err = pbToCert(certPB)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return certs, nil
+}
+
+func (sac StorageAuthorityAdminClientWrapper) SearchRegistrations(ctx context.Context, req *sapb.SearchRegistrationsRequest) ([]core.Registration, error) {
+	response, err := sac.inner.SearchRegistrations(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		return nil, errIncompleteResponse
+	}
+
+	regs := make([]core.Registration, len(response.Registrations))
+	for i, regPB := range response.Registrations {
+		if regPB == nil || !registrationValid(regPB) {
+			return nil, errIncompleteResponse
+		}
+		regs[i], err = pbToRegistration(regPB)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return regs, nil
+}
+
+func (sac StorageAuthorityAdminClientWrapper) GetRegistrationHistory(ctx context.Context, regID int64) ([]core.RegistrationChange, error) {
+	response, err := sac.inner.GetRegistrationHistory(ctx, &sapb.RegistrationID{Id: &regID})
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		return nil, errIncompleteResponse
+	}
+
+	changes := make([]core.RegistrationChange, len(response.Changes))
+	for i, changePB := range response.Changes {
+		if changePB == nil || changePB.Registration == nil || !registrationValid(changePB.Registration) || changePB.ChangedAt == nil {
+			return nil, errIncompleteResponse
+		}
+		reg, err := pbToRegistration(changePB.Registration)
+		if err != nil {
+			return nil, err
+		}
+		var requestIP net.IP
+		err = requestIP.UnmarshalText(changePB.RequestIP)
+		if err != nil {
+			return nil, err
+		}
+		changes[i] = core.RegistrationChange{
+			Registration: reg,
+			ChangedAt:    time.Unix(0, *changePB.ChangedAt),
+			RequestIP:    requestIP,
+		}
+	}
+	return changes, nil
+}
+
+func (sac StorageAuthorityAdminClientWrapper) SearchOrders(ctx context.Context, req *sapb.SearchOrdersRequest) ([]*corepb.Order, error) {
+	response, err := sac.inner.SearchOrders(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		return nil, errIncompleteResponse
+	}
+	for _, order := range response.Orders {
+		if order == nil || !orderValid(order) {
+			return nil, errIncompleteResponse
+		}
+	}
+	return response.Orders, nil
+}
+
+func (sac StorageAuthorityAdminClientWrapper) SearchAuthorizations(ctx context.Context, req *sapb.SearchAuthorizationsRequest) ([]core.Authorization, error) {
+	response, err := sac.inner.SearchAuthorizations(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		return nil, errIncompleteResponse
+	}
+
+	authzs := make([]core.Authorization, len(response.Authorizations))
+	for i, authzPB := range response.Authorizations {
+		if authzPB == nil || !authorizationValid(authzPB) {
+			return nil, errIncompleteResponse
+		}
+		authzs[i], err = PBToAuthz(authzPB)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return authzs, nil
+}
+
+func (sac StorageAuthorityAdminClientWrapper) RegistrationsWithCertificates(ctx context.Context, req *sapb.RegistrationsWithCertificatesRequest) ([]*sapb.RegistrationContacts, error) {
+	response, err := sac.inner.RegistrationsWithCertificates(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		return nil, errIncompleteResponse
+	}
+	for _, reg := range response.Registrations {
+		if reg == nil || reg.Id == nil {
+			return nil, errIncompleteResponse
+		}
+	}
+	return response.Registrations, nil
+}
+
+// StorageAuthorityAdminServerWrapper is the gRPC version of a core.StorageAdmin server
+type StorageAuthorityAdminServerWrapper struct {
+	inner core.StorageAdmin
+}
+
+func NewStorageAuthorityAdminServer(inner core.StorageAdmin) *StorageAuthorityAdminServerWrapper {
+	return &StorageAuthorityAdminServerWrapper{inner}
+}
+
+func (sas StorageAuthorityAdminServerWrapper) SearchCertificates(ctx context.Context, request *sapb.SearchCertificatesRequest) (*sapb.Certificates, error) {
+	if request == nil {
+		return nil, errIncompleteRequest
+	}
+
+	certs, err := sas.inner.SearchCertificates(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &sapb.Certificates{}
+	for _, cert := range certs {
+		resp.Certificates = append(resp.Certificates, certToPB(cert))
+	}
+	return resp, nil
+}
+
+func (sas StorageAuthorityAdminServerWrapper) SearchRegistrations(ctx context.Context, request *sapb.SearchRegistrationsRequest) (*sapb.Registrations, error) {
+	if request == nil || request.Contact == nil {
+		return nil, errIncompleteRequest
+	}
+
+	regs, err := sas.inner.SearchRegistrations(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &sapb.Registrations{}
+	for _, reg := range regs {
+		regPB, err := registrationToPB(reg)
+		if err != nil {
+			return nil, err
+		}
+		resp.Registrations = append(resp.Registrations, regPB)
+	}
+	return resp, nil
+}
+
+func (sas StorageAuthorityAdminServerWrapper) GetRegistrationHistory(ctx context.Context, request *sapb.RegistrationID) (*sapb.RegistrationHistory, error) {
+	if request == nil || request.Id == nil {
+		return nil, errIncompleteRequest
+	}
+
+	changes, err := sas.inner.GetRegistrationHistory(ctx, *request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &sapb.RegistrationHistory{}
+	for _, change := range changes {
+		regPB, err := registrationToPB(change.Registration)
+		if err != nil {
+			return nil, err
+		}
+		ipBytes, err := change.RequestIP.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		changedAt := change.ChangedAt.UnixNano()
+		resp.Changes = append(resp.Changes, &sapb.RegistrationChange{
+			Registration: regPB,
+			ChangedAt:    &changedAt,
+			RequestIP:    ipBytes,
+		})
+	}
+	return resp, nil
+}
+
+func (sas StorageAuthorityAdminServerWrapper) SearchOrders(ctx context.Context, request *sapb.SearchOrdersRequest) (*sapb.Orders, error) {
+	if request == nil || request.RegistrationID == nil {
+		return nil, errIncompleteRequest
+	}
+
+	orders, err := sas.inner.SearchOrders(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &sapb.Orders{Orders: orders}, nil
+}
+
+func (sas StorageAuthorityAdminServerWrapper) SearchAuthorizations(ctx context.Context, request *sapb.SearchAuthorizationsRequest) (*sapb.AuthorizationList, error) {
+	if request == nil || request.RegistrationID == nil {
+		return nil, errIncompleteRequest
+	}
+
+	authzs, err := sas.inner.SearchAuthorizations(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &sapb.AuthorizationList{}
+	for _, authz := range authzs {
+		authzPB, err := AuthzToPB(authz)
+		if err != nil {
+			return nil, err
+		}
+		resp.Authorizations = append(resp.Authorizations, authzPB)
+	}
+	return resp, nil
+}
+
+func (sas StorageAuthorityAdminServerWrapper) RegistrationsWithCertificates(ctx context.Context, request *sapb.RegistrationsWithCertificatesRequest) (*sapb.RegistrationContactsList, error) {
+	if request == nil || request.ExpiresAfter == nil {
+		return nil, errIncompleteRequest
+	}
+
+	regs, err := sas.inner.RegistrationsWithCertificates(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &sapb.RegistrationContactsList{Registrations: regs}, nil
+}
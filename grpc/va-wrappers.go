@@ -24,7 +24,7 @@ func (s *ValidationAuthorityGRPCServer) PerformValidation(ctx context.Context, i
 	if err != nil {
 		return nil, err
 	}
-	records, err := s.impl.PerformValidation(ctx, domain, challenge, authz)
+	records, caaRecord, reputation, err := s.impl.PerformValidation(ctx, domain, challenge, authz)
 	// If the type of error was a ProblemDetails, we need to return
 	// both that and the records to the caller (so it can update
 	// the challenge / authz in the SA with the failing records).
@@ -35,7 +35,7 @@ func (s *ValidationAuthorityGRPCServer) PerformValidation(ctx context.Context, i
 	if !ok && err != nil {
 		return nil, err
 	}
-	return validationResultToPB(records, prob)
+	return validationResultToPB(records, caaRecord, reputation, prob)
 }
 
 func (s *ValidationAuthorityGRPCServer) IsSafeDomain(ctx context.Context, in *vaPB.IsSafeDomainRequest) (*vaPB.IsDomainSafe, error) {
@@ -58,21 +58,21 @@ func NewValidationAuthorityGRPCClient(cc *ggrpc.ClientConn) core.ValidationAutho
 
 // PerformValidation has the VA revalidate the specified challenge and returns
 // the updated Challenge object.
-func (vac ValidationAuthorityGRPCClient) PerformValidation(ctx context.Context, domain string, challenge core.Challenge, authz core.Authorization) ([]core.ValidationRecord, error) {
+func (vac ValidationAuthorityGRPCClient) PerformValidation(ctx context.Context, domain string, challenge core.Challenge, authz core.Authorization) ([]core.ValidationRecord, *core.CAAValidationRecord, []core.ReputationVerdict, error) {
 	req, err := argsToPerformValidationRequest(domain, challenge, authz)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	gRecords, err := vac.gc.PerformValidation(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
-	records, prob, err := pbToValidationResult(gRecords)
+	records, caaRecord, reputation, prob, err := pbToValidationResult(gRecords)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	return records, prob
+	return records, caaRecord, reputation, prob
 }
 
 // IsSafeDomain returns true if the domain given is determined to be safe by an
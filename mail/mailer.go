@@ -15,11 +15,14 @@ import (
 	"net/mail"
 	"net/smtp"
 	"net/textproto"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/jmhodges/clock"
+	"golang.org/x/net/context"
 
 	"github.com/letsencrypt/boulder/core"
 	blog "github.com/letsencrypt/boulder/log"
@@ -61,6 +64,10 @@ type MailerImpl struct {
 	stats         metrics.Scope
 	reconnectBase time.Duration
 	reconnectMax  time.Duration
+
+	suppressions   SuppressionList
+	unsubscribeURL string
+	unsubscribeKey []byte
 }
 
 type dialer interface {
@@ -71,6 +78,7 @@ type smtpClient interface {
 	Mail(string) error
 	Rcpt(string) error
 	Data() (io.WriteCloser, error)
+	Reset() error
 	Close() error
 }
 
@@ -92,6 +100,11 @@ func (d dryRunClient) Rcpt(to string) error {
 	return nil
 }
 
+func (d dryRunClient) Reset() error {
+	d.log.Debugf("RSET")
+	return nil
+}
+
 func (d dryRunClient) Close() error {
 	return nil
 }
@@ -149,6 +162,30 @@ func NewDryRun(from mail.Address, logger blog.Logger) *MailerImpl {
 	}
 }
 
+// SetSuppressionList makes the Mailer skip recipients which are on list, and
+// add recipients rejected with a permanent error to it.
+func (m *MailerImpl) SetSuppressionList(list SuppressionList) {
+	m.suppressions = list
+}
+
+// SetUnsubscribeURL makes the Mailer add an unsubscribe link for each
+// recipient to its messages, in the body and in a List-Unsubscribe header
+// (RFC 2369 and RFC 8058). The link is baseURL with a "token" query parameter
+// made by UnsubscribeToken with key. Each recipient is sent a separate message
+// so that it only contains their own link.
+func (m *MailerImpl) SetUnsubscribeURL(baseURL string, key []byte) {
+	m.unsubscribeURL = baseURL
+	m.unsubscribeKey = key
+}
+
+func (m *MailerImpl) unsubscribeLink(email string) (string, error) {
+	token, err := UnsubscribeToken(m.unsubscribeKey, email)
+	if err != nil {
+		return "", err
+	}
+	return m.unsubscribeURL + "?" + url.Values{"token": {token}}.Encode(), nil
+}
+
 func (m *MailerImpl) generateMessage(to []string, subject, body string) ([]byte, error) {
 	mid := m.csprgSource.generate()
 	now := m.clk.Now().UTC()
@@ -169,6 +206,16 @@ func (m *MailerImpl) generateMessage(to []string, subject, body string) ([]byte,
 		"Content-Type: text/plain; charset=UTF-8",
 		"Content-Transfer-Encoding: quoted-printable",
 	}
+	if m.unsubscribeURL != "" && len(to) == 1 {
+		link, err := m.unsubscribeLink(to[0])
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers,
+			fmt.Sprintf("List-Unsubscribe: <%s>", link),
+			"List-Unsubscribe-Post: List-Unsubscribe=One-Click")
+		body = fmt.Sprintf("%s\n\nTo stop receiving these emails, visit %s\n", strings.TrimRight(body, "\n"), link)
+	}
 	for i := range headers[1:] {
 		// strip LFs
 		headers[i] = strings.Replace(headers[i], "\n", "", -1)
@@ -254,10 +301,27 @@ func (m *MailerImpl) sendOne(to []string, subject, msg string) error {
 	if err = m.client.Mail(m.from.String()); err != nil {
 		return err
 	}
+	accepted := 0
 	for _, t := range to {
-		if err = m.client.Rcpt(t); err != nil {
+		err = m.client.Rcpt(t)
+		if protoErr, ok := err.(*textproto.Error); ok {
+			if isHardBounce(protoErr) {
+				m.stats.Inc("SendMail.HardBounces", 1)
+				m.suppress(t, protoErr)
+				continue
+			}
+			// Abort the transaction, so that the connection can be reused.
+			_ = m.client.Reset()
 			return err
 		}
+		if err != nil {
+			return err
+		}
+		accepted++
+	}
+	if accepted == 0 {
+		// Every recipient bounced, so abort the transaction.
+		return m.client.Reset()
 	}
 	w, err := m.client.Data()
 	if err != nil {
@@ -274,11 +338,119 @@ func (m *MailerImpl) sendOne(to []string, subject, msg string) error {
 	return nil
 }
 
+// isHardBounce returns whether an SMTP reply to RCPT TO means that mail to the
+// recipient will never be accepted. Only permanent failures with a 5.1.x
+// enhanced status code (RFC 3463), which are about the address itself, count:
+// servers also reply 550 or 553 for policy and spam rejections, which don't
+// mean the mailbox is bad.
+func isHardBounce(err *textproto.Error) bool {
+	return err.Code/100 == 5 && strings.HasPrefix(err.Msg, "5.1.")
+}
+
+// suppress adds a hard bounced recipient to the suppression list, if there is
+// one. Failing to do so isn't an error for the message being sent, and the
+// address will be suppressed when it next bounces.
+func (m *MailerImpl) suppress(email string, bounce *textproto.Error) {
+	m.log.Infof("Recipient %q hard bounced: %s", email, bounce)
+	if m.suppressions == nil {
+		return
+	}
+	err := m.suppressions.AddEmailSuppression(context.Background(), email, SuppressionBounce)
+	if err != nil {
+		m.log.AuditErrf("Failed to suppress hard bounced recipient %q: %s", email, err)
+	}
+}
+
+// filterSuppressed returns the recipients in to which aren't suppressed.
+func (m *MailerImpl) filterSuppressed(to []string) ([]string, error) {
+	if m.suppressions == nil || len(to) == 0 {
+		return to, nil
+	}
+	suppressed, err := m.suppressions.GetSuppressedEmails(context.Background(), to)
+	if err != nil {
+		return nil, err
+	}
+	if len(suppressed) == 0 {
+		return to, nil
+	}
+	skip := make(map[string]bool, len(suppressed))
+	for _, email := range suppressed {
+		skip[strings.ToLower(email)] = true
+	}
+	var allowed []string
+	for _, t := range to {
+		if skip[strings.ToLower(t)] {
+			m.stats.Inc("SendMail.Suppressed", 1)
+			continue
+		}
+		allowed = append(allowed, t)
+	}
+	return allowed, nil
+}
+
+// SendError is returned by SendMail when it sent a separate message to each
+// recipient and some of them failed. Sent lists the recipients which were sent
+// the message, so that retries can skip them rather than mail them twice.
+type SendError struct {
+	Sent   []string
+	Failed map[string]error
+}
+
+func (e *SendError) Error() string {
+	var failures []string
+	for recipient, err := range e.Failed {
+		failures = append(failures, fmt.Sprintf("%s: %s", recipient, err))
+	}
+	sort.Strings(failures)
+	return fmt.Sprintf("sending to %d of %d recipients failed: %s",
+		len(e.Failed), len(e.Failed)+len(e.Sent), strings.Join(failures, "; "))
+}
+
 // SendMail sends an email to the provided list of recipients. The email body
-// is simple text.
+// is simple text. Suppressed recipients are skipped; if there are none left,
+// nothing is sent and SendMail returns nil. If an unsubscribe URL is set, each
+// recipient is sent a separate message, and if any of them fail the others are
+// still sent and a *SendError is returned.
 func (m *MailerImpl) SendMail(to []string, subject, msg string) error {
 	m.stats.Inc("SendMail.Attempts", 1)
 
+	to, err := m.filterSuppressed(to)
+	if err != nil {
+		// Fail closed, rather than risk mailing an address which unsubscribed.
+		m.stats.Inc("SendMail.Errors.Suppressions", 1)
+		return fmt.Errorf("checking suppression list: %s", err)
+	}
+	if len(to) == 0 {
+		return nil
+	}
+
+	if m.unsubscribeURL != "" {
+		sendErr := &SendError{Failed: make(map[string]error)}
+		for _, t := range to {
+			err := m.sendWithRetries([]string{t}, subject, msg)
+			if err != nil {
+				sendErr.Failed[t] = err
+				continue
+			}
+			sendErr.Sent = append(sendErr.Sent, t)
+		}
+		if len(sendErr.Failed) > 0 {
+			return sendErr
+		}
+	} else {
+		err := m.sendWithRetries(to, subject, msg)
+		if err != nil {
+			return err
+		}
+	}
+
+	m.stats.Inc("SendMail.Successes", 1)
+	return nil
+}
+
+// sendWithRetries sends a single message, reconnecting if the connection to
+// the server was lost.
+func (m *MailerImpl) sendWithRetries(to []string, subject, msg string) error {
 	for {
 		err := m.sendOne(to, subject, msg)
 		if err == nil {
@@ -321,7 +493,6 @@ func (m *MailerImpl) SendMail(to []string, subject, msg string) error {
 		}
 	}
 
-	m.stats.Inc("SendMail.Successes", 1)
 	return nil
 }
 
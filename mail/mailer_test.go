@@ -4,10 +4,12 @@ import (
 	"bufio"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
+	"mime/quotedprintable"
 	"net"
 	"net/mail"
 	"strconv"
@@ -16,6 +18,7 @@ import (
 	"time"
 
 	"github.com/jmhodges/clock"
+	"golang.org/x/net/context"
 
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/metrics"
@@ -284,3 +287,235 @@ func TestReconnectSMTP421(t *testing.T) {
 		t.Errorf("Expected SendMail() to not fail. Got err: %s", err)
 	}
 }
+
+type fakeSuppressions struct {
+	suppressed map[string]string
+}
+
+func (f *fakeSuppressions) GetSuppressedEmails(_ context.Context, emails []string) ([]string, error) {
+	var result []string
+	for _, email := range emails {
+		if _, ok := f.suppressed[strings.ToLower(email)]; ok {
+			result = append(result, strings.ToLower(email))
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeSuppressions) AddEmailSuppression(_ context.Context, email, reason string) error {
+	f.suppressed[strings.ToLower(email)] = reason
+	return nil
+}
+
+func TestUnsubscribeToken(t *testing.T) {
+	key := []byte("secret")
+	token, err := UnsubscribeToken(key, "Recv@Email.com")
+	test.AssertNotError(t, err, "UnsubscribeToken failed")
+	test.Assert(t, !strings.Contains(strings.ToLower(token), "recv"), "Token contains the address")
+
+	// Each address has a single token
+	again, err := UnsubscribeToken(key, "recv@email.com")
+	test.AssertNotError(t, err, "UnsubscribeToken failed")
+	test.AssertEquals(t, again, token)
+
+	email, err := VerifyUnsubscribeToken(key, token)
+	test.AssertNotError(t, err, "VerifyUnsubscribeToken failed")
+	test.AssertEquals(t, email, "recv@email.com")
+
+	_, err = VerifyUnsubscribeToken([]byte("other secret"), token)
+	test.AssertError(t, err, "Verified a token made with another key")
+
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	test.AssertNotError(t, err, "Failed to decode token")
+	sealed[len(sealed)-1] ^= 1
+	_, err = VerifyUnsubscribeToken(key, base64.RawURLEncoding.EncodeToString(sealed))
+	test.AssertError(t, err, "Verified a modified token")
+
+	for _, malformed := range []string{"", "abc", "a.b.c", "!!.!!"} {
+		_, err = VerifyUnsubscribeToken(key, malformed)
+		test.AssertError(t, err, "Verified malformed token "+malformed)
+	}
+}
+
+func TestGenerateMessageUnsubscribe(t *testing.T) {
+	fromAddress, _ := mail.ParseAddress("send@email.com")
+	m := New("", "", "", "", nil, *fromAddress, blog.UseMock(), metrics.NewNoopScope(), 0, 0)
+	m.clk = clock.NewFake()
+	m.csprgSource = fakeSource{}
+	m.SetUnsubscribeURL("https://boulder/unsubscribe", []byte("secret"))
+	token, err := UnsubscribeToken([]byte("secret"), "recv@email.com")
+	test.AssertNotError(t, err, "UnsubscribeToken failed")
+	link := "https://boulder/unsubscribe?token=" + token
+
+	messageBytes, err := m.generateMessage([]string{"recv@email.com"}, "test subject", "this is the body\n")
+	test.AssertNotError(t, err, "Failed to generate email body")
+	fields := strings.Split(string(messageBytes), "\r\n")
+	test.AssertEquals(t, fields[8], "List-Unsubscribe: <"+link+">")
+	test.AssertEquals(t, fields[9], "List-Unsubscribe-Post: List-Unsubscribe=One-Click")
+	test.AssertEquals(t, fields[10], "")
+	test.AssertEquals(t, fields[11], "this is the body")
+	body, err := ioutil.ReadAll(quotedprintable.NewReader(strings.NewReader(strings.Join(fields[11:], "\r\n"))))
+	test.AssertNotError(t, err, "Failed to decode body")
+	test.AssertEquals(t, string(body), "this is the body\r\n\r\nTo stop receiving these emails, visit "+link+"\r\n\r\n")
+
+	// Messages to several recipients can't have a single unsubscribe link
+	messageBytes, err = m.generateMessage([]string{"a@email.com", "b@email.com"}, "test subject", "this is the body\n")
+	test.AssertNotError(t, err, "Failed to generate email body")
+	test.Assert(t, !strings.Contains(string(messageBytes), "nsubscribe"), "Message to several recipients has an unsubscribe link")
+}
+
+func TestSendMailSuppressed(t *testing.T) {
+	fromAddress, _ := mail.ParseAddress("send@email.com")
+	m := New("", "", "", "", nil, *fromAddress, blog.UseMock(), metrics.NewNoopScope(), 0, 0)
+	m.SetSuppressionList(&fakeSuppressions{suppressed: map[string]string{"recv@email.com": SuppressionUnsubscribe}})
+
+	// The mailer isn't connected, so SendMail fails if it tries to send
+	// anything.
+	err := m.SendMail([]string{"Recv@email.com"}, "test subject", "this is the body\n")
+	test.AssertNotError(t, err, "SendMail tried to mail a suppressed address")
+	err = m.SendMail([]string{"Recv@email.com", "other@email.com"}, "test subject", "this is the body\n")
+	test.AssertError(t, err, "SendMail didn't try to mail an unsuppressed address")
+}
+
+// hardBounceHandler rejects the recipient "bounce@bye.com" with a 550 5.1.1,
+// "spam@bye.com" with a 550 5.7.1, and accepts all others. If no recipient was
+// accepted it expects the client to reset the transaction.
+func hardBounceHandler(connID int, t *testing.T, conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+	}()
+	authenticateClient(t, conn)
+
+	buf := bufio.NewReader(conn)
+	if err := expect(t, buf, "MAIL FROM:<<you-are-a-winner@example.com>> BODY=8BITMIME"); err != nil {
+		return
+	}
+	_, _ = conn.Write([]byte("250 Sure. Go on. \r\n"))
+
+	accepted := 0
+	for {
+		line, _, err := buf.ReadLine()
+		if err != nil {
+			t.Errorf("readline: %s", err)
+			return
+		}
+		switch string(line) {
+		case "RCPT TO:<bounce@bye.com>":
+			_, _ = conn.Write([]byte("550 5.1.1 No such user\r\n"))
+		case "RCPT TO:<spam@bye.com>":
+			_, _ = conn.Write([]byte("550 5.7.1 Message rejected as spam\r\n"))
+		case "RCPT TO:<hi@bye.com>":
+			accepted++
+			_, _ = conn.Write([]byte("250 Tell Me More \r\n"))
+		case "RSET":
+			if accepted != 0 {
+				t.Errorf("Unexpected RSET")
+			}
+			_, _ = conn.Write([]byte("250 Reset\r\n"))
+			return
+		case "DATA":
+			if accepted == 0 {
+				t.Errorf("Unexpected DATA with no accepted recipients")
+			}
+			_, _ = conn.Write([]byte("354 Cool Data\r\n"))
+			_, _ = conn.Write([]byte("250 Peace Out\r\n"))
+			return
+		default:
+			t.Errorf("Unexpected command %q", line)
+			return
+		}
+	}
+}
+
+func TestHardBounce(t *testing.T) {
+	m, l, cleanUp := setup(t)
+	defer cleanUp()
+	suppressions := &fakeSuppressions{suppressed: map[string]string{}}
+	m.SetSuppressionList(suppressions)
+
+	go listenForever(l, t, hardBounceHandler)
+
+	// The message is delivered to the recipients that didn't bounce
+	err := m.Connect()
+	test.AssertNotError(t, err, "Failed to connect")
+	err = m.SendMail([]string{"bounce@bye.com", "hi@bye.com"}, "You are already a winner!", "Just kidding")
+	test.AssertNotError(t, err, "SendMail failed")
+	test.AssertDeepEquals(t, suppressions.suppressed, map[string]string{"bounce@bye.com": SuppressionBounce})
+
+	// If all recipients bounce nothing is sent, which isn't an error
+	delete(suppressions.suppressed, "bounce@bye.com")
+	err = m.Connect()
+	test.AssertNotError(t, err, "Failed to connect")
+	err = m.SendMail([]string{"bounce@bye.com"}, "You are already a winner!", "Just kidding")
+	test.AssertNotError(t, err, "SendMail failed")
+	test.AssertDeepEquals(t, suppressions.suppressed, map[string]string{"bounce@bye.com": SuppressionBounce})
+
+	// Rejections which aren't about the address, like spam filtering, aren't
+	// hard bounces
+	err = m.Connect()
+	test.AssertNotError(t, err, "Failed to connect")
+	err = m.SendMail([]string{"spam@bye.com"}, "You are already a winner!", "Just kidding")
+	test.AssertError(t, err, "SendMail succeeded despite the recipient being rejected")
+	test.AssertDeepEquals(t, suppressions.suppressed, map[string]string{"bounce@bye.com": SuppressionBounce})
+}
+
+// perRecipientHandler accepts any number of transactions on one connection,
+// rejecting the recipient "full@bye.com" with a temporary failure.
+func perRecipientHandler(connID int, t *testing.T, conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+	}()
+	authenticateClient(t, conn)
+
+	buf := bufio.NewReader(conn)
+	for {
+		line, _, err := buf.ReadLine()
+		if err != nil {
+			return
+		}
+		switch {
+		case strings.HasPrefix(string(line), "MAIL FROM:"):
+			_, _ = conn.Write([]byte("250 Sure. Go on. \r\n"))
+		case string(line) == "RCPT TO:<full@bye.com>":
+			_, _ = conn.Write([]byte("452 4.2.2 Mailbox full\r\n"))
+		case strings.HasPrefix(string(line), "RCPT TO:"):
+			_, _ = conn.Write([]byte("250 Tell Me More \r\n"))
+		case string(line) == "RSET":
+			_, _ = conn.Write([]byte("250 Reset\r\n"))
+		case string(line) == "DATA":
+			_, _ = conn.Write([]byte("354 Cool Data\r\n"))
+			for {
+				line, _, err := buf.ReadLine()
+				if err != nil {
+					return
+				}
+				if string(line) == "." {
+					break
+				}
+			}
+			_, _ = conn.Write([]byte("250 Peace Out\r\n"))
+		default:
+			t.Errorf("Unexpected command %q", line)
+			return
+		}
+	}
+}
+
+func TestSendMailPartialFailure(t *testing.T) {
+	m, l, cleanUp := setup(t)
+	defer cleanUp()
+	m.SetUnsubscribeURL("https://boulder/unsubscribe", []byte("secret"))
+
+	go listenForever(l, t, perRecipientHandler)
+
+	// Every recipient is tried, and the error records which were sent
+	err := m.Connect()
+	test.AssertNotError(t, err, "Failed to connect")
+	err = m.SendMail([]string{"hi@bye.com", "full@bye.com", "hey@bye.com"}, "You are already a winner!", "Just kidding")
+	test.AssertError(t, err, "SendMail succeeded despite a failed recipient")
+	sendErr, ok := err.(*SendError)
+	test.Assert(t, ok, fmt.Sprintf("Expected a *SendError, got %T", err))
+	test.AssertDeepEquals(t, sendErr.Sent, []string{"hi@bye.com", "hey@bye.com"})
+	test.AssertEquals(t, len(sendErr.Failed), 1)
+	test.Assert(t, sendErr.Failed["full@bye.com"] != nil, "Expected full@bye.com to have failed")
+}
@@ -0,0 +1,94 @@
+package mail
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+// Reasons an address is suppressed.
+const (
+	SuppressionUnsubscribe = "unsubscribe"
+	SuppressionBounce      = "bounce"
+)
+
+// SuppressionList holds the email addresses which must not be mailed, because
+// their owner unsubscribed or mail to them hard bounced. It is implemented by
+// the SA.
+type SuppressionList interface {
+	// GetSuppressedEmails returns those of emails which are suppressed,
+	// lowercased.
+	GetSuppressedEmails(ctx context.Context, emails []string) ([]string, error)
+	AddEmailSuppression(ctx context.Context, email, reason string) error
+}
+
+var errBadUnsubscribeToken = errors.New("malformed or invalid unsubscribe token")
+
+// unsubscribeSubkey derives the key for one use in unsubscribe tokens from
+// the configured key.
+func unsubscribeSubkey(key []byte, use string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte("boulder unsubscribe " + use))
+	return h.Sum(nil)
+}
+
+// unsubscribeNonce returns the AES-GCM nonce an address is sealed with: the
+// truncated HMAC-SHA256 of the address. Deriving it from the address gives
+// each address a single, stable token, and a nonce is only ever reused to seal
+// the same address again.
+func unsubscribeNonce(key []byte, email string) []byte {
+	h := hmac.New(sha256.New, unsubscribeSubkey(key, "nonce"))
+	_, _ = h.Write([]byte(email))
+	return h.Sum(nil)[:12]
+}
+
+func unsubscribeAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(unsubscribeSubkey(key, "encryption"))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// UnsubscribeToken returns a token for an unsubscribe link, which proves that
+// the holder received mail sent to email. It is the base64url-encoded nonce
+// and AES-GCM sealed lowercased address, so the address can't be read from
+// the link, nor a token made for another address, without key. Tokens don't
+// expire, so that links in old mails keep working.
+func UnsubscribeToken(key []byte, email string) (string, error) {
+	email = strings.ToLower(email)
+	aead, err := unsubscribeAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := unsubscribeNonce(key, email)
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(email), nil)), nil
+}
+
+// VerifyUnsubscribeToken checks a token made by UnsubscribeToken with the
+// same key, and returns the address it was made for.
+func VerifyUnsubscribeToken(key []byte, token string) (string, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", errBadUnsubscribeToken
+	}
+	aead, err := unsubscribeAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errBadUnsubscribeToken
+	}
+	nonce := sealed[:aead.NonceSize()]
+	email, err := aead.Open(nil, nonce, sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errBadUnsubscribeToken
+	}
+	return string(email), nil
+}
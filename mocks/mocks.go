@@ -451,6 +451,41 @@ func (sa *StorageAuthority) SetOrderProcessing(_ context.Context, order *corepb.
 	return nil
 }
 
+// GetSuppressedEmails is a mock
+func (sa *StorageAuthority) GetSuppressedEmails(_ context.Context, _ []string) ([]string, error) {
+	return nil, nil
+}
+
+// AddEmailSuppression is a mock
+func (sa *StorageAuthority) AddEmailSuppression(_ context.Context, _, _ string) error {
+	return nil
+}
+
+// AddCAAIodefReport is a mock
+func (sa *StorageAuthority) AddCAAIodefReport(_ context.Context, _ core.CAAIodefReport) error {
+	return nil
+}
+
+// FinishCAAIodefReport is a mock
+func (sa *StorageAuthority) FinishCAAIodefReport(_ context.Context, _ core.CAAIodefReportResult) error {
+	return nil
+}
+
+// GetPendingCAAIodefReports is a mock
+func (sa *StorageAuthority) GetPendingCAAIodefReports(_ context.Context, _ int) ([]core.CAAIodefReport, error) {
+	return nil, nil
+}
+
+// CountCAAIodefReportsSent is a mock
+func (sa *StorageAuthority) CountCAAIodefReportsSent(_ context.Context, _ string, _ time.Time) (int, error) {
+	return 0, nil
+}
+
+// CountCAAIodefDeliveries is a mock
+func (sa *StorageAuthority) CountCAAIodefDeliveries(_ context.Context, _ string, _ time.Time) (int, error) {
+	return 0, nil
+}
+
 // SetOrderError is a mock
 func (sa *StorageAuthority) SetOrderError(_ context.Context, order *corepb.Order) error {
 	return nil
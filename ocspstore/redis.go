@@ -0,0 +1,269 @@
+package ocspstore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/net/context"
+
+	"github.com/letsencrypt/boulder/metrics"
+)
+
+// keyPrefix namespaces the OCSP responses in Redis so the same server can be
+// shared with other data.
+const keyPrefix = "ocsp:"
+
+// RedisStore is a Store that keeps OCSP responses in Redis, keyed by serial
+// and expired by Redis itself once their TTL has passed. It speaks just
+// enough of the Redis protocol (RESP) to AUTH, SET with an expiry and GET. It
+// is safe for concurrent use; connections are pooled up to poolSize idle
+// connections.
+type RedisStore struct {
+	addr     string
+	password string
+	timeout  time.Duration
+	pool     chan *redisConn
+	clk      clock.Clock
+
+	latency *prometheus.HistogramVec
+}
+
+// NewRedisStore returns a RedisStore connecting to the Redis server at addr.
+// If password is not empty each new connection is authenticated with it.
+// timeout bounds dialing and every command, and must be set so a hung Redis
+// server can't block callers indefinitely.
+func NewRedisStore(addr, password string, timeout time.Duration, poolSize int, scope metrics.Scope) (*RedisStore, error) {
+	if timeout <= 0 {
+		return nil, errors.New("a timeout is required for the Redis OCSP response store")
+	}
+	if poolSize <= 0 {
+		poolSize = 1
+	}
+	latency := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "ocsp_store_redis_latency",
+			Help: "Time taken by OCSP response store commands sent to Redis, by command and result",
+		},
+		[]string{"command", "result"})
+	scope.MustRegister(latency)
+
+	return &RedisStore{
+		addr:     addr,
+		password: password,
+		timeout:  timeout,
+		pool:     make(chan *redisConn, poolSize),
+		clk:      clock.Default(),
+		latency:  latency,
+	}, nil
+}
+
+// StoreResponse stores response for serial with the given TTL. Redis rounds
+// the expiry to the millisecond, so TTLs below that are rejected.
+func (rs *RedisStore) StoreResponse(ctx context.Context, serial string, response []byte, ttl time.Duration) error {
+	ms := int64(ttl / time.Millisecond)
+	if ms <= 0 {
+		return fmt.Errorf("invalid TTL %s for OCSP response %s", ttl, serial)
+	}
+	reply, err := rs.do(ctx, "set", []byte("SET"), []byte(keyPrefix+serial), response, []byte("PX"), []byte(strconv.FormatInt(ms, 10)))
+	if err != nil {
+		return err
+	}
+	if s, ok := reply.(string); !ok || s != "OK" {
+		return fmt.Errorf("unexpected reply from Redis SET: %v", reply)
+	}
+	return nil
+}
+
+// DeleteResponse removes the response stored for serial, if any.
+func (rs *RedisStore) DeleteResponse(ctx context.Context, serial string) error {
+	reply, err := rs.do(ctx, "del", []byte("DEL"), []byte(keyPrefix+serial))
+	if err != nil {
+		return err
+	}
+	if _, ok := reply.(int64); !ok {
+		return fmt.Errorf("unexpected reply from Redis DEL: %v", reply)
+	}
+	return nil
+}
+
+// GetResponse returns the response stored for serial, or ErrNotFound.
+func (rs *RedisStore) GetResponse(ctx context.Context, serial string) ([]byte, error) {
+	reply, err := rs.do(ctx, "get", []byte("GET"), []byte(keyPrefix+serial))
+	if err != nil {
+		return nil, err
+	}
+	switch r := reply.(type) {
+	case nil:
+		return nil, ErrNotFound
+	case []byte:
+		return r, nil
+	default:
+		return nil, fmt.Errorf("unexpected reply from Redis GET: %v", reply)
+	}
+}
+
+// do sends a single command and returns its reply, recording its latency
+// under the given command label.
+func (rs *RedisStore) do(ctx context.Context, command string, args ...[]byte) (reply interface{}, err error) {
+	start := rs.clk.Now()
+	defer func() {
+		result := "success"
+		if err != nil {
+			result = "failure"
+		}
+		rs.latency.With(prometheus.Labels{"command": command, "result": result}).Observe(rs.clk.Since(start).Seconds())
+	}()
+
+	conn, err := rs.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn.setDeadline(ctx, rs.timeout)
+	reply, err = conn.do(args...)
+	if err != nil {
+		if _, isRedisErr := err.(redisError); !isRedisErr {
+			// The connection may be left with a partial command or reply on it,
+			// so don't reuse it.
+			conn.Close()
+			return nil, err
+		}
+	}
+	rs.put(conn)
+	return reply, err
+}
+
+// get returns an idle pooled connection or dials a new one.
+func (rs *RedisStore) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-rs.pool:
+		return conn, nil
+	default:
+	}
+	dialer := net.Dialer{Timeout: rs.timeout}
+	if deadline, ok := ctx.Deadline(); ok {
+		dialer.Deadline = deadline
+	}
+	c, err := dialer.Dial("tcp", rs.addr)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{Conn: c, r: bufio.NewReader(c)}
+	if rs.password != "" {
+		conn.setDeadline(ctx, rs.timeout)
+		if _, err := conn.do([]byte("AUTH"), []byte(rs.password)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("authenticating to Redis: %s", err)
+		}
+	}
+	return conn, nil
+}
+
+// put returns a connection to the pool, closing it if the pool is full.
+func (rs *RedisStore) put(conn *redisConn) {
+	select {
+	case rs.pool <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// redisError is an error reply sent by the Redis server. It leaves the
+// connection in a usable state.
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *redisConn) setDeadline(ctx context.Context, timeout time.Duration) {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	_ = c.SetDeadline(deadline)
+}
+
+// do writes a command as a RESP array of bulk strings and reads the reply.
+func (c *redisConn) do(args ...[]byte) (interface{}, error) {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	if _, err := c.Write(buf); err != nil {
+		return nil, err
+	}
+	return readReply(c.r)
+}
+
+// readReply parses a single RESP reply. Simple strings are returned as
+// string, integers as int64, bulk strings as []byte, null bulk strings as nil
+// and arrays as []interface{}. Error replies are returned as a redisError.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("malformed reply from Redis")
+	}
+	kind, body := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		elems := make([]interface{}, n)
+		for i := range elems {
+			elems[i], err = readReply(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return elems, nil
+	default:
+		return nil, fmt.Errorf("unknown reply type %q from Redis", kind)
+	}
+}
@@ -0,0 +1,182 @@
+package ocspstore
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/test"
+)
+
+// redisServer is a minimal in-process Redis server implementing AUTH, SET
+// with PX, GET and DEL.
+type redisServer struct {
+	sync.Mutex
+	l        net.Listener
+	password string
+	data     map[string][]byte
+	ttls     map[string]int64
+	dials    int
+}
+
+func newRedisServer(t *testing.T, password string) *redisServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	test.AssertNotError(t, err, "Failed to listen")
+	s := &redisServer{
+		l:        l,
+		password: password,
+		data:     make(map[string][]byte),
+		ttls:     make(map[string]int64),
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.Lock()
+			s.dials++
+			s.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *redisServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := s.password == ""
+	for {
+		req, err := readReply(r)
+		if err != nil {
+			return
+		}
+		elems, ok := req.([]interface{})
+		if !ok || len(elems) == 0 {
+			return
+		}
+		var args []string
+		for _, e := range elems {
+			args = append(args, string(e.([]byte)))
+		}
+
+		s.Lock()
+		var reply string
+		switch {
+		case strings.ToUpper(args[0]) == "AUTH":
+			if len(args) == 2 && args[1] == s.password {
+				authed = true
+				reply = "+OK\r\n"
+			} else {
+				reply = "-ERR invalid password\r\n"
+			}
+		case !authed:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case strings.ToUpper(args[0]) == "SET" && len(args) == 5 && args[3] == "PX":
+			ttl, _ := strconv.ParseInt(args[4], 10, 64)
+			s.data[args[1]] = []byte(args[2])
+			s.ttls[args[1]] = ttl
+			reply = "+OK\r\n"
+		case strings.ToUpper(args[0]) == "GET" && len(args) == 2:
+			if v, present := s.data[args[1]]; present {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case strings.ToUpper(args[0]) == "DEL" && len(args) == 2:
+			_, present := s.data[args[1]]
+			delete(s.data, args[1])
+			if present {
+				reply = ":1\r\n"
+			} else {
+				reply = ":0\r\n"
+			}
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		s.Unlock()
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func TestRedisStore(t *testing.T) {
+	srv := newRedisServer(t, "hunter2")
+	defer srv.l.Close()
+	rs, err := NewRedisStore(srv.l.Addr().String(), "hunter2", time.Second, 2, metrics.NewNoopScope())
+	test.AssertNotError(t, err, "NewRedisStore failed")
+	ctx := context.Background()
+
+	_, err = rs.GetResponse(ctx, "00ff")
+	test.AssertEquals(t, err, ErrNotFound)
+
+	response := []byte("resp\r\nonse")
+	err = rs.StoreResponse(ctx, "00ff", response, 90*time.Minute)
+	test.AssertNotError(t, err, "StoreResponse failed")
+	srv.Lock()
+	test.AssertEquals(t, srv.ttls["ocsp:00ff"], int64(5400000))
+	srv.Unlock()
+
+	stored, err := rs.GetResponse(ctx, "00ff")
+	test.AssertNotError(t, err, "GetResponse failed")
+	test.AssertByteEquals(t, stored, response)
+
+	// All commands were sent sequentially, so a single connection was reused
+	srv.Lock()
+	test.AssertEquals(t, srv.dials, 1)
+	srv.Unlock()
+
+	err = rs.StoreResponse(ctx, "00ff", response, 0)
+	test.AssertError(t, err, "StoreResponse accepted a zero TTL")
+
+	err = rs.DeleteResponse(ctx, "00ff")
+	test.AssertNotError(t, err, "DeleteResponse failed")
+	_, err = rs.GetResponse(ctx, "00ff")
+	test.AssertEquals(t, err, ErrNotFound)
+	// Deleting a missing response isn't an error
+	err = rs.DeleteResponse(ctx, "00ff")
+	test.AssertNotError(t, err, "DeleteResponse failed for a missing response")
+}
+
+func TestRedisStoreAuthFailure(t *testing.T) {
+	srv := newRedisServer(t, "hunter2")
+	defer srv.l.Close()
+	rs, err := NewRedisStore(srv.l.Addr().String(), "wrong", time.Second, 1, metrics.NewNoopScope())
+	test.AssertNotError(t, err, "NewRedisStore failed")
+
+	_, err = rs.GetResponse(context.Background(), "00ff")
+	test.AssertError(t, err, "GetResponse succeeded with the wrong password")
+
+	rs, err = NewRedisStore(srv.l.Addr().String(), "", time.Second, 1, metrics.NewNoopScope())
+	test.AssertNotError(t, err, "NewRedisStore failed")
+	_, err = rs.GetResponse(context.Background(), "00ff")
+	test.AssertError(t, err, "GetResponse succeeded without authenticating")
+	test.AssertEquals(t, err.Error(), "redis: NOAUTH Authentication required.")
+}
+
+func TestRedisStoreUnavailable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	test.AssertNotError(t, err, "Failed to listen")
+	addr := l.Addr().String()
+	l.Close()
+
+	rs, err := NewRedisStore(addr, "", time.Second, 1, metrics.NewNoopScope())
+	test.AssertNotError(t, err, "NewRedisStore failed")
+	err = rs.StoreResponse(context.Background(), "00ff", []byte{1}, time.Hour)
+	test.AssertError(t, err, "StoreResponse succeeded with no server")
+}
+
+func TestRedisStoreRequiresTimeout(t *testing.T) {
+	_, err := NewRedisStore("127.0.0.1:6379", "", 0, 1, metrics.NewNoopScope())
+	test.AssertError(t, err, "NewRedisStore accepted a zero timeout")
+}
@@ -0,0 +1,37 @@
+// Package ocspstore provides storage for pre-signed OCSP responses outside of
+// the certificateStatus table. The ocsp-updater writes each response it
+// generates to a Storer, and the ocsp-responder reads them back through a
+// Getter before falling back to the database.
+package ocspstore
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// ErrNotFound is returned by a Getter when it holds no response for the
+// requested serial, for instance because the response's TTL has elapsed.
+var ErrNotFound = errors.New("no OCSP response stored for serial")
+
+// Storer stores a DER encoded OCSP response for a certificate serial. The
+// response should no longer be returned by a Getter once ttl has elapsed, or
+// once it has been deleted.
+type Storer interface {
+	StoreResponse(ctx context.Context, serial string, response []byte, ttl time.Duration) error
+	DeleteResponse(ctx context.Context, serial string) error
+}
+
+// Getter returns the DER encoded OCSP response stored for a certificate
+// serial, or ErrNotFound if there is none.
+type Getter interface {
+	GetResponse(ctx context.Context, serial string) ([]byte, error)
+}
+
+// Store is implemented by response stores that can be both written to and
+// read from.
+type Store interface {
+	Storer
+	Getter
+}
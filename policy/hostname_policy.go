@@ -0,0 +1,289 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	berrors "github.com/letsencrypt/boulder/errors"
+)
+
+// Hostname policy rule types, the values of hostnameRuleJSON.Type
+const (
+	// ruleSuffixBlock forbids issuance for a name and all of its subdomains.
+	ruleSuffixBlock = "suffix-block"
+	// ruleExactBlock forbids issuance for exactly a name, and for the wildcard
+	// name that would cover it.
+	ruleExactBlock = "exact-block"
+	// ruleRegexBlock forbids issuance for names matching a regular expression.
+	ruleRegexBlock = "regex-block"
+	// ruleAccountAllow exempts a name and all of its subdomains from every
+	// other rule, for the listed accounts only.
+	ruleAccountAllow = "account-allow"
+	// ruleManualReview forbids issuance for a name and all of its subdomains
+	// until someone reviews the request and adds an account-allow rule.
+	ruleManualReview = "manual-review"
+)
+
+// blacklistJSON is the format of the hostname policy file. Version 1 policies
+// only have Blacklist, which is treated as suffix-block rules, and
+// ExactBlacklist, which is treated as exact-block rules. Version 2 policies
+// only have Rules.
+type blacklistJSON struct {
+	Blacklist      []string
+	ExactBlacklist []string
+	Rules          []hostnameRuleJSON `json:",omitempty"`
+}
+
+// hostnameRuleJSON is a rule in a version 2 hostname policy.
+type hostnameRuleJSON struct {
+	Type string
+	// Name is the domain name the rule is for. Every type of rule but
+	// regex-block has one.
+	Name string `json:",omitempty"`
+	// Pattern is the regular expression a regex-block rule matches names
+	// against. It should be anchored, as it may match any part of a name.
+	Pattern string `json:",omitempty"`
+	// Accounts are the registration IDs an account-allow rule applies to.
+	Accounts []int64 `json:",omitempty"`
+	// Reason is included in the error returned for names the rule rejects.
+	Reason string `json:",omitempty"`
+	// Expires, if set, is the time after which the rule no longer applies.
+	Expires *time.Time `json:",omitempty"`
+}
+
+// hostnameRule is a parsed hostnameRuleJSON.
+type hostnameRule struct {
+	ruleType string
+	name     string
+	pattern  *regexp.Regexp
+	accounts map[int64]bool
+	reason   string
+	expires  time.Time
+}
+
+// activeAt returns whether the rule applies at the given time.
+func (r *hostnameRule) activeAt(now time.Time) bool {
+	return r.expires.IsZero() || now.Before(r.expires)
+}
+
+// hostnamePolicy is a loaded hostname policy, with its rules indexed by the
+// names they are for.
+type hostnamePolicy struct {
+	suffixBlocks map[string][]*hostnameRule
+	exactBlocks  map[string][]*hostnameRule
+	// wildcardExactBlocks holds exact-block rules under the name with its
+	// leftmost label removed. e.g. if "highvalue.example.com" is exactly
+	// blocked, "*.example.com" must not be issued since it would include it.
+	wildcardExactBlocks map[string][]*hostnameRule
+	regexBlocks         []*hostnameRule
+	manualReviews       map[string][]*hostnameRule
+	accountAllows       map[string][]*hostnameRule
+	// rules is every rule in the policy, in the order they were listed.
+	rules []*hostnameRule
+}
+
+// parseHostnamePolicy parses the contents of a hostname policy file, in either
+// version.
+func parseHostnamePolicy(b []byte) (*hostnamePolicy, error) {
+	var bl blacklistJSON
+	err := json.Unmarshal(b, &bl)
+	if err != nil {
+		return nil, err
+	}
+	if len(bl.Rules) > 0 && (len(bl.Blacklist) > 0 || len(bl.ExactBlacklist) > 0) {
+		return nil, fmt.Errorf("Hostname policy has both Rules and a Blacklist or ExactBlacklist.")
+	}
+
+	rules := bl.Rules
+	if len(rules) == 0 {
+		if len(bl.Blacklist) == 0 {
+			return nil, fmt.Errorf("No entries in blacklist.")
+		}
+		for _, v := range bl.Blacklist {
+			rules = append(rules, hostnameRuleJSON{Type: ruleSuffixBlock, Name: v})
+		}
+		for _, v := range bl.ExactBlacklist {
+			if !strings.Contains(v, ".") {
+				return nil, fmt.Errorf(
+					"Malformed exact blacklist entry, only one label: %q", v)
+			}
+			rules = append(rules, hostnameRuleJSON{Type: ruleExactBlock, Name: v})
+		}
+	}
+
+	p := &hostnamePolicy{
+		suffixBlocks:        make(map[string][]*hostnameRule),
+		exactBlocks:         make(map[string][]*hostnameRule),
+		wildcardExactBlocks: make(map[string][]*hostnameRule),
+		manualReviews:       make(map[string][]*hostnameRule),
+		accountAllows:       make(map[string][]*hostnameRule),
+	}
+	for i, rj := range rules {
+		rule, err := parseHostnameRule(rj)
+		if err != nil {
+			return nil, fmt.Errorf("Hostname policy rule %d: %s", i, err)
+		}
+		switch rule.ruleType {
+		case ruleSuffixBlock:
+			p.suffixBlocks[rule.name] = append(p.suffixBlocks[rule.name], rule)
+		case ruleExactBlock:
+			p.exactBlocks[rule.name] = append(p.exactBlocks[rule.name], rule)
+			base := strings.SplitN(rule.name, ".", 2)[1]
+			p.wildcardExactBlocks[base] = append(p.wildcardExactBlocks[base], rule)
+		case ruleRegexBlock:
+			p.regexBlocks = append(p.regexBlocks, rule)
+		case ruleAccountAllow:
+			p.accountAllows[rule.name] = append(p.accountAllows[rule.name], rule)
+		case ruleManualReview:
+			p.manualReviews[rule.name] = append(p.manualReviews[rule.name], rule)
+		}
+		p.rules = append(p.rules, rule)
+	}
+	return p, nil
+}
+
+func parseHostnameRule(rj hostnameRuleJSON) (*hostnameRule, error) {
+	rule := &hostnameRule{
+		ruleType: rj.Type,
+		name:     strings.ToLower(rj.Name),
+		reason:   rj.Reason,
+	}
+	if rj.Expires != nil {
+		rule.expires = *rj.Expires
+	}
+
+	switch rj.Type {
+	case ruleRegexBlock:
+		if rj.Name != "" {
+			return nil, fmt.Errorf("%s rule has a Name", rj.Type)
+		}
+		if rj.Pattern == "" {
+			return nil, fmt.Errorf("%s rule has no Pattern", rj.Type)
+		}
+		pattern, err := regexp.Compile(rj.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s rule has an invalid Pattern: %s", rj.Type, err)
+		}
+		rule.pattern = pattern
+	case ruleSuffixBlock, ruleExactBlock, ruleAccountAllow, ruleManualReview:
+		if rj.Pattern != "" {
+			return nil, fmt.Errorf("%s rule has a Pattern", rj.Type)
+		}
+		if rule.name == "" {
+			return nil, fmt.Errorf("%s rule has no Name", rj.Type)
+		}
+		if strings.Contains(rule.name, "*") || strings.HasPrefix(rule.name, ".") || strings.HasSuffix(rule.name, ".") {
+			return nil, fmt.Errorf("%s rule has a malformed Name: %q", rj.Type, rj.Name)
+		}
+		if rj.Type == ruleExactBlock && !strings.Contains(rule.name, ".") {
+			return nil, fmt.Errorf("%s rule Name has only one label: %q", rj.Type, rj.Name)
+		}
+	default:
+		return nil, fmt.Errorf("unknown rule type %q", rj.Type)
+	}
+
+	if rj.Type == ruleAccountAllow {
+		if len(rj.Accounts) == 0 {
+			return nil, fmt.Errorf("%s rule has no Accounts", rj.Type)
+		}
+		rule.accounts = make(map[int64]bool, len(rj.Accounts))
+		for _, id := range rj.Accounts {
+			rule.accounts[id] = true
+		}
+	} else if len(rj.Accounts) > 0 {
+		return nil, fmt.Errorf("%s rule has Accounts", rj.Type)
+	}
+	return rule, nil
+}
+
+// firstActive returns the first of rules active at now, or nil if none are.
+func firstActive(rules []*hostnameRule, now time.Time) *hostnameRule {
+	for _, rule := range rules {
+		if rule.activeAt(now) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// matchSuffix returns the first rule active at now in rules for domain or one
+// of its parent domains.
+func matchSuffix(rules map[string][]*hostnameRule, domain string, now time.Time) *hostnameRule {
+	labels := strings.Split(domain, ".")
+	for i := range labels {
+		if rule := firstActive(rules[strings.Join(labels[i:], ".")], now); rule != nil {
+			return rule
+		}
+	}
+	return nil
+}
+
+// allowed returns whether an account-allow rule exempts domain from the
+// policy's other rules for the registration ID.
+func (p *hostnamePolicy) allowed(domain string, regID int64, now time.Time) bool {
+	labels := strings.Split(domain, ".")
+	for i := range labels {
+		for _, rule := range p.accountAllows[strings.Join(labels[i:], ".")] {
+			if rule.accounts[regID] && rule.activeAt(now) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// blocked returns the block rule forbidding issuance for domain, if any.
+func (p *hostnamePolicy) blocked(domain string, now time.Time) *hostnameRule {
+	if rule := matchSuffix(p.suffixBlocks, domain, now); rule != nil {
+		return rule
+	}
+	if rule := firstActive(p.exactBlocks[domain], now); rule != nil {
+		return rule
+	}
+	for _, rule := range p.regexBlocks {
+		if rule.activeAt(now) && rule.pattern.MatchString(domain) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// ruleError returns the error for a name rejected by rule, including the
+// rule's reason if it has one.
+func ruleError(rule *hostnameRule) error {
+	if rule.ruleType == ruleManualReview {
+		if rule.reason == "" {
+			return errManualReview
+		}
+		return berrors.RejectedIdentifierError("Issuance for name requires manual review: %s", rule.reason)
+	}
+	if rule.reason == "" {
+		return errBlacklisted
+	}
+	return berrors.RejectedIdentifierError("Policy forbids issuing for name: %s", rule.reason)
+}
+
+// CheckHostnamePolicy returns an error if the PA would refuse to load the
+// given hostname policy file contents. Otherwise it returns warnings about
+// rules in the policy that have expired as of now.
+func CheckHostnamePolicy(b []byte, now time.Time) ([]string, error) {
+	p, err := parseHostnamePolicy(b)
+	if err != nil {
+		return nil, err
+	}
+	var warnings []string
+	for i, rule := range p.rules {
+		if !rule.activeAt(now) {
+			target := rule.name
+			if rule.pattern != nil {
+				target = rule.pattern.String()
+			}
+			warnings = append(warnings, fmt.Sprintf("rule %d (%s %q) expired at %s",
+				i, rule.ruleType, target, rule.expires.Format(time.RFC3339)))
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,159 @@
+package policy
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	"github.com/letsencrypt/boulder/core"
+	berrors "github.com/letsencrypt/boulder/errors"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/test"
+)
+
+const testPolicyV2 = `{
+	"Rules": [
+		{"Type": "suffix-block", "Name": "blocked.com", "Reason": "Known phishing domain"},
+		{"Type": "suffix-block", "Name": "expired.com", "Expires": "2018-07-01T00:00:00Z"},
+		{"Type": "suffix-block", "Name": "expiring.com", "Expires": "2018-08-01T00:00:00Z"},
+		{"Type": "exact-block", "Name": "highvalue.example.com"},
+		{"Type": "regex-block", "Pattern": "(^|\\.)paypa1[^.]*\\.", "Reason": "Lookalike of a high value domain"},
+		{"Type": "account-allow", "Name": "blocked.com", "Accounts": [1001]},
+		{"Type": "account-allow", "Name": "example.com", "Accounts": [1002],
+			"Expires": "2018-08-01T00:00:00Z"},
+		{"Type": "manual-review", "Name": "bank", "Reason": "High risk TLD"}
+	]
+}`
+
+func paWithPolicyV2(t *testing.T) (*AuthorityImpl, clock.FakeClock, *blog.Mock) {
+	pa := paImpl(t)
+	fc := clock.NewFake()
+	fc.Set(time.Date(2018, 7, 15, 0, 0, 0, 0, time.UTC))
+	pa.clk = fc
+	mockLog := blog.NewMock()
+	pa.log = mockLog
+	err := pa.loadHostnamePolicy([]byte(testPolicyV2))
+	test.AssertNotError(t, err, "Couldn't load hostname policy")
+	return pa, fc, mockLog
+}
+
+func TestHostnamePolicyV2(t *testing.T) {
+	pa, fc, mockLog := paWithPolicyV2(t)
+
+	testCases := []struct {
+		domain string
+		regID  int64
+		err    string
+	}{
+		{"allowed.com", testRegID, ""},
+		// Suffix blocks apply to the name and its subdomains
+		{"blocked.com", testRegID, "Policy forbids issuing for name: Known phishing domain"},
+		{"www.blocked.com", testRegID, "Policy forbids issuing for name: Known phishing domain"},
+		{"notblocked.com", testRegID, ""},
+		// Expired rules don't apply
+		{"expired.com", testRegID, ""},
+		{"expiring.com", testRegID, "Policy forbids issuing for name"},
+		// Exact blocks only apply to the exact name
+		{"highvalue.example.com", testRegID, "Policy forbids issuing for name"},
+		{"www.highvalue.example.com", testRegID, ""},
+		{"paypa1-login.com", testRegID, "Policy forbids issuing for name: Lookalike of a high value domain"},
+		{"secure.paypa1.com", testRegID, "Policy forbids issuing for name: Lookalike of a high value domain"},
+		{"mypaypa1.com", testRegID, ""},
+		// Account allow rules exempt names from the other rules for their accounts
+		{"www.blocked.com", 1001, ""},
+		{"highvalue.example.com", 1002, ""},
+		{"highvalue.example.com", 1001, "Policy forbids issuing for name"},
+		{"example.bank", testRegID, "Issuance for name requires manual review: High risk TLD"},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s for %d", tc.domain, tc.regID), func(t *testing.T) {
+			err := pa.WillingToIssue(core.AcmeIdentifier{Type: core.IdentifierDNS, Value: tc.domain}, tc.regID)
+			if tc.err == "" {
+				test.AssertNotError(t, err, "WillingToIssue failed")
+				return
+			}
+			test.AssertError(t, err, "WillingToIssue succeeded")
+			test.Assert(t, berrors.Is(err, berrors.RejectedIdentifier), "Wrong error type")
+			test.AssertEquals(t, err.Error(), tc.err)
+		})
+	}
+	test.AssertEquals(t, len(mockLog.GetAllMatching(
+		`Hostname policy requires manual review of "example.bank" for registration ID 1234: High risk TLD`)), 1)
+
+	// Once they expire, rules no longer block or allow names
+	fc.Add(30 * 24 * time.Hour)
+	err := pa.WillingToIssue(core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "expiring.com"}, testRegID)
+	test.AssertNotError(t, err, "WillingToIssue failed for a name with an expired block rule")
+	err = pa.WillingToIssue(core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "highvalue.example.com"}, 1002)
+	test.AssertError(t, err, "WillingToIssue succeeded for a name with an expired allow rule")
+}
+
+func TestHostnamePolicyV2Wildcard(t *testing.T) {
+	pa, _, _ := paWithPolicyV2(t)
+
+	wildcard := core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "*.example.com"}
+	err := pa.WillingToIssueWildcard(wildcard, testRegID)
+	test.AssertEquals(t, err, errBlacklisted)
+	err = pa.WillingToIssueWildcard(wildcard, 1002)
+	test.AssertNotError(t, err, "WillingToIssueWildcard failed for an allowed account")
+
+	err = pa.WillingToIssueWildcard(core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "*.blocked.com"}, testRegID)
+	test.AssertEquals(t, err.Error(), "Policy forbids issuing for name: Known phishing domain")
+}
+
+func TestParseHostnamePolicy(t *testing.T) {
+	// Version 1 policies are loaded as suffix-block and exact-block rules
+	p, err := parseHostnamePolicy([]byte(`{"Blacklist": ["example.org"], "ExactBlacklist": ["www.example.net"]}`))
+	test.AssertNotError(t, err, "Couldn't parse v1 policy")
+	test.AssertEquals(t, len(p.rules), 2)
+	test.AssertEquals(t, p.suffixBlocks["example.org"][0].ruleType, ruleSuffixBlock)
+	test.AssertEquals(t, p.exactBlocks["www.example.net"][0].ruleType, ruleExactBlock)
+	test.AssertEquals(t, len(p.wildcardExactBlocks["example.net"]), 1)
+
+	testCases := []struct {
+		policy string
+		err    string
+	}{
+		{`{}`, "No entries in blacklist."},
+		{`{"Blacklist": ["example.org"], "Rules": [{"Type": "suffix-block", "Name": "example.com"}]}`,
+			"Hostname policy has both Rules and a Blacklist or ExactBlacklist."},
+		{`{"Rules": [{"Type": "deny", "Name": "example.com"}]}`,
+			`Hostname policy rule 0: unknown rule type "deny"`},
+		{`{"Rules": [{"Type": "suffix-block"}]}`,
+			"Hostname policy rule 0: suffix-block rule has no Name"},
+		{`{"Rules": [{"Type": "suffix-block", "Name": "*.example.com"}]}`,
+			`Hostname policy rule 0: suffix-block rule has a malformed Name: "*.example.com"`},
+		{`{"Rules": [{"Type": "suffix-block", "Name": "example.com", "Pattern": "example"}]}`,
+			"Hostname policy rule 0: suffix-block rule has a Pattern"},
+		{`{"Rules": [{"Type": "exact-block", "Name": "com"}]}`,
+			`Hostname policy rule 0: exact-block rule Name has only one label: "com"`},
+		{`{"Rules": [{"Type": "regex-block", "Pattern": "("}]}`,
+			"Hostname policy rule 0: regex-block rule has an invalid Pattern: error parsing regexp: missing closing ): `(`"},
+		{`{"Rules": [{"Type": "regex-block", "Name": "example.com", "Pattern": "example"}]}`,
+			"Hostname policy rule 0: regex-block rule has a Name"},
+		{`{"Rules": [{"Type": "regex-block"}]}`,
+			"Hostname policy rule 0: regex-block rule has no Pattern"},
+		{`{"Rules": [{"Type": "account-allow", "Name": "example.com"}]}`,
+			"Hostname policy rule 0: account-allow rule has no Accounts"},
+		{`{"Rules": [{"Type": "suffix-block", "Name": "example.com"}, {"Type": "manual-review", "Name": "example.com", "Accounts": [1]}]}`,
+			"Hostname policy rule 1: manual-review rule has Accounts"},
+	}
+	for _, tc := range testCases {
+		_, err := parseHostnamePolicy([]byte(tc.policy))
+		test.AssertError(t, err, fmt.Sprintf("Parsed invalid policy %s", tc.policy))
+		test.AssertEquals(t, err.Error(), tc.err)
+	}
+}
+
+func TestCheckHostnamePolicy(t *testing.T) {
+	warnings, err := CheckHostnamePolicy([]byte(testPolicyV2), time.Date(2018, 7, 15, 0, 0, 0, 0, time.UTC))
+	test.AssertNotError(t, err, "CheckHostnamePolicy failed")
+	test.AssertDeepEquals(t, warnings, []string{
+		`rule 1 (suffix-block "expired.com") expired at 2018-07-01T00:00:00Z`,
+	})
+
+	_, err = CheckHostnamePolicy([]byte(`{"Rules": [{"Type": "regex-block"}]}`), time.Now())
+	test.AssertError(t, err, "CheckHostnamePolicy accepted an invalid policy")
+}
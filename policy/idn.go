@@ -0,0 +1,199 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+
+	"github.com/letsencrypt/boulder/cmd"
+	berrors "github.com/letsencrypt/boulder/errors"
+)
+
+// idnPolicy is a parsed cmd.IDNPolicyConfig.
+type idnPolicy struct {
+	allowedScripts     map[string]bool
+	rejectMixedScripts bool
+	rejectConfusables  bool
+}
+
+// cjkScripts are the combinations of scripts that are used together to write
+// Chinese, Japanese and Korean, along with Latin, as in the "Highly
+// Restrictive" level of UTS #39. Labels using only the scripts in one of them
+// aren't considered to mix scripts.
+var cjkScripts = []map[string]bool{
+	{"Latin": true, "Han": true, "Hiragana": true, "Katakana": true},
+	{"Latin": true, "Han": true, "Bopomofo": true},
+	{"Latin": true, "Han": true, "Hangul": true},
+}
+
+// latinConfusables maps letters of other scripts to the Latin letters they are
+// confusable with, from the Unicode confusables data.
+var latinConfusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'с': 'c', 'ԁ': 'd', 'е': 'e', 'һ': 'h', 'і': 'i', 'ј': 'j',
+	'ӏ': 'l', 'о': 'o', 'р': 'p', 'ԛ': 'q', 'ѕ': 's', 'ԝ': 'w', 'х': 'x',
+	'у': 'y',
+	// Greek
+	'α': 'a', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'υ': 'u',
+}
+
+// SetIDNPolicies sets the script policies applied to the labels of
+// internationalized domain names, by TLD. It returns an error if a TLD or
+// script name in the config is invalid.
+func (pa *AuthorityImpl) SetIDNPolicies(c cmd.IDNConfig) error {
+	def, err := parseIDNPolicy(c.Default)
+	if err != nil {
+		return fmt.Errorf("default IDN policy: %s", err)
+	}
+	policies := make(map[string]idnPolicy, len(c.TLDs))
+	for tld, pc := range c.TLDs {
+		// TLDs may be given as U-labels, but names are checked as A-labels
+		aTLD, err := idna.Registration.ToASCII(strings.ToLower(tld))
+		if err != nil || aTLD == "" || strings.Contains(aTLD, ".") {
+			return fmt.Errorf("malformed TLD %q in IDN policies", tld)
+		}
+		if _, present := policies[aTLD]; present {
+			return fmt.Errorf("IDN policy for TLD %q configured more than once", aTLD)
+		}
+		p, err := parseIDNPolicy(pc)
+		if err != nil {
+			return fmt.Errorf("IDN policy for TLD %q: %s", tld, err)
+		}
+		policies[aTLD] = p
+	}
+	pa.idnDefault = def
+	pa.idnPolicies = policies
+	return nil
+}
+
+func parseIDNPolicy(pc cmd.IDNPolicyConfig) (idnPolicy, error) {
+	p := idnPolicy{
+		rejectMixedScripts: pc.RejectMixedScripts,
+		rejectConfusables:  pc.RejectConfusables,
+	}
+	if len(pc.AllowedScripts) > 0 {
+		p.allowedScripts = make(map[string]bool, len(pc.AllowedScripts))
+		for _, script := range pc.AllowedScripts {
+			if _, ok := unicode.Scripts[script]; !ok || script == "Common" || script == "Inherited" {
+				return idnPolicy{}, fmt.Errorf("unknown script %q", script)
+			}
+			p.allowedScripts[script] = true
+		}
+	}
+	return p, nil
+}
+
+// checkIDN checks that ulabel, decoded from the A-label label, is a valid
+// IDNA2008 label. If checkScripts is true it's also checked against the IDN
+// policy for the TLD.
+func (pa *AuthorityImpl) checkIDN(label, ulabel, tld string, checkScripts bool) error {
+	// The A-label must be the canonical encoding of a U-label that is valid
+	// for registration, which excludes disallowed and unmapped characters,
+	// misplaced hyphens, joiners and combining marks, and Bidi rule violations.
+	aLabel, err := idna.Registration.ToASCII(ulabel)
+	if err != nil || aLabel != label {
+		return berrors.MalformedError("DNS label %q is not a valid IDNA2008 label", label)
+	}
+	// The vendored IDNA tables are those of UTS #46, which allow symbols
+	// IDNA2008 doesn't, so also require the letters, marks and digits of RFC
+	// 5892 section 2.1.
+	for _, r := range ulabel {
+		if r != '-' && !unicode.In(r, unicode.Ll, unicode.Lu, unicode.Lo, unicode.Lm, unicode.Mn, unicode.Mc, unicode.Nd) {
+			return berrors.MalformedError("DNS label %q contains %U, which IDNA2008 doesn't allow", label, r)
+		}
+	}
+	if !checkScripts {
+		return nil
+	}
+
+	policy, ok := pa.idnPolicies[tld]
+	if !ok {
+		policy = pa.idnDefault
+	}
+	scripts := labelScripts(ulabel)
+	if policy.allowedScripts != nil {
+		for _, script := range scripts {
+			if !policy.allowedScripts[script] {
+				return berrors.MalformedError("DNS label %q (%q) uses the %s script, which isn't allowed under .%s",
+					label, ulabel, script, tld)
+			}
+		}
+	}
+	if policy.rejectMixedScripts && !singleScript(scripts) {
+		return berrors.MalformedError("DNS label %q (%q) mixes scripts: %s",
+			label, ulabel, strings.Join(scripts, ", "))
+	}
+	if policy.rejectConfusables {
+		if skeleton, confusable := latinSkeleton(ulabel); confusable {
+			return berrors.MalformedError("DNS label %q (%q) is confusable with %q", label, ulabel, skeleton)
+		}
+	}
+	return nil
+}
+
+// scriptOf returns the name of the Unicode script of r, or "" if r is common
+// to all scripts or inherits the script of the preceding character.
+func scriptOf(r rune) string {
+	for name, table := range unicode.Scripts {
+		if name != "Common" && name != "Inherited" && unicode.Is(table, r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// labelScripts returns the sorted names of the scripts used in ulabel.
+func labelScripts(ulabel string) []string {
+	seen := make(map[string]bool)
+	var scripts []string
+	for _, r := range ulabel {
+		if script := scriptOf(r); script != "" && !seen[script] {
+			seen[script] = true
+			scripts = append(scripts, script)
+		}
+	}
+	sort.Strings(scripts)
+	return scripts
+}
+
+// singleScript returns whether scripts, from labelScripts, are one script or
+// one of the combinations in cjkScripts.
+func singleScript(scripts []string) bool {
+	if len(scripts) <= 1 {
+		return true
+	}
+	for _, combination := range cjkScripts {
+		all := true
+		for _, script := range scripts {
+			if !combination[script] {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+// latinSkeleton returns ulabel with each letter in latinConfusables replaced
+// by the Latin letter it's confusable with, and true, if that leaves only
+// ASCII. Otherwise it returns false, as the label isn't confusable with an
+// ASCII label.
+func latinSkeleton(ulabel string) (string, bool) {
+	var skeleton []rune
+	for _, r := range ulabel {
+		if latin, ok := latinConfusables[r]; ok {
+			skeleton = append(skeleton, latin)
+		} else if r <= unicode.MaxASCII {
+			skeleton = append(skeleton, r)
+		} else {
+			return "", false
+		}
+	}
+	return string(skeleton), true
+}
@@ -0,0 +1,126 @@
+package policy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestWillingToIssueIDNA2008(t *testing.T) {
+	pa := paImpl(t)
+	err := pa.SetHostnamePolicyFile("../test/hostname-policy.json")
+	test.AssertNotError(t, err, "Couldn't load hostname policy")
+
+	testCases := []struct {
+		domain string
+		err    string
+	}{
+		{"xn--mnich-kva.com", ""},
+		{"xn--1ca0960bnsf.com", ""},
+		// Emoji are valid under UTS #46 but not IDNA2008
+		{"xn--ls8h.com", `DNS label "xn--ls8h" contains U+1F4A9, which IDNA2008 doesn't allow`},
+		// A ZERO WIDTH NON-JOINER without a virama before it
+		{"xn--ab-j1t.com", `DNS label "xn--ab-j1t" is not a valid IDNA2008 label`},
+		// U+2488 DIGIT ONE FULL STOP is disallowed
+		{"xn--a-ecp.com", `DNS label "xn--a-ecp" is not a valid IDNA2008 label`},
+		// Punycode for the ASCII label "abc", which must not be encoded
+		{"xn--abc-.com", "Invalid character in DNS name"},
+		{"xn--abc.com", `DNS label "xn--abc" is not a valid IDNA2008 label`},
+		// Uppercase letters must be mapped to lowercase before encoding
+		{"xn--mnich-2pa.com", `DNS label "xn--mnich-2pa" is not a valid IDNA2008 label`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.domain, func(t *testing.T) {
+			err := pa.WillingToIssue(core.AcmeIdentifier{Type: core.IdentifierDNS, Value: tc.domain}, testRegID)
+			if tc.err == "" {
+				test.AssertNotError(t, err, "WillingToIssue failed")
+				return
+			}
+			test.AssertError(t, err, "WillingToIssue succeeded")
+			test.Assert(t, berrors.Is(err, berrors.Malformed), "Wrong error type")
+			test.AssertEquals(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestWillingToIssueIDNPolicies(t *testing.T) {
+	pa := paImpl(t)
+	err := pa.SetHostnamePolicyFile("../test/hostname-policy.json")
+	test.AssertNotError(t, err, "Couldn't load hostname policy")
+	err = pa.SetIDNPolicies(cmd.IDNConfig{
+		Default: cmd.IDNPolicyConfig{
+			RejectMixedScripts: true,
+			RejectConfusables:  true,
+		},
+		TLDs: map[string]cmd.IDNPolicyConfig{
+			"рф":  {AllowedScripts: []string{"Cyrillic"}},
+			"org": {},
+		},
+	})
+	test.AssertNotError(t, err, "SetIDNPolicies failed")
+
+	testCases := []struct {
+		domain string
+		err    string
+	}{
+		{"xn--mnich-kva.com", ""},
+		// Cyrillic "аррӏе", confusable with "apple"
+		{"xn--80ak6aa92e.com", `DNS label "xn--80ak6aa92e" ("аррӏе") is confusable with "apple"`},
+		// Cyrillic "ра" in an otherwise Latin "paypal"
+		{"xn--aypl-73d6g.com", `DNS label "xn--aypl-73d6g" ("рaypаl") mixes scripts: Cyrillic, Latin`},
+		// Latin with Han is allowed, being used to write Chinese and Japanese
+		{"xn--1ca0960bnsf.com", ""},
+		// Cyrillic that isn't confusable
+		{"xn--e1afmkfd.com", ""},
+		// The .org policy doesn't check scripts
+		{"xn--80ak6aa92e.org", ""},
+		// The .рф policy only allows Cyrillic
+		{"xn--e1afmkfd.xn--p1ai", ""},
+		{"xn--mnich-kva.xn--p1ai", `DNS label "xn--mnich-kva" ("münich") uses the Latin script, which isn't allowed under .xn--p1ai`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.domain, func(t *testing.T) {
+			err := pa.WillingToIssue(core.AcmeIdentifier{Type: core.IdentifierDNS, Value: tc.domain}, testRegID)
+			if tc.err == "" {
+				test.AssertNotError(t, err, "WillingToIssue failed")
+				return
+			}
+			test.AssertError(t, err, "WillingToIssue succeeded")
+			test.Assert(t, berrors.Is(err, berrors.Malformed), "Wrong error type")
+			test.AssertEquals(t, err.Error(), tc.err)
+		})
+	}
+
+	// Wildcard names are checked the same way
+	err = pa.WillingToIssueWildcard(core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "*.xn--80ak6aa92e.com"}, testRegID)
+	test.AssertError(t, err, "WillingToIssueWildcard succeeded for a confusable name")
+}
+
+func TestSetIDNPolicies(t *testing.T) {
+	pa := paImpl(t)
+	err := pa.SetIDNPolicies(cmd.IDNConfig{
+		TLDs: map[string]cmd.IDNPolicyConfig{
+			"COM":      {AllowedScripts: []string{"Latin"}},
+			"xn--p1ai": {AllowedScripts: []string{"Cyrillic"}},
+		},
+	})
+	test.AssertNotError(t, err, "SetIDNPolicies failed")
+	test.Assert(t, pa.idnPolicies["com"].allowedScripts["Latin"], "Latin isn't allowed for com")
+	test.Assert(t, pa.idnPolicies["xn--p1ai"].allowedScripts["Cyrillic"], "Cyrillic isn't allowed for xn--p1ai")
+
+	for _, c := range []cmd.IDNConfig{
+		{Default: cmd.IDNPolicyConfig{AllowedScripts: []string{"Klingon"}}},
+		{Default: cmd.IDNPolicyConfig{AllowedScripts: []string{"Common"}}},
+		{TLDs: map[string]cmd.IDNPolicyConfig{"co.uk": {}}},
+		{TLDs: map[string]cmd.IDNPolicyConfig{"": {}}},
+		{TLDs: map[string]cmd.IDNPolicyConfig{"com": {AllowedScripts: []string{"Klingon"}}}},
+		{TLDs: map[string]cmd.IDNPolicyConfig{"рф": {}, "xn--p1ai": {}}},
+	} {
+		err := pa.SetIDNPolicies(c)
+		test.AssertError(t, err, fmt.Sprintf("SetIDNPolicies accepted %#v", c))
+	}
+}
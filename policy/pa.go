@@ -11,6 +11,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/jmhodges/clock"
 	"github.com/weppos/publicsuffix-go/publicsuffix"
 	"golang.org/x/net/idna"
 	"golang.org/x/text/unicode/norm"
@@ -26,13 +27,17 @@ import (
 type AuthorityImpl struct {
 	log blog.Logger
 
-	blacklist              map[string]bool
-	exactBlacklist         map[string]bool
-	wildcardExactBlacklist map[string]bool
-	blacklistMu            sync.RWMutex
+	clk clock.Clock
+
+	hostnamePolicy *hostnamePolicy
+	blacklistMu    sync.RWMutex
+
+	idnDefault  idnPolicy
+	idnPolicies map[string]idnPolicy
 
 	enabledChallenges          map[string]bool
 	enabledChallengesWhitelist map[string]map[int64]bool
+	contactSchemes             map[string]bool
 	pseudoRNG                  *rand.Rand
 	rngMu                      sync.Mutex
 }
@@ -42,7 +47,9 @@ func New(challengeTypes map[string]bool) (*AuthorityImpl, error) {
 
 	pa := AuthorityImpl{
 		log:               blog.Get(),
+		clk:               clock.Default(),
 		enabledChallenges: challengeTypes,
+		contactSchemes:    map[string]bool{"mailto": true},
 		// We don't need real randomness for this.
 		pseudoRNG: rand.New(rand.NewSource(99)),
 	}
@@ -50,11 +57,6 @@ func New(challengeTypes map[string]bool) (*AuthorityImpl, error) {
 	return &pa, nil
 }
 
-type blacklistJSON struct {
-	Blacklist      []string
-	ExactBlacklist []string
-}
-
 // SetHostnamePolicyFile will load the given policy file, returning error if it
 // fails. It will also start a reloader in case the file changes.
 func (pa *AuthorityImpl) SetHostnamePolicyFile(f string) error {
@@ -69,44 +71,12 @@ func (pa *AuthorityImpl) hostnamePolicyLoadError(err error) {
 func (pa *AuthorityImpl) loadHostnamePolicy(b []byte) error {
 	hash := sha256.Sum256(b)
 	pa.log.Infof("loading hostname policy, sha256: %s", hex.EncodeToString(hash[:]))
-	var bl blacklistJSON
-	err := json.Unmarshal(b, &bl)
+	policy, err := parseHostnamePolicy(b)
 	if err != nil {
 		return err
 	}
-	if len(bl.Blacklist) == 0 {
-		return fmt.Errorf("No entries in blacklist.")
-	}
-	nameMap := make(map[string]bool)
-	for _, v := range bl.Blacklist {
-		nameMap[v] = true
-	}
-	exactNameMap := make(map[string]bool)
-	wildcardNameMap := make(map[string]bool)
-	for _, v := range bl.ExactBlacklist {
-		exactNameMap[v] = true
-		// Remove the leftmost label of the exact blacklist entry to make an exact
-		// wildcard blacklist entry that will prevent issuing a wildcard that would
-		// include the exact blacklist entry. e.g. if "highvalue.example.com" is on
-		// the exact blacklist we want "example.com" to be on the
-		// wildcardExactBlacklist so that "*.example.com" cannot be issued.
-		//
-		// First, split the domain into two parts: the first label and the rest of the domain.
-		parts := strings.SplitN(v, ".", 2)
-		// if there are less than 2 parts then this entry is malformed! There should
-		// at least be a "something." and a TLD like "com"
-		if len(parts) < 2 {
-			return fmt.Errorf(
-				"Malformed exact blacklist entry, only one label: %q", v)
-		}
-		// Add the second part, the domain minus the first label, to the
-		// wildcardNameMap to block issuance for `*.`+parts[1]
-		wildcardNameMap[parts[1]] = true
-	}
 	pa.blacklistMu.Lock()
-	pa.blacklist = nameMap
-	pa.exactBlacklist = exactNameMap
-	pa.wildcardExactBlacklist = wildcardNameMap
+	pa.hostnamePolicy = policy
 	pa.blacklistMu.Unlock()
 	return nil
 }
@@ -194,11 +164,12 @@ var (
 	errMalformedWildcard    = berrors.MalformedError("DNS name had a malformed wildcard label")
 	errICANNTLDWildcard     = berrors.MalformedError("DNS name was a wildcard for an ICANN TLD")
 	errWildcardNotSupported = berrors.MalformedError("Wildcard names not supported")
+	errManualReview         = berrors.RejectedIdentifierError("Issuance for name requires manual review")
 )
 
 // WillingToIssue determines whether the CA is willing to issue for the provided
-// identifier. It expects domains in id to be lowercase to prevent mismatched
-// cases breaking queries.
+// identifier to the given registration ID. It expects domains in id to be
+// lowercase to prevent mismatched cases breaking queries.
 //
 // We place several criteria on identifiers we are willing to issue for:
 //
@@ -208,15 +179,17 @@ var (
 //  * MUST follow the DNS hostname syntax rules in RFC 1035 and RFC 2181
 //    In particular:
 //    * MUST NOT contain underscores
+//  * Labels starting with "xn--" MUST be valid IDNA2008 A-labels, and MUST
+//    follow the IDN policy for the TLD
 //  * MUST NOT match the syntax of an IP address
 //  * MUST end in a public suffix
 //  * MUST have at least one label in addition to the public suffix
-//  * MUST NOT be a label-wise suffix match for a name on the black list,
-//    where comparison is case-independent (normalized to lower case)
+//  * MUST NOT be blocked or held for manual review by the hostname policy,
+//    unless the policy allows it for the registration ID
 //
 // If WillingToIssue returns an error, it will be of type MalformedRequestError
 // or RejectedIdentifierError
-func (pa *AuthorityImpl) WillingToIssue(id core.AcmeIdentifier) error {
+func (pa *AuthorityImpl) WillingToIssue(id core.AcmeIdentifier, regID int64) error {
 	if id.Type != core.IdentifierDNS {
 		return errInvalidIdentifier
 	}
@@ -255,7 +228,8 @@ func (pa *AuthorityImpl) WillingToIssue(id core.AcmeIdentifier) error {
 	if len(labels) < 2 {
 		return errTooFewLabels
 	}
-	for _, label := range labels {
+	tld := labels[len(labels)-1]
+	for i, label := range labels {
 		if len(label) < 1 {
 			return errLabelTooShort
 		}
@@ -272,10 +246,6 @@ func (pa *AuthorityImpl) WillingToIssue(id core.AcmeIdentifier) error {
 		}
 
 		if punycodeRegexp.MatchString(label) {
-			// We don't care about script usage, if a name is resolvable it was
-			// registered with a higher power and they should be enforcing their
-			// own policy. As long as it was properly encoded that is enough
-			// for us.
 			ulabel, err := idna.ToUnicode(label)
 			if err != nil {
 				return errMalformedIDN
@@ -283,6 +253,11 @@ func (pa *AuthorityImpl) WillingToIssue(id core.AcmeIdentifier) error {
 			if !norm.NFC.IsNormalString(ulabel) {
 				return errMalformedIDN
 			}
+			// The TLD's script usage is ICANN's concern, so only the labels
+			// under it are checked against the IDN policy.
+			if err := pa.checkIDN(label, ulabel, tld, i < len(labels)-1); err != nil {
+				return err
+			}
 		} else if idnReservedRegexp.MatchString(label) {
 			return errInvalidRLDH
 		}
@@ -297,8 +272,8 @@ func (pa *AuthorityImpl) WillingToIssue(id core.AcmeIdentifier) error {
 		return errICANNTLD
 	}
 
-	// Require no match against blacklist
-	if err := pa.checkHostLists(domain); err != nil {
+	// Require no match against the hostname policy
+	if err := pa.checkHostLists(domain, regID); err != nil {
 		return err
 	}
 
@@ -318,7 +293,7 @@ func (pa *AuthorityImpl) WillingToIssue(id core.AcmeIdentifier) error {
 //
 // If all of the above is true then the base domain (e.g. without the *.) is run
 // through WillingToIssue to catch other illegal things (blocked hosts, etc).
-func (pa *AuthorityImpl) WillingToIssueWildcard(ident core.AcmeIdentifier) error {
+func (pa *AuthorityImpl) WillingToIssueWildcard(ident core.AcmeIdentifier, regID int64) error {
 	// We're only willing to process DNS identifiers
 	if ident.Type != core.IdentifierDNS {
 		return errInvalidIdentifier
@@ -352,7 +327,7 @@ func (pa *AuthorityImpl) WillingToIssueWildcard(ident core.AcmeIdentifier) error
 			return errICANNTLDWildcard
 		}
 		// The base domain can't be in the wildcard exact blacklist
-		if err := pa.checkWildcardHostList(baseDomain); err != nil {
+		if err := pa.checkWildcardHostList(baseDomain, regID); err != nil {
 			return err
 		}
 		// Check that the PA is willing to issue for the base domain
@@ -367,57 +342,69 @@ func (pa *AuthorityImpl) WillingToIssueWildcard(ident core.AcmeIdentifier) error
 		return pa.WillingToIssue(core.AcmeIdentifier{
 			Type:  core.IdentifierDNS,
 			Value: "x." + baseDomain,
-		})
+		}, regID)
 	}
 
-	return pa.WillingToIssue(ident)
+	return pa.WillingToIssue(ident, regID)
 }
 
-// checkWildcardHostList checks the wildcardExactBlacklist for a given domain.
-// If the domain is not present on the list nil is returned, otherwise
-// errBlacklisted is returned.
-func (pa *AuthorityImpl) checkWildcardHostList(domain string) error {
+// checkWildcardHostList checks the hostname policy's exact-block rules for
+// names a wildcard for the given base domain would cover. If there are none, or
+// the policy allows the domain for the registration ID, nil is returned.
+// Otherwise the error for the exact-block rule is returned.
+func (pa *AuthorityImpl) checkWildcardHostList(domain string, regID int64) error {
 	pa.blacklistMu.RLock()
-	defer pa.blacklistMu.RUnlock()
+	policy := pa.hostnamePolicy
+	pa.blacklistMu.RUnlock()
 
-	if pa.blacklist == nil {
+	if policy == nil {
 		return fmt.Errorf("Hostname policy not yet loaded.")
 	}
 
-	if pa.wildcardExactBlacklist[domain] {
-		return errBlacklisted
+	now := pa.clk.Now()
+	if policy.allowed(domain, regID, now) {
+		return nil
+	}
+	if rule := firstActive(policy.wildcardExactBlocks[domain], now); rule != nil {
+		return ruleError(rule)
 	}
 
 	return nil
 }
 
-func (pa *AuthorityImpl) checkHostLists(domain string) error {
+// checkHostLists checks the domain against the hostname policy's rules. Rules
+// allowing the domain for the registration ID take precedence over the block
+// rules, which take precedence over the manual review rules.
+func (pa *AuthorityImpl) checkHostLists(domain string, regID int64) error {
 	pa.blacklistMu.RLock()
-	defer pa.blacklistMu.RUnlock()
+	policy := pa.hostnamePolicy
+	pa.blacklistMu.RUnlock()
 
-	if pa.blacklist == nil {
+	if policy == nil {
 		return fmt.Errorf("Hostname policy not yet loaded.")
 	}
 
-	labels := strings.Split(domain, ".")
-	for i := range labels {
-		joined := strings.Join(labels[i:], ".")
-		if pa.blacklist[joined] {
-			return errBlacklisted
-		}
+	now := pa.clk.Now()
+	if policy.allowed(domain, regID, now) {
+		return nil
 	}
-
-	if pa.exactBlacklist[domain] {
-		return errBlacklisted
+	if rule := policy.blocked(domain, now); rule != nil {
+		return ruleError(rule)
+	}
+	if rule := matchSuffix(policy.manualReviews, domain, now); rule != nil {
+		pa.log.AuditInfof("Hostname policy requires manual review of %q for registration ID %d: %s",
+			domain, regID, rule.reason)
+		return ruleError(rule)
 	}
 	return nil
 }
 
 // ChallengesFor makes a decision of what challenges, and combinations, are
-// acceptable for the given identifier. If the TLSSNIRevalidation feature flag
-// is set, create TLS-SNI-01 challenges for revalidation requests even if
-// TLS-SNI-01 is not among the configured challenges.
-func (pa *AuthorityImpl) ChallengesFor(identifier core.AcmeIdentifier, regID int64, revalidation bool) ([]core.Challenge, [][]int, error) {
+// acceptable for the given identifier. Only challenge types allowed by the
+// registration's challenge preferences are offered. If the TLSSNIRevalidation
+// feature flag is set, create TLS-SNI-01 challenges for revalidation requests
+// even if TLS-SNI-01 is not among the configured challenges.
+func (pa *AuthorityImpl) ChallengesFor(identifier core.AcmeIdentifier, reg core.Registration, revalidation bool) ([]core.Challenge, [][]int, error) {
 	challenges := []core.Challenge{}
 
 	// If the identifier is for a DNS wildcard name we only
@@ -425,33 +412,49 @@ func (pa *AuthorityImpl) ChallengesFor(identifier core.AcmeIdentifier, regID int
 	if strings.HasPrefix(identifier.Value, "*.") {
 		// We must have the DNS-01 challenge type enabled to create challenges for
 		// a wildcard identifier per LE policy.
-		if !pa.ChallengeTypeEnabled(core.ChallengeTypeDNS01, regID) {
+		if !pa.ChallengeTypeEnabled(core.ChallengeTypeDNS01, reg.ID) {
 			return nil, nil, fmt.Errorf(
 				"Challenges requested for wildcard identifier but DNS-01 " +
 					"challenge type is not enabled")
 		}
+		if !pa.AccountAllowsChallengeType(core.ChallengeTypeDNS01, reg) {
+			return nil, nil, berrors.UnauthorizedError(
+				"Account's challenge preferences do not allow DNS-01, which is " +
+					"required for wildcard identifiers")
+		}
 		// Only provide a DNS-01-Wildcard challenge
 		challenges = []core.Challenge{core.DNSChallenge01()}
 	} else {
+		enabled := func(t string) bool {
+			return pa.ChallengeTypeEnabled(t, reg.ID) && pa.AccountAllowsChallengeType(t, reg)
+		}
+
 		// Otherwise we collect up challenges based on what is enabled.
-		if pa.ChallengeTypeEnabled(core.ChallengeTypeHTTP01, regID) {
+		if enabled(core.ChallengeTypeHTTP01) {
 			challenges = append(challenges, core.HTTPChallenge01())
 		}
 
 		// Add a TLS-SNI challenge, if either (a) the challenge is enabled, or (b)
 		// the TLSSNIRevalidation feature flag is on and this is a revalidation.
-		if pa.ChallengeTypeEnabled(core.ChallengeTypeTLSSNI01, regID) ||
-			(features.Enabled(features.TLSSNIRevalidation) && revalidation) {
+		if enabled(core.ChallengeTypeTLSSNI01) ||
+			(features.Enabled(features.TLSSNIRevalidation) && revalidation &&
+				pa.AccountAllowsChallengeType(core.ChallengeTypeTLSSNI01, reg)) {
 			challenges = append(challenges, core.TLSSNIChallenge01())
 		}
 
-		if pa.ChallengeTypeEnabled(core.ChallengeTypeTLSALPN01, regID) {
+		if enabled(core.ChallengeTypeTLSALPN01) {
 			challenges = append(challenges, core.TLSALPNChallenge01())
 		}
 
-		if pa.ChallengeTypeEnabled(core.ChallengeTypeDNS01, regID) {
+		if enabled(core.ChallengeTypeDNS01) {
 			challenges = append(challenges, core.DNSChallenge01())
 		}
+
+		if len(challenges) == 0 && reg.ChallengeTypes != nil && len(*reg.ChallengeTypes) > 0 {
+			return nil, nil, berrors.UnauthorizedError(
+				"None of the challenge types allowed by the account's challenge " +
+					"preferences are enabled")
+		}
 	}
 
 	// We shuffle the challenges and combinations to prevent ACME clients from
@@ -506,3 +509,46 @@ func (pa *AuthorityImpl) ChallengeTypeEnabled(t string, regID int64) bool {
 	return pa.enabledChallenges[t] ||
 		(pa.enabledChallengesWhitelist[t] != nil && pa.enabledChallengesWhitelist[t][regID])
 }
+
+// AccountAllowsChallengeType returns whether the registration's challenge
+// preferences allow the specified challenge type. A registration without
+// challenge preferences allows every type.
+func (pa *AuthorityImpl) AccountAllowsChallengeType(t string, reg core.Registration) bool {
+	if reg.ChallengeTypes == nil || len(*reg.ChallengeTypes) == 0 {
+		return true
+	}
+	for _, allowed := range *reg.ChallengeTypes {
+		if allowed == t {
+			return true
+		}
+	}
+	return false
+}
+
+// supportedContactSchemes are the registration contact URL schemes that
+// Boulder knows how to validate and deliver notifications to.
+var supportedContactSchemes = map[string]bool{
+	"mailto": true,
+	"https":  true,
+}
+
+// SetContactSchemes sets the URL schemes accepted for registration contacts,
+// replacing the default of only "mailto". It returns an error if a scheme
+// isn't supported.
+func (pa *AuthorityImpl) SetContactSchemes(schemes []string) error {
+	enabled := make(map[string]bool, len(schemes))
+	for _, scheme := range schemes {
+		if !supportedContactSchemes[scheme] {
+			return fmt.Errorf("unsupported contact scheme %q", scheme)
+		}
+		enabled[scheme] = true
+	}
+	pa.contactSchemes = enabled
+	return nil
+}
+
+// ContactSchemeAllowed returns whether registration contacts may use the
+// given URL scheme.
+func (pa *AuthorityImpl) ContactSchemeAllowed(scheme string) bool {
+	return pa.contactSchemes[scheme]
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/letsencrypt/boulder/core"
+	berrors "github.com/letsencrypt/boulder/errors"
 	"github.com/letsencrypt/boulder/features"
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/test"
@@ -153,7 +154,7 @@ func TestWillingToIssue(t *testing.T) {
 
 	// Test for invalid identifier type
 	identifier := core.AcmeIdentifier{Type: "ip", Value: "example.com"}
-	err = pa.WillingToIssue(identifier)
+	err = pa.WillingToIssue(identifier, testRegID)
 	if err != errInvalidIdentifier {
 		t.Error("Identifier was not correctly forbidden: ", identifier)
 	}
@@ -161,27 +162,27 @@ func TestWillingToIssue(t *testing.T) {
 	// Test syntax errors
 	for _, tc := range testCases {
 		identifier := core.AcmeIdentifier{Type: core.IdentifierDNS, Value: tc.domain}
-		err := pa.WillingToIssue(identifier)
+		err := pa.WillingToIssue(identifier, testRegID)
 		if err != tc.err {
 			t.Errorf("WillingToIssue(%q) = %q, expected %q", tc.domain, err, tc.err)
 		}
 	}
 
 	// Invalid encoding
-	err = pa.WillingToIssue(core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "www.xn--m.com"})
+	err = pa.WillingToIssue(core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "www.xn--m.com"}, testRegID)
 	test.AssertError(t, err, "WillingToIssue didn't fail on a malformed IDN")
 	// Valid encoding
-	err = pa.WillingToIssue(core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "www.xn--mnich-kva.com"})
+	err = pa.WillingToIssue(core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "www.xn--mnich-kva.com"}, testRegID)
 	test.AssertNotError(t, err, "WillingToIssue failed on a properly formed IDN")
 	// IDN TLD
-	err = pa.WillingToIssue(core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "xn--example--3bhk5a.xn--p1ai"})
+	err = pa.WillingToIssue(core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "xn--example--3bhk5a.xn--p1ai"}, testRegID)
 	test.AssertNotError(t, err, "WillingToIssue failed on a properly formed domain with IDN TLD")
 	features.Reset()
 
 	// Test domains that are equal to public suffixes
 	for _, domain := range shouldBeTLDError {
 		identifier := core.AcmeIdentifier{Type: core.IdentifierDNS, Value: domain}
-		err := pa.WillingToIssue(identifier)
+		err := pa.WillingToIssue(identifier, testRegID)
 		if err != errICANNTLD {
 			t.Error("Identifier was not correctly forbidden: ", identifier, err)
 		}
@@ -190,7 +191,7 @@ func TestWillingToIssue(t *testing.T) {
 	// Test blacklisting
 	for _, domain := range shouldBeBlacklisted {
 		identifier := core.AcmeIdentifier{Type: core.IdentifierDNS, Value: domain}
-		err := pa.WillingToIssue(identifier)
+		err := pa.WillingToIssue(identifier, testRegID)
 		if err != errBlacklisted {
 			t.Error("Identifier was not correctly forbidden: ", identifier, err)
 		}
@@ -199,7 +200,7 @@ func TestWillingToIssue(t *testing.T) {
 	// Test acceptance of good names
 	for _, domain := range shouldBeAccepted {
 		identifier := core.AcmeIdentifier{Type: core.IdentifierDNS, Value: domain}
-		if err := pa.WillingToIssue(identifier); err != nil {
+		if err := pa.WillingToIssue(identifier, testRegID); err != nil {
 			t.Error("Identifier was incorrectly forbidden: ", identifier, err)
 		}
 	}
@@ -298,7 +299,7 @@ func TestWillingToIssueWildcard(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
-			result := pa.WillingToIssueWildcard(tc.Ident)
+			result := pa.WillingToIssueWildcard(tc.Ident, testRegID)
 			test.AssertEquals(t, result, tc.ExpectedErr)
 		})
 	}
@@ -313,7 +314,7 @@ var accountKeyJSON = `{
 func TestChallengesFor(t *testing.T) {
 	pa := paImpl(t)
 
-	challenges, combinations, err := pa.ChallengesFor(core.AcmeIdentifier{}, testRegID, false)
+	challenges, combinations, err := pa.ChallengesFor(core.AcmeIdentifier{}, core.Registration{ID: testRegID}, false)
 	test.AssertNotError(t, err, "ChallengesFor failed")
 
 	test.Assert(t, len(challenges) == len(enabledChallenges), "Wrong number of challenges returned")
@@ -353,11 +354,11 @@ func TestChallengesForWhitelist(t *testing.T) {
 	err = pa.SetChallengesWhitelistFile(f.Name())
 	test.AssertNotError(t, err, "Couldn't load policy contents from file")
 
-	challenges, _, err := pa.ChallengesFor(core.AcmeIdentifier{}, testRegID, false)
+	challenges, _, err := pa.ChallengesFor(core.AcmeIdentifier{}, core.Registration{ID: testRegID}, false)
 	test.AssertNotError(t, err, "ChallengesFor failed")
 	test.Assert(t, len(challenges) == len(enabledChallenges)-1, "Wrong number of challenges returned")
 
-	challenges, _, err = pa.ChallengesFor(core.AcmeIdentifier{}, testRegIDWhitelisted, false)
+	challenges, _, err = pa.ChallengesFor(core.AcmeIdentifier{}, core.Registration{ID: testRegIDWhitelisted}, false)
 	test.AssertNotError(t, err, "ChallengesFor failed")
 	test.Assert(t, len(challenges) == len(enabledChallenges), "Wrong number of challenges returned")
 }
@@ -383,7 +384,7 @@ func TestChallengesForWildcard(t *testing.T) {
 		core.ChallengeTypeDNS01:    false,
 	}
 	pa := mustConstructPA(t, enabledChallenges)
-	_, _, err := pa.ChallengesFor(wildcardIdent, testRegID, false)
+	_, _, err := pa.ChallengesFor(wildcardIdent, core.Registration{ID: testRegID}, false)
 	test.AssertError(t, err, "ChallengesFor did not error for a wildcard ident "+
 		"when DNS-01 was disabled")
 	test.AssertEquals(t, err.Error(), "Challenges requested for wildcard "+
@@ -393,12 +394,75 @@ func TestChallengesForWildcard(t *testing.T) {
 	// should return only one DNS-01 type challenge
 	enabledChallenges[core.ChallengeTypeDNS01] = true
 	pa = mustConstructPA(t, enabledChallenges)
-	challenges, combinations, err := pa.ChallengesFor(wildcardIdent, testRegID, false)
+	challenges, combinations, err := pa.ChallengesFor(wildcardIdent, core.Registration{ID: testRegID}, false)
 	test.AssertNotError(t, err, "ChallengesFor errored for a wildcard ident "+
 		"unexpectedly")
 	test.AssertEquals(t, len(combinations), 1)
 	test.AssertEquals(t, len(challenges), 1)
 	test.AssertEquals(t, challenges[0].Type, core.ChallengeTypeDNS01)
+
+	// An account whose challenge preferences don't allow DNS-01 can't get
+	// challenges for a wildcard ident
+	httpOnly := core.Registration{ID: testRegID, ChallengeTypes: &[]string{core.ChallengeTypeHTTP01}}
+	_, _, err = pa.ChallengesFor(wildcardIdent, httpOnly, false)
+	test.AssertError(t, err, "ChallengesFor did not error for a wildcard ident "+
+		"when the account didn't allow DNS-01")
+	test.Assert(t, berrors.Is(err, berrors.Unauthorized), "Wrong error type")
+}
+
+func TestChallengesForAccountPreferences(t *testing.T) {
+	pa, err := New(map[string]bool{
+		core.ChallengeTypeHTTP01:   true,
+		core.ChallengeTypeTLSSNI01: false,
+		core.ChallengeTypeDNS01:    true,
+	})
+	test.AssertNotError(t, err, "Couldn't create policy implementation")
+
+	dnsOnly := core.Registration{ID: testRegID, ChallengeTypes: &[]string{core.ChallengeTypeDNS01}}
+	challenges, combinations, err := pa.ChallengesFor(core.AcmeIdentifier{}, dnsOnly, false)
+	test.AssertNotError(t, err, "ChallengesFor failed")
+	test.AssertEquals(t, len(challenges), 1)
+	test.AssertEquals(t, len(combinations), 1)
+	test.AssertEquals(t, challenges[0].Type, core.ChallengeTypeDNS01)
+
+	// An empty list of preferences allows every enabled challenge type
+	challenges, _, err = pa.ChallengesFor(core.AcmeIdentifier{}, core.Registration{ChallengeTypes: &[]string{}}, false)
+	test.AssertNotError(t, err, "ChallengesFor failed")
+	test.AssertEquals(t, len(challenges), 2)
+
+	// Preferences are only for challenge types which aren't enabled
+	alpnOnly := core.Registration{ID: testRegID, ChallengeTypes: &[]string{core.ChallengeTypeTLSALPN01}}
+	_, _, err = pa.ChallengesFor(core.AcmeIdentifier{}, alpnOnly, false)
+	test.AssertError(t, err, "ChallengesFor didn't fail without any allowed challenge type")
+	test.Assert(t, berrors.Is(err, berrors.Unauthorized), "Wrong error type")
+
+	// The account's preferences apply to TLS-SNI-01 revalidation challenges too
+	_ = features.Set(map[string]bool{"TLSSNIRevalidation": true})
+	defer features.Reset()
+	challenges, _, err = pa.ChallengesFor(core.AcmeIdentifier{}, dnsOnly, true)
+	test.AssertNotError(t, err, "ChallengesFor failed")
+	test.AssertEquals(t, len(challenges), 1)
+	test.AssertEquals(t, challenges[0].Type, core.ChallengeTypeDNS01)
+	sniOnly := core.Registration{ID: testRegID, ChallengeTypes: &[]string{core.ChallengeTypeTLSSNI01}}
+	challenges, _, err = pa.ChallengesFor(core.AcmeIdentifier{}, sniOnly, true)
+	test.AssertNotError(t, err, "ChallengesFor failed")
+	test.AssertEquals(t, len(challenges), 1)
+	test.AssertEquals(t, challenges[0].Type, core.ChallengeTypeTLSSNI01)
+}
+
+func TestAccountAllowsChallengeType(t *testing.T) {
+	pa := paImpl(t)
+
+	test.Assert(t, pa.AccountAllowsChallengeType(core.ChallengeTypeHTTP01, core.Registration{}),
+		"Account without preferences didn't allow HTTP-01")
+	test.Assert(t, pa.AccountAllowsChallengeType(core.ChallengeTypeHTTP01, core.Registration{ChallengeTypes: &[]string{}}),
+		"Account with empty preferences didn't allow HTTP-01")
+
+	dnsOnly := core.Registration{ChallengeTypes: &[]string{core.ChallengeTypeDNS01}}
+	test.Assert(t, pa.AccountAllowsChallengeType(core.ChallengeTypeDNS01, dnsOnly),
+		"DNS-only account didn't allow DNS-01")
+	test.Assert(t, !pa.AccountAllowsChallengeType(core.ChallengeTypeHTTP01, dnsOnly),
+		"DNS-only account allowed HTTP-01")
 }
 
 func TestExtractDomainIANASuffix_Valid(t *testing.T) {
@@ -496,3 +560,20 @@ func TestMalformedExactBlacklist(t *testing.T) {
 	test.AssertError(t, err, "Loaded invalid exact blacklist content without error")
 	test.AssertEquals(t, err.Error(), "Malformed exact blacklist entry, only one label: \"com\"")
 }
+
+func TestContactSchemes(t *testing.T) {
+	pa := paImpl(t)
+
+	// Only mailto is allowed by default
+	test.Assert(t, pa.ContactSchemeAllowed("mailto"), "mailto should be allowed by default")
+	test.Assert(t, !pa.ContactSchemeAllowed("https"), "https shouldn't be allowed by default")
+
+	err := pa.SetContactSchemes([]string{"mailto", "https"})
+	test.AssertNotError(t, err, "SetContactSchemes failed")
+	test.Assert(t, pa.ContactSchemeAllowed("https"), "https should be allowed")
+	test.Assert(t, !pa.ContactSchemeAllowed("tel"), "tel shouldn't be allowed")
+
+	err = pa.SetContactSchemes([]string{"tel"})
+	test.AssertError(t, err, "SetContactSchemes accepted an unsupported scheme")
+	test.Assert(t, pa.ContactSchemeAllowed("https"), "A failed SetContactSchemes shouldn't change the allowed schemes")
+}
@@ -0,0 +1,24 @@
+package purger
+
+import (
+	"github.com/letsencrypt/boulder/akamai"
+)
+
+// AkamaiPurger purges OCSP responses by URL through the Akamai CCU API.
+type AkamaiPurger struct {
+	client *akamai.CachePurgeClient
+}
+
+// NewAkamaiPurger returns an AkamaiPurger using the given client.
+func NewAkamaiPurger(client *akamai.CachePurgeClient) *AkamaiPurger {
+	return &AkamaiPurger{client: client}
+}
+
+// Purge implements CachePurger.
+func (ap *AkamaiPurger) Purge(targets []Target) error {
+	var urls []string
+	for _, t := range targets {
+		urls = append(urls, t.URLs...)
+	}
+	return ap.client.Purge(urls)
+}
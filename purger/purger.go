@@ -0,0 +1,214 @@
+// Package purger removes stale OCSP responses from CDN caches. A CachePurger
+// talks to a single CDN, and a Queue batches purge requests for it and retries
+// failed ones in the background so that the ocsp-updater never blocks on a
+// CDN.
+package purger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/letsencrypt/boulder/core"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/metrics"
+)
+
+// Target identifies the cached OCSP responses for a single certificate.
+type Target struct {
+	// Serial is the certificate serial, as formatted by core.SerialToString.
+	Serial string
+	// URLs are the GET and POST cache keys for the certificate's OCSP
+	// responses, one set for each of its OCSP servers.
+	URLs []string
+}
+
+// CachePurger purges the cached OCSP responses for a batch of certificates
+// from a CDN. Implementations must be safe for concurrent use.
+type CachePurger interface {
+	Purge(targets []Target) error
+}
+
+type pendingTarget struct {
+	Target
+	attempts  int
+	notBefore time.Time
+}
+
+// Queue collects purge Targets for a CachePurger and sends them in batches of
+// up to batchSize, at least every flushInterval. Targets of a failed batch are
+// retried with exponential backoff up to maxAttempts times, after which they
+// are dropped. At most maxPending Targets are held; when the queue is full the
+// oldest are dropped to make room.
+type Queue struct {
+	name          string
+	purger        CachePurger
+	batchSize     int
+	maxPending    int
+	flushInterval time.Duration
+	maxAttempts   int
+	retryBackoff  time.Duration
+	clk           clock.Clock
+	log           blog.Logger
+
+	mu      sync.Mutex
+	pending []pendingTarget
+	wake    chan struct{}
+
+	latency prometheus.Histogram
+	results *prometheus.CounterVec
+	dropped prometheus.Counter
+}
+
+// NewQueue returns a Queue for the given CachePurger. The name identifies the
+// backend in logs and metrics, and must be unique within a process.
+func NewQueue(
+	name string,
+	purger CachePurger,
+	batchSize int,
+	flushInterval time.Duration,
+	maxAttempts int,
+	retryBackoff time.Duration,
+	clk clock.Clock,
+	log blog.Logger,
+	scope metrics.Scope,
+) *Queue {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if flushInterval <= 0 {
+		flushInterval = 10 * time.Second
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	backend := prometheus.Labels{"backend": name}
+	latency := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:        "purge_latency",
+		Help:        "Time taken by purge requests to a CDN",
+		ConstLabels: backend,
+	})
+	results := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "purge_requests",
+			Help:        "Number of purge requests sent to a CDN, by result",
+			ConstLabels: backend,
+		},
+		[]string{"result"})
+	dropped := prometheus.NewCounter(prometheus.CounterOpts{
+		Name:        "purge_targets_dropped",
+		Help:        "Number of certificates whose OCSP responses were never purged from a CDN",
+		ConstLabels: backend,
+	})
+	scope.MustRegister(latency, results, dropped)
+
+	return &Queue{
+		name:          name,
+		purger:        purger,
+		batchSize:     batchSize,
+		maxPending:    batchSize * 100,
+		flushInterval: flushInterval,
+		maxAttempts:   maxAttempts,
+		retryBackoff:  retryBackoff,
+		clk:           clk,
+		log:           log,
+		wake:          make(chan struct{}, 1),
+		latency:       latency,
+		results:       results,
+		dropped:       dropped,
+	}
+}
+
+// Add queues a Target to be purged. It never blocks.
+func (q *Queue) Add(t Target) {
+	q.mu.Lock()
+	if len(q.pending) >= q.maxPending {
+		q.pending = q.pending[1:]
+		q.dropped.Inc()
+	}
+	q.pending = append(q.pending, pendingTarget{Target: t})
+	full := len(q.pending) >= q.batchSize
+	q.mu.Unlock()
+
+	if full {
+		select {
+		case q.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run sends queued purges until stop is closed. It should be run in its own
+// goroutine.
+func (q *Queue) Run(stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-q.wake:
+		case <-q.clk.After(q.flushInterval):
+		}
+		q.flush()
+	}
+}
+
+// next removes and returns up to batchSize Targets that are ready to be sent.
+func (q *Queue) next(now time.Time) []pendingTarget {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var batch []pendingTarget
+	remaining := q.pending[:0]
+	for _, p := range q.pending {
+		if len(batch) < q.batchSize && !now.Before(p.notBefore) {
+			batch = append(batch, p)
+		} else {
+			remaining = append(remaining, p)
+		}
+	}
+	q.pending = remaining
+	return batch
+}
+
+// flush sends every batch that is ready.
+func (q *Queue) flush() {
+	for {
+		now := q.clk.Now()
+		batch := q.next(now)
+		if len(batch) == 0 {
+			return
+		}
+		targets := make([]Target, len(batch))
+		for i, p := range batch {
+			targets[i] = p.Target
+		}
+
+		err := q.purger.Purge(targets)
+		q.latency.Observe(q.clk.Since(now).Seconds())
+		if err == nil {
+			q.results.With(prometheus.Labels{"result": "success"}).Inc()
+			continue
+		}
+		q.results.With(prometheus.Labels{"result": "failure"}).Inc()
+		q.log.AuditErrf("Purging %d OCSP responses from %s failed: %s", len(targets), q.name, err)
+
+		var retries []pendingTarget
+		for _, p := range batch {
+			p.attempts++
+			if p.attempts >= q.maxAttempts {
+				q.dropped.Inc()
+				q.log.AuditErrf("Giving up on purging OCSP response for serial %s from %s", p.Serial, q.name)
+				continue
+			}
+			p.notBefore = now.Add(core.RetryBackoff(p.attempts, q.retryBackoff, time.Hour, 2))
+			retries = append(retries, p)
+		}
+		q.mu.Lock()
+		q.pending = append(retries, q.pending...)
+		q.mu.Unlock()
+		// Don't hammer a failing CDN with the rest of the queue; the next
+		// flush will try again.
+		return
+	}
+}
@@ -0,0 +1,174 @@
+package purger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/test"
+)
+
+type mockPurger struct {
+	sync.Mutex
+	err     error
+	batches [][]Target
+}
+
+func (m *mockPurger) Purge(targets []Target) error {
+	m.Lock()
+	defer m.Unlock()
+	m.batches = append(m.batches, targets)
+	return m.err
+}
+
+func target(i int) Target {
+	return Target{Serial: fmt.Sprintf("%02x", i), URLs: []string{fmt.Sprintf("http://ocsp.invalid/%d", i)}}
+}
+
+func TestQueueBatches(t *testing.T) {
+	fc := clock.NewFake()
+	mp := &mockPurger{}
+	q := NewQueue("mock", mp, 2, time.Minute, 3, time.Minute, fc, blog.NewMock(), metrics.NewNoopScope())
+
+	for i := 0; i < 5; i++ {
+		q.Add(target(i))
+	}
+	q.flush()
+	test.AssertEquals(t, len(mp.batches), 3)
+	test.AssertDeepEquals(t, mp.batches[0], []Target{target(0), target(1)})
+	test.AssertDeepEquals(t, mp.batches[2], []Target{target(4)})
+	test.AssertEquals(t, test.CountCounterVec("result", "success", q.results), 3)
+
+	// Nothing left to send
+	q.flush()
+	test.AssertEquals(t, len(mp.batches), 3)
+}
+
+func TestQueueRetries(t *testing.T) {
+	fc := clock.NewFake()
+	mp := &mockPurger{err: errors.New("CDN on fire")}
+	log := blog.NewMock()
+	q := NewQueue("mock", mp, 10, time.Minute, 2, time.Minute, fc, log, metrics.NewNoopScope())
+
+	q.Add(target(1))
+	q.flush()
+	test.AssertEquals(t, len(mp.batches), 1)
+	test.AssertEquals(t, test.CountCounterVec("result", "failure", q.results), 1)
+
+	// The failed target is held back until its backoff has passed
+	q.Add(target(2))
+	q.flush()
+	test.AssertEquals(t, len(mp.batches), 2)
+	test.AssertDeepEquals(t, mp.batches[1], []Target{target(2)})
+
+	// Once it has, both are retried in one batch and, having reached
+	// maxAttempts, target 1 is dropped
+	fc.Add(2 * time.Minute)
+	q.flush()
+	test.AssertEquals(t, len(mp.batches), 3)
+	test.AssertEquals(t, len(mp.batches[2]), 2)
+	test.AssertEquals(t, test.CountCounter(q.dropped), 2)
+	test.AssertEquals(t, len(log.GetAllMatching("Giving up on purging OCSP response for serial 01")), 1)
+
+	fc.Add(time.Hour)
+	q.flush()
+	test.AssertEquals(t, len(mp.batches), 3)
+}
+
+func TestQueueFull(t *testing.T) {
+	fc := clock.NewFake()
+	mp := &mockPurger{}
+	q := NewQueue("mock", mp, 1, time.Minute, 1, time.Minute, fc, blog.NewMock(), metrics.NewNoopScope())
+	for i := 0; i < q.maxPending+1; i++ {
+		q.Add(target(i))
+	}
+	test.AssertEquals(t, len(q.pending), q.maxPending)
+	test.AssertEquals(t, test.CountCounter(q.dropped), 1)
+	test.AssertEquals(t, q.pending[0].Serial, target(1).Serial)
+}
+
+func TestQueueRun(t *testing.T) {
+	mp := &mockPurger{}
+	q := NewQueue("mock", mp, 1, time.Hour, 1, time.Minute, clock.NewFake(), blog.NewMock(), metrics.NewNoopScope())
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		q.Run(stop)
+		close(done)
+	}()
+
+	// A full batch is sent without waiting for the flush interval
+	q.Add(target(1))
+	for i := 0; i < 1000 && test.CountCounterVec("result", "success", q.results) == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	test.AssertEquals(t, test.CountCounterVec("result", "success", q.results), 1)
+	close(stop)
+	<-done
+}
+
+func TestQueueMetricsPerBackend(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	scope := metrics.NewPromScope(registry)
+	fc := clock.NewFake()
+	// Queues for different backends must be able to share a registry
+	NewQueue("a", &mockPurger{}, 1, time.Minute, 1, time.Minute, fc, blog.NewMock(), scope)
+	NewQueue("b", &mockPurger{}, 1, time.Minute, 1, time.Minute, fc, blog.NewMock(), scope)
+}
+
+func TestWebhookPurger(t *testing.T) {
+	var got webhookRequest
+	var auth string
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &got)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	wp := NewWebhookPurger(srv.URL, map[string]string{"Authorization": "Bearer token"}, time.Second)
+	err := wp.Purge([]Target{target(1), target(2)})
+	test.AssertNotError(t, err, "Purge failed")
+	test.AssertEquals(t, auth, "Bearer token")
+	test.AssertDeepEquals(t, got.Serials, []string{"01", "02"})
+	test.AssertDeepEquals(t, got.URLs, []string{"http://ocsp.invalid/1", "http://ocsp.invalid/2"})
+
+	status = http.StatusServiceUnavailable
+	err = wp.Purge([]Target{target(1)})
+	test.AssertError(t, err, "Purge succeeded despite a 503")
+}
+
+func TestSurrogateKeyPurger(t *testing.T) {
+	var paths, keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		test.AssertEquals(t, r.Header.Get("Fastly-Key"), "api key")
+		paths = append(paths, r.URL.Path)
+		keys = append(keys, r.Header.Get("Surrogate-Key"))
+	}))
+	defer srv.Close()
+
+	sp := NewSurrogateKeyPurger(srv.URL+"/", "svc", "api key", time.Second)
+	var targets []Target
+	for i := 0; i < maxSurrogateKeys+1; i++ {
+		targets = append(targets, target(i))
+	}
+	err := sp.Purge(targets)
+	test.AssertNotError(t, err, "Purge failed")
+	test.AssertDeepEquals(t, paths, []string{"/service/svc/purge", "/service/svc/purge"})
+	test.AssertEquals(t, len(strings.Fields(keys[0])), maxSurrogateKeys)
+	test.AssertEquals(t, keys[1], target(maxSurrogateKeys).Serial)
+}
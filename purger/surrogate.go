@@ -0,0 +1,64 @@
+package purger
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxSurrogateKeys is the number of surrogate keys purged per request.
+const maxSurrogateKeys = 256
+
+// SurrogateKeyPurger purges OCSP responses from a Fastly-style CDN by
+// surrogate key. The ocsp-responder tags each response with a Surrogate-Key
+// header containing the certificate serial, so all cached variants of a
+// response (GET, POST, differently encoded URLs) are purged at once,
+// regardless of the URL they were requested with.
+type SurrogateKeyPurger struct {
+	client    *http.Client
+	endpoint  string
+	serviceID string
+	apiKey    string
+}
+
+// NewSurrogateKeyPurger returns a SurrogateKeyPurger for the given service,
+// using the API at endpoint (e.g. "https://api.fastly.com").
+func NewSurrogateKeyPurger(endpoint, serviceID, apiKey string, timeout time.Duration) *SurrogateKeyPurger {
+	return &SurrogateKeyPurger{
+		client:    &http.Client{Timeout: timeout},
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		serviceID: serviceID,
+		apiKey:    apiKey,
+	}
+}
+
+// Purge implements CachePurger.
+func (sp *SurrogateKeyPurger) Purge(targets []Target) error {
+	for len(targets) > 0 {
+		n := len(targets)
+		if n > maxSurrogateKeys {
+			n = maxSurrogateKeys
+		}
+		keys := make([]string, n)
+		for i, t := range targets[:n] {
+			keys[i] = t.Serial
+		}
+		if err := sp.purge(keys); err != nil {
+			return err
+		}
+		targets = targets[n:]
+	}
+	return nil
+}
+
+func (sp *SurrogateKeyPurger) purge(keys []string) error {
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/service/%s/purge", sp.endpoint, sp.serviceID), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Fastly-Key", sp.apiKey)
+	req.Header.Set("Surrogate-Key", strings.Join(keys, " "))
+	req.Header.Set("Accept", "application/json")
+	return doPurgeRequest(sp.client, req)
+}
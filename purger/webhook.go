@@ -0,0 +1,77 @@
+package purger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// webhookRequest is the JSON body POSTed by a WebhookPurger.
+type webhookRequest struct {
+	Serials []string `json:"serials"`
+	URLs    []string `json:"urls"`
+}
+
+// WebhookPurger purges OCSP responses by POSTing the serials and cache URLs
+// to purge, as JSON, to an arbitrary HTTP endpoint. Any 2xx response is
+// treated as success. It suits CDNs without a dedicated implementation, and
+// in-house caches.
+type WebhookPurger struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+// NewWebhookPurger returns a WebhookPurger that POSTs to url with the given
+// extra request headers, e.g. for authentication.
+func NewWebhookPurger(url string, headers map[string]string, timeout time.Duration) *WebhookPurger {
+	return &WebhookPurger{
+		client:  &http.Client{Timeout: timeout},
+		url:     url,
+		headers: headers,
+	}
+}
+
+// Purge implements CachePurger.
+func (wp *WebhookPurger) Purge(targets []Target) error {
+	var body webhookRequest
+	for _, t := range targets {
+		body.Serials = append(body.Serials, t.Serial)
+		body.URLs = append(body.URLs, t.URLs...)
+	}
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", wp.url, bytes.NewReader(bodyJSON))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range wp.headers {
+		req.Header.Set(k, v)
+	}
+	return doPurgeRequest(wp.client, req)
+}
+
+// doPurgeRequest sends req and returns an error unless the response status is
+// 2xx.
+func doPurgeRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Unexpected HTTP status code '%d': %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -173,3 +173,31 @@ func (sa *mockInvalidAuthorizationsAuthority) SetOrderError(ctx context.Context,
 func (sa *mockInvalidAuthorizationsAuthority) FinalizeOrder(ctx context.Context, in *core.Order, opts ...grpc.CallOption) (*core.Empty, error) {
 	return nil, nil
 }
+
+func (sa *mockInvalidAuthorizationsAuthority) GetSuppressedEmails(ctx context.Context, in *sapb.Emails, opts ...grpc.CallOption) (*sapb.Emails, error) {
+	return nil, nil
+}
+
+func (sa *mockInvalidAuthorizationsAuthority) AddEmailSuppression(ctx context.Context, in *sapb.EmailSuppression, opts ...grpc.CallOption) (*core.Empty, error) {
+	return nil, nil
+}
+
+func (sa *mockInvalidAuthorizationsAuthority) AddCAAIodefReport(ctx context.Context, in *sapb.CAAIodefReport, opts ...grpc.CallOption) (*core.Empty, error) {
+	return nil, nil
+}
+
+func (sa *mockInvalidAuthorizationsAuthority) FinishCAAIodefReport(ctx context.Context, in *sapb.FinishCAAIodefReportRequest, opts ...grpc.CallOption) (*core.Empty, error) {
+	return nil, nil
+}
+
+func (sa *mockInvalidAuthorizationsAuthority) GetPendingCAAIodefReports(ctx context.Context, in *sapb.GetPendingCAAIodefReportsRequest, opts ...grpc.CallOption) (*sapb.CAAIodefReports, error) {
+	return nil, nil
+}
+
+func (sa *mockInvalidAuthorizationsAuthority) CountCAAIodefReportsSent(ctx context.Context, in *sapb.CountCAAIodefReportsSentRequest, opts ...grpc.CallOption) (*sapb.Count, error) {
+	return nil, nil
+}
+
+func (sa *mockInvalidAuthorizationsAuthority) CountCAAIodefDeliveries(ctx context.Context, in *sapb.CountCAAIodefDeliveriesRequest, opts ...grpc.CallOption) (*sapb.Count, error) {
+	return nil, nil
+}
@@ -222,6 +222,7 @@ func (m *AdministrativelyRevokeCertificateRequest) GetAdminName() string {
 type NewOrderRequest struct {
 	RegistrationID   *int64   `protobuf:"varint,1,opt,name=registrationID" json:"registrationID,omitempty"`
 	Names            []string `protobuf:"bytes,2,rep,name=names" json:"names,omitempty"`
+	Profile          *string  `protobuf:"bytes,3,opt,name=profile" json:"profile,omitempty"`
 	XXX_unrecognized []byte   `json:"-"`
 }
 
@@ -244,6 +245,13 @@ func (m *NewOrderRequest) GetNames() []string {
 	return nil
 }
 
+func (m *NewOrderRequest) GetProfile() string {
+	if m != nil && m.Profile != nil {
+		return *m.Profile
+	}
+	return ""
+}
+
 type FinalizeOrderRequest struct {
 	Order            *core.Order `protobuf:"bytes,1,opt,name=order" json:"order,omitempty"`
 	Csr              []byte      `protobuf:"bytes,2,opt,name=csr" json:"csr,omitempty"`
@@ -685,42 +693,43 @@ var _RegistrationAuthority_serviceDesc = grpc.ServiceDesc{
 func init() { proto1.RegisterFile("ra/proto/ra.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 585 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0x6b, 0x6f, 0xd2, 0x50,
-	0x18, 0x06, 0x3a, 0xe6, 0xf6, 0x4e, 0xd9, 0xf6, 0x32, 0x5c, 0x57, 0x6f, 0xac, 0x26, 0x0b, 0x5e,
-	0xc2, 0x92, 0x7d, 0x32, 0x59, 0x8c, 0xce, 0xe1, 0x12, 0xa2, 0x61, 0x49, 0x13, 0x63, 0xb2, 0x2f,
-	0x7a, 0x2c, 0xaf, 0xd0, 0x08, 0x2d, 0x9e, 0x1e, 0x40, 0xf8, 0x13, 0xfe, 0x01, 0x7f, 0xac, 0xe9,
-	0x39, 0x87, 0xf5, 0x42, 0xc9, 0x5c, 0xfc, 0x76, 0xfa, 0x5e, 0x9e, 0xf7, 0xf6, 0x3c, 0x29, 0xec,
-	0x72, 0x76, 0x3c, 0xe2, 0x81, 0x08, 0x8e, 0x39, 0x6b, 0xca, 0x07, 0x96, 0x38, 0xb3, 0x6a, 0x6e,
-	0xc0, 0x49, 0x3b, 0xa2, 0xa7, 0x72, 0xd9, 0x57, 0xb0, 0xdf, 0xa1, 0xe9, 0xd9, 0x58, 0xf4, 0x03,
-	0xee, 0xcd, 0x99, 0xf0, 0x02, 0xdf, 0xa1, 0x9f, 0x63, 0x0a, 0x05, 0x3e, 0x83, 0x32, 0x1b, 0x8b,
-	0xfe, 0xdc, 0x2c, 0xd6, 0x8b, 0x8d, 0xad, 0x93, 0x6a, 0x53, 0xa6, 0xa5, 0x43, 0x55, 0x04, 0xee,
-	0x41, 0x99, 0x53, 0xaf, 0xdd, 0x32, 0x4b, 0xf5, 0x62, 0xc3, 0x70, 0xd4, 0x87, 0xfd, 0x06, 0x6a,
-	0x1d, 0x9a, 0x9e, 0x13, 0x17, 0xde, 0x77, 0xcf, 0x65, 0x82, 0x16, 0xc8, 0x3b, 0x60, 0xb8, 0x21,
-	0x97, 0xb8, 0x77, 0x9d, 0xe8, 0xb9, 0x02, 0x20, 0x80, 0x83, 0x4f, 0xa3, 0xae, 0x4c, 0xec, 0x79,
-	0xa1, 0xe0, 0xa9, 0xf6, 0x8e, 0x60, 0xed, 0x1b, 0x0b, 0x49, 0x77, 0x87, 0xaa, 0xbb, 0x54, 0xa0,
-	0xf4, 0xe3, 0x73, 0x58, 0x1f, 0x4b, 0x10, 0x89, 0x9d, 0x1f, 0xa9, 0x23, 0xec, 0x3f, 0x45, 0xb0,
-	0x54, 0xc5, 0xff, 0xdd, 0xc8, 0x11, 0x54, 0xdc, 0x3e, 0x1b, 0x0c, 0xc8, 0xef, 0x51, 0xdb, 0xef,
-	0xd2, 0x2f, 0x3d, 0x59, 0xc6, 0x8a, 0x2f, 0x60, 0x83, 0x53, 0x38, 0x0a, 0xfc, 0x90, 0x4c, 0x43,
-	0xa2, 0x6e, 0x2b, 0xd4, 0xf3, 0x45, 0x9c, 0x73, 0x1d, 0x60, 0x7f, 0x81, 0x27, 0x0e, 0x4d, 0x82,
-	0x1f, 0x94, 0xd8, 0xe9, 0x67, 0x4f, 0xf4, 0x1d, 0xea, 0x2d, 0x5a, 0x44, 0x58, 0x73, 0x89, 0x0b,
-	0xbd, 0x5b, 0xf9, 0x96, 0xb6, 0xa0, 0x4b, 0xba, 0x03, 0xf9, 0x8e, 0x17, 0x6e, 0x24, 0x17, 0x3e,
-	0x82, 0xc6, 0x59, 0x77, 0xe8, 0xf9, 0x7a, 0x33, 0x13, 0x1a, 0xcc, 0x96, 0x0a, 0xde, 0xb6, 0xd2,
-	0x43, 0xd8, 0x64, 0x11, 0x66, 0x87, 0x0d, 0xd5, 0x88, 0x9b, 0x4e, 0x6c, 0xb0, 0x2f, 0x61, 0xbb,
-	0x43, 0xd3, 0x4b, 0xde, 0x25, 0x1e, 0x1f, 0xb6, 0xc2, 0x13, 0xc7, 0x69, 0xb7, 0x64, 0x09, 0xc3,
-	0xc9, 0x58, 0xa3, 0x11, 0x7c, 0x36, 0xa4, 0xd0, 0x2c, 0xd5, 0x8d, 0xc6, 0xa6, 0xa3, 0x3e, 0xec,
-	0x0f, 0xb0, 0x77, 0xe1, 0xf9, 0x6c, 0xe0, 0xcd, 0x29, 0x85, 0x7a, 0x08, 0xe5, 0x20, 0xfa, 0xd6,
-	0xb7, 0xdb, 0x52, 0x5b, 0x56, 0x21, 0xca, 0xb3, 0xa0, 0x65, 0xe9, 0x9a, 0x96, 0x27, 0xbf, 0xd7,
-	0xa1, 0x96, 0x24, 0x8a, 0x3e, 0xb5, 0x98, 0xe1, 0xa9, 0xec, 0x3b, 0xe9, 0xc3, 0x1c, 0x62, 0x59,
-	0x39, 0x36, 0xbb, 0x80, 0x17, 0xb0, 0x93, 0x15, 0x1d, 0x3e, 0x68, 0x72, 0xd6, 0x5c, 0x21, 0x45,
-	0x2b, 0x8f, 0x69, 0x76, 0x01, 0xdf, 0x42, 0x25, 0x2d, 0x30, 0x3c, 0xd0, 0x28, 0xcb, 0xf7, 0xb2,
-	0x76, 0x35, 0xaf, 0x62, 0x8f, 0x5d, 0xc0, 0x36, 0xe0, 0xb2, 0xc2, 0xf0, 0x51, 0x84, 0xb2, 0x52,
-	0x79, 0x2b, 0x86, 0xfa, 0x08, 0xd5, 0x1c, 0xe9, 0xe0, 0xe3, 0x18, 0xeb, 0x36, 0xa3, 0x75, 0xc0,
-	0x5c, 0x45, 0x75, 0x7c, 0x1a, 0x41, 0xde, 0x20, 0x04, 0x4b, 0x1f, 0xf8, 0xfd, 0x70, 0x24, 0x66,
-	0x76, 0x01, 0x4f, 0xe1, 0x7e, 0x8b, 0x98, 0x2b, 0xbc, 0x49, 0x76, 0xd8, 0xbc, 0xb3, 0x65, 0x92,
-	0x5f, 0xc3, 0x7e, 0x9c, 0x9c, 0x1e, 0x2f, 0xaf, 0xfd, 0x6c, 0xfa, 0x57, 0x38, 0xbc, 0x51, 0x55,
-	0xf8, 0x32, 0x1a, 0xea, 0x5f, 0xc5, 0x97, 0xad, 0xd0, 0x84, 0x8d, 0x85, 0x8a, 0xb0, 0xaa, 0x29,
-	0x90, 0x64, 0xbf, 0x95, 0xa4, 0xbb, 0x5d, 0xc0, 0x57, 0x70, 0x2f, 0x25, 0x12, 0x34, 0xa3, 0xa4,
-	0x3c, 0xdd, 0x64, 0x32, 0xdf, 0xdd, 0xb9, 0x2a, 0xcb, 0x1f, 0xc7, 0xdf, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x13, 0x2f, 0xf4, 0x68, 0x67, 0x06, 0x00, 0x00,
+	// 594 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0xd9, 0x6e, 0xd3, 0x4c,
+	0x14, 0x4e, 0xe2, 0xa6, 0xcb, 0xe9, 0xff, 0x77, 0x39, 0x6d, 0xa9, 0x6b, 0xb6, 0xd6, 0x48, 0x55,
+	0x58, 0x94, 0x4a, 0xbd, 0x42, 0xaa, 0x10, 0x94, 0x96, 0x4a, 0x11, 0x28, 0x48, 0x96, 0x10, 0x52,
+	0x6f, 0x60, 0x70, 0x4e, 0x93, 0x11, 0x89, 0x6d, 0xc6, 0x93, 0x86, 0xe4, 0x25, 0x78, 0x01, 0x1e,
+	0x16, 0x79, 0x3c, 0xae, 0x97, 0xd8, 0x2a, 0x15, 0x77, 0xe3, 0xb3, 0x7c, 0x67, 0xfb, 0x3e, 0x19,
+	0x36, 0x05, 0x3b, 0x0a, 0x84, 0x2f, 0xfd, 0x23, 0xc1, 0xda, 0xea, 0x81, 0x0d, 0xc1, 0xac, 0x1d,
+	0xd7, 0x17, 0xa4, 0x1d, 0xd1, 0x33, 0x76, 0xd9, 0x97, 0xb0, 0xdb, 0xa5, 0xc9, 0xe9, 0x58, 0x0e,
+	0x7c, 0xc1, 0x67, 0x4c, 0x72, 0xdf, 0x73, 0xe8, 0xc7, 0x98, 0x42, 0x89, 0x4f, 0xa1, 0xc9, 0xc6,
+	0x72, 0x30, 0x33, 0xeb, 0xfb, 0xf5, 0xd6, 0xea, 0xf1, 0x56, 0x5b, 0xa5, 0xe5, 0x43, 0xe3, 0x08,
+	0xdc, 0x86, 0xa6, 0xa0, 0x7e, 0xe7, 0xdc, 0x6c, 0xec, 0xd7, 0x5b, 0x86, 0x13, 0x7f, 0xd8, 0xaf,
+	0x61, 0xa7, 0x4b, 0x93, 0x33, 0x12, 0x92, 0x5f, 0x71, 0x97, 0x49, 0x4a, 0x90, 0x37, 0xc0, 0x70,
+	0x43, 0xa1, 0x70, 0xff, 0x73, 0xa2, 0x67, 0x05, 0x80, 0x0f, 0x7b, 0x9f, 0x82, 0x9e, 0x4a, 0xec,
+	0xf3, 0x50, 0x8a, 0x5c, 0x7b, 0x87, 0xb0, 0xf0, 0x8d, 0x85, 0xa4, 0xbb, 0xc3, 0xb8, 0xbb, 0x5c,
+	0xa0, 0xf2, 0xe3, 0x33, 0x58, 0x1c, 0x2b, 0x10, 0xb3, 0x51, 0x19, 0xa9, 0x23, 0xec, 0xdf, 0x75,
+	0xb0, 0xe2, 0x8a, 0xff, 0xba, 0x91, 0x43, 0x58, 0x73, 0x07, 0x6c, 0x38, 0x24, 0xaf, 0x4f, 0x1d,
+	0xaf, 0x47, 0x3f, 0xf5, 0x64, 0x05, 0x2b, 0x3e, 0x87, 0x65, 0x41, 0x61, 0xe0, 0x7b, 0x21, 0x99,
+	0x86, 0x42, 0x5d, 0x8f, 0x51, 0xcf, 0x92, 0x38, 0xe7, 0x26, 0xc0, 0xfe, 0x02, 0x8f, 0x1d, 0xba,
+	0xf6, 0xbf, 0x53, 0x66, 0xa7, 0x9f, 0xb9, 0x1c, 0x38, 0xd4, 0x4f, 0x5a, 0x44, 0x58, 0x70, 0x49,
+	0x48, 0xbd, 0x5b, 0xf5, 0x56, 0x36, 0xbf, 0x47, 0xba, 0x03, 0xf5, 0x4e, 0x17, 0x6e, 0x64, 0x17,
+	0x1e, 0x40, 0xeb, 0xb4, 0x37, 0xe2, 0x9e, 0xde, 0xcc, 0x35, 0x0d, 0xa7, 0x73, 0x05, 0xef, 0x5a,
+	0xe9, 0x01, 0xac, 0xb0, 0x08, 0xb3, 0xcb, 0x46, 0xf1, 0x88, 0x2b, 0x4e, 0x6a, 0xb0, 0x39, 0xac,
+	0x77, 0x69, 0xf2, 0x51, 0xf4, 0x48, 0xa4, 0x87, 0x5d, 0x13, 0x99, 0xe3, 0x74, 0xce, 0x55, 0x09,
+	0xc3, 0x29, 0x58, 0xa3, 0x11, 0x3c, 0x36, 0xa2, 0xd0, 0x6c, 0xec, 0x1b, 0xad, 0x15, 0x27, 0xfe,
+	0x40, 0x13, 0x96, 0x02, 0xe1, 0x5f, 0xf1, 0x61, 0x52, 0x2c, 0xf9, 0xb4, 0xdf, 0xc3, 0xf6, 0x05,
+	0xf7, 0xd8, 0x90, 0xcf, 0x28, 0x57, 0xef, 0x00, 0x9a, 0x7e, 0xf4, 0xad, 0xaf, 0xba, 0x1a, 0xef,
+	0x3f, 0x0e, 0x89, 0x3d, 0x09, 0x61, 0x1b, 0x37, 0x84, 0x3d, 0xfe, 0xb5, 0x08, 0x3b, 0x59, 0x0a,
+	0x69, 0x12, 0xc8, 0x29, 0x9e, 0xa8, 0x89, 0xb2, 0x3e, 0x2c, 0xa1, 0x9c, 0x55, 0x62, 0xb3, 0x6b,
+	0x78, 0x01, 0x1b, 0x45, 0x39, 0xe2, 0xfd, 0xb6, 0x60, 0xed, 0x0a, 0x91, 0x5a, 0x65, 0x1c, 0xb4,
+	0x6b, 0xf8, 0x06, 0xd6, 0xf2, 0xd2, 0xc3, 0x3d, 0x8d, 0x32, 0x7f, 0x49, 0x6b, 0x53, 0x33, 0x2e,
+	0xf5, 0xd8, 0x35, 0xec, 0x00, 0xce, 0x6b, 0x0f, 0x1f, 0x46, 0x28, 0x95, 0x9a, 0xac, 0x18, 0xea,
+	0x03, 0x6c, 0x95, 0x88, 0x0a, 0x1f, 0xa5, 0x58, 0x77, 0x19, 0xad, 0x0b, 0x66, 0x95, 0x08, 0xf0,
+	0x49, 0x04, 0x79, 0x8b, 0x44, 0x2c, 0x7d, 0xe0, 0x77, 0xa3, 0x40, 0x4e, 0xed, 0x1a, 0x9e, 0xc0,
+	0xbd, 0x73, 0x62, 0xae, 0xe4, 0xd7, 0xc5, 0x61, 0xcb, 0xce, 0x56, 0x48, 0x7e, 0x05, 0xbb, 0x69,
+	0x72, 0x7e, 0xbc, 0xb2, 0xf6, 0x8b, 0xe9, 0x5f, 0xe1, 0xe0, 0x56, 0xbd, 0xe1, 0x8b, 0x68, 0xa8,
+	0xbf, 0x95, 0x65, 0xb1, 0x42, 0x1b, 0x96, 0x13, 0x7d, 0xe1, 0x96, 0xa6, 0x40, 0x96, 0xfd, 0x56,
+	0x96, 0xee, 0x76, 0x0d, 0x5f, 0xc2, 0xff, 0x39, 0x91, 0xa0, 0x19, 0x25, 0x95, 0xe9, 0xa6, 0x90,
+	0xf9, 0x76, 0xe9, 0xb2, 0xa9, 0x7e, 0x29, 0x7f, 0x06, 0x00, 0x61, 0xd9, 0xce, 0x66, 0x81, 0x06,
+	0x00, 0x00,
 }
@@ -82,6 +82,8 @@ type RegistrationAuthorityImpl struct {
 	forceCNFromSAN               bool
 	reuseValidAuthz              bool
 	orderLifetime                time.Duration
+	issuanceProfiles             map[string]issuanceProfileAccounts
+	iodefReports                 bool
 
 	regByIPStats           metrics.Scope
 	regByIPRangeStats      metrics.Scope
@@ -157,6 +159,103 @@ func (ra *RegistrationAuthorityImpl) SetRateLimitPoliciesFile(filename string) e
 	return nil
 }
 
+// IssuanceProfile configures which accounts may request a named CA issuance
+// profile in their new orders.
+type IssuanceProfile struct {
+	Name string
+	// AllAccounts allows every account to request the profile
+	AllAccounts bool
+	// Accounts are the IDs of the accounts allowed to request the profile when
+	// AllAccounts is false
+	Accounts []int64
+}
+
+type issuanceProfileAccounts struct {
+	all      bool
+	accounts map[int64]bool
+}
+
+// EnableIodefReports enables iodef reporting: from now on, whenever a CAA
+// check denies issuance and the domain's CAA records have usable iodef targets,
+// a report is queued in the SA for the caa-iodef-reporter.
+func (ra *RegistrationAuthorityImpl) EnableIodefReports() {
+	ra.iodefReports = true
+}
+
+// queueIodefReport queues a report of a CAA check which denied issuance for
+// domain, if iodef reporting is enabled and the check found iodef targets.
+// Failing to queue the report is logged, but doesn't affect the request.
+func (ra *RegistrationAuthorityImpl) queueIodefReport(
+	ctx context.Context,
+	domain string,
+	regID int64,
+	method string,
+	record *core.CAAValidationRecord) {
+	if !ra.iodefReports || record == nil || len(record.Iodef) == 0 {
+		return
+	}
+	records, err := json.Marshal(record.RRSet)
+	if err != nil {
+		ra.log.AuditErrf("Failed to queue CAA iodef report for %s: %s", domain, err)
+		return
+	}
+	err = ra.SA.AddCAAIodefReport(ctx, core.CAAIodefReport{
+		Domain:           domain,
+		AccountID:        regID,
+		ValidationMethod: method,
+		Records:          string(records),
+		Targets:          record.Iodef,
+	})
+	if err != nil {
+		ra.stats.Inc("CAA.IodefQueueErrors", 1)
+		ra.log.AuditErrf("Failed to queue CAA iodef report for %s: %s", domain, err)
+		return
+	}
+	ra.stats.Inc("CAA.IodefQueued", 1)
+}
+
+// SetIssuanceProfiles sets the issuance profiles, other than the CA's default,
+// that may be requested in new orders. Each profile must also be configured in
+// the CA.
+func (ra *RegistrationAuthorityImpl) SetIssuanceProfiles(profiles []IssuanceProfile) error {
+	parsed := make(map[string]issuanceProfileAccounts, len(profiles))
+	for _, p := range profiles {
+		if p.Name == "" {
+			return fmt.Errorf("issuance profile has no name")
+		}
+		if _, present := parsed[p.Name]; present {
+			return fmt.Errorf("issuance profile %q configured more than once", p.Name)
+		}
+		if !p.AllAccounts && len(p.Accounts) == 0 {
+			return fmt.Errorf("issuance profile %q allows no accounts", p.Name)
+		}
+		accounts := make(map[int64]bool, len(p.Accounts))
+		for _, id := range p.Accounts {
+			accounts[id] = true
+		}
+		parsed[p.Name] = issuanceProfileAccounts{all: p.AllAccounts, accounts: accounts}
+	}
+	ra.issuanceProfiles = parsed
+	return nil
+}
+
+// checkIssuanceProfile returns an error if the named issuance profile isn't
+// configured or the account isn't allowed to request it. The empty name is
+// the CA's default profile, which every account may use.
+func (ra *RegistrationAuthorityImpl) checkIssuanceProfile(name string, acctID int64) error {
+	if name == "" {
+		return nil
+	}
+	profile, ok := ra.issuanceProfiles[name]
+	if !ok {
+		return berrors.MalformedError("unknown certificate profile %q", name)
+	}
+	if !profile.all && !profile.accounts[acctID] {
+		return berrors.UnauthorizedError("account is not allowed to request certificate profile %q", name)
+	}
+	return nil
+}
+
 func (ra *RegistrationAuthorityImpl) rateLimitPoliciesLoadError(err error) {
 	ra.log.Errf("error reloading rate limit policy: %s", err)
 }
@@ -253,6 +352,8 @@ type certificateRequestEvent struct {
 	Requester int64 `json:",omitempty"`
 	// OrderID is the associated order ID (may be empty for an ACME v1 issuance)
 	OrderID int64 `json:",omitempty"`
+	// Profile is the issuance profile requested by the order, if any
+	Profile string `json:",omitempty"`
 	// SerialNumber is the string representation of the issued certificate's
 	// serial number
 	SerialNumber string `json:",omitempty"`
@@ -374,6 +475,10 @@ func (ra *RegistrationAuthorityImpl) NewRegistration(ctx context.Context, init c
 		return core.Registration{}, err
 	}
 
+	if err := validateChallengeTypes(reg.ChallengeTypes); err != nil {
+		return core.Registration{}, err
+	}
+
 	// Store the authorization object, then return it
 	reg, err := ra.SA.NewRegistration(ctx, reg)
 	if err != nil {
@@ -384,6 +489,35 @@ func (ra *RegistrationAuthorityImpl) NewRegistration(ctx context.Context, init c
 	return reg, nil
 }
 
+// validateWebhookContact checks that an https: contact is an absolute URL
+// that expiration notifications can be POSTed to. Its host must be a name
+// under a public suffix, so that notifications can't be aimed at internal
+// services; the expiration-mailer also refuses to connect to private addresses
+// that a public name resolves to.
+func validateWebhookContact(raw string, contact *url.URL) error {
+	if !core.IsASCII(raw) {
+		return berrors.MalformedError("contact URL [%s] contains non-ASCII characters", contact)
+	}
+	if contact.Opaque != "" || contact.Hostname() == "" {
+		return berrors.MalformedError("contact URL [%s] has no host", contact)
+	}
+	if contact.User != nil {
+		return berrors.MalformedError("contact URL [%s] must not contain credentials", contact)
+	}
+	if contact.Fragment != "" {
+		return berrors.MalformedError("contact URL [%s] must not contain a fragment", contact)
+	}
+	host := strings.ToLower(contact.Hostname())
+	if net.ParseIP(host) != nil {
+		return berrors.MalformedError("contact URL [%s] must use a hostname, not an IP address", contact)
+	}
+	rule := publicsuffix.DefaultList.Find(host, &publicsuffix.FindOptions{IgnorePrivate: true, DefaultRule: nil})
+	if rule == nil || rule.Decompose(host)[1] == "" {
+		return berrors.MalformedError("contact URL [%s] must use a public hostname", contact)
+	}
+	return nil
+}
+
 func (ra *RegistrationAuthorityImpl) validateContacts(ctx context.Context, contacts *[]string) error {
 	if contacts == nil || len(*contacts) == 0 {
 		return nil // Nothing to validate
@@ -404,9 +538,16 @@ func (ra *RegistrationAuthorityImpl) validateContacts(ctx context.Context, conta
 		if err != nil {
 			return berrors.MalformedError("invalid contact")
 		}
-		if parsed.Scheme != "mailto" {
+		if !ra.PA.ContactSchemeAllowed(parsed.Scheme) {
 			return berrors.MalformedError("contact method %s is not supported", parsed.Scheme)
 		}
+		if parsed.Scheme == "https" {
+			err = validateWebhookContact(contact, parsed)
+			if err != nil {
+				return err
+			}
+			continue
+		}
 		if !core.IsASCII(contact) {
 			return berrors.MalformedError(
 				"contact email [%s] contains non-ASCII characters",
@@ -516,7 +657,7 @@ func (ra *RegistrationAuthorityImpl) NewAuthorization(ctx context.Context, reque
 	identifier.Value = strings.ToLower(identifier.Value)
 
 	// Check that the identifier is present and appropriate
-	if err := ra.PA.WillingToIssue(identifier); err != nil {
+	if err := ra.PA.WillingToIssue(identifier, regID); err != nil {
 		return core.Authorization{}, err
 	}
 
@@ -528,6 +669,13 @@ func (ra *RegistrationAuthorityImpl) NewAuthorization(ctx context.Context, reque
 		return core.Authorization{}, err
 	}
 
+	// Look up the account for its challenge preferences
+	reg, err := ra.SA.GetRegistration(ctx, regID)
+	if err != nil {
+		return core.Authorization{}, berrors.InternalServerError(
+			"unable to get registration for regID: %d: %s", regID, err)
+	}
+
 	if ra.reuseValidAuthz {
 		auths, err := ra.SA.GetValidAuthorizations(ctx, regID, []string{identifier.Value}, ra.clk.Now())
 		if err != nil {
@@ -554,7 +702,7 @@ func (ra *RegistrationAuthorityImpl) NewAuthorization(ctx context.Context, reque
 				ra.log.Warningf("%s: %s", outErr.Error(), existingAuthz.ID)
 				return core.Authorization{}, outErr
 			}
-			if ra.authzValidChallengeEnabled(&populatedAuthz) {
+			if ra.authzValidChallengeEnabled(&populatedAuthz, reg) {
 				// The existing authorization must not expire within the next 24 hours for
 				// it to be OK for reuse
 				reuseCutOff := ra.clk.Now().Add(time.Hour * 24)
@@ -581,12 +729,18 @@ func (ra *RegistrationAuthorityImpl) NewAuthorization(ctx context.Context, reque
 				identifier.Value,
 				err)
 		} else if err == nil {
-			return *pendingAuth, nil
+			// Only reuse the pending authorization if it offers a challenge the
+			// account's challenge preferences allow.
+			for _, chall := range pendingAuth.Challenges {
+				if ra.PA.AccountAllowsChallengeType(chall.Type, reg) {
+					return *pendingAuth, nil
+				}
+			}
 		}
 		// Fall through to normal creation flow.
 	}
 
-	authzPB, err := ra.createPendingAuthz(ctx, regID, identifier)
+	authzPB, err := ra.createPendingAuthz(ctx, reg, identifier)
 	if err != nil {
 		return core.Authorization{}, err
 	}
@@ -816,8 +970,19 @@ func (ra *RegistrationAuthorityImpl) recheckCAA(ctx context.Context, authzs []*c
 					"Internal error rechecking CAA for authorization ID %v (%v)",
 					authz.ID, name,
 				)
-			} else if resp.Problem != nil {
-				err = berrors.CAAError(*resp.Problem.Detail)
+			} else {
+				// Rechecks aren't stored with the authorization, so the
+				// decision record is audit logged instead.
+				if len(resp.CaaRecord) > 0 {
+					ra.log.AuditInfof("Rechecked CAA for authorization ID %v: %s", authz.ID, resp.CaaRecord)
+				}
+				if resp.Problem != nil {
+					err = berrors.CAAError(*resp.Problem.Detail)
+					var record core.CAAValidationRecord
+					if json.Unmarshal(resp.CaaRecord, &record) == nil {
+						ra.queueIodefReport(ctx, name, authz.RegistrationID, method, &record)
+					}
+				}
 			}
 			ch <- err
 		}(authz)
@@ -939,7 +1104,7 @@ func (ra *RegistrationAuthorityImpl) FinalizeOrder(ctx context.Context, req *rap
 		Bytes: req.Csr,
 		CSR:   csrOb,
 	}
-	cert, err := ra.issueCertificate(ctx, issueReq, accountID(*order.RegistrationID), orderID(*order.Id))
+	cert, err := ra.issueCertificate(ctx, issueReq, accountID(*order.RegistrationID), orderID(*order.Id), order.GetProfile())
 	if err != nil {
 		// Fail the order. The problem is computed using
 		// `web.ProblemDetailsForError`, the same function the WFE uses to convert
@@ -986,7 +1151,7 @@ func (ra *RegistrationAuthorityImpl) NewCertificate(ctx context.Context, req cor
 	// NewCertificate provides an order ID of 0, indicating this is a classic ACME
 	// v1 issuance request from the new certificate endpoint that is not
 	// associated with an ACME v2 order.
-	return ra.issueCertificate(ctx, req, accountID(regID), orderID(0))
+	return ra.issueCertificate(ctx, req, accountID(regID), orderID(0), "")
 }
 
 // To help minimize the chance that an accountID would be used as an order ID
@@ -996,21 +1161,24 @@ type accountID int64
 type orderID int64
 
 // issueCertificate sets up a log event structure and captures any errors
-// encountered during issuance, then calls issueCertificateInner.
+// encountered during issuance, then calls issueCertificateInner. The profile
+// is the name of the CA issuance profile to use, or "" for the default.
 func (ra *RegistrationAuthorityImpl) issueCertificate(
 	ctx context.Context,
 	req core.CertificateRequest,
 	acctID accountID,
-	oID orderID) (core.Certificate, error) {
+	oID orderID,
+	profile string) (core.Certificate, error) {
 	// Construct the log event
 	logEvent := certificateRequestEvent{
 		ID:          core.NewToken(),
 		OrderID:     int64(oID),
+		Profile:     profile,
 		Requester:   int64(acctID),
 		RequestTime: ra.clk.Now(),
 	}
 	var result string
-	cert, err := ra.issueCertificateInner(ctx, req, acctID, oID, &logEvent)
+	cert, err := ra.issueCertificateInner(ctx, req, acctID, oID, profile, &logEvent)
 	if err != nil {
 		logEvent.Error = err.Error()
 		result = "error"
@@ -1031,6 +1199,7 @@ func (ra *RegistrationAuthorityImpl) issueCertificateInner(
 	req core.CertificateRequest,
 	acctID accountID,
 	oID orderID,
+	profile string,
 	logEvent *certificateRequestEvent) (core.Certificate, error) {
 	emptyCert := core.Certificate{}
 	if acctID <= 0 {
@@ -1043,6 +1212,12 @@ func (ra *RegistrationAuthorityImpl) issueCertificateInner(
 		return emptyCert, berrors.MalformedError("invalid order ID: %d", oID)
 	}
 
+	// The profile was checked when the order was created, but the account may
+	// no longer be allowed to use it.
+	if err := ra.checkIssuanceProfile(profile, int64(acctID)); err != nil {
+		return emptyCert, err
+	}
+
 	account, err := ra.SA.GetRegistration(ctx, int64(acctID))
 	if err != nil {
 		return emptyCert, err
@@ -1114,6 +1289,9 @@ func (ra *RegistrationAuthorityImpl) issueCertificateInner(
 		RegistrationID: &acctIDInt,
 		OrderID:        &orderIDInt,
 	}
+	if profile != "" {
+		issueReq.Profile = &profile
+	}
 
 	// wrapError adds a prefix to an error. If the error is a boulder error then
 	// the problem detail is updated with the prefix. Otherwise a new error is
@@ -1372,6 +1550,10 @@ func (ra *RegistrationAuthorityImpl) UpdateRegistration(ctx context.Context, bas
 		return core.Registration{}, err
 	}
 
+	if err := validateChallengeTypes(base.ChallengeTypes); err != nil {
+		return core.Registration{}, err
+	}
+
 	err = ra.SA.UpdateRegistration(ctx, base)
 	if err != nil {
 		// berrors.InternalServerError since the user-data was validated before being
@@ -1444,9 +1626,61 @@ func mergeUpdate(r *core.Registration, input core.Registration) bool {
 		}
 	}
 
+	// As with contacts, an empty list of challenge types clears the
+	// registration's challenge preferences, while a nil one leaves them be.
+	if input.ChallengeTypes != nil && !challengeTypesEqual(r.ChallengeTypes, *input.ChallengeTypes) {
+		r.ChallengeTypes = nil
+		if len(*input.ChallengeTypes) > 0 {
+			r.ChallengeTypes = input.ChallengeTypes
+		}
+		changed = true
+	}
+
 	return changed
 }
 
+// challengeTypesEqual returns whether a registration's challenge preferences
+// are the given challenge types, in any order. No preferences are equal to an
+// empty list.
+func challengeTypesEqual(current *[]string, types []string) bool {
+	var existing []string
+	if current != nil {
+		existing = *current
+	}
+	if len(existing) != len(types) {
+		return false
+	}
+	a := append([]string{}, existing...)
+	b := append([]string{}, types...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// validateChallengeTypes checks that a registration's challenge preferences
+// name known challenge types, once each.
+func validateChallengeTypes(types *[]string) error {
+	if types == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(*types))
+	for _, t := range *types {
+		if !core.ValidChallenge(t) {
+			return berrors.MalformedError("unknown challenge type %q in challenge preferences", t)
+		}
+		if seen[t] {
+			return berrors.MalformedError("challenge type %q listed more than once in challenge preferences", t)
+		}
+		seen[t] = true
+	}
+	return nil
+}
+
 // UpdateAuthorization updates an authorization with new values.
 func (ra *RegistrationAuthorityImpl) UpdateAuthorization(
 	ctx context.Context,
@@ -1508,6 +1742,13 @@ func (ra *RegistrationAuthorityImpl) UpdateAuthorization(
 		return core.Authorization{}, berrors.InternalServerError(err.Error())
 	}
 
+	// The account's challenge preferences apply even to revalidations, and to
+	// authorizations created before the preferences were set.
+	if !ra.PA.AccountAllowsChallengeType(ch.Type, reg) {
+		return core.Authorization{}, berrors.UnauthorizedError(
+			"challenge type %q not allowed by account's challenge preferences", ch.Type)
+	}
+
 	// Compute the key authorization field based on the registration key
 	expectedKeyAuthorization, err := ch.ExpectedKeyAuthorization(reg.Key)
 	if err != nil {
@@ -1556,7 +1797,7 @@ func (ra *RegistrationAuthorityImpl) UpdateAuthorization(
 		copy(challenges, authz.Challenges)
 		authz.Challenges = challenges
 
-		records, err := ra.VA.PerformValidation(vaCtx, authz.Identifier.Value, authz.Challenges[challengeIndex], authz)
+		records, caaRecord, reputation, err := ra.VA.PerformValidation(vaCtx, authz.Identifier.Value, authz.Challenges[challengeIndex], authz)
 		var prob *probs.ProblemDetails
 		if p, ok := err.(*probs.ProblemDetails); ok {
 			prob = p
@@ -1568,6 +1809,8 @@ func (ra *RegistrationAuthorityImpl) UpdateAuthorization(
 		// Save the updated records
 		challenge := &authz.Challenges[challengeIndex]
 		challenge.ValidationRecord = records
+		challenge.CAARecord = caaRecord
+		challenge.Reputation = reputation
 
 		if !challenge.RecordsSane() && prob == nil {
 			prob = probs.ServerInternal("Records for validation failed sanity check")
@@ -1576,6 +1819,9 @@ func (ra *RegistrationAuthorityImpl) UpdateAuthorization(
 		if prob != nil {
 			challenge.Status = core.StatusInvalid
 			challenge.Error = prob
+			if prob.Type == probs.CAAProblem {
+				ra.queueIodefReport(vaCtx, authz.Identifier.Value, authz.RegistrationID, challenge.Type, caaRecord)
+			}
 		} else {
 			challenge.Status = core.StatusValid
 		}
@@ -1734,15 +1980,21 @@ func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.New
 		RegistrationID: req.RegistrationID,
 		Names:          core.UniqueLowerNames(req.Names),
 	}
+	if err := ra.checkIssuanceProfile(req.GetProfile(), req.GetRegistrationID()); err != nil {
+		return nil, err
+	}
+	if req.GetProfile() != "" {
+		order.Profile = req.Profile
+	}
 
 	// Validate that our policy allows issuing for each of the names in the order
 	for _, name := range order.Names {
 		id := core.AcmeIdentifier{Value: name, Type: core.IdentifierDNS}
 		if features.Enabled(features.WildcardDomains) {
-			if err := ra.PA.WillingToIssueWildcard(id); err != nil {
+			if err := ra.PA.WillingToIssueWildcard(id, *order.RegistrationID); err != nil {
 				return nil, err
 			}
-		} else if err := ra.PA.WillingToIssue(id); err != nil {
+		} else if err := ra.PA.WillingToIssue(id, *order.RegistrationID); err != nil {
 			return nil, err
 		}
 	}
@@ -1753,11 +2005,12 @@ func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.New
 		}
 	}
 
-	// See if there is an existing, pending, unexpired order that can be reused
-	// for this account
+	// See if there is an existing, pending, unexpired order for the same
+	// profile that can be reused for this account
 	existingOrder, err := ra.SA.GetOrderForNames(ctx, &sapb.GetOrderForNamesRequest{
-		AcctID: order.RegistrationID,
-		Names:  order.Names,
+		AcctID:  order.RegistrationID,
+		Names:   order.Names,
+		Profile: order.Profile,
 	})
 	// If there was an error and it wasn't an acceptable "NotFound" error, return
 	// immediately
@@ -1776,6 +2029,12 @@ func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.New
 		return nil, err
 	}
 
+	// Look up the account for its challenge preferences
+	reg, err := ra.SA.GetRegistration(ctx, *order.RegistrationID)
+	if err != nil {
+		return nil, err
+	}
+
 	// An order's lifetime is effectively bound by the shortest remaining lifetime
 	// of its associated authorizations. For that reason it would be Uncool if
 	// `sa.GetAuthorizations` returned an authorization that was very close to
@@ -1816,6 +2075,13 @@ func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.New
 			continue
 		}
 		authz := nameToExistingAuthz[name]
+		// An authz the account's challenge preferences don't allow can't be
+		// reused.
+		if !ra.authzAllowedForAccount(authz, reg) {
+			delete(nameToExistingAuthz, name)
+			missingAuthzNames = append(missingAuthzNames, name)
+			continue
+		}
 		// If the identifier is a wildcard and the existing authz only has one
 		// DNS-01 type challenge we can reuse it. In theory we will
 		// never get back an authorization for a domain with a wildcard prefix
@@ -1854,7 +2120,7 @@ func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.New
 		if err := ra.checkInvalidAuthorizationLimit(ctx, *order.RegistrationID, name); err != nil {
 			return nil, err
 		}
-		pb, err := ra.createPendingAuthz(ctx, *order.RegistrationID, core.AcmeIdentifier{
+		pb, err := ra.createPendingAuthz(ctx, reg, core.AcmeIdentifier{
 			Type:  core.IdentifierDNS,
 			Value: name,
 		})
@@ -1916,12 +2182,12 @@ func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.New
 // createPendingAuthz checks that a name is allowed for issuance and creates the
 // necessary challenges for it and puts this and all of the relevant information
 // into a corepb.Authorization for transmission to the SA to be stored
-func (ra *RegistrationAuthorityImpl) createPendingAuthz(ctx context.Context, reg int64, identifier core.AcmeIdentifier) (*corepb.Authorization, error) {
+func (ra *RegistrationAuthorityImpl) createPendingAuthz(ctx context.Context, reg core.Registration, identifier core.AcmeIdentifier) (*corepb.Authorization, error) {
 	expires := ra.clk.Now().Add(ra.pendingAuthorizationLifetime).Truncate(time.Second).UnixNano()
 	status := string(core.StatusPending)
 	authz := &corepb.Authorization{
 		Identifier:     &identifier.Value,
-		RegistrationID: &reg,
+		RegistrationID: &reg.ID,
 		Status:         &status,
 		Expires:        &expires,
 	}
@@ -1948,7 +2214,7 @@ func (ra *RegistrationAuthorityImpl) createPendingAuthz(ctx context.Context, reg
 	if features.Enabled(features.TLSSNIRevalidation) {
 		existsResp, err := ra.SA.PreviousCertificateExists(ctx, &sapb.PreviousCertificateExistsRequest{
 			Domain: &identifier.Value,
-			RegID:  &reg,
+			RegID:  &reg.ID,
 		})
 		if err != nil {
 			return nil, err
@@ -1958,8 +2224,12 @@ func (ra *RegistrationAuthorityImpl) createPendingAuthz(ctx context.Context, reg
 
 	// Create challenges. The WFE will update them with URIs before sending them out.
 	challenges, combinations, err := ra.PA.ChallengesFor(identifier, reg, previousCertificateExists)
-	if err != nil {
-		// The only time ChallengesFor errors it is a fatal configuration error
+	if berrors.Is(err, berrors.Unauthorized) {
+		// The account's challenge preferences don't allow any of the challenges
+		// that could be offered for the identifier.
+		return nil, err
+	} else if err != nil {
+		// Otherwise ChallengesFor only errors for a fatal configuration error
 		// where challenges required by policy for an identifier are not enabled. We
 		// want to treat this as an internal server error.
 		return nil, berrors.InternalServerError(err.Error())
@@ -1987,11 +2257,32 @@ func (ra *RegistrationAuthorityImpl) createPendingAuthz(ctx context.Context, reg
 }
 
 // authzValidChallengeEnabled checks whether the valid challenge in an authorization uses a type
-// which is still enabled for given regID
-func (ra *RegistrationAuthorityImpl) authzValidChallengeEnabled(authz *core.Authorization) bool {
+// which is still enabled for given regID, and allowed by the account's challenge preferences
+func (ra *RegistrationAuthorityImpl) authzValidChallengeEnabled(authz *core.Authorization, reg core.Registration) bool {
 	for _, chall := range authz.Challenges {
 		if chall.Status == core.StatusValid {
-			return ra.PA.ChallengeTypeEnabled(chall.Type, authz.RegistrationID)
+			return ra.PA.ChallengeTypeEnabled(chall.Type, authz.RegistrationID) &&
+				ra.PA.AccountAllowsChallengeType(chall.Type, reg)
+		}
+	}
+	return false
+}
+
+// authzAllowedForAccount checks whether an existing authorization can be
+// reused by an order for an account with challenge preferences: a valid
+// authorization must have been validated with a challenge type the account
+// allows, and a pending one must offer at least one such challenge.
+func (ra *RegistrationAuthorityImpl) authzAllowedForAccount(authz *corepb.Authorization, reg core.Registration) bool {
+	valid := authz.GetStatus() == string(core.StatusValid)
+	for _, chall := range authz.Challenges {
+		if valid && chall.GetStatus() != string(core.StatusValid) {
+			continue
+		}
+		if ra.PA.AccountAllowsChallengeType(chall.GetType(), reg) {
+			return true
+		}
+		if valid {
+			return false
 		}
 	}
 	return false
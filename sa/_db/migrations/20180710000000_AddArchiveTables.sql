@@ -0,0 +1,50 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- The archive tables hold rows moved out of the main tables by the
+-- expired-archiver once they are past a configurable age. They are created
+-- LIKE their main tables, and the archiver copies the columns it lists by
+-- name. A column added to a main table later must be added to its archive
+-- table and to the archiver's list to be archived.
+
+-- certificatesArchive and authzArchive are partitioned by month of expiry, so
+-- that old months can be exported and dropped without a large DELETE. Every
+-- unique key of a partitioned table must include the partitioning column.
+-- The archiver adds a partition for each month it archives by splitting pMax.
+CREATE TABLE `certificatesArchive` LIKE `certificates`;
+
+ALTER TABLE `certificatesArchive`
+  DROP PRIMARY KEY,
+  DROP INDEX `serial`,
+  ADD PRIMARY KEY (`id`, `expires`),
+  ADD UNIQUE KEY `serial_expires` (`serial`, `expires`);
+
+ALTER TABLE `certificatesArchive`
+  PARTITION BY RANGE (TO_DAYS(`expires`)) (
+    PARTITION pMax VALUES LESS THAN MAXVALUE);
+
+CREATE TABLE `authzArchive` LIKE `authz`;
+
+ALTER TABLE `authzArchive`
+  MODIFY `expires` datetime NOT NULL,
+  DROP PRIMARY KEY,
+  ADD PRIMARY KEY (`id`, `expires`);
+
+ALTER TABLE `authzArchive`
+  PARTITION BY RANGE (TO_DAYS(`expires`)) (
+    PARTITION pMax VALUES LESS THAN MAXVALUE);
+
+-- The rows of these tables are archived along with their certificate or
+-- authorization.
+CREATE TABLE `certificateStatusArchive` LIKE `certificateStatus`;
+CREATE TABLE `issuedNamesArchive` LIKE `issuedNames`;
+CREATE TABLE `challengesArchive` LIKE `challenges`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `certificatesArchive`;
+DROP TABLE `authzArchive`;
+DROP TABLE `certificateStatusArchive`;
+DROP TABLE `issuedNamesArchive`;
+DROP TABLE `challengesArchive`;
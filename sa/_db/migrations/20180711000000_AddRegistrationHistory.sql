@@ -0,0 +1,25 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- registrationHistory is append-only: the SA adds a row with the new state of
+-- a registration's key, contacts, agreement and status each time one of them
+-- changes, including when the registration is created.
+CREATE TABLE `registrationHistory` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `registrationID` bigint(20) NOT NULL,
+  `changedAt` datetime NOT NULL,
+  `requestIP` binary(16) DEFAULT NULL,
+  `jwk` mediumblob NOT NULL,
+  `jwk_sha256` varchar(255) NOT NULL,
+  `contact` varchar(191) CHARACTER SET utf8mb4 NOT NULL,
+  `agreement` varchar(255) NOT NULL,
+  `status` varchar(255) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `registrationID_changedAt_idx` (`registrationID`, `changedAt`),
+  KEY `jwk_sha256_changedAt_idx` (`jwk_sha256`, `changedAt`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `registrationHistory`;
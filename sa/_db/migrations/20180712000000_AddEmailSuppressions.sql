@@ -0,0 +1,19 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- emailSuppressions holds the addresses Boulder's mailers must not send to,
+-- because their owner unsubscribed or mail to them hard bounced. Addresses
+-- are stored lowercased.
+CREATE TABLE `emailSuppressions` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `email` varchar(255) CHARACTER SET ascii NOT NULL,
+  `reason` varchar(32) NOT NULL,
+  `createdAt` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `email` (`email`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `emailSuppressions`;
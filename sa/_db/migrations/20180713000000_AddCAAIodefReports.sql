@@ -0,0 +1,28 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- caaIodefReports queues reports of CAA checks which denied issuance, for the
+-- caa-iodef-reporter to send to the iodef targets in the domain's CAA records.
+-- Rows are kept after they are processed, as an audit record of what was sent.
+CREATE TABLE `caaIodefReports` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `domain` varchar(255) NOT NULL,
+  `accountID` bigint(20) NOT NULL,
+  `validationMethod` varchar(32) NOT NULL,
+  `records` mediumtext NOT NULL,
+  `targets` text NOT NULL,
+  -- One of "pending", "sent", "failed" or "ratelimited"
+  `status` varchar(16) NOT NULL,
+  `createdAt` datetime NOT NULL,
+  `processedAt` datetime DEFAULT NULL,
+  -- JSON object of the outcome of delivery to each target
+  `results` text DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `status_id` (`status`, `id`),
+  KEY `domain_status_processedAt` (`domain`, `status`, `processedAt`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `caaIodefReports`;
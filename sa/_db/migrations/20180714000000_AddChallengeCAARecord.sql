@@ -0,0 +1,14 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- caaRecord holds the JSON record of the CAA check made when the challenge was
+-- validated. It is added to challengesArchive too, so that the
+-- expired-archiver carries it over.
+ALTER TABLE `challenges` ADD COLUMN `caaRecord` mediumblob DEFAULT NULL;
+ALTER TABLE `challengesArchive` ADD COLUMN `caaRecord` mediumblob DEFAULT NULL;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE `challengesArchive` DROP COLUMN `caaRecord`;
+ALTER TABLE `challenges` DROP COLUMN `caaRecord`;
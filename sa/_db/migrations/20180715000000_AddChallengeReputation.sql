@@ -0,0 +1,14 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- reputation holds the JSON verdicts of the domain reputation checks made when
+-- the challenge was validated. It is added to challengesArchive too, so that
+-- the expired-archiver carries it over.
+ALTER TABLE `challenges` ADD COLUMN `reputation` mediumblob DEFAULT NULL;
+ALTER TABLE `challengesArchive` ADD COLUMN `reputation` mediumblob DEFAULT NULL;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE `challengesArchive` DROP COLUMN `reputation`;
+ALTER TABLE `challenges` DROP COLUMN `reputation`;
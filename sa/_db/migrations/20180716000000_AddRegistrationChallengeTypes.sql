@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- challengeTypes holds the JSON list of challenge types an account allows for
+-- its authorizations, or "null" if it has no challenge preferences.
+ALTER TABLE `registrations` ADD COLUMN `challengeTypes` varchar(255) NOT NULL DEFAULT 'null';
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE `registrations` DROP COLUMN `challengeTypes`;
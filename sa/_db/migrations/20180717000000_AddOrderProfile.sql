@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- profile is the name of the CA issuance profile an order's certificate is
+-- issued with, or empty for the default profile.
+ALTER TABLE `orders` ADD COLUMN `profile` varchar(255) NOT NULL DEFAULT '';
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE `orders` DROP COLUMN `profile`;
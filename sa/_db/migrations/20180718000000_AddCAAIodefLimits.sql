@@ -0,0 +1,37 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- registeredDomain is the registered domain (the public suffix plus one label)
+-- of a processed report's domain. The caa-iodef-reporter rate limits reports by
+-- registered domain, so that subdomains can't be used to get around the limit.
+-- The accountID index lets the SA cap the reports each account can queue.
+ALTER TABLE `caaIodefReports`
+  ADD COLUMN `registeredDomain` varchar(255) DEFAULT NULL,
+  ADD KEY `registeredDomain_status_processedAt` (`registeredDomain`, `status`, `processedAt`),
+  ADD KEY `accountID_createdAt` (`accountID`, `createdAt`),
+  DROP KEY `domain_status_processedAt`;
+
+-- caaIodefDeliveries records each successful delivery of a report to a target,
+-- so that the caa-iodef-reporter can rate limit deliveries to each target,
+-- whichever domains list it. Targets are looked up by their SHA-256 hash, since
+-- they can be longer than an index allows.
+CREATE TABLE `caaIodefDeliveries` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `reportID` bigint(20) NOT NULL,
+  `target` text NOT NULL,
+  `targetHash` binary(32) NOT NULL,
+  `sentAt` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `targetHash_sentAt` (`targetHash`, `sentAt`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `caaIodefDeliveries`;
+
+ALTER TABLE `caaIodefReports`
+  ADD KEY `domain_status_processedAt` (`domain`, `status`, `processedAt`),
+  DROP KEY `accountID_createdAt`,
+  DROP KEY `registeredDomain_status_processedAt`,
+  DROP COLUMN `registeredDomain`;
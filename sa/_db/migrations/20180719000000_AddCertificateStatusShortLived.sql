@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- shortLived marks certificates issued without an OCSP URL, by an issuance
+-- profile relying on a short validity period instead of revocation. The
+-- ocsp-updater doesn't sign responses for them and the expiration-mailer
+-- doesn't send expiration mail for them.
+ALTER TABLE `certificateStatus` ADD COLUMN `shortLived` tinyint(1) NOT NULL DEFAULT 0;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE `certificateStatus` DROP COLUMN `shortLived`;
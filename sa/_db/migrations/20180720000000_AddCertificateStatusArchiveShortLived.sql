@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- certificateStatusArchive gets the shortLived column added to
+-- certificateStatus, so that the expired-archiver carries it over.
+ALTER TABLE `certificateStatusArchive` ADD COLUMN `shortLived` tinyint(1) NOT NULL DEFAULT 0;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE `certificateStatusArchive` DROP COLUMN `shortLived`;
@@ -0,0 +1,354 @@
+package sa
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	berrors "github.com/letsencrypt/boulder/errors"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+const (
+	// defaultSearchLimit is the page size used by the admin search methods
+	// when the request doesn't set one.
+	defaultSearchLimit = 100
+	// maxSearchLimit is the largest page size the admin search methods return.
+	maxSearchLimit = 1000
+)
+
+func searchLimit(limit *int64) int64 {
+	if limit == nil || *limit <= 0 {
+		return defaultSearchLimit
+	}
+	if *limit > maxSearchLimit {
+		return maxSearchLimit
+	}
+	return *limit
+}
+
+// qualifiedFields prefixes each of a comma separated list of fields with a
+// table alias.
+func qualifiedFields(alias, fields string) string {
+	split := strings.Split(fields, ", ")
+	for i, f := range split {
+		split[i] = alias + "." + f
+	}
+	return strings.Join(split, ", ")
+}
+
+// escapeLike escapes the LIKE wildcards in s, so that it only matches itself.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// SearchCertificates returns a page of the certificates matching all of the
+// request's criteria, ordered by serial. A name matches the certificates
+// containing it, and with includeSubdomains also those containing any of its
+// subdomains, using the reversed-name index of the issuedNames table.
+func (ssa *SQLStorageAuthority) SearchCertificates(ctx context.Context, req *sapb.SearchCertificatesRequest) ([]core.Certificate, error) {
+	if req == nil || (req.Name == nil && req.RegistrationID == nil && req.Issued == nil && req.Expires == nil) {
+		return nil, berrors.MalformedError("certificate search requires a name, registration ID, issued range or expires range")
+	}
+
+	var conditions []string
+	joins := ""
+	params := map[string]interface{}{
+		"afterSerial": "",
+		"limit":       searchLimit(req.Limit),
+	}
+	if req.Name != nil {
+		nameCondition := "reversedName = :reversedName"
+		params["reversedName"] = ReverseName(*req.Name)
+		if req.IncludeSubdomains != nil && *req.IncludeSubdomains {
+			nameCondition = "(reversedName = :reversedName OR reversedName LIKE :reversedSubdomains)"
+			params["reversedSubdomains"] = escapeLike(ReverseName(*req.Name)) + ".%"
+		}
+		conditions = append(conditions,
+			"c.serial IN (SELECT serial FROM issuedNames WHERE "+nameCondition+")")
+	}
+	if req.RegistrationID != nil {
+		conditions = append(conditions, "c.registrationID = :regID")
+		params["regID"] = *req.RegistrationID
+	}
+	if req.Issued != nil {
+		if req.Issued.Earliest != nil {
+			conditions = append(conditions, "c.issued >= :earliest")
+			params["earliest"] = time.Unix(0, *req.Issued.Earliest)
+		}
+		if req.Issued.Latest != nil {
+			conditions = append(conditions, "c.issued < :latest")
+			params["latest"] = time.Unix(0, *req.Issued.Latest)
+		}
+	}
+	if req.Expires != nil {
+		if req.Expires.Earliest != nil {
+			conditions = append(conditions, "c.expires >= :expiresEarliest")
+			params["expiresEarliest"] = time.Unix(0, *req.Expires.Earliest)
+		}
+		if req.Expires.Latest != nil {
+			conditions = append(conditions, "c.expires < :expiresLatest")
+			params["expiresLatest"] = time.Unix(0, *req.Expires.Latest)
+		}
+	}
+	if req.Status != nil {
+		joins = "JOIN certificateStatus AS cs ON cs.serial = c.serial"
+		conditions = append(conditions, "cs.status = :status")
+		params["status"] = *req.Status
+	}
+	if req.AfterSerial != nil {
+		params["afterSerial"] = *req.AfterSerial
+	}
+	conditions = append(conditions, "c.serial > :afterSerial")
+
+	var certs []core.Certificate
+	_, err := ssa.readOnlyDb().Select(
+		&certs,
+		fmt.Sprintf(`SELECT %s FROM certificates AS c %s
+		WHERE %s
+		ORDER BY c.serial
+		LIMIT :limit`,
+			qualifiedFields("c", certFields), joins, strings.Join(conditions, " AND ")),
+		params)
+	if err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+// SearchRegistrations returns a page of the registrations with the given
+// contact, ordered by ID.
+func (ssa *SQLStorageAuthority) SearchRegistrations(ctx context.Context, req *sapb.SearchRegistrationsRequest) ([]core.Registration, error) {
+	if req == nil || req.Contact == nil || *req.Contact == "" {
+		return nil, berrors.MalformedError("registration search requires a contact")
+	}
+	// Contacts are stored as a JSON array, so search for the contact as a
+	// JSON string, encoded the same way, and check for an exact match below.
+	contactJSON, err := json.Marshal(*req.Contact)
+	if err != nil {
+		return nil, err
+	}
+	var afterID int64
+	if req.AfterID != nil {
+		afterID = *req.AfterID
+	}
+	limit := searchLimit(req.Limit)
+
+	// The LIKE can match registrations without an exact match, which are
+	// dropped, so keep reading until the page is full or there are no more
+	// candidates.
+	var regs []core.Registration
+	for int64(len(regs)) < limit {
+		var models []*regModel
+		_, err = ssa.readOnlyDb().Select(
+			&models,
+			`SELECT `+regFields+` FROM registrations
+			WHERE contact LIKE :contact AND
+			id > :afterID
+			ORDER BY id
+			LIMIT :limit`,
+			map[string]interface{}{
+				"contact": "%" + escapeLike(string(contactJSON)) + "%",
+				"afterID": afterID,
+				"limit":   limit,
+			})
+		if err != nil {
+			return nil, err
+		}
+		for _, model := range models {
+			afterID = model.ID
+			if !hasContact(model.Contact, *req.Contact) {
+				continue
+			}
+			reg, err := modelToRegistration(model)
+			if err != nil {
+				return nil, err
+			}
+			regs = append(regs, reg)
+			if int64(len(regs)) == limit {
+				break
+			}
+		}
+		if int64(len(models)) < limit {
+			break
+		}
+	}
+	return regs, nil
+}
+
+func hasContact(contacts []string, contact string) bool {
+	for _, c := range contacts {
+		if c == contact {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRegistrationHistory returns the changes recorded for a registration,
+// from oldest to newest. Registrations last changed before the history was
+// added have no changes recorded.
+func (ssa *SQLStorageAuthority) GetRegistrationHistory(ctx context.Context, regID int64) ([]core.RegistrationChange, error) {
+	var models []*regHistoryModel
+	_, err := ssa.readOnlyDb().Select(
+		&models,
+		`SELECT id, registrationID, changedAt, requestIP, jwk, jwk_sha256, contact, agreement, status
+		FROM registrationHistory
+		WHERE registrationID = ?
+		ORDER BY changedAt, id`,
+		regID)
+	if err != nil {
+		return nil, err
+	}
+	var changes []core.RegistrationChange
+	for _, model := range models {
+		change, err := modelToRegistrationChange(model)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// SearchOrders returns a page of a registration's orders, ordered by ID.
+func (ssa *SQLStorageAuthority) SearchOrders(ctx context.Context, req *sapb.SearchOrdersRequest) ([]*corepb.Order, error) {
+	if req == nil || req.RegistrationID == nil {
+		return nil, berrors.MalformedError("order search requires a registration ID")
+	}
+	var afterID int64
+	if req.AfterID != nil {
+		afterID = *req.AfterID
+	}
+
+	var ids []int64
+	_, err := ssa.readOnlyDb().Select(
+		&ids,
+		`SELECT id FROM orders
+		WHERE registrationID = :regID AND
+		id > :afterID
+		ORDER BY id
+		LIMIT :limit`,
+		map[string]interface{}{
+			"regID":   *req.RegistrationID,
+			"afterID": afterID,
+			"limit":   searchLimit(req.Limit),
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []*corepb.Order
+	for _, id := range ids {
+		id := id
+		order, err := ssa.GetOrder(ctx, &sapb.OrderRequest{Id: &id})
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// SearchAuthorizations returns a page of a registration's pending and final
+// authorizations, with their challenges, ordered by ID.
+func (ssa *SQLStorageAuthority) SearchAuthorizations(ctx context.Context, req *sapb.SearchAuthorizationsRequest) ([]core.Authorization, error) {
+	if req == nil || req.RegistrationID == nil {
+		return nil, berrors.MalformedError("authorization search requires a registration ID")
+	}
+	limit := searchLimit(req.Limit)
+	params := map[string]interface{}{
+		"regID":   *req.RegistrationID,
+		"afterID": "",
+		"limit":   limit,
+	}
+	if req.AfterID != nil {
+		params["afterID"] = *req.AfterID
+	}
+
+	// Take a page from each table, then the first page of their union.
+	var ids []string
+	for _, table := range authorizationTables {
+		var tableIDs []string
+		_, err := ssa.readOnlyDb().Select(
+			&tableIDs,
+			fmt.Sprintf(`SELECT id FROM %s
+			WHERE registrationID = :regID AND
+			id > :afterID
+			ORDER BY id
+			LIMIT :limit`, table),
+			params)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, tableIDs...)
+	}
+	sort.Strings(ids)
+	if int64(len(ids)) > limit {
+		ids = ids[:limit]
+	}
+
+	var authzs []core.Authorization
+	for _, id := range ids {
+		authz, err := ssa.GetAuthorization(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		authzs = append(authzs, authz)
+	}
+	return authzs, nil
+}
+
+// RegistrationsWithCertificates returns the ID and contacts of each
+// registration with a certificate expiring at or after the request's
+// expiresAfter, ordered by ID. If the request has names, only certificates
+// containing one of them are considered.
+func (ssa *SQLStorageAuthority) RegistrationsWithCertificates(ctx context.Context, req *sapb.RegistrationsWithCertificatesRequest) ([]*sapb.RegistrationContacts, error) {
+	if req == nil || req.ExpiresAfter == nil {
+		return nil, berrors.MalformedError("registration search requires an expiry cutoff")
+	}
+
+	nameCondition := ""
+	params := map[string]interface{}{
+		"expiresAfter": time.Unix(0, *req.ExpiresAfter),
+	}
+	if len(req.Names) > 0 {
+		placeholders := make([]string, len(req.Names))
+		for i, name := range req.Names {
+			placeholder := fmt.Sprintf("name%d", i)
+			placeholders[i] = ":" + placeholder
+			params[placeholder] = ReverseName(name)
+		}
+		nameCondition = fmt.Sprintf(
+			"AND serial IN (SELECT serial FROM issuedNames WHERE reversedName IN (%s))",
+			strings.Join(placeholders, ", "))
+	}
+
+	var models []struct {
+		ID      int64    `db:"id"`
+		Contact []string `db:"contact"`
+	}
+	_, err := ssa.readOnlyDb().Select(
+		&models,
+		fmt.Sprintf(`SELECT id, contact FROM registrations
+		WHERE id IN (SELECT registrationID FROM certificates
+			WHERE expires >= :expiresAfter %s)
+		ORDER BY id`, nameCondition),
+		params)
+	if err != nil {
+		return nil, err
+	}
+
+	regs := make([]*sapb.RegistrationContacts, len(models))
+	for i, model := range models {
+		id := model.ID
+		regs[i] = &sapb.RegistrationContacts{Id: &id, Contact: model.Contact}
+	}
+	return regs, nil
+}
@@ -0,0 +1,330 @@
+package sa
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	jose "gopkg.in/square/go-jose.v2"
+
+	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/revocation"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/sa/satest"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func serials(certs []core.Certificate) []string {
+	var s []string
+	for _, c := range certs {
+		s = append(s, c.Serial)
+	}
+	return s
+}
+
+func TestSearchCertificates(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	// Names www.eff.org, eff.org and *.eff.org
+	effDER, err := ioutil.ReadFile("www.eff.org.der")
+	test.AssertNotError(t, err, "Couldn't read example cert DER")
+	effSerial := "000000000000000000000000000000021bd4"
+	// Names example.com, www.example.com and admin.example.com
+	exampleDER, err := ioutil.ReadFile("test-cert.der")
+	test.AssertNotError(t, err, "Couldn't read example cert DER")
+	exampleSerial := "ffdd9b8a82126d96f61d378d5ba99a0474f0"
+
+	issued := fc.Now()
+	_, err = sa.AddCertificate(ctx, effDER, reg.ID, nil, &issued)
+	test.AssertNotError(t, err, "Couldn't add www.eff.org.der")
+	issuedLater := issued.Add(time.Hour)
+	_, err = sa.AddCertificate(ctx, exampleDER, reg.ID, nil, &issuedLater)
+	test.AssertNotError(t, err, "Couldn't add test-cert.der")
+
+	search := func(req *sapb.SearchCertificatesRequest) []string {
+		t.Helper()
+		certs, err := sa.SearchCertificates(ctx, req)
+		test.AssertNotError(t, err, "SearchCertificates failed")
+		return serials(certs)
+	}
+	name := "example.com"
+	parent := "eff.org"
+	other := "example.net"
+	yes := true
+	one := int64(1)
+	otherRegID := reg.ID + 1
+	latest := issuedLater.UnixNano()
+	past := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	future := time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	revoked := string(core.OCSPStatusRevoked)
+
+	test.AssertDeepEquals(t, search(&sapb.SearchCertificatesRequest{Name: &name}), []string{exampleSerial})
+	test.AssertEquals(t, len(search(&sapb.SearchCertificatesRequest{Name: &other})), 0)
+	// www.eff.org is only found by eff.org when subdomains are included
+	test.AssertDeepEquals(t, search(&sapb.SearchCertificatesRequest{Name: &parent, IncludeSubdomains: &yes}), []string{effSerial})
+
+	test.AssertDeepEquals(t, search(&sapb.SearchCertificatesRequest{RegistrationID: &reg.ID}), []string{effSerial, exampleSerial})
+	test.AssertEquals(t, len(search(&sapb.SearchCertificatesRequest{RegistrationID: &otherRegID})), 0)
+	test.AssertDeepEquals(t, search(&sapb.SearchCertificatesRequest{
+		RegistrationID: &reg.ID,
+		Issued:         &sapb.Range{Latest: &latest},
+	}), []string{effSerial})
+	test.AssertDeepEquals(t, search(&sapb.SearchCertificatesRequest{
+		Expires: &sapb.Range{Earliest: &past},
+	}), []string{effSerial, exampleSerial})
+	test.AssertEquals(t, len(search(&sapb.SearchCertificatesRequest{
+		Expires: &sapb.Range{Earliest: &future},
+	})), 0)
+
+	// Pages are ordered by serial
+	test.AssertDeepEquals(t, search(&sapb.SearchCertificatesRequest{RegistrationID: &reg.ID, Limit: &one}), []string{effSerial})
+	test.AssertDeepEquals(t, search(&sapb.SearchCertificatesRequest{RegistrationID: &reg.ID, Limit: &one, AfterSerial: &effSerial}), []string{exampleSerial})
+
+	test.AssertEquals(t, len(search(&sapb.SearchCertificatesRequest{RegistrationID: &reg.ID, Status: &revoked})), 0)
+	err = sa.MarkCertificateRevoked(ctx, effSerial, revocation.KeyCompromise)
+	test.AssertNotError(t, err, "MarkCertificateRevoked failed")
+	test.AssertDeepEquals(t, search(&sapb.SearchCertificatesRequest{RegistrationID: &reg.ID, Status: &revoked}), []string{effSerial})
+
+	// Unbounded searches are refused
+	_, err = sa.SearchCertificates(ctx, &sapb.SearchCertificatesRequest{Status: &revoked})
+	test.Assert(t, berrors.Is(err, berrors.Malformed), "Expected a malformed error for an unbounded search")
+}
+
+func TestSearchRegistrations(t *testing.T) {
+	sa, _, cleanUp := initSA(t)
+	defer cleanUp()
+
+	// Registered with mailto:foo@example.com
+	regA := satest.CreateWorkingRegistration(t, sa)
+	var jwk jose.JSONWebKey
+	err := json.Unmarshal([]byte(anotherKey), &jwk)
+	test.AssertNotError(t, err, "Couldn't unmarshal anotherKey")
+	regB, err := sa.NewRegistration(ctx, core.Registration{
+		Key:       &jwk,
+		Contact:   &[]string{"mailto:bar@example.com", "mailto:foo@example.com"},
+		InitialIP: net.ParseIP("88.77.66.11"),
+		CreatedAt: time.Date(2003, 5, 10, 0, 0, 0, 0, time.UTC),
+		Status:    core.StatusValid,
+	})
+	test.AssertNotError(t, err, "Couldn't create registration")
+
+	ids := func(contact string, afterID int64) []int64 {
+		t.Helper()
+		one := int64(1)
+		regs, err := sa.SearchRegistrations(ctx, &sapb.SearchRegistrationsRequest{Contact: &contact, AfterID: &afterID, Limit: &one})
+		test.AssertNotError(t, err, "SearchRegistrations failed")
+		var ids []int64
+		for _, reg := range regs {
+			ids = append(ids, reg.ID)
+		}
+		return ids
+	}
+	test.AssertDeepEquals(t, ids("mailto:foo@example.com", 0), []int64{regA.ID})
+	test.AssertDeepEquals(t, ids("mailto:foo@example.com", regA.ID), []int64{regB.ID})
+	test.AssertEquals(t, len(ids("mailto:foo@example.com", regB.ID)), 0)
+	test.AssertDeepEquals(t, ids("mailto:bar@example.com", 0), []int64{regB.ID})
+	// Contacts only match exactly
+	test.AssertEquals(t, len(ids("mailto:%@example.com", 0)), 0)
+	test.AssertEquals(t, len(ids("foo@example.com", 0)), 0)
+
+	// Registrations which only match case-insensitively are skipped without
+	// cutting the page short
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "Couldn't generate key")
+	regC, err := sa.NewRegistration(ctx, core.Registration{
+		Key:       &jose.JSONWebKey{Key: key.Public()},
+		Contact:   &[]string{"mailto:FOO@example.com"},
+		InitialIP: net.ParseIP("88.77.66.11"),
+		CreatedAt: time.Date(2003, 5, 10, 0, 0, 0, 0, time.UTC),
+		Status:    core.StatusValid,
+	})
+	test.AssertNotError(t, err, "Couldn't create registration")
+	test.AssertDeepEquals(t, ids("mailto:FOO@example.com", 0), []int64{regC.ID})
+}
+
+func TestGetRegistrationHistory(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	// Created from its initial IP
+	reg := satest.CreateWorkingRegistration(t, sa)
+
+	// A key rollover from another IP
+	var jwk jose.JSONWebKey
+	err := json.Unmarshal([]byte(anotherKey), &jwk)
+	test.AssertNotError(t, err, "Couldn't unmarshal anotherKey")
+	fc.Add(time.Hour)
+	reg.Key = &jwk
+	err = sa.UpdateRegistration(core.WithRequestIP(ctx, net.ParseIP("10.0.0.1")), reg)
+	test.AssertNotError(t, err, "UpdateRegistration failed")
+
+	// Updates which don't change a recorded field aren't recorded
+	fc.Add(time.Hour)
+	err = sa.UpdateRegistration(ctx, reg)
+	test.AssertNotError(t, err, "UpdateRegistration failed")
+
+	fc.Add(time.Hour)
+	err = sa.DeactivateRegistration(ctx, reg.ID)
+	test.AssertNotError(t, err, "DeactivateRegistration failed")
+	// Deactivating again changes nothing
+	err = sa.DeactivateRegistration(ctx, reg.ID)
+	test.AssertNotError(t, err, "DeactivateRegistration failed")
+
+	changes, err := sa.GetRegistrationHistory(ctx, reg.ID)
+	test.AssertNotError(t, err, "GetRegistrationHistory failed")
+	test.AssertEquals(t, len(changes), 3)
+
+	test.AssertEquals(t, changes[0].RequestIP.String(), "88.77.66.11")
+	test.AssertEquals(t, changes[0].Registration.Status, core.StatusValid)
+	test.AssertDeepEquals(t, *changes[0].Registration.Contact, []string{"mailto:foo@example.com"})
+	test.AssertDeepEquals(t, changes[0].Registration.Key, satest.GoodJWK())
+
+	test.AssertEquals(t, changes[1].RequestIP.String(), "10.0.0.1")
+	test.AssertEquals(t, changes[1].ChangedAt, changes[0].ChangedAt.Add(time.Hour))
+	test.AssertDeepEquals(t, changes[1].Registration.Key, &jwk)
+
+	test.Assert(t, changes[2].RequestIP == nil, "Expected no request IP")
+	test.AssertEquals(t, changes[2].Registration.Status, core.StatusDeactivated)
+
+	changes, err = sa.GetRegistrationHistory(ctx, reg.ID+1)
+	test.AssertNotError(t, err, "GetRegistrationHistory failed")
+	test.AssertEquals(t, len(changes), 0)
+}
+
+func TestSearchOrdersAndAuthorizations(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	expires := fc.Now().Add(time.Hour)
+	var authzIDs []string
+	for _, name := range []string{"example.com", "example.net"} {
+		authz, err := sa.NewPendingAuthorization(ctx, core.Authorization{
+			RegistrationID: reg.ID,
+			Identifier:     core.AcmeIdentifier{Type: core.IdentifierDNS, Value: name},
+			Expires:        &expires,
+			Status:         core.StatusPending,
+			Challenges:     []core.Challenge{{Type: core.ChallengeTypeHTTP01, Token: name, Status: core.StatusPending}},
+		})
+		test.AssertNotError(t, err, "NewPendingAuthorization failed")
+		authzIDs = append(authzIDs, authz.ID)
+	}
+	// One pending and one final authorization
+	final, err := sa.GetAuthorization(ctx, authzIDs[1])
+	test.AssertNotError(t, err, "GetAuthorization failed")
+	final.Status = core.StatusValid
+	err = sa.FinalizeAuthorization(ctx, final)
+	test.AssertNotError(t, err, "FinalizeAuthorization failed")
+	sort.Strings(authzIDs)
+
+	one := int64(1)
+	var found []string
+	req := &sapb.SearchAuthorizationsRequest{RegistrationID: &reg.ID, Limit: &one}
+	for {
+		authzs, err := sa.SearchAuthorizations(ctx, req)
+		test.AssertNotError(t, err, "SearchAuthorizations failed")
+		if len(authzs) == 0 {
+			break
+		}
+		test.AssertEquals(t, len(authzs), 1)
+		test.AssertEquals(t, len(authzs[0].Challenges), 1)
+		found = append(found, authzs[0].ID)
+		req.AfterID = &authzs[0].ID
+	}
+	test.AssertDeepEquals(t, found, authzIDs)
+
+	expiresNano := expires.UnixNano()
+	order, err := sa.NewOrder(ctx, &corepb.Order{
+		RegistrationID: &reg.ID,
+		Expires:        &expiresNano,
+		Names:          []string{"example.com", "example.net"},
+		Authorizations: authzIDs,
+	})
+	test.AssertNotError(t, err, "NewOrder failed")
+
+	orders, err := sa.SearchOrders(ctx, &sapb.SearchOrdersRequest{RegistrationID: &reg.ID})
+	test.AssertNotError(t, err, "SearchOrders failed")
+	test.AssertEquals(t, len(orders), 1)
+	test.AssertEquals(t, *orders[0].Id, *order.Id)
+	test.AssertDeepEquals(t, orders[0].Names, []string{"example.com", "example.net"})
+	orders, err = sa.SearchOrders(ctx, &sapb.SearchOrdersRequest{RegistrationID: &reg.ID, AfterID: order.Id})
+	test.AssertNotError(t, err, "SearchOrders failed")
+	test.AssertEquals(t, len(orders), 0)
+
+	_, err = sa.SearchOrders(ctx, &sapb.SearchOrdersRequest{})
+	test.Assert(t, berrors.Is(err, berrors.Malformed), "Expected a malformed error without a registration ID")
+}
+
+func TestRegistrationsWithCertificates(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	// Registered with mailto:foo@example.com
+	regA := satest.CreateWorkingRegistration(t, sa)
+	var jwk jose.JSONWebKey
+	err := json.Unmarshal([]byte(anotherKey), &jwk)
+	test.AssertNotError(t, err, "Couldn't unmarshal anotherKey")
+	regB, err := sa.NewRegistration(ctx, core.Registration{
+		Key:       &jwk,
+		InitialIP: net.ParseIP("88.77.66.11"),
+		CreatedAt: time.Date(2003, 5, 10, 0, 0, 0, 0, time.UTC),
+		Status:    core.StatusValid,
+	})
+	test.AssertNotError(t, err, "Couldn't create registration")
+
+	// Names www.eff.org, eff.org and *.eff.org, expires 2016-04-14
+	effDER, err := ioutil.ReadFile("www.eff.org.der")
+	test.AssertNotError(t, err, "Couldn't read example cert DER")
+	issued := fc.Now()
+	_, err = sa.AddCertificate(ctx, effDER, regA.ID, nil, &issued)
+	test.AssertNotError(t, err, "Couldn't add www.eff.org.der")
+	// Names example.com, www.example.com and admin.example.com, expires
+	// 2015-12-27
+	exampleDER, err := ioutil.ReadFile("test-cert.der")
+	test.AssertNotError(t, err, "Couldn't read example cert DER")
+	_, err = sa.AddCertificate(ctx, exampleDER, regB.ID, nil, &issued)
+	test.AssertNotError(t, err, "Couldn't add test-cert.der")
+
+	search := func(expiresAfter time.Time, names ...string) []*sapb.RegistrationContacts {
+		t.Helper()
+		cutoff := expiresAfter.UnixNano()
+		regs, err := sa.RegistrationsWithCertificates(ctx, &sapb.RegistrationsWithCertificatesRequest{
+			ExpiresAfter: &cutoff,
+			Names:        names,
+		})
+		test.AssertNotError(t, err, "RegistrationsWithCertificates failed")
+		return regs
+	}
+	past := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	regs := search(past)
+	test.AssertEquals(t, len(regs), 2)
+	test.AssertEquals(t, *regs[0].Id, regA.ID)
+	test.AssertDeepEquals(t, regs[0].Contact, []string{"mailto:foo@example.com"})
+	test.AssertEquals(t, *regs[1].Id, regB.ID)
+	test.AssertEquals(t, len(regs[1].Contact), 0)
+
+	// Only regA's certificate expires after 2016-01-01
+	regs = search(time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC))
+	test.AssertEquals(t, len(regs), 1)
+	test.AssertEquals(t, *regs[0].Id, regA.ID)
+
+	regs = search(past, "www.example.com")
+	test.AssertEquals(t, len(regs), 1)
+	test.AssertEquals(t, *regs[0].Id, regB.ID)
+	test.AssertEquals(t, len(search(past, "eff.org", "example.com")), 2)
+	test.AssertEquals(t, len(search(past, "example.net")), 0)
+
+	_, err = sa.RegistrationsWithCertificates(ctx, &sapb.RegistrationsWithCertificatesRequest{})
+	test.Assert(t, berrors.Is(err, berrors.Malformed), "Expected a malformed error without an expiry cutoff")
+}
@@ -0,0 +1,227 @@
+package sa
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/go-gorp/gorp.v2"
+)
+
+// Migration is a single goose-formatted SQL migration.
+type Migration struct {
+	Version int64
+	// Name is the migration's file name.
+	Name string
+	// Up holds the statements of the migration's "-- +goose Up" section.
+	Up []string
+}
+
+// migrationTable is the table in which goose, and the migration runner,
+// record applied migrations. The same table is used so that databases
+// previously migrated with goose can be taken over by the runner.
+const migrationTable = "goose_db_version"
+
+const createMigrationTable = `CREATE TABLE IF NOT EXISTS goose_db_version (
+	id serial NOT NULL,
+	version_id bigint NOT NULL,
+	is_applied boolean NOT NULL,
+	tstamp timestamp NULL default now(),
+	PRIMARY KEY(id)
+)`
+
+// LoadMigrations reads the migrations in dir, ordered by version. Migration
+// files are named "<version>_<description>.sql", as created by goose.
+func LoadMigrations(dir string) ([]Migration, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	var migrations []Migration
+	for _, path := range paths {
+		name := filepath.Base(path)
+		idx := strings.Index(name, "_")
+		if idx < 0 {
+			return nil, fmt.Errorf("migration file name %q has no version prefix", name)
+		}
+		version, err := strconv.ParseInt(name[:idx], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration file name %q has an invalid version: %s", name, err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		up, err := parseMigration(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parsing migration %q: %s", name, err)
+		}
+		migrations = append(migrations, Migration{Version: version, Name: name, Up: up})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("migrations %q and %q have the same version", migrations[i-1].Name, migrations[i].Name)
+		}
+	}
+	return migrations, nil
+}
+
+// parseMigration returns the statements of the "-- +goose Up" section of a
+// goose SQL migration. Statements end with a semicolon at the end of a line,
+// unless they are wrapped in "-- +goose StatementBegin" and
+// "-- +goose StatementEnd".
+func parseMigration(r io.Reader) ([]string, error) {
+	var statements []string
+	var buf []string
+	inUp, inStatement, sawUp := false, false, false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "-- +goose ") {
+			switch strings.TrimSpace(strings.TrimPrefix(trimmed, "-- +goose ")) {
+			case "Up":
+				inUp, sawUp = true, true
+			case "Down":
+				inUp = false
+			case "StatementBegin":
+				inStatement = true
+			case "StatementEnd":
+				inStatement = false
+				if inUp && len(buf) > 0 {
+					statements = append(statements, strings.Join(buf, "\n"))
+				}
+				buf = nil
+			}
+			continue
+		}
+		if !inUp || trimmed == "" || (strings.HasPrefix(trimmed, "--") && !inStatement) {
+			continue
+		}
+		buf = append(buf, line)
+		if !inStatement && strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.Join(buf, "\n"))
+			buf = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !sawUp {
+		return nil, fmt.Errorf("no \"-- +goose Up\" section")
+	}
+	if inStatement {
+		return nil, fmt.Errorf("unterminated \"-- +goose StatementBegin\"")
+	}
+	if len(buf) > 0 {
+		statements = append(statements, strings.Join(buf, "\n"))
+	}
+	return statements, nil
+}
+
+// AppliedMigrations returns the versions of the migrations applied to the
+// database, mapped to when they were applied.
+func AppliedMigrations(dbMap *gorp.DbMap) (map[int64]time.Time, error) {
+	rows, err := dbMap.Db.Query("SELECT version_id, is_applied, tstamp FROM " + migrationTable + " ORDER BY id DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	// goose records rolled back migrations as new rows, so only the most
+	// recent row for each version counts.
+	seen := make(map[int64]bool)
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		var tstamp *time.Time
+		if err := rows.Scan(&version, &isApplied, &tstamp); err != nil {
+			return nil, err
+		}
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+		if isApplied {
+			applied[version] = time.Time{}
+			if tstamp != nil {
+				applied[version] = *tstamp
+			}
+		}
+	}
+	return applied, rows.Err()
+}
+
+// PendingMigrations returns the migrations, in order, that have not been
+// applied.
+func PendingMigrations(migrations []Migration, applied map[int64]time.Time) []Migration {
+	var pending []Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// EnsureMigrationTable creates the table recording applied migrations if it
+// doesn't exist yet.
+func EnsureMigrationTable(dbMap *gorp.DbMap) error {
+	_, err := dbMap.Exec(createMigrationTable)
+	return err
+}
+
+// ApplyMigration runs a migration's statements and records it as applied, in
+// a single transaction. Note that MySQL/MariaDB implicitly commit DDL
+// statements such as ALTER TABLE, so a migration containing them can't be
+// rolled back if a later statement fails.
+func ApplyMigration(dbMap *gorp.DbMap, m Migration) error {
+	tx, err := dbMap.Begin()
+	if err != nil {
+		return err
+	}
+	for _, statement := range m.Up {
+		if _, err := tx.Exec(statement); err != nil {
+			return Rollback(tx, fmt.Errorf("applying migration %q: %s", m.Name, err))
+		}
+	}
+	_, err = tx.Exec("INSERT INTO "+migrationTable+" (version_id, is_applied) VALUES (?, true)", m.Version)
+	if err != nil {
+		return Rollback(tx, fmt.Errorf("recording migration %q: %s", m.Name, err))
+	}
+	return tx.Commit()
+}
+
+// CheckSchemaVersion returns an error if any of the migrations, i.e. those
+// shipped with this version of the SA, has not been applied to the database.
+func CheckSchemaVersion(dbMap *gorp.DbMap, migrations []Migration) error {
+	applied, err := AppliedMigrations(dbMap)
+	if err != nil {
+		return fmt.Errorf("reading applied migrations: %s", err)
+	}
+	return checkSchemaVersion(migrations, applied)
+}
+
+func checkSchemaVersion(migrations []Migration, applied map[int64]time.Time) error {
+	if len(migrations) == 0 {
+		return fmt.Errorf("no migrations to check the database schema against")
+	}
+	pending := PendingMigrations(migrations, applied)
+	if len(pending) == 0 {
+		return nil
+	}
+	names := make([]string, len(pending))
+	for i, m := range pending {
+		names[i] = m.Name
+	}
+	return fmt.Errorf("database schema is behind: migrations %s have not been applied, run `boulder-sa migrate`",
+		strings.Join(names, ", "))
+}
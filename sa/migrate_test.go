@@ -0,0 +1,77 @@
+package sa
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestParseMigration(t *testing.T) {
+	statements, err := parseMigration(strings.NewReader(`
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE foo
+  ADD COLUMN bar INT;
+CREATE TABLE baz (id INT);
+
+-- +goose StatementBegin
+CREATE PROCEDURE p()
+BEGIN
+  SELECT 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE baz;
+`))
+	test.AssertNotError(t, err, "parseMigration failed")
+	test.AssertDeepEquals(t, statements, []string{
+		"ALTER TABLE foo\n  ADD COLUMN bar INT;",
+		"CREATE TABLE baz (id INT);",
+		"CREATE PROCEDURE p()\nBEGIN\n  SELECT 1;\nEND;",
+	})
+
+	_, err = parseMigration(strings.NewReader("CREATE TABLE baz (id INT);\n"))
+	test.AssertError(t, err, "parseMigration accepted a migration without an Up section")
+
+	_, err = parseMigration(strings.NewReader("-- +goose Up\n-- +goose StatementBegin\nSELECT 1;\n"))
+	test.AssertError(t, err, "parseMigration accepted an unterminated statement")
+}
+
+func TestLoadRepoMigrations(t *testing.T) {
+	migrations, err := LoadMigrations("_db/migrations")
+	test.AssertNotError(t, err, "LoadMigrations failed")
+	test.Assert(t, len(migrations) > 0, "No migrations found")
+	for i := 1; i < len(migrations); i++ {
+		test.Assert(t, migrations[i-1].Version < migrations[i].Version, "Migrations aren't ordered by version")
+	}
+	for _, m := range migrations {
+		test.Assert(t, len(m.Up) > 0, "Migration "+m.Name+" has no statements")
+	}
+}
+
+func TestPendingMigrations(t *testing.T) {
+	migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 3}}
+	applied := map[int64]time.Time{1: time.Now(), 3: time.Now()}
+	test.AssertDeepEquals(t, PendingMigrations(migrations, applied), []Migration{{Version: 2}})
+	test.AssertEquals(t, len(PendingMigrations(migrations, nil)), 3)
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	migrations := []Migration{{Version: 1, Name: "1_a.sql"}, {Version: 2, Name: "2_b.sql"}, {Version: 3, Name: "3_c.sql"}}
+	err := checkSchemaVersion(migrations, map[int64]time.Time{1: time.Now(), 2: time.Now()})
+	test.AssertError(t, err, "checkSchemaVersion accepted an old schema")
+	// A skipped migration is caught even though a later one was applied
+	err = checkSchemaVersion(migrations, map[int64]time.Time{1: time.Now(), 3: time.Now()})
+	test.AssertError(t, err, "checkSchemaVersion accepted a schema missing a migration")
+	test.Assert(t, strings.Contains(err.Error(), "2_b.sql"), "Missing migration not named in error")
+	err = checkSchemaVersion(migrations, map[int64]time.Time{1: time.Now(), 2: time.Now(), 3: time.Now(), 4: time.Now()})
+	test.AssertNotError(t, err, "checkSchemaVersion rejected a current schema")
+	err = checkSchemaVersion(nil, map[int64]time.Time{1: time.Now()})
+	test.AssertError(t, err, "checkSchemaVersion accepted an empty set of migrations")
+}
@@ -25,7 +25,7 @@ type dbSelector interface {
 	Select(interface{}, string, ...interface{}) ([]interface{}, error)
 }
 
-const regFields = "id, jwk, jwk_sha256, contact, agreement, initialIP, createdAt, LockCol, status"
+const regFields = "id, jwk, jwk_sha256, contact, agreement, initialIP, createdAt, LockCol, status, challengeTypes"
 
 // selectRegistration selects all fields of one registration model
 func selectRegistration(s dbOneSelector, q string, args ...interface{}) (*regModel, error) {
@@ -95,6 +95,18 @@ func SelectCertificates(s dbSelector, q string, args map[string]interface{}) ([]
 	return models, err
 }
 
+// SelectArchivedCertificate selects all fields of one certificate object from
+// the certificatesArchive table
+func SelectArchivedCertificate(s dbOneSelector, q string, args ...interface{}) (core.Certificate, error) {
+	var model core.Certificate
+	err := s.SelectOne(
+		&model,
+		"SELECT "+certFields+" FROM certificatesArchive "+q,
+		args...,
+	)
+	return model, err
+}
+
 const certStatusFields = "serial, status, ocspLastUpdated, revokedDate, revokedReason, lastExpirationNagSent, ocspResponse, notAfter, isExpired"
 
 // SelectCertificateStatus selects all fields of one certificate status model
@@ -141,6 +153,24 @@ type regModel struct {
 	CreatedAt time.Time `db:"createdAt"`
 	LockCol   int64
 	Status    string `db:"status"`
+	// ChallengeTypes is nil if the registration has no challenge preferences.
+	ChallengeTypes []string `db:"challengeTypes"`
+}
+
+// regHistoryModel is a row of the append-only registrationHistory table,
+// recording the state of a registration after a change.
+type regHistoryModel struct {
+	ID             int64     `db:"id"`
+	RegistrationID int64     `db:"registrationID"`
+	ChangedAt      time.Time `db:"changedAt"`
+	// RequestIP is stored as sixteen binary bytes like regModel's InitialIP,
+	// or NULL if the IP of the client making the change is unknown.
+	RequestIP []byte   `db:"requestIP"`
+	Key       []byte   `db:"jwk"`
+	KeySHA256 string   `db:"jwk_sha256"`
+	Contact   []string `db:"contact"`
+	Agreement string   `db:"agreement"`
+	Status    string   `db:"status"`
 }
 
 type certStatusModel struct {
@@ -153,6 +183,10 @@ type certStatusModel struct {
 	OCSPResponse          []byte            `db:"ocspResponse"`
 	NotAfter              time.Time         `db:"notAfter"`
 	IsExpired             bool              `db:"isExpired"`
+	// ShortLived is true for certificates without an OCSP URL, which are
+	// issued by a short-lived issuance profile. No OCSP responses or
+	// expiration mail are sent for them.
+	ShortLived bool `db:"shortLived"`
 
 	// TODO(#856, #873): Deprecated, remove once #2882 has been deployed
 	// to production
@@ -173,6 +207,8 @@ type challModel struct {
 	Token            string          `db:"token"`
 	KeyAuthorization string          `db:"keyAuthorization"`
 	ValidationRecord []byte          `db:"validationRecord"`
+	CAARecord        []byte          `db:"caaRecord"`
+	Reputation       []byte          `db:"reputation"`
 
 	// TODO(#1818): Remove, this field is unused, but is kept temporarily to avoid a database migration.
 	Validated bool `db:"validated"`
@@ -184,7 +220,7 @@ type challModel struct {
 // challenges table.
 const getChallengesQuery = `
 	SELECT id, authorizationID, type, status, error, token,
-		keyAuthorization, validationRecord
+		keyAuthorization, validationRecord, caaRecord, reputation
 	FROM challenges WHERE authorizationID = :authID ORDER BY id ASC`
 
 // newReg creates a reg model object from a core.Registration
@@ -214,6 +250,9 @@ func registrationToModel(r *core.Registration) (*regModel, error) {
 		CreatedAt: r.CreatedAt,
 		Status:    string(r.Status),
 	}
+	if r.ChallengeTypes != nil && len(*r.ChallengeTypes) > 0 {
+		rm.ChallengeTypes = *r.ChallengeTypes
+	}
 
 	return &rm, nil
 }
@@ -243,10 +282,54 @@ func modelToRegistration(reg *regModel) (core.Registration, error) {
 		CreatedAt: reg.CreatedAt,
 		Status:    core.AcmeStatus(reg.Status),
 	}
+	if len(reg.ChallengeTypes) > 0 {
+		challengeTypes := reg.ChallengeTypes
+		r.ChallengeTypes = &challengeTypes
+	}
 
 	return r, nil
 }
 
+// regModelToHistory returns the registrationHistory row recording the state
+// of a registration after a change made at changedAt from requestIP.
+func regModelToHistory(rm *regModel, changedAt time.Time, requestIP net.IP) *regHistoryModel {
+	hm := &regHistoryModel{
+		RegistrationID: rm.ID,
+		ChangedAt:      changedAt,
+		Key:            rm.Key,
+		KeySHA256:      rm.KeySHA256,
+		Contact:        rm.Contact,
+		Agreement:      rm.Agreement,
+		Status:         rm.Status,
+	}
+	if requestIP != nil {
+		hm.RequestIP = []byte(requestIP.To16())
+	}
+	return hm
+}
+
+func modelToRegistrationChange(hm *regHistoryModel) (core.RegistrationChange, error) {
+	reg, err := modelToRegistration(&regModel{
+		ID:        hm.RegistrationID,
+		Key:       hm.Key,
+		KeySHA256: hm.KeySHA256,
+		Contact:   hm.Contact,
+		Agreement: hm.Agreement,
+		Status:    hm.Status,
+	})
+	if err != nil {
+		return core.RegistrationChange{}, err
+	}
+	change := core.RegistrationChange{
+		Registration: reg,
+		ChangedAt:    hm.ChangedAt,
+	}
+	if hm.RequestIP != nil {
+		change.RequestIP = net.IP(hm.RequestIP)
+	}
+	return change, nil
+}
+
 func challengeToModel(c *core.Challenge, authID string) (*challModel, error) {
 	cm := challModel{
 		ID:               c.ID,
@@ -276,6 +359,26 @@ func challengeToModel(c *core.Challenge, authID string) (*challModel, error) {
 		}
 		cm.ValidationRecord = vrJSON
 	}
+	if c.CAARecord != nil {
+		caaJSON, err := json.Marshal(c.CAARecord)
+		if err != nil {
+			return nil, err
+		}
+		if len(caaJSON) > mediumBlobSize {
+			return nil, fmt.Errorf("CAA Record object is too large to store in the database")
+		}
+		cm.CAARecord = caaJSON
+	}
+	if len(c.Reputation) > 0 {
+		reputationJSON, err := json.Marshal(c.Reputation)
+		if err != nil {
+			return nil, err
+		}
+		if len(reputationJSON) > mediumBlobSize {
+			return nil, fmt.Errorf("Reputation object is too large to store in the database")
+		}
+		cm.Reputation = reputationJSON
+	}
 	return &cm, nil
 }
 
@@ -303,6 +406,22 @@ func modelToChallenge(cm *challModel) (core.Challenge, error) {
 		}
 		c.ValidationRecord = vr
 	}
+	if len(cm.CAARecord) > 0 {
+		var caaRecord core.CAAValidationRecord
+		err := json.Unmarshal(cm.CAARecord, &caaRecord)
+		if err != nil {
+			return core.Challenge{}, err
+		}
+		c.CAARecord = &caaRecord
+	}
+	if len(cm.Reputation) > 0 {
+		var reputation []core.ReputationVerdict
+		err := json.Unmarshal(cm.Reputation, &reputation)
+		if err != nil {
+			return core.Challenge{}, err
+		}
+		c.Reputation = reputation
+	}
 	return c, nil
 }
 
@@ -314,6 +433,7 @@ type orderModel struct {
 	Error             []byte
 	CertificateSerial string
 	BeganProcessing   bool
+	Profile           string
 }
 
 type requestedNameModel struct {
@@ -338,6 +458,9 @@ func orderToModel(order *corepb.Order) (*orderModel, error) {
 	if order.CertificateSerial != nil {
 		om.CertificateSerial = *order.CertificateSerial
 	}
+	if order.Profile != nil {
+		om.Profile = *order.Profile
+	}
 
 	if order.Error != nil {
 		errJSON, err := json.Marshal(order.Error)
@@ -362,6 +485,7 @@ func modelToOrder(om *orderModel) (*corepb.Order, error) {
 		Created:           &created,
 		CertificateSerial: &om.CertificateSerial,
 		BeganProcessing:   &om.BeganProcessing,
+		Profile:           &om.Profile,
 	}
 	if len(om.Error) > 0 {
 		var problem corepb.ProblemDetails
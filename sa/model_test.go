@@ -1,7 +1,14 @@
 package sa
 
 import (
+	"net"
 	"testing"
+	"time"
+
+	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	"github.com/letsencrypt/boulder/sa/satest"
+	"github.com/letsencrypt/boulder/test"
 )
 
 func TestModelToRegistrationNilContact(t *testing.T) {
@@ -35,3 +42,108 @@ func TestModelToRegistrationNonNilContact(t *testing.T) {
 		t.Errorf("Expected empty Contact field, got %#v", reg.Contact)
 	}
 }
+
+func TestRegistrationModelChallengeTypes(t *testing.T) {
+	reg := core.Registration{
+		Key:            satest.GoodJWK(),
+		InitialIP:      net.ParseIP("1.2.3.4"),
+		ChallengeTypes: &[]string{core.ChallengeTypeDNS01},
+	}
+	rm, err := registrationToModel(&reg)
+	test.AssertNotError(t, err, "registrationToModel failed")
+	test.AssertDeepEquals(t, rm.ChallengeTypes, []string{core.ChallengeTypeDNS01})
+	out, err := modelToRegistration(rm)
+	test.AssertNotError(t, err, "modelToRegistration failed")
+	test.AssertDeepEquals(t, *out.ChallengeTypes, []string{core.ChallengeTypeDNS01})
+
+	// Empty challenge preferences are stored as no preferences
+	reg.ChallengeTypes = &[]string{}
+	rm, err = registrationToModel(&reg)
+	test.AssertNotError(t, err, "registrationToModel failed")
+	test.Assert(t, rm.ChallengeTypes == nil, "Empty challenge types weren't stored as nil")
+	out, err = modelToRegistration(rm)
+	test.AssertNotError(t, err, "modelToRegistration failed")
+	test.Assert(t, out.ChallengeTypes == nil, "Registration had challenge types")
+}
+
+func TestOrderModelProfile(t *testing.T) {
+	id, regID, expires, created := int64(1), int64(2), int64(3), int64(4)
+	beganProcessing := false
+	profile := "shortLived"
+	order := &corepb.Order{
+		Id:              &id,
+		RegistrationID:  &regID,
+		Expires:         &expires,
+		Created:         &created,
+		BeganProcessing: &beganProcessing,
+		Profile:         &profile,
+	}
+	om, err := orderToModel(order)
+	test.AssertNotError(t, err, "orderToModel failed")
+	test.AssertEquals(t, om.Profile, profile)
+	out, err := modelToOrder(om)
+	test.AssertNotError(t, err, "modelToOrder failed")
+	test.AssertEquals(t, *out.Profile, profile)
+
+	// Orders without a profile use the default profile
+	order.Profile = nil
+	om, err = orderToModel(order)
+	test.AssertNotError(t, err, "orderToModel failed")
+	test.AssertEquals(t, om.Profile, "")
+}
+
+func TestChallengeModelCAARecord(t *testing.T) {
+	chall := core.Challenge{
+		Type:   core.ChallengeTypeHTTP01,
+		Status: core.StatusValid,
+		Token:  "token",
+		CAARecord: &core.CAAValidationRecord{
+			QueryName:     "example.com",
+			RRSet:         []string{`0 issue "letsencrypt.org"`},
+			MatchedRecord: `0 issue "letsencrypt.org"`,
+			Identity:      "letsencrypt.org",
+			Resolver:      "127.0.0.1:53",
+			Present:       true,
+			Valid:         true,
+			CheckedAt:     time.Date(2018, 7, 14, 1, 2, 3, 0, time.UTC),
+		},
+	}
+	cm, err := challengeToModel(&chall, "authz")
+	test.AssertNotError(t, err, "challengeToModel failed")
+	recon, err := modelToChallenge(cm)
+	test.AssertNotError(t, err, "modelToChallenge failed")
+	test.AssertDeepEquals(t, recon, chall)
+
+	// Challenges validated before CAA records were stored have none
+	cm.CAARecord = nil
+	recon, err = modelToChallenge(cm)
+	test.AssertNotError(t, err, "modelToChallenge failed")
+	test.Assert(t, recon.CAARecord == nil, "Expected a nil CAA record")
+}
+
+func TestChallengeModelReputation(t *testing.T) {
+	chall := core.Challenge{
+		Type:   core.ChallengeTypeHTTP01,
+		Status: core.StatusInvalid,
+		Token:  "token",
+		Reputation: []core.ReputationVerdict{
+			{
+				Provider:  "blocklist",
+				Reason:    "listed in blocklist as example.com",
+				Blocked:   true,
+				CheckedAt: time.Date(2018, 7, 15, 1, 2, 3, 0, time.UTC),
+			},
+		},
+	}
+	cm, err := challengeToModel(&chall, "authz")
+	test.AssertNotError(t, err, "challengeToModel failed")
+	recon, err := modelToChallenge(cm)
+	test.AssertNotError(t, err, "modelToChallenge failed")
+	test.AssertDeepEquals(t, recon, chall)
+
+	// Challenges without reputation checks have no verdicts
+	chall.Reputation = nil
+	cm, err = challengeToModel(&chall, "authz")
+	test.AssertNotError(t, err, "challengeToModel failed")
+	test.Assert(t, cm.Reputation == nil, "Expected no stored reputation verdicts")
+}
@@ -40,6 +40,27 @@ It has these top-level messages:
 	Authorizations
 	AddPendingAuthorizationsRequest
 	AuthorizationIDs
+	SearchCertificatesRequest
+	Certificates
+	SearchRegistrationsRequest
+	Registrations
+	RegistrationChange
+	RegistrationHistory
+	SearchOrdersRequest
+	Orders
+	SearchAuthorizationsRequest
+	AuthorizationList
+	RegistrationsWithCertificatesRequest
+	RegistrationContacts
+	RegistrationContactsList
+	Emails
+	EmailSuppression
+	CAAIodefReport
+	GetPendingCAAIodefReportsRequest
+	CAAIodefReports
+	CountCAAIodefReportsSentRequest
+	CountCAAIodefDeliveriesRequest
+	FinishCAAIodefReportRequest
 */
 package proto
 
@@ -804,6 +825,7 @@ func (m *GetValidOrderAuthorizationsRequest) GetAcctID() int64 {
 type GetOrderForNamesRequest struct {
 	AcctID           *int64   `protobuf:"varint,1,opt,name=acctID" json:"acctID,omitempty"`
 	Names            []string `protobuf:"bytes,2,rep,name=names" json:"names,omitempty"`
+	Profile          *string  `protobuf:"bytes,3,opt,name=profile" json:"profile,omitempty"`
 	XXX_unrecognized []byte   `json:"-"`
 }
 
@@ -826,6 +848,13 @@ func (m *GetOrderForNamesRequest) GetNames() []string {
 	return nil
 }
 
+func (m *GetOrderForNamesRequest) GetProfile() string {
+	if m != nil && m.Profile != nil {
+		return *m.Profile
+	}
+	return ""
+}
+
 type GetAuthorizationsRequest struct {
 	RegistrationID   *int64   `protobuf:"varint,1,opt,name=registrationID" json:"registrationID,omitempty"`
 	Domains          []string `protobuf:"bytes,2,rep,name=domains" json:"domains,omitempty"`
@@ -945,6 +974,630 @@ func (m *AuthorizationIDs) GetIds() []string {
 	return nil
 }
 
+type SearchCertificatesRequest struct {
+	// All set fields must match. At least one of name, registrationID,
+	// issued and expires must be set.
+	Name              *string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	IncludeSubdomains *bool   `protobuf:"varint,2,opt,name=includeSubdomains" json:"includeSubdomains,omitempty"`
+	RegistrationID    *int64  `protobuf:"varint,3,opt,name=registrationID" json:"registrationID,omitempty"`
+	Issued            *Range  `protobuf:"bytes,4,opt,name=issued" json:"issued,omitempty"`
+	Status            *string `protobuf:"bytes,5,opt,name=status" json:"status,omitempty"`
+	// Results are ordered by serial. To fetch the next page, set
+	// afterSerial to the last serial of the previous one.
+	AfterSerial      *string `protobuf:"bytes,6,opt,name=afterSerial" json:"afterSerial,omitempty"`
+	Limit            *int64  `protobuf:"varint,7,opt,name=limit" json:"limit,omitempty"`
+	Expires          *Range  `protobuf:"bytes,8,opt,name=expires" json:"expires,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SearchCertificatesRequest) Reset()                    { *m = SearchCertificatesRequest{} }
+func (m *SearchCertificatesRequest) String() string            { return proto1.CompactTextString(m) }
+func (*SearchCertificatesRequest) ProtoMessage()               {}
+func (*SearchCertificatesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *SearchCertificatesRequest) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *SearchCertificatesRequest) GetIncludeSubdomains() bool {
+	if m != nil && m.IncludeSubdomains != nil {
+		return *m.IncludeSubdomains
+	}
+	return false
+}
+
+func (m *SearchCertificatesRequest) GetRegistrationID() int64 {
+	if m != nil && m.RegistrationID != nil {
+		return *m.RegistrationID
+	}
+	return 0
+}
+
+func (m *SearchCertificatesRequest) GetIssued() *Range {
+	if m != nil {
+		return m.Issued
+	}
+	return nil
+}
+
+func (m *SearchCertificatesRequest) GetStatus() string {
+	if m != nil && m.Status != nil {
+		return *m.Status
+	}
+	return ""
+}
+
+func (m *SearchCertificatesRequest) GetAfterSerial() string {
+	if m != nil && m.AfterSerial != nil {
+		return *m.AfterSerial
+	}
+	return ""
+}
+
+func (m *SearchCertificatesRequest) GetLimit() int64 {
+	if m != nil && m.Limit != nil {
+		return *m.Limit
+	}
+	return 0
+}
+
+func (m *SearchCertificatesRequest) GetExpires() *Range {
+	if m != nil {
+		return m.Expires
+	}
+	return nil
+}
+
+type Certificates struct {
+	Certificates     []*core.Certificate `protobuf:"bytes,1,rep,name=certificates" json:"certificates,omitempty"`
+	XXX_unrecognized []byte              `json:"-"`
+}
+
+func (m *Certificates) Reset()                    { *m = Certificates{} }
+func (m *Certificates) String() string            { return proto1.CompactTextString(m) }
+func (*Certificates) ProtoMessage()               {}
+func (*Certificates) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *Certificates) GetCertificates() []*core.Certificate {
+	if m != nil {
+		return m.Certificates
+	}
+	return nil
+}
+
+type SearchRegistrationsRequest struct {
+	Contact *string `protobuf:"bytes,1,opt,name=contact" json:"contact,omitempty"`
+	// Results are ordered by ID. To fetch the next page, set afterID to
+	// the last ID of the previous one.
+	AfterID          *int64 `protobuf:"varint,2,opt,name=afterID" json:"afterID,omitempty"`
+	Limit            *int64 `protobuf:"varint,3,opt,name=limit" json:"limit,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *SearchRegistrationsRequest) Reset()                    { *m = SearchRegistrationsRequest{} }
+func (m *SearchRegistrationsRequest) String() string            { return proto1.CompactTextString(m) }
+func (*SearchRegistrationsRequest) ProtoMessage()               {}
+func (*SearchRegistrationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *SearchRegistrationsRequest) GetContact() string {
+	if m != nil && m.Contact != nil {
+		return *m.Contact
+	}
+	return ""
+}
+
+func (m *SearchRegistrationsRequest) GetAfterID() int64 {
+	if m != nil && m.AfterID != nil {
+		return *m.AfterID
+	}
+	return 0
+}
+
+func (m *SearchRegistrationsRequest) GetLimit() int64 {
+	if m != nil && m.Limit != nil {
+		return *m.Limit
+	}
+	return 0
+}
+
+type Registrations struct {
+	Registrations    []*core.Registration `protobuf:"bytes,1,rep,name=registrations" json:"registrations,omitempty"`
+	XXX_unrecognized []byte               `json:"-"`
+}
+
+func (m *Registrations) Reset()                    { *m = Registrations{} }
+func (m *Registrations) String() string            { return proto1.CompactTextString(m) }
+func (*Registrations) ProtoMessage()               {}
+func (*Registrations) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *Registrations) GetRegistrations() []*core.Registration {
+	if m != nil {
+		return m.Registrations
+	}
+	return nil
+}
+
+type RegistrationChange struct {
+	Registration     *core.Registration `protobuf:"bytes,1,opt,name=registration" json:"registration,omitempty"`
+	ChangedAt        *int64             `protobuf:"varint,2,opt,name=changedAt" json:"changedAt,omitempty"`
+	RequestIP        []byte             `protobuf:"bytes,3,opt,name=requestIP" json:"requestIP,omitempty"`
+	XXX_unrecognized []byte             `json:"-"`
+}
+
+func (m *RegistrationChange) Reset()                    { *m = RegistrationChange{} }
+func (m *RegistrationChange) String() string            { return proto1.CompactTextString(m) }
+func (*RegistrationChange) ProtoMessage()               {}
+func (*RegistrationChange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+func (m *RegistrationChange) GetRegistration() *core.Registration {
+	if m != nil {
+		return m.Registration
+	}
+	return nil
+}
+
+func (m *RegistrationChange) GetChangedAt() int64 {
+	if m != nil && m.ChangedAt != nil {
+		return *m.ChangedAt
+	}
+	return 0
+}
+
+func (m *RegistrationChange) GetRequestIP() []byte {
+	if m != nil {
+		return m.RequestIP
+	}
+	return nil
+}
+
+type RegistrationHistory struct {
+	// Ordered from oldest to newest
+	Changes          []*RegistrationChange `protobuf:"bytes,1,rep,name=changes" json:"changes,omitempty"`
+	XXX_unrecognized []byte                `json:"-"`
+}
+
+func (m *RegistrationHistory) Reset()                    { *m = RegistrationHistory{} }
+func (m *RegistrationHistory) String() string            { return proto1.CompactTextString(m) }
+func (*RegistrationHistory) ProtoMessage()               {}
+func (*RegistrationHistory) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *RegistrationHistory) GetChanges() []*RegistrationChange {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+type SearchOrdersRequest struct {
+	RegistrationID *int64 `protobuf:"varint,1,opt,name=registrationID" json:"registrationID,omitempty"`
+	// Results are ordered by ID. To fetch the next page, set afterID to
+	// the last ID of the previous one.
+	AfterID          *int64 `protobuf:"varint,2,opt,name=afterID" json:"afterID,omitempty"`
+	Limit            *int64 `protobuf:"varint,3,opt,name=limit" json:"limit,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *SearchOrdersRequest) Reset()                    { *m = SearchOrdersRequest{} }
+func (m *SearchOrdersRequest) String() string            { return proto1.CompactTextString(m) }
+func (*SearchOrdersRequest) ProtoMessage()               {}
+func (*SearchOrdersRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *SearchOrdersRequest) GetRegistrationID() int64 {
+	if m != nil && m.RegistrationID != nil {
+		return *m.RegistrationID
+	}
+	return 0
+}
+
+func (m *SearchOrdersRequest) GetAfterID() int64 {
+	if m != nil && m.AfterID != nil {
+		return *m.AfterID
+	}
+	return 0
+}
+
+func (m *SearchOrdersRequest) GetLimit() int64 {
+	if m != nil && m.Limit != nil {
+		return *m.Limit
+	}
+	return 0
+}
+
+type Orders struct {
+	Orders           []*core.Order `protobuf:"bytes,1,rep,name=orders" json:"orders,omitempty"`
+	XXX_unrecognized []byte        `json:"-"`
+}
+
+func (m *Orders) Reset()                    { *m = Orders{} }
+func (m *Orders) String() string            { return proto1.CompactTextString(m) }
+func (*Orders) ProtoMessage()               {}
+func (*Orders) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *Orders) GetOrders() []*core.Order {
+	if m != nil {
+		return m.Orders
+	}
+	return nil
+}
+
+type SearchAuthorizationsRequest struct {
+	RegistrationID *int64 `protobuf:"varint,1,opt,name=registrationID" json:"registrationID,omitempty"`
+	// Results, both pending and final, are ordered by ID. To fetch the
+	// next page, set afterID to the last ID of the previous one.
+	AfterID          *string `protobuf:"bytes,2,opt,name=afterID" json:"afterID,omitempty"`
+	Limit            *int64  `protobuf:"varint,3,opt,name=limit" json:"limit,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SearchAuthorizationsRequest) Reset()                    { *m = SearchAuthorizationsRequest{} }
+func (m *SearchAuthorizationsRequest) String() string            { return proto1.CompactTextString(m) }
+func (*SearchAuthorizationsRequest) ProtoMessage()               {}
+func (*SearchAuthorizationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *SearchAuthorizationsRequest) GetRegistrationID() int64 {
+	if m != nil && m.RegistrationID != nil {
+		return *m.RegistrationID
+	}
+	return 0
+}
+
+func (m *SearchAuthorizationsRequest) GetAfterID() string {
+	if m != nil && m.AfterID != nil {
+		return *m.AfterID
+	}
+	return ""
+}
+
+func (m *SearchAuthorizationsRequest) GetLimit() int64 {
+	if m != nil && m.Limit != nil {
+		return *m.Limit
+	}
+	return 0
+}
+
+type AuthorizationList struct {
+	Authorizations   []*core.Authorization `protobuf:"bytes,1,rep,name=authorizations" json:"authorizations,omitempty"`
+	XXX_unrecognized []byte                `json:"-"`
+}
+
+func (m *AuthorizationList) Reset()                    { *m = AuthorizationList{} }
+func (m *AuthorizationList) String() string            { return proto1.CompactTextString(m) }
+func (*AuthorizationList) ProtoMessage()               {}
+func (*AuthorizationList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *AuthorizationList) GetAuthorizations() []*core.Authorization {
+	if m != nil {
+		return m.Authorizations
+	}
+	return nil
+}
+
+type RegistrationsWithCertificatesRequest struct {
+	// Only match certificates expiring at or after this Unix timestamp
+	// (nanoseconds)
+	ExpiresAfter *int64 `protobuf:"varint,1,opt,name=expiresAfter" json:"expiresAfter,omitempty"`
+	// If set, only match certificates containing one of these names
+	Names            []string `protobuf:"bytes,2,rep,name=names" json:"names,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *RegistrationsWithCertificatesRequest) Reset()         { *m = RegistrationsWithCertificatesRequest{} }
+func (m *RegistrationsWithCertificatesRequest) String() string { return proto1.CompactTextString(m) }
+func (*RegistrationsWithCertificatesRequest) ProtoMessage()    {}
+func (*RegistrationsWithCertificatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{41}
+}
+
+func (m *RegistrationsWithCertificatesRequest) GetExpiresAfter() int64 {
+	if m != nil && m.ExpiresAfter != nil {
+		return *m.ExpiresAfter
+	}
+	return 0
+}
+
+func (m *RegistrationsWithCertificatesRequest) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+type RegistrationContacts struct {
+	Id               *int64   `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Contact          []string `protobuf:"bytes,2,rep,name=contact" json:"contact,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *RegistrationContacts) Reset()                    { *m = RegistrationContacts{} }
+func (m *RegistrationContacts) String() string            { return proto1.CompactTextString(m) }
+func (*RegistrationContacts) ProtoMessage()               {}
+func (*RegistrationContacts) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *RegistrationContacts) GetId() int64 {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return 0
+}
+
+func (m *RegistrationContacts) GetContact() []string {
+	if m != nil {
+		return m.Contact
+	}
+	return nil
+}
+
+type RegistrationContactsList struct {
+	// Ordered by ID, with each registration listed once
+	Registrations    []*RegistrationContacts `protobuf:"bytes,1,rep,name=registrations" json:"registrations,omitempty"`
+	XXX_unrecognized []byte                  `json:"-"`
+}
+
+func (m *RegistrationContactsList) Reset()                    { *m = RegistrationContactsList{} }
+func (m *RegistrationContactsList) String() string            { return proto1.CompactTextString(m) }
+func (*RegistrationContactsList) ProtoMessage()               {}
+func (*RegistrationContactsList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *RegistrationContactsList) GetRegistrations() []*RegistrationContacts {
+	if m != nil {
+		return m.Registrations
+	}
+	return nil
+}
+
+type Emails struct {
+	Emails           []string `protobuf:"bytes,1,rep,name=emails" json:"emails,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *Emails) Reset()                    { *m = Emails{} }
+func (m *Emails) String() string            { return proto1.CompactTextString(m) }
+func (*Emails) ProtoMessage()               {}
+func (*Emails) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *Emails) GetEmails() []string {
+	if m != nil {
+		return m.Emails
+	}
+	return nil
+}
+
+type EmailSuppression struct {
+	Email *string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+	// Why the address is suppressed, e.g. "unsubscribe" or "bounce"
+	Reason           *string `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *EmailSuppression) Reset()                    { *m = EmailSuppression{} }
+func (m *EmailSuppression) String() string            { return proto1.CompactTextString(m) }
+func (*EmailSuppression) ProtoMessage()               {}
+func (*EmailSuppression) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *EmailSuppression) GetEmail() string {
+	if m != nil && m.Email != nil {
+		return *m.Email
+	}
+	return ""
+}
+
+func (m *EmailSuppression) GetReason() string {
+	if m != nil && m.Reason != nil {
+		return *m.Reason
+	}
+	return ""
+}
+
+type CAAIodefReport struct {
+	Domain           *string `protobuf:"bytes,1,opt,name=domain" json:"domain,omitempty"`
+	AccountID        *int64  `protobuf:"varint,2,opt,name=accountID" json:"accountID,omitempty"`
+	ValidationMethod *string `protobuf:"bytes,3,opt,name=validationMethod" json:"validationMethod,omitempty"`
+	// JSON encoding of the CAA records that denied issuance
+	Records *string  `protobuf:"bytes,4,opt,name=records" json:"records,omitempty"`
+	Targets []string `protobuf:"bytes,5,rep,name=targets" json:"targets,omitempty"`
+	// Set by the SA when the report is queued
+	Id               *int64 `protobuf:"varint,6,opt,name=id" json:"id,omitempty"`
+	CreatedAt        *int64 `protobuf:"varint,7,opt,name=createdAt" json:"createdAt,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *CAAIodefReport) Reset()                    { *m = CAAIodefReport{} }
+func (m *CAAIodefReport) String() string            { return proto1.CompactTextString(m) }
+func (*CAAIodefReport) ProtoMessage()               {}
+func (*CAAIodefReport) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+func (m *CAAIodefReport) GetDomain() string {
+	if m != nil && m.Domain != nil {
+		return *m.Domain
+	}
+	return ""
+}
+
+func (m *CAAIodefReport) GetAccountID() int64 {
+	if m != nil && m.AccountID != nil {
+		return *m.AccountID
+	}
+	return 0
+}
+
+func (m *CAAIodefReport) GetValidationMethod() string {
+	if m != nil && m.ValidationMethod != nil {
+		return *m.ValidationMethod
+	}
+	return ""
+}
+
+func (m *CAAIodefReport) GetRecords() string {
+	if m != nil && m.Records != nil {
+		return *m.Records
+	}
+	return ""
+}
+
+func (m *CAAIodefReport) GetTargets() []string {
+	if m != nil {
+		return m.Targets
+	}
+	return nil
+}
+
+func (m *CAAIodefReport) GetId() int64 {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return 0
+}
+
+func (m *CAAIodefReport) GetCreatedAt() int64 {
+	if m != nil && m.CreatedAt != nil {
+		return *m.CreatedAt
+	}
+	return 0
+}
+
+type GetPendingCAAIodefReportsRequest struct {
+	Limit            *int64 `protobuf:"varint,1,opt,name=limit" json:"limit,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *GetPendingCAAIodefReportsRequest) Reset()         { *m = GetPendingCAAIodefReportsRequest{} }
+func (m *GetPendingCAAIodefReportsRequest) String() string { return proto1.CompactTextString(m) }
+func (*GetPendingCAAIodefReportsRequest) ProtoMessage()    {}
+func (*GetPendingCAAIodefReportsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{47}
+}
+
+func (m *GetPendingCAAIodefReportsRequest) GetLimit() int64 {
+	if m != nil && m.Limit != nil {
+		return *m.Limit
+	}
+	return 0
+}
+
+type CAAIodefReports struct {
+	Reports          []*CAAIodefReport `protobuf:"bytes,1,rep,name=reports" json:"reports,omitempty"`
+	XXX_unrecognized []byte            `json:"-"`
+}
+
+func (m *CAAIodefReports) Reset()                    { *m = CAAIodefReports{} }
+func (m *CAAIodefReports) String() string            { return proto1.CompactTextString(m) }
+func (*CAAIodefReports) ProtoMessage()               {}
+func (*CAAIodefReports) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+
+func (m *CAAIodefReports) GetReports() []*CAAIodefReport {
+	if m != nil {
+		return m.Reports
+	}
+	return nil
+}
+
+type CountCAAIodefReportsSentRequest struct {
+	RegisteredDomain *string `protobuf:"bytes,1,opt,name=registeredDomain" json:"registeredDomain,omitempty"`
+	Since            *int64  `protobuf:"varint,2,opt,name=since" json:"since,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *CountCAAIodefReportsSentRequest) Reset()         { *m = CountCAAIodefReportsSentRequest{} }
+func (m *CountCAAIodefReportsSentRequest) String() string { return proto1.CompactTextString(m) }
+func (*CountCAAIodefReportsSentRequest) ProtoMessage()    {}
+func (*CountCAAIodefReportsSentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{49}
+}
+
+func (m *CountCAAIodefReportsSentRequest) GetRegisteredDomain() string {
+	if m != nil && m.RegisteredDomain != nil {
+		return *m.RegisteredDomain
+	}
+	return ""
+}
+
+func (m *CountCAAIodefReportsSentRequest) GetSince() int64 {
+	if m != nil && m.Since != nil {
+		return *m.Since
+	}
+	return 0
+}
+
+type CountCAAIodefDeliveriesRequest struct {
+	Target           *string `protobuf:"bytes,1,opt,name=target" json:"target,omitempty"`
+	Since            *int64  `protobuf:"varint,2,opt,name=since" json:"since,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *CountCAAIodefDeliveriesRequest) Reset()                    { *m = CountCAAIodefDeliveriesRequest{} }
+func (m *CountCAAIodefDeliveriesRequest) String() string            { return proto1.CompactTextString(m) }
+func (*CountCAAIodefDeliveriesRequest) ProtoMessage()               {}
+func (*CountCAAIodefDeliveriesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+func (m *CountCAAIodefDeliveriesRequest) GetTarget() string {
+	if m != nil && m.Target != nil {
+		return *m.Target
+	}
+	return ""
+}
+
+func (m *CountCAAIodefDeliveriesRequest) GetSince() int64 {
+	if m != nil && m.Since != nil {
+		return *m.Since
+	}
+	return 0
+}
+
+type FinishCAAIodefReportRequest struct {
+	Id *int64 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	// One of "sent", "failed" or "ratelimited"
+	Status           *string `protobuf:"bytes,2,opt,name=status" json:"status,omitempty"`
+	RegisteredDomain *string `protobuf:"bytes,3,opt,name=registeredDomain" json:"registeredDomain,omitempty"`
+	// JSON object of the outcome of delivery to each target
+	Results *string `protobuf:"bytes,4,opt,name=results" json:"results,omitempty"`
+	// The targets the report was delivered to
+	Delivered        []string `protobuf:"bytes,5,rep,name=delivered" json:"delivered,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *FinishCAAIodefReportRequest) Reset()                    { *m = FinishCAAIodefReportRequest{} }
+func (m *FinishCAAIodefReportRequest) String() string            { return proto1.CompactTextString(m) }
+func (*FinishCAAIodefReportRequest) ProtoMessage()               {}
+func (*FinishCAAIodefReportRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+
+func (m *FinishCAAIodefReportRequest) GetId() int64 {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return 0
+}
+
+func (m *FinishCAAIodefReportRequest) GetStatus() string {
+	if m != nil && m.Status != nil {
+		return *m.Status
+	}
+	return ""
+}
+
+func (m *FinishCAAIodefReportRequest) GetRegisteredDomain() string {
+	if m != nil && m.RegisteredDomain != nil {
+		return *m.RegisteredDomain
+	}
+	return ""
+}
+
+func (m *FinishCAAIodefReportRequest) GetResults() string {
+	if m != nil && m.Results != nil {
+		return *m.Results
+	}
+	return ""
+}
+
+func (m *FinishCAAIodefReportRequest) GetDelivered() []string {
+	if m != nil {
+		return m.Delivered
+	}
+	return nil
+}
+
 func init() {
 	proto1.RegisterType((*RegistrationID)(nil), "sa.RegistrationID")
 	proto1.RegisterType((*JSONWebKey)(nil), "sa.JSONWebKey")
@@ -980,6 +1633,27 @@ func init() {
 	proto1.RegisterType((*Authorizations_MapElement)(nil), "sa.Authorizations.MapElement")
 	proto1.RegisterType((*AddPendingAuthorizationsRequest)(nil), "sa.AddPendingAuthorizationsRequest")
 	proto1.RegisterType((*AuthorizationIDs)(nil), "sa.AuthorizationIDs")
+	proto1.RegisterType((*SearchCertificatesRequest)(nil), "sa.SearchCertificatesRequest")
+	proto1.RegisterType((*Certificates)(nil), "sa.Certificates")
+	proto1.RegisterType((*SearchRegistrationsRequest)(nil), "sa.SearchRegistrationsRequest")
+	proto1.RegisterType((*Registrations)(nil), "sa.Registrations")
+	proto1.RegisterType((*RegistrationChange)(nil), "sa.RegistrationChange")
+	proto1.RegisterType((*RegistrationHistory)(nil), "sa.RegistrationHistory")
+	proto1.RegisterType((*SearchOrdersRequest)(nil), "sa.SearchOrdersRequest")
+	proto1.RegisterType((*Orders)(nil), "sa.Orders")
+	proto1.RegisterType((*SearchAuthorizationsRequest)(nil), "sa.SearchAuthorizationsRequest")
+	proto1.RegisterType((*AuthorizationList)(nil), "sa.AuthorizationList")
+	proto1.RegisterType((*RegistrationsWithCertificatesRequest)(nil), "sa.RegistrationsWithCertificatesRequest")
+	proto1.RegisterType((*RegistrationContacts)(nil), "sa.RegistrationContacts")
+	proto1.RegisterType((*RegistrationContactsList)(nil), "sa.RegistrationContactsList")
+	proto1.RegisterType((*Emails)(nil), "sa.Emails")
+	proto1.RegisterType((*EmailSuppression)(nil), "sa.EmailSuppression")
+	proto1.RegisterType((*CAAIodefReport)(nil), "sa.CAAIodefReport")
+	proto1.RegisterType((*GetPendingCAAIodefReportsRequest)(nil), "sa.GetPendingCAAIodefReportsRequest")
+	proto1.RegisterType((*CAAIodefReports)(nil), "sa.CAAIodefReports")
+	proto1.RegisterType((*CountCAAIodefReportsSentRequest)(nil), "sa.CountCAAIodefReportsSentRequest")
+	proto1.RegisterType((*CountCAAIodefDeliveriesRequest)(nil), "sa.CountCAAIodefDeliveriesRequest")
+	proto1.RegisterType((*FinishCAAIodefReportRequest)(nil), "sa.FinishCAAIodefReportRequest")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1013,6 +1687,16 @@ type StorageAuthorityClient interface {
 	CountFQDNSets(ctx context.Context, in *CountFQDNSetsRequest, opts ...grpc.CallOption) (*Count, error)
 	FQDNSetExists(ctx context.Context, in *FQDNSetExistsRequest, opts ...grpc.CallOption) (*Exists, error)
 	PreviousCertificateExists(ctx context.Context, in *PreviousCertificateExistsRequest, opts ...grpc.CallOption) (*Exists, error)
+	// Return those of the given email addresses which are suppressed.
+	GetSuppressedEmails(ctx context.Context, in *Emails, opts ...grpc.CallOption) (*Emails, error)
+	// Return the oldest pending CAA iodef reports, for the caa-iodef-reporter.
+	GetPendingCAAIodefReports(ctx context.Context, in *GetPendingCAAIodefReportsRequest, opts ...grpc.CallOption) (*CAAIodefReports, error)
+	// Return a count of the CAA iodef reports for a registered domain sent
+	// since the given time.
+	CountCAAIodefReportsSent(ctx context.Context, in *CountCAAIodefReportsSentRequest, opts ...grpc.CallOption) (*Count, error)
+	// Return a count of the deliveries of CAA iodef reports to a target
+	// since the given time.
+	CountCAAIodefDeliveries(ctx context.Context, in *CountCAAIodefDeliveriesRequest, opts ...grpc.CallOption) (*Count, error)
 	// Adders
 	NewRegistration(ctx context.Context, in *core.Registration, opts ...grpc.CallOption) (*core.Registration, error)
 	UpdateRegistration(ctx context.Context, in *core.Registration, opts ...grpc.CallOption) (*core.Empty, error)
@@ -1033,6 +1717,11 @@ type StorageAuthorityClient interface {
 	GetOrderForNames(ctx context.Context, in *GetOrderForNamesRequest, opts ...grpc.CallOption) (*core.Order, error)
 	GetAuthorizations(ctx context.Context, in *GetAuthorizationsRequest, opts ...grpc.CallOption) (*Authorizations, error)
 	AddPendingAuthorizations(ctx context.Context, in *AddPendingAuthorizationsRequest, opts ...grpc.CallOption) (*AuthorizationIDs, error)
+	AddEmailSuppression(ctx context.Context, in *EmailSuppression, opts ...grpc.CallOption) (*core.Empty, error)
+	// Queue a report of a denied CAA check for the caa-iodef-reporter.
+	AddCAAIodefReport(ctx context.Context, in *CAAIodefReport, opts ...grpc.CallOption) (*core.Empty, error)
+	// Record the outcome of processing a pending CAA iodef report.
+	FinishCAAIodefReport(ctx context.Context, in *FinishCAAIodefReportRequest, opts ...grpc.CallOption) (*core.Empty, error)
 }
 
 type storageAuthorityClient struct {
@@ -1196,6 +1885,42 @@ func (c *storageAuthorityClient) PreviousCertificateExists(ctx context.Context,
 	return out, nil
 }
 
+func (c *storageAuthorityClient) GetSuppressedEmails(ctx context.Context, in *Emails, opts ...grpc.CallOption) (*Emails, error) {
+	out := new(Emails)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthority/GetSuppressedEmails", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageAuthorityClient) GetPendingCAAIodefReports(ctx context.Context, in *GetPendingCAAIodefReportsRequest, opts ...grpc.CallOption) (*CAAIodefReports, error) {
+	out := new(CAAIodefReports)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthority/GetPendingCAAIodefReports", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageAuthorityClient) CountCAAIodefReportsSent(ctx context.Context, in *CountCAAIodefReportsSentRequest, opts ...grpc.CallOption) (*Count, error) {
+	out := new(Count)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthority/CountCAAIodefReportsSent", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageAuthorityClient) CountCAAIodefDeliveries(ctx context.Context, in *CountCAAIodefDeliveriesRequest, opts ...grpc.CallOption) (*Count, error) {
+	out := new(Count)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthority/CountCAAIodefDeliveries", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *storageAuthorityClient) NewRegistration(ctx context.Context, in *core.Registration, opts ...grpc.CallOption) (*core.Registration, error) {
 	out := new(core.Registration)
 	err := grpc.Invoke(ctx, "/sa.StorageAuthority/NewRegistration", in, out, c.cc, opts...)
@@ -1358,9 +2083,36 @@ func (c *storageAuthorityClient) GetAuthorizations(ctx context.Context, in *GetA
 	return out, nil
 }
 
-func (c *storageAuthorityClient) AddPendingAuthorizations(ctx context.Context, in *AddPendingAuthorizationsRequest, opts ...grpc.CallOption) (*AuthorizationIDs, error) {
-	out := new(AuthorizationIDs)
-	err := grpc.Invoke(ctx, "/sa.StorageAuthority/AddPendingAuthorizations", in, out, c.cc, opts...)
+func (c *storageAuthorityClient) AddPendingAuthorizations(ctx context.Context, in *AddPendingAuthorizationsRequest, opts ...grpc.CallOption) (*AuthorizationIDs, error) {
+	out := new(AuthorizationIDs)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthority/AddPendingAuthorizations", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageAuthorityClient) AddEmailSuppression(ctx context.Context, in *EmailSuppression, opts ...grpc.CallOption) (*core.Empty, error) {
+	out := new(core.Empty)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthority/AddEmailSuppression", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageAuthorityClient) AddCAAIodefReport(ctx context.Context, in *CAAIodefReport, opts ...grpc.CallOption) (*core.Empty, error) {
+	out := new(core.Empty)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthority/AddCAAIodefReport", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageAuthorityClient) FinishCAAIodefReport(ctx context.Context, in *FinishCAAIodefReportRequest, opts ...grpc.CallOption) (*core.Empty, error) {
+	out := new(core.Empty)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthority/FinishCAAIodefReport", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1390,6 +2142,16 @@ type StorageAuthorityServer interface {
 	CountFQDNSets(context.Context, *CountFQDNSetsRequest) (*Count, error)
 	FQDNSetExists(context.Context, *FQDNSetExistsRequest) (*Exists, error)
 	PreviousCertificateExists(context.Context, *PreviousCertificateExistsRequest) (*Exists, error)
+	// Return those of the given email addresses which are suppressed.
+	GetSuppressedEmails(context.Context, *Emails) (*Emails, error)
+	// Return the oldest pending CAA iodef reports, for the caa-iodef-reporter.
+	GetPendingCAAIodefReports(context.Context, *GetPendingCAAIodefReportsRequest) (*CAAIodefReports, error)
+	// Return a count of the CAA iodef reports for a registered domain sent
+	// since the given time.
+	CountCAAIodefReportsSent(context.Context, *CountCAAIodefReportsSentRequest) (*Count, error)
+	// Return a count of the deliveries of CAA iodef reports to a target
+	// since the given time.
+	CountCAAIodefDeliveries(context.Context, *CountCAAIodefDeliveriesRequest) (*Count, error)
 	// Adders
 	NewRegistration(context.Context, *core.Registration) (*core.Registration, error)
 	UpdateRegistration(context.Context, *core.Registration) (*core.Empty, error)
@@ -1410,6 +2172,11 @@ type StorageAuthorityServer interface {
 	GetOrderForNames(context.Context, *GetOrderForNamesRequest) (*core.Order, error)
 	GetAuthorizations(context.Context, *GetAuthorizationsRequest) (*Authorizations, error)
 	AddPendingAuthorizations(context.Context, *AddPendingAuthorizationsRequest) (*AuthorizationIDs, error)
+	AddEmailSuppression(context.Context, *EmailSuppression) (*core.Empty, error)
+	// Queue a report of a denied CAA check for the caa-iodef-reporter.
+	AddCAAIodefReport(context.Context, *CAAIodefReport) (*core.Empty, error)
+	// Record the outcome of processing a pending CAA iodef report.
+	FinishCAAIodefReport(context.Context, *FinishCAAIodefReportRequest) (*core.Empty, error)
 }
 
 func RegisterStorageAuthorityServer(s *grpc.Server, srv StorageAuthorityServer) {
@@ -1722,6 +2489,78 @@ func _StorageAuthority_PreviousCertificateExists_Handler(srv interface{}, ctx co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StorageAuthority_GetSuppressedEmails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Emails)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageAuthorityServer).GetSuppressedEmails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sa.StorageAuthority/GetSuppressedEmails",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageAuthorityServer).GetSuppressedEmails(ctx, req.(*Emails))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageAuthority_GetPendingCAAIodefReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPendingCAAIodefReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageAuthorityServer).GetPendingCAAIodefReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sa.StorageAuthority/GetPendingCAAIodefReports",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageAuthorityServer).GetPendingCAAIodefReports(ctx, req.(*GetPendingCAAIodefReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageAuthority_CountCAAIodefReportsSent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountCAAIodefReportsSentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageAuthorityServer).CountCAAIodefReportsSent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sa.StorageAuthority/CountCAAIodefReportsSent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageAuthorityServer).CountCAAIodefReportsSent(ctx, req.(*CountCAAIodefReportsSentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageAuthority_CountCAAIodefDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountCAAIodefDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageAuthorityServer).CountCAAIodefDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sa.StorageAuthority/CountCAAIodefDeliveries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageAuthorityServer).CountCAAIodefDeliveries(ctx, req.(*CountCAAIodefDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _StorageAuthority_NewRegistration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(core.Registration)
 	if err := dec(in); err != nil {
@@ -2064,6 +2903,60 @@ func _StorageAuthority_AddPendingAuthorizations_Handler(srv interface{}, ctx con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StorageAuthority_AddEmailSuppression_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailSuppression)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageAuthorityServer).AddEmailSuppression(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sa.StorageAuthority/AddEmailSuppression",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageAuthorityServer).AddEmailSuppression(ctx, req.(*EmailSuppression))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageAuthority_AddCAAIodefReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CAAIodefReport)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageAuthorityServer).AddCAAIodefReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sa.StorageAuthority/AddCAAIodefReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageAuthorityServer).AddCAAIodefReport(ctx, req.(*CAAIodefReport))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageAuthority_FinishCAAIodefReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinishCAAIodefReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageAuthorityServer).FinishCAAIodefReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sa.StorageAuthority/FinishCAAIodefReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageAuthorityServer).FinishCAAIodefReport(ctx, req.(*FinishCAAIodefReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _StorageAuthority_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "sa.StorageAuthority",
 	HandlerType: (*StorageAuthorityServer)(nil),
@@ -2136,6 +3029,22 @@ var _StorageAuthority_serviceDesc = grpc.ServiceDesc{
 			MethodName: "PreviousCertificateExists",
 			Handler:    _StorageAuthority_PreviousCertificateExists_Handler,
 		},
+		{
+			MethodName: "GetSuppressedEmails",
+			Handler:    _StorageAuthority_GetSuppressedEmails_Handler,
+		},
+		{
+			MethodName: "GetPendingCAAIodefReports",
+			Handler:    _StorageAuthority_GetPendingCAAIodefReports_Handler,
+		},
+		{
+			MethodName: "CountCAAIodefReportsSent",
+			Handler:    _StorageAuthority_CountCAAIodefReportsSent_Handler,
+		},
+		{
+			MethodName: "CountCAAIodefDeliveries",
+			Handler:    _StorageAuthority_CountCAAIodefDeliveries_Handler,
+		},
 		{
 			MethodName: "NewRegistration",
 			Handler:    _StorageAuthority_NewRegistration_Handler,
@@ -2212,6 +3121,247 @@ var _StorageAuthority_serviceDesc = grpc.ServiceDesc{
 			MethodName: "AddPendingAuthorizations",
 			Handler:    _StorageAuthority_AddPendingAuthorizations_Handler,
 		},
+		{
+			MethodName: "AddEmailSuppression",
+			Handler:    _StorageAuthority_AddEmailSuppression_Handler,
+		},
+		{
+			MethodName: "AddCAAIodefReport",
+			Handler:    _StorageAuthority_AddCAAIodefReport_Handler,
+		},
+		{
+			MethodName: "FinishCAAIodefReport",
+			Handler:    _StorageAuthority_FinishCAAIodefReport_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sa/proto/sa.proto",
+}
+
+// Client API for StorageAuthorityAdmin service
+
+type StorageAuthorityAdminClient interface {
+	SearchCertificates(ctx context.Context, in *SearchCertificatesRequest, opts ...grpc.CallOption) (*Certificates, error)
+	SearchRegistrations(ctx context.Context, in *SearchRegistrationsRequest, opts ...grpc.CallOption) (*Registrations, error)
+	GetRegistrationHistory(ctx context.Context, in *RegistrationID, opts ...grpc.CallOption) (*RegistrationHistory, error)
+	SearchOrders(ctx context.Context, in *SearchOrdersRequest, opts ...grpc.CallOption) (*Orders, error)
+	SearchAuthorizations(ctx context.Context, in *SearchAuthorizationsRequest, opts ...grpc.CallOption) (*AuthorizationList, error)
+	RegistrationsWithCertificates(ctx context.Context, in *RegistrationsWithCertificatesRequest, opts ...grpc.CallOption) (*RegistrationContactsList, error)
+}
+
+type storageAuthorityAdminClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewStorageAuthorityAdminClient(cc *grpc.ClientConn) StorageAuthorityAdminClient {
+	return &storageAuthorityAdminClient{cc}
+}
+
+func (c *storageAuthorityAdminClient) SearchCertificates(ctx context.Context, in *SearchCertificatesRequest, opts ...grpc.CallOption) (*Certificates, error) {
+	out := new(Certificates)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthorityAdmin/SearchCertificates", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageAuthorityAdminClient) SearchRegistrations(ctx context.Context, in *SearchRegistrationsRequest, opts ...grpc.CallOption) (*Registrations, error) {
+	out := new(Registrations)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthorityAdmin/SearchRegistrations", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageAuthorityAdminClient) GetRegistrationHistory(ctx context.Context, in *RegistrationID, opts ...grpc.CallOption) (*RegistrationHistory, error) {
+	out := new(RegistrationHistory)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthorityAdmin/GetRegistrationHistory", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageAuthorityAdminClient) SearchOrders(ctx context.Context, in *SearchOrdersRequest, opts ...grpc.CallOption) (*Orders, error) {
+	out := new(Orders)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthorityAdmin/SearchOrders", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageAuthorityAdminClient) SearchAuthorizations(ctx context.Context, in *SearchAuthorizationsRequest, opts ...grpc.CallOption) (*AuthorizationList, error) {
+	out := new(AuthorizationList)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthorityAdmin/SearchAuthorizations", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageAuthorityAdminClient) RegistrationsWithCertificates(ctx context.Context, in *RegistrationsWithCertificatesRequest, opts ...grpc.CallOption) (*RegistrationContactsList, error) {
+	out := new(RegistrationContactsList)
+	err := grpc.Invoke(ctx, "/sa.StorageAuthorityAdmin/RegistrationsWithCertificates", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for StorageAuthorityAdmin service
+
+type StorageAuthorityAdminServer interface {
+	SearchCertificates(context.Context, *SearchCertificatesRequest) (*Certificates, error)
+	SearchRegistrations(context.Context, *SearchRegistrationsRequest) (*Registrations, error)
+	GetRegistrationHistory(context.Context, *RegistrationID) (*RegistrationHistory, error)
+	SearchOrders(context.Context, *SearchOrdersRequest) (*Orders, error)
+	SearchAuthorizations(context.Context, *SearchAuthorizationsRequest) (*AuthorizationList, error)
+	RegistrationsWithCertificates(context.Context, *RegistrationsWithCertificatesRequest) (*RegistrationContactsList, error)
+}
+
+func RegisterStorageAuthorityAdminServer(s *grpc.Server, srv StorageAuthorityAdminServer) {
+	s.RegisterService(&_StorageAuthorityAdmin_serviceDesc, srv)
+}
+
+func _StorageAuthorityAdmin_SearchCertificates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchCertificatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageAuthorityAdminServer).SearchCertificates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sa.StorageAuthorityAdmin/SearchCertificates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageAuthorityAdminServer).SearchCertificates(ctx, req.(*SearchCertificatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageAuthorityAdmin_SearchRegistrations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRegistrationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageAuthorityAdminServer).SearchRegistrations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sa.StorageAuthorityAdmin/SearchRegistrations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageAuthorityAdminServer).SearchRegistrations(ctx, req.(*SearchRegistrationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageAuthorityAdmin_GetRegistrationHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegistrationID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageAuthorityAdminServer).GetRegistrationHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sa.StorageAuthorityAdmin/GetRegistrationHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageAuthorityAdminServer).GetRegistrationHistory(ctx, req.(*RegistrationID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageAuthorityAdmin_SearchOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageAuthorityAdminServer).SearchOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sa.StorageAuthorityAdmin/SearchOrders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageAuthorityAdminServer).SearchOrders(ctx, req.(*SearchOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageAuthorityAdmin_SearchAuthorizations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchAuthorizationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageAuthorityAdminServer).SearchAuthorizations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sa.StorageAuthorityAdmin/SearchAuthorizations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageAuthorityAdminServer).SearchAuthorizations(ctx, req.(*SearchAuthorizationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageAuthorityAdmin_RegistrationsWithCertificates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegistrationsWithCertificatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageAuthorityAdminServer).RegistrationsWithCertificates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sa.StorageAuthorityAdmin/RegistrationsWithCertificates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageAuthorityAdminServer).RegistrationsWithCertificates(ctx, req.(*RegistrationsWithCertificatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _StorageAuthorityAdmin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "sa.StorageAuthorityAdmin",
+	HandlerType: (*StorageAuthorityAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SearchCertificates",
+			Handler:    _StorageAuthorityAdmin_SearchCertificates_Handler,
+		},
+		{
+			MethodName: "SearchRegistrations",
+			Handler:    _StorageAuthorityAdmin_SearchRegistrations_Handler,
+		},
+		{
+			MethodName: "GetRegistrationHistory",
+			Handler:    _StorageAuthorityAdmin_GetRegistrationHistory_Handler,
+		},
+		{
+			MethodName: "SearchOrders",
+			Handler:    _StorageAuthorityAdmin_SearchOrders_Handler,
+		},
+		{
+			MethodName: "SearchAuthorizations",
+			Handler:    _StorageAuthorityAdmin_SearchAuthorizations_Handler,
+		},
+		{
+			MethodName: "RegistrationsWithCertificates",
+			Handler:    _StorageAuthorityAdmin_RegistrationsWithCertificates_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "sa/proto/sa.proto",
@@ -2220,106 +3370,159 @@ var _StorageAuthority_serviceDesc = grpc.ServiceDesc{
 func init() { proto1.RegisterFile("sa/proto/sa.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1604 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0xdb, 0x6e, 0xdb, 0x46,
-	0x13, 0xd6, 0x21, 0x72, 0xa4, 0xf1, 0x79, 0x6d, 0xcb, 0x0c, 0x7d, 0x88, 0xb3, 0xf1, 0x9f, 0xdf,
-	0x41, 0x01, 0x27, 0x75, 0x8b, 0xa4, 0x80, 0x9b, 0xb6, 0x76, 0xec, 0x28, 0xce, 0xc1, 0x76, 0xa9,
-	0xc4, 0x09, 0x5a, 0xa0, 0x00, 0x23, 0x6e, 0x14, 0xd6, 0x32, 0xa9, 0xec, 0xae, 0xac, 0xc8, 0x2f,
-	0xd0, 0x3e, 0x41, 0xd1, 0xab, 0xa2, 0xcf, 0xd1, 0x37, 0xeb, 0x5d, 0xb1, 0x07, 0x52, 0x24, 0x45,
-	0x4a, 0x31, 0x52, 0xf4, 0x6e, 0x67, 0x76, 0xe6, 0x9b, 0xd9, 0xd9, 0xd9, 0xe1, 0x27, 0xc1, 0x2c,
-	0xb3, 0xef, 0xb4, 0xa9, 0xcf, 0xfd, 0x3b, 0xcc, 0xde, 0x94, 0x0b, 0x54, 0x60, 0xb6, 0xb9, 0xd0,
-	0xf0, 0x29, 0xd1, 0x1b, 0x62, 0xa9, 0xb6, 0xf0, 0x1a, 0x4c, 0x59, 0xa4, 0xe9, 0x32, 0x4e, 0x6d,
-	0xee, 0xfa, 0xde, 0xc1, 0x1e, 0x9a, 0x82, 0x82, 0xeb, 0x18, 0xf9, 0xb5, 0xfc, 0x46, 0xd1, 0x2a,
-	0xb8, 0x0e, 0x5e, 0x05, 0x78, 0x52, 0x3f, 0x3a, 0x7c, 0x45, 0xde, 0x3c, 0x25, 0x3d, 0x34, 0x03,
-	0xc5, 0x9f, 0xbb, 0xa7, 0x72, 0x7b, 0xc2, 0x12, 0x4b, 0x7c, 0x03, 0xa6, 0x77, 0x3a, 0xfc, 0x9d,
-	0x4f, 0xdd, 0x8b, 0x41, 0x88, 0x8a, 0x84, 0xf8, 0x2b, 0x0f, 0xab, 0x35, 0xc2, 0x8f, 0x89, 0xe7,
-	0xb8, 0x5e, 0x33, 0x66, 0x6d, 0x91, 0xf7, 0x1d, 0xc2, 0x38, 0xba, 0x05, 0x53, 0x34, 0x96, 0x87,
-	0xce, 0x20, 0xa1, 0x15, 0x76, 0xae, 0x43, 0x3c, 0xee, 0xbe, 0x75, 0x09, 0x7d, 0xd1, 0x6b, 0x13,
-	0xa3, 0x20, 0xc3, 0x24, 0xb4, 0x68, 0x03, 0xa6, 0xfb, 0x9a, 0x13, 0xbb, 0xd5, 0x21, 0x46, 0x51,
-	0x1a, 0x26, 0xd5, 0x68, 0x15, 0xe0, 0xdc, 0x6e, 0xb9, 0xce, 0x4b, 0x8f, 0xbb, 0x2d, 0xe3, 0x8a,
-	0x8c, 0x1a, 0xd1, 0x60, 0x06, 0x2b, 0x35, 0xc2, 0x4f, 0x84, 0x22, 0x96, 0x39, 0xbb, 0x6c, 0xea,
-	0x06, 0x5c, 0x75, 0xfc, 0x33, 0xdb, 0xf5, 0x98, 0x51, 0x58, 0x2b, 0x6e, 0x54, 0xac, 0x40, 0x14,
-	0x45, 0xf5, 0xfc, 0xae, 0x4c, 0xb0, 0x68, 0x89, 0x25, 0xfe, 0x33, 0x0f, 0x73, 0x29, 0x21, 0xd1,
-	0x57, 0x50, 0x92, 0xa9, 0x19, 0xf9, 0xb5, 0xe2, 0xc6, 0xf8, 0x16, 0xde, 0x64, 0xf6, 0x66, 0x8a,
-	0xdd, 0xe6, 0x73, 0xbb, 0xbd, 0xdf, 0x22, 0x67, 0xc4, 0xe3, 0x96, 0x72, 0x30, 0x8f, 0x00, 0xfa,
-	0x4a, 0x54, 0x85, 0x31, 0x15, 0x5c, 0xdf, 0x92, 0x96, 0xd0, 0x6d, 0x28, 0xd9, 0x1d, 0xfe, 0xee,
-	0x42, 0x56, 0x75, 0x7c, 0x6b, 0x6e, 0x53, 0xb6, 0x4a, 0xfc, 0xc6, 0x94, 0x05, 0xfe, 0xbb, 0x00,
-	0xb3, 0x0f, 0x09, 0x15, 0xa5, 0x6c, 0xd8, 0x9c, 0xd4, 0xb9, 0xcd, 0x3b, 0x4c, 0x00, 0x33, 0x42,
-	0x5d, 0xbb, 0x15, 0x00, 0x2b, 0x09, 0x6d, 0x02, 0x62, 0x9d, 0x37, 0xac, 0x41, 0xdd, 0x37, 0x84,
-	0xee, 0xb4, 0xdb, 0xd4, 0x3f, 0x27, 0x8e, 0x8c, 0x52, 0xb6, 0x52, 0x76, 0x24, 0x8e, 0x44, 0xd4,
-	0xd7, 0xa6, 0x25, 0x71, 0xaf, 0x7e, 0x83, 0xb5, 0x9f, 0xd9, 0x8c, 0xbf, 0x6c, 0x3b, 0x36, 0x27,
-	0x8e, 0xbe, 0xb2, 0xa4, 0x1a, 0xad, 0xc1, 0x38, 0x25, 0xe7, 0xfe, 0x29, 0x71, 0xf6, 0x6c, 0x4e,
-	0x8c, 0x92, 0xb4, 0x8a, 0xaa, 0xd0, 0x3a, 0x4c, 0x6a, 0xd1, 0x22, 0x36, 0xf3, 0x3d, 0x63, 0x4c,
-	0xda, 0xc4, 0x95, 0xe8, 0x4b, 0x58, 0x68, 0xd9, 0x8c, 0xef, 0x7f, 0x68, 0xbb, 0xea, 0x2a, 0x0f,
-	0xed, 0x66, 0x9d, 0x78, 0xdc, 0xb8, 0x2a, 0xad, 0xd3, 0x37, 0x11, 0x86, 0x09, 0x91, 0x90, 0x45,
-	0x58, 0xdb, 0xf7, 0x18, 0x31, 0xca, 0xf2, 0xc1, 0xc4, 0x74, 0xc8, 0x84, 0xb2, 0xe7, 0xf3, 0x9d,
-	0xb7, 0x9c, 0x50, 0xa3, 0x22, 0xc1, 0x42, 0x19, 0x2d, 0x43, 0xc5, 0x65, 0x12, 0x96, 0x38, 0x06,
-	0xc8, 0x32, 0xf5, 0x15, 0x78, 0x0d, 0xc6, 0xea, 0xaa, 0xae, 0x19, 0xf5, 0xc6, 0xdb, 0x50, 0xb2,
-	0x6c, 0xaf, 0x29, 0x83, 0x10, 0x9b, 0xb6, 0x5c, 0xc2, 0xb8, 0xee, 0xcb, 0x50, 0x16, 0xce, 0x2d,
-	0x9b, 0x8b, 0x9d, 0x82, 0xdc, 0xd1, 0x12, 0x5e, 0x81, 0xd2, 0x43, 0xbf, 0xe3, 0x71, 0x34, 0x0f,
-	0xa5, 0x86, 0x58, 0x68, 0x4f, 0x25, 0xe0, 0xd7, 0x70, 0x5d, 0x6e, 0x47, 0x6e, 0x9f, 0xed, 0xf6,
-	0x0e, 0xed, 0x33, 0x12, 0xbe, 0x89, 0xeb, 0x50, 0xa2, 0x22, 0xbc, 0x74, 0x1c, 0xdf, 0xaa, 0x88,
-	0x3e, 0x95, 0xf9, 0x58, 0x4a, 0x2f, 0x90, 0x3d, 0xe1, 0xa0, 0x9f, 0x82, 0x12, 0xf0, 0x2f, 0x79,
-	0x98, 0x90, 0xd0, 0x1a, 0x0e, 0x7d, 0x0b, 0x13, 0x8d, 0x88, 0xac, 0xdb, 0x7e, 0x49, 0xc0, 0x45,
-	0xed, 0xa2, 0xfd, 0x1e, 0x73, 0x30, 0xef, 0xc5, 0xda, 0x1e, 0xc1, 0x15, 0x11, 0x48, 0xd7, 0x4a,
-	0xae, 0xfb, 0x67, 0x2c, 0x44, 0xcf, 0x78, 0x0c, 0x2b, 0x32, 0x40, 0x74, 0x38, 0xb2, 0xdd, 0xde,
-	0xc1, 0x71, 0x70, 0x42, 0x31, 0xe3, 0xda, 0x7a, 0x0e, 0x16, 0xdc, 0x76, 0xff, 0xc4, 0x85, 0xf4,
-	0x13, 0xe3, 0x5f, 0xf3, 0x70, 0x43, 0x42, 0x1e, 0x78, 0xe7, 0x9f, 0x3e, 0x4c, 0x4c, 0x28, 0xbf,
-	0xf3, 0x19, 0x97, 0xa7, 0x51, 0x13, 0x30, 0x94, 0xfb, 0xa9, 0x14, 0x33, 0x52, 0xa9, 0x03, 0x92,
-	0x99, 0x1c, 0x51, 0x87, 0xd0, 0x30, 0xf4, 0x32, 0x54, 0xec, 0x86, 0x3c, 0x7d, 0x18, 0xb5, 0xaf,
-	0x18, 0x7d, 0xbe, 0xc7, 0x30, 0x2f, 0x41, 0x1f, 0x7d, 0xbf, 0x77, 0x58, 0x27, 0x3c, 0x84, 0xad,
-	0xc2, 0x58, 0xd7, 0xf5, 0x1c, 0xbf, 0xab, 0x31, 0xb5, 0x94, 0x3d, 0x0e, 0xf1, 0x5d, 0x98, 0xd7,
-	0x20, 0xfb, 0x1f, 0x5c, 0xd6, 0x47, 0x8a, 0x78, 0xe4, 0xe3, 0x1e, 0xc7, 0xb0, 0x76, 0x4c, 0xc9,
-	0xb9, 0xeb, 0x77, 0x58, 0xa4, 0x29, 0xe3, 0xde, 0x59, 0x23, 0x6f, 0x1e, 0x4a, 0x94, 0x34, 0x0f,
-	0xf6, 0x82, 0xfb, 0x97, 0x82, 0x78, 0x61, 0xca, 0x5d, 0xf8, 0x11, 0xb9, 0x92, 0x7e, 0x65, 0x4b,
-	0x4b, 0xf8, 0x29, 0xac, 0x3c, 0xb7, 0xe9, 0x69, 0x24, 0x9e, 0x15, 0xcc, 0x8d, 0x30, 0x60, 0xea,
-	0x28, 0x44, 0x70, 0xa5, 0xe1, 0x3b, 0x44, 0xc7, 0x93, 0x6b, 0x7c, 0x0a, 0x0b, 0x3b, 0x8e, 0x13,
-	0xc3, 0x52, 0x20, 0x33, 0x50, 0x74, 0x08, 0x0d, 0xbe, 0xb7, 0x0e, 0xa1, 0xe9, 0xf9, 0x0a, 0x50,
-	0x31, 0x5b, 0xe4, 0x95, 0x4f, 0x58, 0x72, 0x2d, 0x12, 0x70, 0x19, 0xeb, 0x84, 0x23, 0x52, 0x4b,
-	0xf8, 0x2e, 0x54, 0x93, 0xc1, 0xf4, 0x44, 0x12, 0x35, 0x72, 0x9b, 0xc1, 0xa8, 0x10, 0x35, 0x92,
-	0x12, 0x7e, 0x00, 0x37, 0xd5, 0xe1, 0xe2, 0x4d, 0xbb, 0xdb, 0xdb, 0x93, 0x35, 0x1c, 0x51, 0x62,
-	0xfc, 0x13, 0xac, 0x0f, 0x77, 0xd7, 0xe1, 0x97, 0xa1, 0xf2, 0xd6, 0xf5, 0xec, 0x96, 0x7b, 0x41,
-	0x02, 0x06, 0xd2, 0x57, 0x88, 0xeb, 0x6f, 0x2b, 0x06, 0xa1, 0x8f, 0x1e, 0x88, 0x78, 0x15, 0x26,
-	0x64, 0x2b, 0x47, 0xdf, 0x66, 0x94, 0xc2, 0x3c, 0x03, 0x1c, 0x7c, 0xc2, 0xa5, 0x5d, 0xfa, 0xd3,
-	0x4b, 0x78, 0x89, 0xd3, 0xd8, 0x8d, 0x06, 0x0f, 0x2b, 0xad, 0x25, 0x5c, 0x83, 0xc5, 0x1a, 0x51,
-	0x6f, 0xe7, 0x91, 0x4f, 0x63, 0x63, 0xaf, 0xef, 0x92, 0x8f, 0xba, 0x64, 0x4c, 0xbb, 0xdf, 0xf3,
-	0x60, 0xd4, 0x08, 0xff, 0xcf, 0x58, 0x85, 0xf8, 0x78, 0x52, 0xf2, 0xbe, 0xe3, 0x52, 0x72, 0xb2,
-	0x25, 0xa2, 0x5e, 0x30, 0xd9, 0x19, 0x65, 0x2b, 0xa9, 0xc6, 0xbf, 0xe5, 0x61, 0x2a, 0x41, 0x3d,
-	0xbe, 0x08, 0xa8, 0x81, 0x9a, 0xc1, 0x2b, 0x62, 0x00, 0x0c, 0x61, 0x1d, 0xd2, 0xf6, 0xdf, 0x67,
-	0x1d, 0xcf, 0xe0, 0xfa, 0x8e, 0xe3, 0xa4, 0x31, 0xc9, 0xb0, 0x72, 0xb7, 0xe3, 0x89, 0x0e, 0x43,
-	0x5b, 0x87, 0x99, 0x04, 0x77, 0x95, 0x65, 0x73, 0x9d, 0x60, 0xc2, 0x88, 0xe5, 0xd6, 0x1f, 0x73,
-	0x30, 0x53, 0xe7, 0x3e, 0xb5, 0x9b, 0x41, 0x03, 0xf3, 0x1e, 0xda, 0x86, 0xe9, 0x1a, 0x89, 0x7d,
-	0x1e, 0x10, 0x92, 0x33, 0x31, 0x76, 0x3d, 0x26, 0x52, 0xd1, 0xa3, 0x5a, 0x9c, 0x43, 0x5f, 0xc3,
-	0x7c, 0xc2, 0x79, 0xb7, 0x27, 0xd8, 0xf5, 0x94, 0x40, 0xe8, 0xb3, 0xed, 0x0c, 0xef, 0x6f, 0x60,
-	0x26, 0xd9, 0x36, 0x68, 0x6e, 0xe0, 0x3a, 0x0e, 0xf6, 0xcc, 0xb4, 0xa3, 0xe3, 0x1c, 0x7a, 0x21,
-	0x1b, 0x38, 0xad, 0x86, 0x48, 0x12, 0xca, 0xe1, 0x54, 0x3d, 0x0b, 0xf5, 0x04, 0xaa, 0xe9, 0x3c,
-	0x19, 0xdd, 0xd0, 0xa0, 0xd9, 0x1c, 0xda, 0x5c, 0xcc, 0x20, 0xb2, 0x38, 0x87, 0x3e, 0x87, 0xa9,
-	0x1a, 0x89, 0x72, 0x0d, 0x04, 0xc2, 0x58, 0xf1, 0x1f, 0x73, 0x56, 0x25, 0x13, 0xd9, 0xc6, 0x39,
-	0xb4, 0x2d, 0xcb, 0x3b, 0x48, 0x4e, 0xa3, 0x8e, 0x0b, 0x92, 0x43, 0x24, 0x4d, 0x70, 0x0e, 0xd5,
-	0xc1, 0xc8, 0x62, 0x37, 0xe8, 0x66, 0x48, 0x3c, 0xb2, 0xb9, 0x8f, 0x39, 0x93, 0x64, 0x27, 0x38,
-	0x87, 0x5e, 0x6b, 0x3a, 0x11, 0x77, 0xdb, 0xff, 0x60, 0x37, 0xf8, 0x27, 0x22, 0x3f, 0x86, 0x6a,
-	0x3a, 0x51, 0x51, 0x65, 0x1f, 0x4a, 0x62, 0xcc, 0x4a, 0x68, 0x82, 0x73, 0xe8, 0x39, 0x2c, 0x65,
-	0x58, 0x4b, 0xc6, 0x76, 0x59, 0xb8, 0x07, 0x60, 0xca, 0x65, 0xea, 0x5b, 0x4d, 0x7d, 0x2b, 0x31,
-	0xf7, 0x2d, 0x18, 0x8f, 0x70, 0x14, 0x54, 0x0d, 0xf7, 0x62, 0xa4, 0x25, 0xee, 0x73, 0xac, 0x43,
-	0xa6, 0x32, 0x2c, 0xf4, 0xbf, 0xd0, 0x74, 0x18, 0x03, 0x8b, 0x23, 0xde, 0x83, 0xc9, 0x18, 0xa9,
-	0x41, 0x46, 0xb8, 0x9b, 0xe0, 0x39, 0x71, 0xbf, 0xfb, 0x30, 0x19, 0xa3, 0x30, 0xca, 0x2f, 0x8d,
-	0xd5, 0x98, 0xb2, 0x29, 0x95, 0x0a, 0xe7, 0xd0, 0x11, 0x5c, 0xcb, 0x64, 0x32, 0x68, 0x5d, 0x98,
-	0x8e, 0x22, 0x3a, 0x09, 0xc0, 0x6d, 0x98, 0x3e, 0x24, 0xdd, 0xc4, 0x9c, 0x1a, 0x98, 0x2a, 0x19,
-	0x93, 0xe6, 0x3e, 0x20, 0xf5, 0x73, 0x6a, 0xa4, 0xff, 0xb8, 0xd2, 0xed, 0x9f, 0xb5, 0x79, 0x0f,
-	0xe7, 0xd0, 0x3e, 0x2c, 0x1e, 0x92, 0x6e, 0xea, 0x88, 0x49, 0x1b, 0x1f, 0x59, 0x33, 0xe5, 0x3b,
-	0x30, 0x55, 0xfc, 0x8f, 0x47, 0x4a, 0x24, 0xb2, 0x0d, 0x0b, 0x8f, 0x34, 0x83, 0xb8, 0xbc, 0xf3,
-	0x13, 0xa8, 0xa6, 0x53, 0x3c, 0xf5, 0x18, 0x86, 0xd2, 0xbf, 0x24, 0xd6, 0x01, 0x4c, 0xc5, 0x49,
-	0x17, 0xba, 0x26, 0x47, 0x76, 0x1a, 0xeb, 0x33, 0xcd, 0xb4, 0x2d, 0x45, 0x92, 0x70, 0x0e, 0x31,
-	0x58, 0x1e, 0x46, 0xa7, 0xd0, 0xff, 0xd5, 0xdb, 0x1a, 0xc9, 0xd7, 0xcc, 0x8d, 0xd1, 0x86, 0x61,
-	0xd0, 0x6d, 0xa8, 0xee, 0x11, 0xbb, 0xc1, 0xdd, 0xf3, 0xc1, 0x76, 0x18, 0x7c, 0xca, 0x89, 0xc3,
-	0x3f, 0x80, 0xc5, 0xbe, 0xf3, 0x47, 0x7c, 0xb8, 0x12, 0xee, 0xb7, 0xa0, 0x7c, 0x48, 0xba, 0xf2,
-	0xe1, 0x23, 0xbd, 0x25, 0x05, 0x33, 0x2a, 0xe0, 0x1c, 0xba, 0x0b, 0xa8, 0xae, 0x99, 0xd9, 0x31,
-	0xf5, 0x1b, 0x84, 0x31, 0xd7, 0x6b, 0xa6, 0x7a, 0x04, 0xc8, 0x9f, 0xc1, 0x64, 0xe0, 0xb1, 0x4f,
-	0xa9, 0x4f, 0x47, 0x19, 0x07, 0xbd, 0x94, 0x9d, 0x4b, 0xdf, 0xb8, 0x1c, 0xb0, 0x44, 0x24, 0xe7,
-	0x76, 0x94, 0xa1, 0x26, 0x13, 0xff, 0x11, 0x96, 0x86, 0x10, 0x54, 0x74, 0x2b, 0xfa, 0x01, 0xcd,
-	0x66, 0xb0, 0x26, 0x1a, 0xe4, 0x64, 0x21, 0x5d, 0x88, 0xf1, 0x55, 0xb4, 0xa4, 0x11, 0xd3, 0x58,
-	0x6c, 0x32, 0xb9, 0x1a, 0xcc, 0x0e, 0xb0, 0x54, 0xb4, 0xac, 0x01, 0x2e, 0x93, 0xc8, 0x2b, 0x30,
-	0xb2, 0xb8, 0x9b, 0xfa, 0xfe, 0x8d, 0x60, 0x76, 0xe6, 0x7c, 0x4a, 0xaf, 0x30, 0x9c, 0xdb, 0xbd,
-	0xfa, 0x43, 0x49, 0xfe, 0x9b, 0xf9, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x8f, 0x1f, 0xd9, 0xd1,
-	0xfc, 0x14, 0x00, 0x00,
+	// 2454 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x5a, 0xef, 0x52, 0xdc, 0xc8,
+	0x11, 0xdf, 0x3f, 0x5e, 0xcc, 0x36, 0x18, 0xc3, 0x00, 0xcb, 0x5a, 0xfc, 0x31, 0x1e, 0x3b, 0x0e,
+	0x77, 0xb9, 0x60, 0x87, 0x24, 0x3e, 0x57, 0x88, 0xef, 0xbc, 0x36, 0x18, 0xe3, 0xc3, 0x98, 0x68,
+	0xef, 0xec, 0xab, 0x4b, 0x55, 0x2a, 0xb2, 0x34, 0xc0, 0xc4, 0x8b, 0xb4, 0x37, 0xa3, 0x05, 0xe3,
+	0x17, 0x48, 0x9e, 0x20, 0x95, 0x2f, 0xa9, 0xca, 0xd7, 0xbc, 0x42, 0x9e, 0x23, 0x2f, 0x91, 0x47,
+	0xc8, 0xa7, 0xa4, 0xe6, 0x8f, 0xa4, 0x19, 0xad, 0xb4, 0x1c, 0xe5, 0x54, 0xbe, 0x4d, 0xf7, 0x74,
+	0xf7, 0xf4, 0xf4, 0xcc, 0x74, 0xb7, 0x7e, 0x25, 0x98, 0xe1, 0xde, 0xbd, 0x3e, 0x8b, 0xe2, 0xe8,
+	0x1e, 0xf7, 0xd6, 0xe5, 0x00, 0xd5, 0xb8, 0xe7, 0xcc, 0xfb, 0x11, 0x23, 0x7a, 0x42, 0x0c, 0xd5,
+	0x14, 0x5e, 0x85, 0x29, 0x97, 0x1c, 0x51, 0x1e, 0x33, 0x2f, 0xa6, 0x51, 0xb8, 0xbb, 0x85, 0xa6,
+	0xa0, 0x46, 0x83, 0x76, 0x75, 0xb5, 0xba, 0x56, 0x77, 0x6b, 0x34, 0xc0, 0x2b, 0x00, 0x2f, 0xba,
+	0xaf, 0xf6, 0xdf, 0x90, 0xb7, 0x5f, 0x91, 0x73, 0x34, 0x0d, 0xf5, 0x3f, 0x9c, 0xbd, 0x93, 0xd3,
+	0x93, 0xae, 0x18, 0xe2, 0x5b, 0x70, 0xbd, 0x33, 0x88, 0x8f, 0x23, 0x46, 0x3f, 0x0c, 0x9b, 0x68,
+	0x4a, 0x13, 0xff, 0xa8, 0xc2, 0xca, 0x0e, 0x89, 0x0f, 0x48, 0x18, 0xd0, 0xf0, 0xc8, 0x92, 0x76,
+	0xc9, 0xf7, 0x03, 0xc2, 0x63, 0x74, 0x17, 0xa6, 0x98, 0xe5, 0x87, 0xf6, 0x20, 0xc7, 0x15, 0x72,
+	0x34, 0x20, 0x61, 0x4c, 0x0f, 0x29, 0x61, 0x5f, 0x9f, 0xf7, 0x49, 0xbb, 0x26, 0x97, 0xc9, 0x71,
+	0xd1, 0x1a, 0x5c, 0xcf, 0x38, 0xaf, 0xbd, 0xde, 0x80, 0xb4, 0xeb, 0x52, 0x30, 0xcf, 0x46, 0x2b,
+	0x00, 0xa7, 0x5e, 0x8f, 0x06, 0xdf, 0x84, 0x31, 0xed, 0xb5, 0xaf, 0xc8, 0x55, 0x0d, 0x0e, 0xe6,
+	0xb0, 0xbc, 0x43, 0xe2, 0xd7, 0x82, 0x61, 0x79, 0xce, 0x2f, 0xeb, 0x7a, 0x1b, 0xae, 0x06, 0xd1,
+	0x89, 0x47, 0x43, 0xde, 0xae, 0xad, 0xd6, 0xd7, 0x9a, 0x6e, 0x42, 0x8a, 0xa0, 0x86, 0xd1, 0x99,
+	0x74, 0xb0, 0xee, 0x8a, 0x21, 0xfe, 0x5b, 0x15, 0x66, 0x0b, 0x96, 0x44, 0x0f, 0xa1, 0x21, 0x5d,
+	0x6b, 0x57, 0x57, 0xeb, 0x6b, 0x13, 0x1b, 0x78, 0x9d, 0x7b, 0xeb, 0x05, 0x72, 0xeb, 0x2f, 0xbd,
+	0xfe, 0x76, 0x8f, 0x9c, 0x90, 0x30, 0x76, 0x95, 0x82, 0xf3, 0x0a, 0x20, 0x63, 0xa2, 0x16, 0x8c,
+	0xa9, 0xc5, 0xf5, 0x29, 0x69, 0x0a, 0x7d, 0x02, 0x0d, 0x6f, 0x10, 0x1f, 0x7f, 0x90, 0x51, 0x9d,
+	0xd8, 0x98, 0x5d, 0x97, 0x57, 0xc5, 0x3e, 0x31, 0x25, 0x81, 0xff, 0x5d, 0x83, 0x99, 0xa7, 0x84,
+	0x89, 0x50, 0xfa, 0x5e, 0x4c, 0xba, 0xb1, 0x17, 0x0f, 0xb8, 0x30, 0xcc, 0x09, 0xa3, 0x5e, 0x2f,
+	0x31, 0xac, 0x28, 0xb4, 0x0e, 0x88, 0x0f, 0xde, 0x72, 0x9f, 0xd1, 0xb7, 0x84, 0x75, 0xfa, 0x7d,
+	0x16, 0x9d, 0x92, 0x40, 0xae, 0x32, 0xee, 0x16, 0xcc, 0x48, 0x3b, 0xd2, 0xa2, 0x3e, 0x36, 0x4d,
+	0x89, 0x73, 0x8d, 0x7c, 0xde, 0xdf, 0xf3, 0x78, 0xfc, 0x4d, 0x3f, 0xf0, 0x62, 0x12, 0xe8, 0x23,
+	0xcb, 0xb3, 0xd1, 0x2a, 0x4c, 0x30, 0x72, 0x1a, 0xbd, 0x23, 0xc1, 0x96, 0x17, 0x93, 0x76, 0x43,
+	0x4a, 0x99, 0x2c, 0x74, 0x07, 0xae, 0x69, 0xd2, 0x25, 0x1e, 0x8f, 0xc2, 0xf6, 0x98, 0x94, 0xb1,
+	0x99, 0xe8, 0x17, 0x30, 0xdf, 0xf3, 0x78, 0xbc, 0xfd, 0xbe, 0x4f, 0xd5, 0x51, 0xee, 0x7b, 0x47,
+	0x5d, 0x12, 0xc6, 0xed, 0xab, 0x52, 0xba, 0x78, 0x12, 0x61, 0x98, 0x14, 0x0e, 0xb9, 0x84, 0xf7,
+	0xa3, 0x90, 0x93, 0xf6, 0xb8, 0x7c, 0x30, 0x16, 0x0f, 0x39, 0x30, 0x1e, 0x46, 0x71, 0xe7, 0x30,
+	0x26, 0xac, 0xdd, 0x94, 0xc6, 0x52, 0x1a, 0x2d, 0x41, 0x93, 0x72, 0x69, 0x96, 0x04, 0x6d, 0x90,
+	0x61, 0xca, 0x18, 0x78, 0x15, 0xc6, 0xba, 0x2a, 0xae, 0x25, 0xf1, 0xc6, 0x9b, 0xd0, 0x70, 0xbd,
+	0xf0, 0x48, 0x2e, 0x42, 0x3c, 0xd6, 0xa3, 0x84, 0xc7, 0xfa, 0x5e, 0xa6, 0xb4, 0x50, 0xee, 0x79,
+	0xb1, 0x98, 0xa9, 0xc9, 0x19, 0x4d, 0xe1, 0x65, 0x68, 0x3c, 0x8d, 0x06, 0x61, 0x8c, 0xe6, 0xa0,
+	0xe1, 0x8b, 0x81, 0xd6, 0x54, 0x04, 0xfe, 0x16, 0x6e, 0xca, 0x69, 0xe3, 0xf4, 0xf9, 0x93, 0xf3,
+	0x7d, 0xef, 0x84, 0xa4, 0x6f, 0xe2, 0x26, 0x34, 0x98, 0x58, 0x5e, 0x2a, 0x4e, 0x6c, 0x34, 0xc5,
+	0x3d, 0x95, 0xfe, 0xb8, 0x8a, 0x2f, 0x2c, 0x87, 0x42, 0x41, 0x3f, 0x05, 0x45, 0xe0, 0x3f, 0x56,
+	0x61, 0x52, 0x9a, 0xd6, 0xe6, 0xd0, 0x97, 0x30, 0xe9, 0x1b, 0xb4, 0xbe, 0xf6, 0x8b, 0xc2, 0x9c,
+	0x29, 0x67, 0xde, 0x77, 0x4b, 0xc1, 0x79, 0x60, 0x5d, 0x7b, 0x04, 0x57, 0xc4, 0x42, 0x3a, 0x56,
+	0x72, 0x9c, 0xed, 0xb1, 0x66, 0xee, 0xf1, 0x00, 0x96, 0xe5, 0x02, 0x66, 0x72, 0xe4, 0x4f, 0xce,
+	0x77, 0x0f, 0x92, 0x1d, 0x8a, 0x1c, 0xd7, 0xd7, 0x79, 0xb0, 0x46, 0xfb, 0xd9, 0x8e, 0x6b, 0xc5,
+	0x3b, 0xc6, 0x7f, 0xaa, 0xc2, 0x2d, 0x69, 0x72, 0x37, 0x3c, 0xfd, 0xf8, 0x64, 0xe2, 0xc0, 0xf8,
+	0x71, 0xc4, 0x63, 0xb9, 0x1b, 0x95, 0x01, 0x53, 0x3a, 0x73, 0xa5, 0x5e, 0xe2, 0x4a, 0x17, 0x90,
+	0xf4, 0xe4, 0x15, 0x0b, 0x08, 0x4b, 0x97, 0x5e, 0x82, 0xa6, 0xe7, 0xcb, 0xdd, 0xa7, 0xab, 0x66,
+	0x8c, 0x8b, 0xf7, 0xf7, 0x1c, 0xe6, 0xa4, 0xd1, 0x67, 0xbf, 0xd9, 0xda, 0xef, 0x92, 0x38, 0x35,
+	0xdb, 0x82, 0xb1, 0x33, 0x1a, 0x06, 0xd1, 0x99, 0xb6, 0xa9, 0xa9, 0xf2, 0x74, 0x88, 0xef, 0xc3,
+	0x9c, 0x36, 0xb2, 0xfd, 0x9e, 0xf2, 0xcc, 0x92, 0xa1, 0x51, 0xb5, 0x35, 0x0e, 0x60, 0xf5, 0x80,
+	0x91, 0x53, 0x1a, 0x0d, 0xb8, 0x71, 0x29, 0x6d, 0xed, 0xb2, 0x94, 0x37, 0x07, 0x0d, 0x46, 0x8e,
+	0x76, 0xb7, 0x92, 0xf3, 0x97, 0x84, 0x78, 0x61, 0x4a, 0x5d, 0xe8, 0x11, 0x39, 0x92, 0x7a, 0xe3,
+	0xae, 0xa6, 0xf0, 0x57, 0xb0, 0xfc, 0xd2, 0x63, 0xef, 0x8c, 0xf5, 0xdc, 0x24, 0x6f, 0xa4, 0x0b,
+	0x16, 0xa6, 0x42, 0x04, 0x57, 0xfc, 0x28, 0x20, 0x7a, 0x3d, 0x39, 0xc6, 0xef, 0x60, 0xbe, 0x13,
+	0x04, 0x96, 0x2d, 0x65, 0x64, 0x1a, 0xea, 0x01, 0x61, 0x49, 0xbd, 0x0d, 0x08, 0x2b, 0xf6, 0x57,
+	0x18, 0x15, 0xb9, 0x45, 0x1e, 0xf9, 0xa4, 0x2b, 0xc7, 0xc2, 0x01, 0xca, 0xf9, 0x20, 0x4d, 0x91,
+	0x9a, 0xc2, 0xf7, 0xa1, 0x95, 0x5f, 0x4c, 0x67, 0x24, 0x11, 0x23, 0x7a, 0x94, 0xa4, 0x8a, 0xa6,
+	0xab, 0x29, 0xfc, 0x08, 0x6e, 0xab, 0xcd, 0xd9, 0x97, 0xf6, 0xc9, 0xf9, 0x96, 0x8c, 0xe1, 0x05,
+	0x21, 0xc6, 0xbf, 0x83, 0x3b, 0xa3, 0xd5, 0xf5, 0xf2, 0x4b, 0xd0, 0x3c, 0xa4, 0xa1, 0xd7, 0xa3,
+	0x1f, 0x48, 0xd2, 0x81, 0x64, 0x0c, 0x71, 0xfc, 0x7d, 0xd5, 0x41, 0xe8, 0xad, 0x27, 0x24, 0x5e,
+	0x81, 0x49, 0x79, 0x95, 0xcd, 0xb7, 0x69, 0xb6, 0x30, 0x7b, 0x80, 0x93, 0x12, 0x2e, 0xe5, 0x8a,
+	0x9f, 0x5e, 0x4e, 0x4b, 0xec, 0xc6, 0xf3, 0xfd, 0x38, 0x8d, 0xb4, 0xa6, 0xb0, 0x07, 0x0b, 0x3b,
+	0x44, 0xbd, 0x9d, 0x67, 0x11, 0xb3, 0xd2, 0x5e, 0xa6, 0x52, 0x35, 0x55, 0x8a, 0xb3, 0x9d, 0xdc,
+	0x10, 0x8b, 0x0e, 0x69, 0x2f, 0xe9, 0x4d, 0x12, 0x12, 0xff, 0xa5, 0x0a, 0xed, 0x1d, 0x12, 0xff,
+	0xdf, 0xfa, 0x0d, 0x51, 0x56, 0x19, 0xf9, 0x7e, 0x40, 0x19, 0x79, 0xbd, 0x21, 0x56, 0xfd, 0xc0,
+	0xe5, 0x9d, 0x19, 0x77, 0xf3, 0x6c, 0xfc, 0xe7, 0x2a, 0x4c, 0xe5, 0x9a, 0x92, 0x9f, 0x27, 0x4d,
+	0x83, 0xca, 0xce, 0xcb, 0x22, 0x35, 0x8c, 0xe8, 0x47, 0xa4, 0xec, 0xff, 0xbe, 0x1f, 0xd9, 0x83,
+	0x9b, 0x9d, 0x20, 0x28, 0xea, 0x31, 0xd3, 0xc8, 0x7d, 0x62, 0x3b, 0x3a, 0xca, 0xda, 0x1d, 0x98,
+	0xce, 0x75, 0xb5, 0x32, 0x6c, 0x34, 0x48, 0x72, 0x8f, 0x18, 0xe2, 0xbf, 0xd6, 0xe0, 0x46, 0x97,
+	0x78, 0xcc, 0x3f, 0x36, 0x6b, 0x61, 0xb2, 0x5c, 0x51, 0xb5, 0xf9, 0x0c, 0x66, 0x68, 0xe8, 0xf7,
+	0x06, 0x01, 0xe9, 0x0e, 0xde, 0x66, 0xc7, 0x23, 0x42, 0x3d, 0x3c, 0x51, 0x70, 0xd4, 0xf5, 0xc2,
+	0xa3, 0xbe, 0x65, 0xbd, 0x74, 0x2b, 0x3b, 0xeb, 0x09, 0xa3, 0xa1, 0x6a, 0x58, 0x0d, 0xd5, 0x2a,
+	0x4c, 0x78, 0xa2, 0xe3, 0x50, 0xfd, 0x84, 0x6c, 0x81, 0x9a, 0xae, 0xc9, 0x12, 0x97, 0xb7, 0x47,
+	0x4f, 0x68, 0xd2, 0xf0, 0x28, 0x02, 0xdd, 0x86, 0xab, 0x44, 0x76, 0x23, 0xbc, 0x3d, 0x9e, 0x5f,
+	0x33, 0x99, 0xc1, 0xdb, 0x30, 0x69, 0x06, 0x06, 0xfd, 0x12, 0x26, 0x7d, 0x83, 0xd6, 0xe7, 0x30,
+	0xa3, 0xce, 0xc1, 0x90, 0x74, 0x2d, 0x31, 0x7c, 0x08, 0x8e, 0x8a, 0xb2, 0x55, 0x8d, 0x8d, 0xb2,
+	0xe0, 0x47, 0x61, 0xec, 0xf9, 0x49, 0xd6, 0x4a, 0x48, 0x31, 0x23, 0x37, 0x92, 0x3e, 0xe1, 0x84,
+	0xcc, 0xf6, 0x54, 0x37, 0xf6, 0x84, 0x77, 0xe1, 0x9a, 0xb5, 0x02, 0x7a, 0x28, 0x3a, 0x44, 0x83,
+	0xa1, 0x1d, 0x46, 0xca, 0x61, 0x53, 0xd6, 0xb5, 0x05, 0x45, 0xb5, 0x47, 0xe6, 0xfc, 0xd3, 0x63,
+	0xd9, 0xf6, 0x3c, 0x80, 0x49, 0x53, 0x4e, 0xb7, 0x47, 0x45, 0xf6, 0x2c, 0x39, 0x91, 0x19, 0x7d,
+	0x69, 0x21, 0xe8, 0x24, 0x8d, 0x4a, 0xc6, 0x10, 0xb3, 0x4c, 0x05, 0x63, 0xf7, 0x40, 0x57, 0x80,
+	0x8c, 0x81, 0x77, 0x60, 0xd6, 0xb4, 0xfc, 0x9c, 0xf2, 0x38, 0x62, 0xe7, 0xe8, 0x3e, 0x5c, 0x55,
+	0x16, 0x92, 0x5d, 0xb5, 0xe4, 0x01, 0x0e, 0xf9, 0xec, 0x26, 0x62, 0xf8, 0x04, 0x66, 0xd5, 0x31,
+	0xd8, 0x7d, 0xc3, 0x25, 0xf2, 0xd1, 0xa5, 0x4e, 0xe3, 0xa7, 0x30, 0xa6, 0x16, 0x42, 0xb7, 0x61,
+	0x2c, 0x92, 0x23, 0xed, 0xe9, 0x84, 0x8a, 0x97, 0x9c, 0x75, 0xf5, 0x14, 0x1e, 0xc0, 0xa2, 0xf2,
+	0xee, 0xa3, 0xb3, 0xa6, 0xe9, 0x65, 0xf3, 0x22, 0x2f, 0x0f, 0x60, 0xc6, 0x5a, 0x70, 0x8f, 0xf2,
+	0x18, 0x6d, 0xc2, 0x94, 0x67, 0x79, 0x31, 0x2a, 0xe3, 0xe4, 0x44, 0xf1, 0xef, 0x45, 0xb5, 0x34,
+	0xee, 0xd2, 0x1b, 0x1a, 0x17, 0xa6, 0x17, 0x0c, 0x93, 0xfa, 0x9d, 0xa9, 0x4f, 0x08, 0xb5, 0x1f,
+	0x8b, 0x57, 0xd2, 0x66, 0x3f, 0x86, 0x39, 0xeb, 0x9c, 0xd5, 0x73, 0xe1, 0x43, 0x15, 0xd0, 0x78,
+	0x59, 0xba, 0x82, 0x68, 0x12, 0x7f, 0x07, 0xed, 0x22, 0x0b, 0x72, 0xf3, 0x5f, 0x14, 0x3f, 0x9a,
+	0xf6, 0xd0, 0xf5, 0xd2, 0x4a, 0xf9, 0xa7, 0x23, 0x5a, 0xaf, 0x13, 0x8f, 0xf6, 0x54, 0xeb, 0x25,
+	0x47, 0x3a, 0xe7, 0x6a, 0x0a, 0x3f, 0x86, 0x69, 0x29, 0xd1, 0x1d, 0xf4, 0xfb, 0x8c, 0x70, 0x2e,
+	0x5e, 0xc8, 0x1c, 0x34, 0xe4, 0xac, 0xce, 0x01, 0x8a, 0x10, 0x16, 0x98, 0xfa, 0xb6, 0x53, 0x87,
+	0xa9, 0x29, 0xfc, 0xcf, 0x2a, 0x4c, 0x3d, 0xed, 0x74, 0x76, 0xa3, 0x80, 0x1c, 0xba, 0xa4, 0x1f,
+	0xb1, 0xf2, 0x12, 0x64, 0xb5, 0xc5, 0xb5, 0x7c, 0x5b, 0xfc, 0x29, 0x4c, 0xcb, 0x6e, 0x5e, 0xfa,
+	0xfe, 0x92, 0xc4, 0xc7, 0x51, 0xa0, 0x8b, 0xf9, 0x10, 0x5f, 0x84, 0x93, 0x11, 0x3f, 0x62, 0x81,
+	0x2a, 0xae, 0x4d, 0x37, 0x21, 0xc5, 0x4c, 0xec, 0xb1, 0x23, 0x12, 0x8b, 0xec, 0x2c, 0x03, 0xad,
+	0x49, 0x7d, 0x24, 0x63, 0xe9, 0x91, 0x88, 0x44, 0xc0, 0x88, 0xf8, 0xc0, 0xed, 0x24, 0x09, 0x39,
+	0x63, 0xe0, 0x87, 0xb0, 0x9a, 0xe1, 0x2c, 0xf6, 0xfe, 0xd2, 0x6b, 0x93, 0x5e, 0xe3, 0xaa, 0x79,
+	0x8d, 0xbf, 0x84, 0xeb, 0x39, 0x79, 0xf4, 0x99, 0x70, 0x57, 0x0e, 0xd3, 0xb4, 0x27, 0x3e, 0xbb,
+	0x2c, 0x29, 0x37, 0x11, 0xc1, 0x7e, 0xf2, 0x51, 0x68, 0x5b, 0x11, 0x1f, 0xc3, 0xc9, 0xca, 0x9f,
+	0xc2, 0xb4, 0x3a, 0x69, 0xc2, 0x48, 0xb0, 0x65, 0xc6, 0x7a, 0x88, 0x2f, 0xbc, 0xe4, 0x34, 0xf4,
+	0x93, 0x2e, 0x59, 0x11, 0x78, 0x1f, 0x56, 0xac, 0x45, 0xb6, 0x48, 0x8f, 0x9e, 0x12, 0x46, 0xad,
+	0x0e, 0x4c, 0x85, 0x2e, 0x39, 0x45, 0x45, 0x95, 0xd8, 0xfb, 0x7b, 0x15, 0x16, 0x9f, 0xd1, 0x90,
+	0xf2, 0xe3, 0xdc, 0xb6, 0xca, 0x5b, 0x42, 0x5d, 0x44, 0x6b, 0x56, 0x11, 0x2d, 0xda, 0x59, 0xbd,
+	0x64, 0x67, 0xf2, 0x16, 0xf0, 0x41, 0x2f, 0x36, 0x6e, 0x81, 0x24, 0xc5, 0xd9, 0x06, 0x6a, 0x43,
+	0x24, 0xd0, 0xf7, 0x20, 0x63, 0x6c, 0xfc, 0xa7, 0x05, 0xd3, 0xdd, 0x38, 0x62, 0xde, 0x51, 0xd2,
+	0x46, 0xc7, 0xe7, 0x68, 0x13, 0xae, 0xef, 0x10, 0xeb, 0x23, 0x15, 0xa1, 0xfc, 0x3b, 0xdb, 0xdd,
+	0x72, 0x0a, 0x0a, 0x0c, 0xae, 0xa0, 0x5f, 0xc3, 0x5c, 0x4e, 0xf9, 0xc9, 0xb9, 0xc0, 0xf8, 0xa6,
+	0x84, 0x85, 0x0c, 0xf3, 0x2b, 0xd1, 0xfe, 0x02, 0xa6, 0xf3, 0x2d, 0x2a, 0x9a, 0x1d, 0x6a, 0xfd,
+	0x76, 0xb7, 0x9c, 0xa2, 0xa4, 0x87, 0x2b, 0xe8, 0x6b, 0x58, 0xc8, 0xee, 0xaa, 0x6d, 0x46, 0xc2,
+	0x5a, 0xa3, 0x01, 0xc3, 0x32, 0xab, 0xaf, 0xa1, 0x55, 0x8c, 0xd6, 0xa1, 0x5b, 0xda, 0x68, 0x39,
+	0x92, 0xe7, 0x2c, 0x94, 0xc0, 0x69, 0xb8, 0x82, 0x7e, 0x06, 0x53, 0x3b, 0xc4, 0x44, 0x3c, 0x10,
+	0x08, 0x61, 0xd5, 0x22, 0x39, 0xc3, 0x1d, 0x0c, 0xae, 0xa0, 0x4d, 0x19, 0xde, 0x61, 0x88, 0xcc,
+	0x54, 0x9c, 0x97, 0x4f, 0x2a, 0x2f, 0x82, 0x2b, 0xa8, 0x0b, 0xed, 0x32, 0x8c, 0x05, 0xdd, 0x4e,
+	0xe1, 0x8f, 0x72, 0x04, 0xc6, 0x99, 0xce, 0x63, 0x24, 0xb8, 0x82, 0xbe, 0x85, 0xe5, 0x02, 0xb5,
+	0xed, 0xf7, 0x9e, 0x1f, 0x7f, 0xa4, 0xe5, 0xe7, 0xd0, 0x2a, 0x86, 0x4b, 0x54, 0xd8, 0x47, 0x42,
+	0x29, 0x4e, 0x33, 0x15, 0xc1, 0x15, 0xf4, 0x12, 0x16, 0x4b, 0xa4, 0x65, 0x03, 0x75, 0x59, 0x73,
+	0x8f, 0xc0, 0x91, 0xc3, 0xc2, 0xef, 0x82, 0xc2, 0xb7, 0x62, 0xa9, 0x6f, 0xc0, 0x84, 0x81, 0x94,
+	0xa0, 0x56, 0x3a, 0x67, 0xb5, 0x40, 0xb6, 0xce, 0x01, 0x38, 0xe5, 0x38, 0x0f, 0xfa, 0x51, 0x2a,
+	0x3a, 0x0a, 0x07, 0xb2, 0x2d, 0x3e, 0x80, 0x6b, 0x16, 0xb4, 0x82, 0xda, 0xe9, 0x6c, 0x0e, 0x6d,
+	0xb1, 0xf5, 0x3e, 0x87, 0x6b, 0x16, 0x90, 0xa2, 0xf4, 0x8a, 0xb0, 0x15, 0x47, 0x5e, 0x4a, 0xc5,
+	0xc2, 0x15, 0xf4, 0x0a, 0x6e, 0x94, 0xe2, 0x29, 0xe8, 0x8e, 0x10, 0xbd, 0x08, 0x6e, 0xc9, 0x19,
+	0xbc, 0x07, 0xb3, 0x3b, 0x24, 0x4e, 0xea, 0x35, 0x09, 0x74, 0x81, 0x57, 0x42, 0x72, 0xec, 0x18,
+	0x63, 0x79, 0x55, 0x6f, 0x94, 0x56, 0x32, 0xe5, 0xc1, 0x45, 0x85, 0xce, 0x99, 0x1d, 0x2e, 0x57,
+	0xc2, 0xf2, 0x5e, 0xf2, 0xb2, 0x86, 0x0b, 0x95, 0x79, 0xff, 0x4b, 0xcb, 0x98, 0x1d, 0xe2, 0x17,
+	0xb0, 0x50, 0x52, 0x91, 0x54, 0x16, 0x1b, 0x5d, 0xae, 0x6c, 0x5b, 0x9b, 0x70, 0x7d, 0x9f, 0x9c,
+	0xe5, 0x92, 0xf9, 0x50, 0xea, 0x2d, 0x49, 0xc7, 0x9f, 0x03, 0x52, 0xc8, 0xf7, 0x85, 0xfa, 0xba,
+	0x7b, 0xde, 0x3e, 0xe9, 0xc7, 0xe7, 0xb8, 0x82, 0xb6, 0x61, 0x61, 0x9f, 0x9c, 0x15, 0xe6, 0xe1,
+	0xa2, 0x1c, 0x5b, 0x96, 0x78, 0x1f, 0x83, 0xa3, 0xd6, 0xff, 0xe1, 0x96, 0x72, 0x8e, 0x6c, 0xc2,
+	0xfc, 0x33, 0x0d, 0xf6, 0x5c, 0x5e, 0xf9, 0x05, 0xb4, 0x8a, 0xd1, 0x38, 0x95, 0x31, 0x46, 0x22,
+	0x75, 0x79, 0x5b, 0xbb, 0x30, 0x65, 0xe3, 0x63, 0xe8, 0x86, 0xac, 0x6b, 0x45, 0x00, 0x9d, 0xe3,
+	0x14, 0x4d, 0x29, 0x3c, 0x0b, 0x57, 0x10, 0x87, 0xa5, 0x51, 0xc8, 0x17, 0xfa, 0xb1, 0x4a, 0x40,
+	0x17, 0x42, 0x6b, 0xce, 0xda, 0xc5, 0x82, 0xe9, 0xa2, 0x9b, 0xd0, 0xda, 0x22, 0x9e, 0x1f, 0xd3,
+	0xd3, 0xe1, 0xeb, 0x30, 0x9c, 0xef, 0x72, 0x9b, 0x7f, 0x04, 0x0b, 0x99, 0xf2, 0x0f, 0xa8, 0xee,
+	0x39, 0xf5, 0xbb, 0x30, 0xbe, 0x4f, 0xce, 0x64, 0x76, 0x44, 0xe6, 0x67, 0x9a, 0x63, 0x12, 0xb8,
+	0x82, 0xee, 0x03, 0xea, 0x6a, 0x10, 0xed, 0x80, 0x45, 0x3e, 0xe1, 0x9c, 0x86, 0x47, 0x85, 0x1a,
+	0x89, 0xe5, 0x9f, 0xc0, 0xb5, 0x44, 0x63, 0x9b, 0xb1, 0x88, 0x5d, 0x24, 0x9c, 0xdc, 0xa5, 0x72,
+	0x5f, 0x32, 0xe1, 0xf1, 0x04, 0xd0, 0x43, 0xb2, 0xb8, 0x99, 0x60, 0x62, 0xde, 0xf1, 0xdf, 0xc2,
+	0xe2, 0x08, 0x2c, 0x11, 0xdd, 0x35, 0xbb, 0x8c, 0x72, 0xb0, 0xd1, 0x41, 0x43, 0xb1, 0xe4, 0x69,
+	0x4f, 0x65, 0x41, 0x8b, 0x68, 0x51, 0x5b, 0x2c, 0x02, 0x1c, 0xf3, 0xce, 0xed, 0xc0, 0xcc, 0x10,
+	0x6c, 0x88, 0x96, 0xb4, 0x81, 0xcb, 0x38, 0xf2, 0x06, 0xda, 0x65, 0x60, 0x9a, 0x4a, 0x92, 0x17,
+	0x40, 0x6d, 0xce, 0x5c, 0xc1, 0x5d, 0x11, 0x86, 0x7f, 0x05, 0xb3, 0x9d, 0x20, 0x18, 0xfe, 0x7a,
+	0x4b, 0x93, 0xbf, 0xc1, 0xcd, 0x9f, 0xd3, 0x03, 0x98, 0x11, 0x0f, 0xcd, 0xfe, 0x6c, 0x2b, 0xf8,
+	0x28, 0xc9, 0xeb, 0x3d, 0x83, 0xb9, 0xa2, 0x26, 0x1f, 0xdd, 0x94, 0xd5, 0xb0, 0xbc, 0xfd, 0xcf,
+	0xd9, 0xd9, 0xf8, 0x57, 0x1d, 0xe6, 0xf3, 0x1d, 0x78, 0x27, 0x38, 0xa1, 0x21, 0xda, 0x01, 0xa4,
+	0xb0, 0x07, 0x0b, 0xed, 0x5a, 0x56, 0x8d, 0x5e, 0x09, 0x3c, 0xa8, 0xfb, 0x28, 0x63, 0x42, 0xa6,
+	0xb1, 0xd9, 0x02, 0xa4, 0x0b, 0xad, 0x64, 0x96, 0x8a, 0x20, 0x30, 0x67, 0x26, 0xff, 0xae, 0xb9,
+	0xbc, 0x0c, 0xad, 0x5c, 0x7b, 0x9f, 0x40, 0x3f, 0x45, 0x69, 0x60, 0x21, 0xcf, 0xd3, 0xc2, 0xb8,
+	0x22, 0x50, 0x3b, 0x13, 0xf7, 0x41, 0x0b, 0x99, 0x37, 0x76, 0x1b, 0x04, 0xe9, 0xe3, 0xe1, 0xb2,
+	0x0f, 0x9a, 0x2b, 0x02, 0x64, 0x54, 0xd8, 0x47, 0x40, 0x35, 0xaa, 0x29, 0x1e, 0x02, 0x55, 0x70,
+	0x05, 0x51, 0x58, 0x1e, 0x89, 0x8c, 0xa0, 0xb5, 0xa1, 0x38, 0x94, 0x80, 0x27, 0xce, 0x52, 0x19,
+	0x1a, 0xa1, 0x96, 0x7a, 0x72, 0xf5, 0xbb, 0x86, 0xfc, 0x41, 0xe2, 0xbf, 0x03, 0x00, 0xbd, 0x9f,
+	0xde, 0xcb, 0x4f, 0x21, 0x00, 0x00,
 }
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/net/context"
 	"gopkg.in/go-gorp/gorp.v2"
 	jose "gopkg.in/square/go-jose.v2"
@@ -38,6 +39,12 @@ type SQLStorageAuthority struct {
 	log   blog.Logger
 	scope metrics.Scope
 
+	// dbReadOnlyMap is used by getters that can tolerate replication lag, such
+	// as the rate limit counts. It is the same as dbMap unless a read replica
+	// has been configured with UseReadReplica.
+	dbReadOnlyMap   *gorp.DbMap
+	readOnlyQueries *prometheus.CounterVec
+
 	// For RPCs that generate multiple, parallelizable SQL queries, this is the
 	// max parallelism they will use (to avoid consuming too many MariaDB
 	// threads).
@@ -99,8 +106,18 @@ func NewSQLStorageAuthority(
 ) (*SQLStorageAuthority, error) {
 	SetSQLDebug(dbMap, logger)
 
+	readOnlyQueries := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sa_read_only_queries",
+			Help: "Number of queries from replica-eligible getters, by the database pool they were sent to",
+		},
+		[]string{"pool"})
+	scope.MustRegister(readOnlyQueries)
+
 	ssa := &SQLStorageAuthority{
 		dbMap:             dbMap,
+		dbReadOnlyMap:     dbMap,
+		readOnlyQueries:   readOnlyQueries,
 		clk:               clk,
 		log:               logger,
 		scope:             scope,
@@ -113,6 +130,29 @@ func NewSQLStorageAuthority(
 	return ssa, nil
 }
 
+// UseReadReplica sends the queries of getters that can tolerate replication
+// lag to the given read-only database. These are the rate limit counts, the
+// existence checks used for renewal detection, and the search for reusable
+// authorizations, whose results are confirmed against the primary before
+// being returned. Everything else, in particular lookups of objects the
+// caller may just have created (e.g. GetOrder after NewOrder), stays on the
+// primary. It must be called before the SA starts serving requests.
+func (ssa *SQLStorageAuthority) UseReadReplica(dbMap *gorp.DbMap) {
+	SetSQLDebug(dbMap, ssa.log)
+	ssa.dbReadOnlyMap = dbMap
+}
+
+// readOnlyDb returns the database to use for a query that can tolerate
+// replication lag.
+func (ssa *SQLStorageAuthority) readOnlyDb() *gorp.DbMap {
+	pool := "replica"
+	if ssa.dbReadOnlyMap == ssa.dbMap {
+		pool = "primary"
+	}
+	ssa.readOnlyQueries.With(prometheus.Labels{"pool": pool}).Inc()
+	return ssa.dbReadOnlyMap
+}
+
 func statusIsPending(status core.AcmeStatus) bool {
 	return status == core.StatusPending || status == core.StatusProcessing || status == core.StatusUnknown
 }
@@ -300,7 +340,7 @@ func ipRange(ip net.IP) (net.IP, net.IP) {
 // time range for a single IP address.
 func (ssa *SQLStorageAuthority) CountRegistrationsByIP(ctx context.Context, ip net.IP, earliest time.Time, latest time.Time) (int, error) {
 	var count int64
-	err := ssa.dbMap.SelectOne(
+	err := ssa.readOnlyDb().SelectOne(
 		&count,
 		`SELECT COUNT(1) FROM registrations
 		 WHERE
@@ -325,7 +365,7 @@ func (ssa *SQLStorageAuthority) CountRegistrationsByIP(ctx context.Context, ip n
 func (ssa *SQLStorageAuthority) CountRegistrationsByIPRange(ctx context.Context, ip net.IP, earliest time.Time, latest time.Time) (int, error) {
 	var count int64
 	beginIP, endIP := ipRange(ip)
-	err := ssa.dbMap.SelectOne(
+	err := ssa.readOnlyDb().SelectOne(
 		&count,
 		`SELECT COUNT(1) FROM registrations
 		 WHERE
@@ -469,7 +509,7 @@ func (ssa *SQLStorageAuthority) countCertificatesByExactName(domain string, earl
 // and are not counted.
 func (ssa *SQLStorageAuthority) countCertificates(domain string, earliest, latest time.Time, query string) (int, error) {
 	var serials []string
-	_, err := ssa.dbMap.Select(
+	_, err := ssa.readOnlyDb().Select(
 		&serials,
 		query,
 		map[string]interface{}{
@@ -520,7 +560,8 @@ func (ssa *SQLStorageAuthority) countCertificates(domain string, earliest, lates
 }
 
 // GetCertificate takes a serial number and returns the corresponding
-// certificate, or error if it does not exist.
+// certificate, from the archive if it has been archived, or error if it does
+// not exist.
 func (ssa *SQLStorageAuthority) GetCertificate(ctx context.Context, serial string) (core.Certificate, error) {
 	if !core.ValidSerial(serial) {
 		err := fmt.Errorf("Invalid certificate serial %s", serial)
@@ -528,6 +569,10 @@ func (ssa *SQLStorageAuthority) GetCertificate(ctx context.Context, serial strin
 	}
 
 	cert, err := SelectCertificate(ssa.dbMap, "WHERE serial = ?", serial)
+	if err == sql.ErrNoRows {
+		// Fall back to certificates moved out by the expired-archiver
+		cert, err = SelectArchivedCertificate(ssa.dbMap, "WHERE serial = ?", serial)
+	}
 	if err == sql.ErrNoRows {
 		return core.Certificate{}, berrors.NotFoundError("certificate with serial %q not found", serial)
 	}
@@ -577,7 +622,24 @@ func (ssa *SQLStorageAuthority) NewRegistration(ctx context.Context, reg core.Re
 	if err != nil {
 		return reg, err
 	}
-	err = ssa.dbMap.Insert(rm)
+
+	tx, err := ssa.dbMap.Begin()
+	if err != nil {
+		return reg, err
+	}
+	err = tx.Insert(rm)
+	if err != nil {
+		return reg, Rollback(tx, err)
+	}
+	requestIP := core.RequestIP(ctx)
+	if requestIP == nil {
+		requestIP = reg.InitialIP
+	}
+	err = tx.Insert(regModelToHistory(rm, reg.CreatedAt, requestIP))
+	if err != nil {
+		return reg, Rollback(tx, err)
+	}
+	err = tx.Commit()
 	if err != nil {
 		return reg, err
 	}
@@ -650,15 +712,43 @@ func (ssa *SQLStorageAuthority) UpdateRegistration(ctx context.Context, reg core
 	// Copy the existing registration model's LockCol to the new updated
 	// registration model's LockCol
 	updatedRegModel.LockCol = model.LockCol
-	n, err := ssa.dbMap.Update(updatedRegModel)
+
+	tx, err := ssa.dbMap.Begin()
 	if err != nil {
 		return err
 	}
+	n, err := tx.Update(updatedRegModel)
+	if err != nil {
+		return Rollback(tx, err)
+	}
 	if n == 0 {
-		return berrors.NotFoundError("registration with ID '%d' not found", reg.ID)
+		return Rollback(tx, berrors.NotFoundError("registration with ID '%d' not found", reg.ID))
+	}
+	if registrationChanged(model, updatedRegModel) {
+		err = tx.Insert(regModelToHistory(updatedRegModel, ssa.clk.Now(), core.RequestIP(ctx)))
+		if err != nil {
+			return Rollback(tx, err)
+		}
 	}
 
-	return nil
+	return tx.Commit()
+}
+
+// registrationChanged returns true if any of the fields recorded in the
+// registration history differ between old and updated.
+func registrationChanged(old, updated *regModel) bool {
+	if old.KeySHA256 != updated.KeySHA256 ||
+		old.Agreement != updated.Agreement ||
+		old.Status != updated.Status ||
+		len(old.Contact) != len(updated.Contact) {
+		return true
+	}
+	for i := range old.Contact {
+		if old.Contact[i] != updated.Contact[i] {
+			return true
+		}
+	}
+	return false
 }
 
 // NewPendingAuthorization retrieves a pending authorization for
@@ -917,6 +1007,7 @@ func (ssa *SQLStorageAuthority) AddCertificate(
 		RevokedDate:     time.Time{},
 		RevokedReason:   0,
 		NotAfter:        parsedCertificate.NotAfter,
+		ShortLived:      len(parsedCertificate.OCSPServer) == 0,
 	}
 	if len(ocspResponse) != 0 {
 		certStatus.OCSPResponse = ocspResponse
@@ -963,7 +1054,7 @@ func (ssa *SQLStorageAuthority) AddCertificate(
 // CountPendingAuthorizations returns the number of pending, unexpired
 // authorizations for the given registration.
 func (ssa *SQLStorageAuthority) CountPendingAuthorizations(ctx context.Context, regID int64) (count int, err error) {
-	err = ssa.dbMap.SelectOne(&count,
+	err = ssa.readOnlyDb().SelectOne(&count,
 		`SELECT count(1) FROM pendingAuthorizations
 		WHERE registrationID = :regID AND
 		expires > :now AND
@@ -978,7 +1069,7 @@ func (ssa *SQLStorageAuthority) CountPendingAuthorizations(ctx context.Context,
 
 func (ssa *SQLStorageAuthority) CountOrders(ctx context.Context, acctID int64, earliest, latest time.Time) (int, error) {
 	var count int
-	err := ssa.dbMap.SelectOne(&count,
+	err := ssa.readOnlyDb().SelectOne(&count,
 		`SELECT count(1) FROM orders
 		WHERE registrationID = :acctID AND
 		created >= :windowLeft AND
@@ -1014,7 +1105,7 @@ func (ssa *SQLStorageAuthority) CountInvalidAuthorizations(
 	count = &sapb.Count{
 		Count: new(int64),
 	}
-	err = ssa.dbMap.SelectOne(count.Count,
+	err = ssa.readOnlyDb().SelectOne(count.Count,
 		`SELECT COUNT(1) FROM authz
 		WHERE registrationID = :regID AND
 		identifier = :identifier AND
@@ -1115,7 +1206,7 @@ func addIssuedNames(tx execable, cert *x509.Certificate) error {
 // |window|
 func (ssa *SQLStorageAuthority) CountFQDNSets(ctx context.Context, window time.Duration, names []string) (int64, error) {
 	var count int64
-	err := ssa.dbMap.SelectOne(
+	err := ssa.readOnlyDb().SelectOne(
 		&count,
 		`SELECT COUNT(1) FROM fqdnSets
 		WHERE setHash = ?
@@ -1150,7 +1241,7 @@ func (ssa *SQLStorageAuthority) getFQDNSetsBySerials(serials []string) ([]setHas
 	}
 	query := "SELECT setHash FROM fqdnSets " +
 		"WHERE serial IN (" + strings.Join(qmarks, ",") + ")"
-	_, err := ssa.dbMap.Select(
+	_, err := ssa.readOnlyDb().Select(
 		&fqdnSets,
 		query,
 		params...)
@@ -1195,7 +1286,7 @@ func (ssa *SQLStorageAuthority) getNewIssuancesByFQDNSet(fqdnSets []setHash, ear
 
 	// First, find the serial, sethash and issued date from the fqdnSets table for
 	// the given fqdn set hashes
-	_, err := ssa.dbMap.Select(
+	_, err := ssa.readOnlyDb().Select(
 		&results,
 		query,
 		params...)
@@ -1240,7 +1331,7 @@ func (ssa *SQLStorageAuthority) getNewIssuancesByFQDNSet(fqdnSets []setHash, ear
 // exists in the database
 func (ssa *SQLStorageAuthority) FQDNSetExists(ctx context.Context, names []string) (bool, error) {
 	var count int64
-	err := ssa.dbMap.SelectOne(
+	err := ssa.readOnlyDb().SelectOne(
 		&count,
 		`SELECT COUNT(1) FROM fqdnSets
 		WHERE setHash = ?
@@ -1268,7 +1359,7 @@ func (ssa *SQLStorageAuthority) PreviousCertificateExists(
 
 	// Find the most recently issued certificate containing this domain name.
 	var serial string
-	err := ssa.dbMap.SelectOne(
+	err := ssa.readOnlyDb().SelectOne(
 		&serial,
 		`SELECT serial FROM issuedNames
 		WHERE reversedName = ?
@@ -1285,7 +1376,7 @@ func (ssa *SQLStorageAuthority) PreviousCertificateExists(
 
 	// Check whether that certificate was issued to the specified account.
 	var count int
-	err = ssa.dbMap.SelectOne(
+	err = ssa.readOnlyDb().SelectOne(
 		&count,
 		`SELECT COUNT(1) FROM certificates
 		WHERE serial = ?
@@ -1310,13 +1401,34 @@ func (ssa *SQLStorageAuthority) PreviousCertificateExists(
 
 // DeactivateRegistration deactivates a currently valid registration
 func (ssa *SQLStorageAuthority) DeactivateRegistration(ctx context.Context, id int64) error {
-	_, err := ssa.dbMap.Exec(
+	tx, err := ssa.dbMap.Begin()
+	if err != nil {
+		return err
+	}
+	result, err := tx.Exec(
 		"UPDATE registrations SET status = ? WHERE status = ? AND id = ?",
 		string(core.StatusDeactivated),
 		string(core.StatusValid),
 		id,
 	)
-	return err
+	if err != nil {
+		return Rollback(tx, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return Rollback(tx, err)
+	}
+	if n > 0 {
+		model, err := selectRegistration(tx, "WHERE id = ?", id)
+		if err != nil {
+			return Rollback(tx, err)
+		}
+		err = tx.Insert(regModelToHistory(model, ssa.clk.Now(), core.RequestIP(ctx)))
+		if err != nil {
+			return Rollback(tx, err)
+		}
+	}
+	return tx.Commit()
 }
 
 // DeactivateAuthorization deactivates a currently valid or pending authorization
@@ -1373,6 +1485,9 @@ func (ssa *SQLStorageAuthority) NewOrder(ctx context.Context, req *corepb.Order)
 		Expires:        time.Unix(0, *req.Expires),
 		Created:        ssa.clk.Now(),
 	}
+	if req.Profile != nil {
+		order.Profile = *req.Profile
+	}
 
 	tx, err := ssa.dbMap.Begin()
 	if err != nil {
@@ -1831,8 +1946,8 @@ func (ssa *SQLStorageAuthority) GetValidOrderAuthorizations(
 }
 
 // GetOrderForNames tries to find a **pending** order with the exact set of
-// names requested, associated with the given accountID. Only unexpired orders
-// with status pending are considered. If no order meeting these requirements is
+// names requested, associated with the given accountID and issuance profile.
+// Only unexpired orders with status pending are considered. If no order meeting these requirements is
 // found a nil corepb.Order pointer is returned.
 func (ssa *SQLStorageAuthority) GetOrderForNames(
 	ctx context.Context,
@@ -1868,6 +1983,10 @@ func (ssa *SQLStorageAuthority) GetOrderForNames(
 	if *order.Status != string(core.StatusPending) {
 		return nil, berrors.NotFoundError("no order matching request found")
 	}
+	// Only return an order for the requested issuance profile
+	if order.GetProfile() != req.GetProfile() {
+		return nil, berrors.NotFoundError("no order matching request found")
+	}
 	return order, nil
 }
 
@@ -1906,7 +2025,7 @@ func (ssa *SQLStorageAuthority) getAuthorizations(
 	}
 
 	var auths []*core.Authorization
-	_, err := ssa.dbMap.Select(
+	_, err := ssa.readOnlyDb().Select(
 		&auths,
 		fmt.Sprintf(`%s
 		WHERE registrationID = ? AND
@@ -1918,6 +2037,12 @@ func (ssa *SQLStorageAuthority) getAuthorizations(
 	if err != nil {
 		return nil, err
 	}
+	// The authorizations may have been found on a lagging replica. Callers
+	// reuse them, so make sure they are still in the requested state.
+	auths, err = ssa.confirmAuthorizations(table, status, now, auths)
+	if err != nil {
+		return nil, err
+	}
 
 	byName := make(map[string]*core.Authorization)
 	for _, auth := range auths {
@@ -1946,6 +2071,38 @@ func (ssa *SQLStorageAuthority) getAuthorizations(
 	return byName, nil
 }
 
+// confirmAuthorizations re-reads the given authorizations from the primary
+// database when they were found on a read replica, and returns those that are
+// still unexpired and have the given status.
+func (ssa *SQLStorageAuthority) confirmAuthorizations(
+	table string,
+	status string,
+	now time.Time,
+	auths []*core.Authorization) ([]*core.Authorization, error) {
+	if ssa.dbReadOnlyMap == ssa.dbMap || len(auths) == 0 {
+		return auths, nil
+	}
+	params := make([]interface{}, len(auths))
+	qmarks := make([]string, len(auths))
+	for i, auth := range auths {
+		params[i] = auth.ID
+		qmarks[i] = "?"
+	}
+	var confirmed []*core.Authorization
+	_, err := ssa.dbMap.Select(
+		&confirmed,
+		fmt.Sprintf(`SELECT %s FROM %s
+		WHERE expires > ? AND
+		status = ? AND
+		id IN (%s)`,
+			authzFields, table, strings.Join(qmarks, ",")),
+		append([]interface{}{now, status}, params...)...)
+	if err != nil {
+		return nil, err
+	}
+	return confirmed, nil
+}
+
 func (ssa *SQLStorageAuthority) getPendingAuthorizations(
 	ctx context.Context,
 	registrationID int64,
@@ -2068,3 +2225,222 @@ func (ssa *SQLStorageAuthority) getChallengesImpl(authID string) ([]core.Challen
 	}
 	return challs, nil
 }
+
+// GetSuppressedEmails returns those of the given email addresses which are
+// suppressed, lowercased. It reads from the primary database so that an
+// address is never mailed after its suppression has been acknowledged.
+func (ssa *SQLStorageAuthority) GetSuppressedEmails(ctx context.Context, emails []string) ([]string, error) {
+	if len(emails) == 0 {
+		return nil, nil
+	}
+	qmarks := make([]string, len(emails))
+	params := make([]interface{}, len(emails))
+	for i, email := range emails {
+		qmarks[i] = "?"
+		params[i] = strings.ToLower(email)
+	}
+	var suppressed []string
+	_, err := ssa.dbMap.Select(
+		&suppressed,
+		fmt.Sprintf(`SELECT email FROM emailSuppressions WHERE email IN (%s)`,
+			strings.Join(qmarks, ",")),
+		params...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return suppressed, nil
+}
+
+// AddEmailSuppression suppresses an email address for the given reason. If
+// the address is already suppressed its original reason is kept.
+func (ssa *SQLStorageAuthority) AddEmailSuppression(ctx context.Context, email, reason string) error {
+	if email == "" || reason == "" {
+		return berrors.MalformedError("email and reason are required")
+	}
+	_, err := ssa.dbMap.Exec(
+		`INSERT IGNORE INTO emailSuppressions (email, reason, createdAt) VALUES (?, ?, ?)`,
+		strings.ToLower(email),
+		reason,
+		ssa.clk.Now(),
+	)
+	return err
+}
+
+const (
+	// maxIodefReportsPerAccount is the number of CAA iodef reports an account
+	// can queue in iodefReportsWindow, so that an account can't use denied
+	// validations to flood the queue.
+	maxIodefReportsPerAccount = 100
+	iodefReportsWindow        = 24 * time.Hour
+)
+
+// AddCAAIodefReport queues a report of a denied CAA check, to be sent to its
+// iodef targets by the caa-iodef-reporter. It returns a rate limit error if
+// the account has already queued maxIodefReportsPerAccount reports in the
+// last iodefReportsWindow.
+func (ssa *SQLStorageAuthority) AddCAAIodefReport(ctx context.Context, report core.CAAIodefReport) error {
+	if report.Domain == "" || len(report.Targets) == 0 {
+		return berrors.MalformedError("domain and targets are required")
+	}
+	targets, err := json.Marshal(report.Targets)
+	if err != nil {
+		return err
+	}
+	queued, err := ssa.dbMap.SelectInt(
+		`SELECT COUNT(*) FROM caaIodefReports
+		WHERE accountID = ? AND createdAt > ?`,
+		report.AccountID,
+		ssa.clk.Now().Add(-iodefReportsWindow),
+	)
+	if err != nil {
+		return err
+	}
+	if queued >= maxIodefReportsPerAccount {
+		return berrors.RateLimitError("too many CAA iodef reports queued for account %d", report.AccountID)
+	}
+	_, err = ssa.dbMap.Exec(
+		`INSERT INTO caaIodefReports
+		(domain, accountID, validationMethod, records, targets, status, createdAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		strings.ToLower(report.Domain),
+		report.AccountID,
+		report.ValidationMethod,
+		report.Records,
+		string(targets),
+		core.IodefReportPending,
+		ssa.clk.Now(),
+	)
+	return err
+}
+
+// GetPendingCAAIodefReports returns up to limit pending CAA iodef reports,
+// oldest first.
+func (ssa *SQLStorageAuthority) GetPendingCAAIodefReports(ctx context.Context, limit int) ([]core.CAAIodefReport, error) {
+	var models []struct {
+		ID               int64     `db:"id"`
+		Domain           string    `db:"domain"`
+		AccountID        int64     `db:"accountID"`
+		ValidationMethod string    `db:"validationMethod"`
+		Records          string    `db:"records"`
+		Targets          string    `db:"targets"`
+		CreatedAt        time.Time `db:"createdAt"`
+	}
+	_, err := ssa.dbMap.Select(
+		&models,
+		`SELECT id, domain, accountID, validationMethod, records, targets, createdAt
+		FROM caaIodefReports WHERE status = ? ORDER BY id LIMIT ?`,
+		core.IodefReportPending,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]core.CAAIodefReport, len(models))
+	for i, model := range models {
+		var targets []string
+		err = json.Unmarshal([]byte(model.Targets), &targets)
+		if err != nil {
+			return nil, err
+		}
+		reports[i] = core.CAAIodefReport{
+			ID:               model.ID,
+			Domain:           model.Domain,
+			AccountID:        model.AccountID,
+			ValidationMethod: model.ValidationMethod,
+			Records:          model.Records,
+			Targets:          targets,
+			CreatedAt:        model.CreatedAt,
+		}
+	}
+	return reports, nil
+}
+
+// CountCAAIodefReportsSent returns the number of CAA iodef reports for the
+// subdomains of a registered domain which were sent after since. It reads from
+// the primary, so that reports finished moments ago are counted.
+func (ssa *SQLStorageAuthority) CountCAAIodefReportsSent(ctx context.Context, registeredDomain string, since time.Time) (int, error) {
+	var count int
+	err := ssa.dbMap.SelectOne(
+		&count,
+		`SELECT COUNT(*) FROM caaIodefReports
+		WHERE registeredDomain = ? AND status = ? AND processedAt > ?`,
+		registeredDomain,
+		core.IodefReportSent,
+		since,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountCAAIodefDeliveries returns the number of deliveries of CAA iodef reports
+// to target after since. It reads from the primary, so that deliveries
+// recorded moments ago are counted.
+func (ssa *SQLStorageAuthority) CountCAAIodefDeliveries(ctx context.Context, target string, since time.Time) (int, error) {
+	var count int
+	err := ssa.dbMap.SelectOne(
+		&count,
+		`SELECT COUNT(*) FROM caaIodefDeliveries
+		WHERE targetHash = ? AND sentAt > ?`,
+		iodefTargetHash(target),
+		since,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// FinishCAAIodefReport records the outcome of processing a pending CAA iodef
+// report, and a delivery for each target it was delivered to.
+func (ssa *SQLStorageAuthority) FinishCAAIodefReport(ctx context.Context, result core.CAAIodefReportResult) error {
+	switch result.Status {
+	case core.IodefReportSent, core.IodefReportFailed, core.IodefReportRateLimited:
+	default:
+		return berrors.MalformedError("invalid CAA iodef report status %q", result.Status)
+	}
+
+	tx, err := ssa.dbMap.Begin()
+	if err != nil {
+		return err
+	}
+	now := ssa.clk.Now()
+	_, err = tx.Exec(
+		`UPDATE caaIodefReports
+		SET status = ?, processedAt = ?, results = ?, registeredDomain = ?
+		WHERE id = ? AND status = ?`,
+		result.Status,
+		now,
+		result.Results,
+		result.RegisteredDomain,
+		result.ID,
+		core.IodefReportPending,
+	)
+	if err != nil {
+		return Rollback(tx, err)
+	}
+	for _, target := range result.Delivered {
+		_, err = tx.Exec(
+			`INSERT INTO caaIodefDeliveries (reportID, target, targetHash, sentAt)
+			VALUES (?, ?, ?, ?)`,
+			result.ID,
+			target,
+			iodefTargetHash(target),
+			now,
+		)
+		if err != nil {
+			return Rollback(tx, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// iodefTargetHash is the SHA-256 hash of an iodef target, by which deliveries
+// are indexed.
+func iodefTargetHash(target string) []byte {
+	h := sha256.Sum256([]byte(target))
+	return h[:]
+}
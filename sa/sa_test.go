@@ -2,6 +2,9 @@ package sa
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"database/sql"
@@ -18,6 +21,7 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
 	gorp "gopkg.in/go-gorp/gorp.v2"
 	jose "gopkg.in/square/go-jose.v2"
 
@@ -113,6 +117,14 @@ func TestAddRegistration(t *testing.T) {
 
 	test.AssertEquals(t, dbReg.ID, newReg.ID)
 	test.AssertEquals(t, dbReg.Agreement, newReg.Agreement)
+	test.Assert(t, dbReg.ChallengeTypes == nil, "Registration had challenge preferences")
+
+	newReg.ChallengeTypes = &[]string{core.ChallengeTypeDNS01}
+	err = sa.UpdateRegistration(ctx, newReg)
+	test.AssertNotError(t, err, "Couldn't update registration's challenge preferences")
+	dbReg, err = sa.GetRegistration(ctx, reg.ID)
+	test.AssertNotError(t, err, "Couldn't get registration")
+	test.AssertDeepEquals(t, dbReg.ChallengeTypes, newReg.ChallengeTypes)
 
 	var anotherJWK jose.JSONWebKey
 	err = json.Unmarshal([]byte(anotherKey), &anotherJWK)
@@ -484,6 +496,42 @@ func TestGetValidAuthorizationsMultiple(t *testing.T) {
 	test.AssertEquals(t, wwwResult.ID, wwwAuthz.ID)
 }
 
+func TestAddCertificateShortLived(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	issued := fc.Now()
+
+	// www.eff.org.der has an OCSP URL
+	certDER, err := ioutil.ReadFile("www.eff.org.der")
+	test.AssertNotError(t, err, "Couldn't read example cert DER")
+	_, err = sa.AddCertificate(ctx, certDER, reg.ID, nil, &issued)
+	test.AssertNotError(t, err, "Couldn't add www.eff.org.der")
+	shortLived, err := sa.dbMap.SelectInt("SELECT shortLived FROM certificateStatus WHERE serial = ?",
+		"000000000000000000000000000000021bd4")
+	test.AssertNotError(t, err, "Couldn't select shortLived")
+	test.AssertEquals(t, shortLived, int64(0))
+
+	// A certificate without an OCSP URL is short-lived
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "Couldn't generate key")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1337),
+		DNSNames:     []string{"short.example.com"},
+		NotBefore:    fc.Now(),
+		NotAfter:     fc.Now().Add(7 * 24 * time.Hour),
+	}
+	certDER, err = x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	test.AssertNotError(t, err, "Couldn't create certificate")
+	_, err = sa.AddCertificate(ctx, certDER, reg.ID, nil, &issued)
+	test.AssertNotError(t, err, "Couldn't add short-lived certificate")
+	shortLived, err = sa.dbMap.SelectInt("SELECT shortLived FROM certificateStatus WHERE serial = ?",
+		core.SerialToString(template.SerialNumber))
+	test.AssertNotError(t, err, "Couldn't select shortLived")
+	test.AssertEquals(t, shortLived, int64(1))
+}
+
 func TestAddCertificate(t *testing.T) {
 	sa, clk, cleanUp := initSA(t)
 	defer cleanUp()
@@ -1848,6 +1896,21 @@ func TestGetOrderForNames(t *testing.T) {
 	// The result should be nil
 	test.Assert(t, result == nil, "sa.GetOrderForNames for diff AcctID returned non-nil result")
 
+	// Call GetOrderForNames with a different issuance profile from the NewOrder
+	// call
+	profile := "shortLived"
+	result, err = sa.GetOrderForNames(ctx, &sapb.GetOrderForNamesRequest{
+		AcctID:  &regA.ID,
+		Names:   names,
+		Profile: &profile,
+	})
+	// It should error
+	test.AssertError(t, err, "sa.GetOrderForNames did not return an error for a different profile")
+	// The error should be a notfound error
+	test.AssertEquals(t, berrors.Is(err, berrors.NotFound), true)
+	// The result should be nil
+	test.Assert(t, result == nil, "sa.GetOrderForNames for diff profile returned non-nil result")
+
 	// Advance the clock beyond the initial order's lifetime
 	fc.Add(2 * orderLifetime)
 
@@ -2195,3 +2258,235 @@ func TestGetAuthorizationsFast(t *testing.T) {
 		t.Errorf("Wrong challenge fetch count: expected 2, got %d", challengeFetchCount)
 	}
 }
+
+func TestReadReplica(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	// Use a second connection pool to the same database as the replica
+	replicaMap, err := NewDbMap(vars.DBConnSA, 0)
+	test.AssertNotError(t, err, "Failed to create replica dbMap")
+	sa.UseReadReplica(replicaMap)
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	now := fc.Now()
+	expires := now.Add(24 * time.Hour).UnixNano()
+	order, err := sa.NewOrder(ctx, &corepb.Order{
+		RegistrationID: &reg.ID,
+		Expires:        &expires,
+		Names:          []string{"example.com"},
+		Authorizations: []string{"a"},
+	})
+	test.AssertNotError(t, err, "Couldn't create new pending order")
+
+	// Lookups of just created objects stay on the primary
+	_, err = sa.GetOrder(ctx, &sapb.OrderRequest{Id: order.Id})
+	test.AssertNotError(t, err, "Couldn't get order")
+	test.AssertEquals(t, test.CountCounter(sa.readOnlyQueries.With(prometheus.Labels{"pool": "replica"})), 0)
+
+	// Rate limit counts go to the replica
+	count, err := sa.CountOrders(ctx, reg.ID, now.Add(-time.Hour), now.Add(time.Second))
+	test.AssertNotError(t, err, "Couldn't count orders")
+	test.AssertEquals(t, count, 1)
+	test.AssertEquals(t, test.CountCounter(sa.readOnlyQueries.With(prometheus.Labels{"pool": "replica"})), 1)
+	test.AssertEquals(t, test.CountCounter(sa.readOnlyQueries.With(prometheus.Labels{"pool": "primary"})), 0)
+}
+
+func TestConfirmAuthorizations(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	exp := fc.Now().AddDate(0, 0, 10)
+	pa, err := sa.NewPendingAuthorization(ctx, core.Authorization{
+		RegistrationID: reg.ID,
+		Identifier:     core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "example.com"},
+		Status:         core.StatusPending,
+		Expires:        &exp,
+	})
+	test.AssertNotError(t, err, "Couldn't create new pending authorization")
+	stale := &core.Authorization{ID: "stale", Status: core.StatusPending, Expires: &exp}
+	auths := []*core.Authorization{&pa, stale}
+
+	// Without a replica there is nothing to confirm
+	confirmed, err := sa.confirmAuthorizations(pendingAuthorizationTable, string(core.StatusPending), fc.Now(), auths)
+	test.AssertNotError(t, err, "confirmAuthorizations failed")
+	test.AssertEquals(t, len(confirmed), 2)
+
+	// With one, authorizations that are no longer pending on the primary are
+	// dropped
+	replicaMap, err := NewDbMap(vars.DBConnSA, 0)
+	test.AssertNotError(t, err, "Failed to create replica dbMap")
+	sa.UseReadReplica(replicaMap)
+	confirmed, err = sa.confirmAuthorizations(pendingAuthorizationTable, string(core.StatusPending), fc.Now(), auths)
+	test.AssertNotError(t, err, "confirmAuthorizations failed")
+	test.AssertEquals(t, len(confirmed), 1)
+	test.AssertEquals(t, confirmed[0].ID, pa.ID)
+}
+
+func TestGetArchivedCertificate(t *testing.T) {
+	sa, _, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	certDER, err := ioutil.ReadFile("www.eff.org.der")
+	test.AssertNotError(t, err, "Couldn't read example cert DER")
+	serial := "000000000000000000000000000000021bd4"
+	_, err = sa.AddCertificate(ctx, certDER, reg.ID, nil, nil)
+	test.AssertNotError(t, err, "Couldn't add www.eff.org.der")
+
+	// Move the certificate to the archive, as the expired-archiver would
+	dbMap, err := NewDbMap(vars.DBConnSAFullPerms, 0)
+	test.AssertNotError(t, err, "Couldn't create full perms dbMap")
+	_, err = dbMap.Exec("INSERT INTO certificatesArchive ("+certFields+") SELECT "+certFields+" FROM certificates WHERE serial = ?", serial)
+	test.AssertNotError(t, err, "Couldn't archive certificate")
+	_, err = dbMap.Exec("DELETE FROM certificates WHERE serial = ?", serial)
+	test.AssertNotError(t, err, "Couldn't delete certificate")
+
+	cert, err := sa.GetCertificate(ctx, serial)
+	test.AssertNotError(t, err, "Couldn't get archived certificate")
+	test.AssertByteEquals(t, cert.DER, certDER)
+
+	_, err = dbMap.Exec("DELETE FROM certificatesArchive WHERE serial = ?", serial)
+	test.AssertNotError(t, err, "Couldn't delete archived certificate")
+	_, err = sa.GetCertificate(ctx, serial)
+	test.Assert(t, berrors.Is(err, berrors.NotFound), "Expected a not found error")
+}
+
+func TestEmailSuppressions(t *testing.T) {
+	sa, _, cleanUp := initSA(t)
+	defer cleanUp()
+
+	suppressed, err := sa.GetSuppressedEmails(ctx, []string{"a@example.com", "b@example.com"})
+	test.AssertNotError(t, err, "GetSuppressedEmails failed")
+	test.AssertEquals(t, len(suppressed), 0)
+
+	err = sa.AddEmailSuppression(ctx, "A@Example.com", "bounce")
+	test.AssertNotError(t, err, "AddEmailSuppression failed")
+	// Suppressing an address twice isn't an error, and keeps the first reason
+	err = sa.AddEmailSuppression(ctx, "a@example.com", "unsubscribe")
+	test.AssertNotError(t, err, "AddEmailSuppression failed for an already suppressed address")
+	reason, err := sa.dbMap.SelectStr("SELECT reason FROM emailSuppressions WHERE email = ?", "a@example.com")
+	test.AssertNotError(t, err, "Failed to select suppression reason")
+	test.AssertEquals(t, reason, "bounce")
+
+	suppressed, err = sa.GetSuppressedEmails(ctx, []string{"a@EXAMPLE.com", "b@example.com"})
+	test.AssertNotError(t, err, "GetSuppressedEmails failed")
+	test.AssertDeepEquals(t, suppressed, []string{"a@example.com"})
+
+	err = sa.AddEmailSuppression(ctx, "", "bounce")
+	test.AssertError(t, err, "AddEmailSuppression accepted an empty address")
+}
+
+func TestAddCAAIodefReport(t *testing.T) {
+	sa, _, cleanUp := initSA(t)
+	defer cleanUp()
+
+	err := sa.AddCAAIodefReport(ctx, core.CAAIodefReport{
+		Domain:           "Example.com",
+		AccountID:        1,
+		ValidationMethod: "http-01",
+		Records:          "[]",
+		Targets:          []string{"mailto:security@example.com", "https://example.com/iodef"},
+	})
+	test.AssertNotError(t, err, "AddCAAIodefReport failed")
+
+	var row struct {
+		Domain  string
+		Targets string
+		Status  string
+	}
+	err = sa.dbMap.SelectOne(&row, "SELECT domain, targets, status FROM caaIodefReports")
+	test.AssertNotError(t, err, "Failed to select iodef report")
+	test.AssertEquals(t, row.Domain, "example.com")
+	test.AssertEquals(t, row.Targets, `["mailto:security@example.com","https://example.com/iodef"]`)
+	test.AssertEquals(t, row.Status, "pending")
+
+	err = sa.AddCAAIodefReport(ctx, core.CAAIodefReport{Domain: "example.com"})
+	test.AssertError(t, err, "AddCAAIodefReport accepted a report without targets")
+}
+
+func TestAddCAAIodefReportAccountLimit(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	report := core.CAAIodefReport{
+		Domain:    "example.com",
+		AccountID: 1,
+		Records:   "[]",
+		Targets:   []string{"mailto:security@example.com"},
+	}
+	for i := 0; i < maxIodefReportsPerAccount; i++ {
+		err := sa.AddCAAIodefReport(ctx, report)
+		test.AssertNotError(t, err, "AddCAAIodefReport failed")
+	}
+	err := sa.AddCAAIodefReport(ctx, report)
+	test.Assert(t, berrors.Is(err, berrors.RateLimit), "Expected a rate limit error over the account's limit")
+
+	// Other accounts aren't limited
+	report.AccountID = 2
+	err = sa.AddCAAIodefReport(ctx, report)
+	test.AssertNotError(t, err, "AddCAAIodefReport failed for another account")
+
+	// Once the window has passed, the account can queue reports again
+	fc.Add(iodefReportsWindow + time.Second)
+	report.AccountID = 1
+	err = sa.AddCAAIodefReport(ctx, report)
+	test.AssertNotError(t, err, "AddCAAIodefReport failed after the window")
+}
+
+func TestFinishCAAIodefReport(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	for _, domain := range []string{"example.com", "www.example.com"} {
+		err := sa.AddCAAIodefReport(ctx, core.CAAIodefReport{
+			Domain:  domain,
+			Records: "[]",
+			Targets: []string{"mailto:security@example.com", "https://example.com/iodef"},
+		})
+		test.AssertNotError(t, err, "AddCAAIodefReport failed")
+	}
+	queuedAt := fc.Now()
+
+	reports, err := sa.GetPendingCAAIodefReports(ctx, 1)
+	test.AssertNotError(t, err, "GetPendingCAAIodefReports failed")
+	test.AssertEquals(t, len(reports), 1)
+	report := reports[0]
+	test.AssertEquals(t, report.Domain, "example.com")
+	test.AssertDeepEquals(t, report.Targets, []string{"mailto:security@example.com", "https://example.com/iodef"})
+	test.Assert(t, report.CreatedAt.Equal(queuedAt), "Wrong creation time")
+
+	fc.Add(time.Hour)
+	err = sa.FinishCAAIodefReport(ctx, core.CAAIodefReportResult{
+		ID:               report.ID,
+		Status:           core.IodefReportSent,
+		RegisteredDomain: "example.com",
+		Results:          `{"mailto:security@example.com":"sent"}`,
+		Delivered:        []string{"mailto:security@example.com"},
+	})
+	test.AssertNotError(t, err, "FinishCAAIodefReport failed")
+
+	// Finished reports are no longer pending
+	reports, err = sa.GetPendingCAAIodefReports(ctx, 10)
+	test.AssertNotError(t, err, "GetPendingCAAIodefReports failed")
+	test.AssertEquals(t, len(reports), 1)
+	test.AssertEquals(t, reports[0].Domain, "www.example.com")
+
+	sent, err := sa.CountCAAIodefReportsSent(ctx, "example.com", queuedAt)
+	test.AssertNotError(t, err, "CountCAAIodefReportsSent failed")
+	test.AssertEquals(t, sent, 1)
+	sent, err = sa.CountCAAIodefReportsSent(ctx, "example.com", fc.Now())
+	test.AssertNotError(t, err, "CountCAAIodefReportsSent failed")
+	test.AssertEquals(t, sent, 0)
+
+	delivered, err := sa.CountCAAIodefDeliveries(ctx, "mailto:security@example.com", queuedAt)
+	test.AssertNotError(t, err, "CountCAAIodefDeliveries failed")
+	test.AssertEquals(t, delivered, 1)
+	delivered, err = sa.CountCAAIodefDeliveries(ctx, "https://example.com/iodef", queuedAt)
+	test.AssertNotError(t, err, "CountCAAIodefDeliveries failed")
+	test.AssertEquals(t, delivered, 0)
+
+	err = sa.FinishCAAIodefReport(ctx, core.CAAIodefReportResult{ID: reports[0].ID, Status: core.IodefReportPending})
+	test.Assert(t, berrors.Is(err, berrors.Malformed), "Expected a malformed error for a pending status")
+}
@@ -26,6 +26,7 @@ CREATE USER IF NOT EXISTS 'cert_checker'@'localhost';
 CREATE USER IF NOT EXISTS 'ocsp_update'@'localhost';
 CREATE USER IF NOT EXISTS 'test_setup'@'localhost';
 CREATE USER IF NOT EXISTS 'purger'@'localhost';
+CREATE USER IF NOT EXISTS 'archiver'@'localhost';
 
 -- Storage Authority
 GRANT SELECT,INSERT,UPDATE ON authz TO 'sa'@'localhost';
@@ -43,10 +44,17 @@ GRANT SELECT,INSERT,UPDATE ON orders TO 'sa'@'localhost';
 GRANT SELECT,INSERT ON orderToAuthz TO 'sa'@'localhost';
 GRANT SELECT,INSERT ON requestedNames TO 'sa'@'localhost';
 GRANT SELECT,INSERT,DELETE ON orderFqdnSets TO 'sa'@'localhost';
+GRANT SELECT ON goose_db_version TO 'sa'@'localhost';
+GRANT SELECT ON certificatesArchive TO 'sa'@'localhost';
+GRANT SELECT,INSERT ON registrationHistory TO 'sa'@'localhost';
+GRANT SELECT,INSERT ON emailSuppressions TO 'sa'@'localhost';
+GRANT SELECT,INSERT,UPDATE ON caaIodefReports TO 'sa'@'localhost';
+GRANT SELECT,INSERT ON caaIodefDeliveries TO 'sa'@'localhost';
 
 -- OCSP Responder
 GRANT SELECT ON certificateStatus TO 'ocsp_resp'@'localhost';
 GRANT SELECT ON ocspResponses TO 'ocsp_resp'@'localhost';
+GRANT SELECT ON certificates TO 'ocsp_resp'@'localhost';
 
 -- OCSP Generator Tool (Updater)
 GRANT INSERT ON ocspResponses TO 'ocsp_update'@'localhost';
@@ -72,5 +80,17 @@ GRANT SELECT,DELETE ON pendingAuthorizations TO 'purger'@'localhost';
 GRANT SELECT,DELETE ON authz TO 'purger'@'localhost';
 GRANT SELECT,DELETE ON challenges TO 'purger'@'localhost';
 
+-- Expired archiver
+GRANT SELECT,DELETE ON certificates TO 'archiver'@'localhost';
+GRANT SELECT,DELETE ON certificateStatus TO 'archiver'@'localhost';
+GRANT SELECT,DELETE ON issuedNames TO 'archiver'@'localhost';
+GRANT SELECT,DELETE ON authz TO 'archiver'@'localhost';
+GRANT SELECT,DELETE ON challenges TO 'archiver'@'localhost';
+GRANT SELECT,INSERT,ALTER ON certificatesArchive TO 'archiver'@'localhost';
+GRANT SELECT,INSERT ON certificateStatusArchive TO 'archiver'@'localhost';
+GRANT SELECT,INSERT ON issuedNamesArchive TO 'archiver'@'localhost';
+GRANT SELECT,INSERT,ALTER ON authzArchive TO 'archiver'@'localhost';
+GRANT SELECT,INSERT ON challengesArchive TO 'archiver'@'localhost';
+
 -- Test setup and teardown
 GRANT ALL PRIVILEGES ON * to 'test_setup'@'localhost';
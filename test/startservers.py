@@ -82,8 +82,8 @@ def start(race_detection, fakeclock=None, account_uri=None):
         ])
     progs.extend([
         [53, 'sd-test-srv --listen :53'], # Service discovery DNS server
-        [8003, 'boulder-sa --config %s --addr sa1.boulder:9095 --debug-addr :8003' % os.path.join(default_config_dir, "sa.json")],
-        [8103, 'boulder-sa --config %s --addr sa2.boulder:9095 --debug-addr :8103' % os.path.join(default_config_dir, "sa.json")],
+        [8003, 'boulder-sa --config %s --addr sa1.boulder:9095 --admin-addr sa1.boulder:9099 --debug-addr :8003' % os.path.join(default_config_dir, "sa.json")],
+        [8103, 'boulder-sa --config %s --addr sa2.boulder:9095 --admin-addr sa2.boulder:9099 --debug-addr :8103' % os.path.join(default_config_dir, "sa.json")],
         [4500, 'ct-test-srv --config test/ct-test-srv/ct-test-srv.json'],
         [8009, 'boulder-publisher --config %s --addr publisher1.boulder:9091 --debug-addr :8009' % os.path.join(default_config_dir, "publisher.json")],
         [8109, 'boulder-publisher --config %s --addr publisher2.boulder:9091 --debug-addr :8109' % os.path.join(default_config_dir, "publisher.json")],
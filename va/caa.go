@@ -3,9 +3,11 @@ package va
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 	"sync"
 
+	"github.com/letsencrypt/boulder/cmd"
 	"github.com/letsencrypt/boulder/core"
 	corepb "github.com/letsencrypt/boulder/core/proto"
 	"github.com/letsencrypt/boulder/features"
@@ -29,33 +31,41 @@ func (va *ValidationAuthorityImpl) IsCAAValid(ctx context.Context, req *vapb.IsC
 		accountURIID:     req.AccountURIID,
 		validationMethod: req.ValidationMethod,
 	}
-	if prob := va.checkCAA(ctx, acmeID, params); prob != nil {
+	record, prob := va.checkCAA(ctx, acmeID, params)
+	resp := &vapb.IsCAAValidResponse{}
+	if record != nil {
+		recordJSON, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		resp.CaaRecord = recordJSON
+	}
+	if prob != nil {
 		typ := string(prob.Type)
 		detail := fmt.Sprintf("While processing CAA for %s: %s", *req.Domain, prob.Detail)
-		return &vapb.IsCAAValidResponse{
-			Problem: &corepb.ProblemDetails{
-				ProblemType: &typ,
-				Detail:      &detail,
-			},
-		}, nil
-	}
-	return &vapb.IsCAAValidResponse{}, nil
+		resp.Problem = &corepb.ProblemDetails{
+			ProblemType: &typ,
+			Detail:      &detail,
+		}
+	}
+	return resp, nil
 }
 
 // checkCAA performs a CAA lookup & validation for the provided identifier. If
-// the CAA lookup & validation fail a problem is returned.
+// the CAA lookup & validation fail a problem is returned. Unless the lookup
+// failed, a record of the decision is returned as well.
 func (va *ValidationAuthorityImpl) checkCAA(
 	ctx context.Context,
 	identifier core.AcmeIdentifier,
-	params *caaParams) *probs.ProblemDetails {
-	present, valid, records, err := va.checkCAARecords(ctx, identifier, params)
+	params *caaParams) (*core.CAAValidationRecord, *probs.ProblemDetails) {
+	present, valid, caaRecord, records, err := va.checkCAARecords(ctx, identifier, params)
 	if err != nil {
-		return probs.DNS("%v", err)
+		return nil, probs.DNS("%v", err)
 	}
 
 	recordsStr, err := json.Marshal(&records)
 	if err != nil {
-		return probs.CAA("CAA records for %s were malformed", identifier.Value)
+		return nil, probs.CAA("CAA records for %s were malformed", identifier.Value)
 	}
 
 	accountID, challengeType := "unknown", "unknown"
@@ -66,12 +76,47 @@ func (va *ValidationAuthorityImpl) checkCAA(
 		challengeType = *params.validationMethod
 	}
 
-	va.log.AuditInfof("Checked CAA records for %s, [Present: %t, Account ID: %s, Challenge: %s, Valid for issuance: %t] Records=%s",
-		identifier.Value, present, accountID, challengeType, valid, recordsStr)
+	identity := caaRecord.Identity
+	if identity == "" {
+		identity = "none"
+	}
+
+	va.log.AuditInfof("Checked CAA records for %s, [Present: %t, Account ID: %s, Challenge: %s, Valid for issuance: %t, CAA identity: %s] Records=%s",
+		identifier.Value, present, accountID, challengeType, valid, identity, recordsStr)
 	if !valid {
-		return probs.CAA("CAA record for %s prevents issuance", identifier.Value)
+		if len(caaRecord.Iodef) > 0 {
+			va.stats.Inc("CAA.DeniedWithIodef", 1)
+		}
+		return caaRecord, probs.CAA("CAA record for %s prevents issuance", identifier.Value)
 	}
-	return nil
+	return caaRecord, nil
+}
+
+// iodefTargets returns the URLs of the iodef records in records which can be
+// reported to: mailto: addresses and https: URLs. Other schemes, including
+// http:, and malformed values are ignored.
+func iodefTargets(records []*dns.CAA) []string {
+	var targets []string
+	for _, caa := range newCAASet(records).Iodef {
+		u, err := url.Parse(strings.TrimSpace(caa.Value))
+		if err != nil {
+			continue
+		}
+		switch strings.ToLower(u.Scheme) {
+		case "mailto":
+			if !strings.Contains(u.Opaque, "@") {
+				continue
+			}
+		case "https":
+			if u.Host == "" {
+				continue
+			}
+		default:
+			continue
+		}
+		targets = append(targets, u.String())
+	}
+	return targets
 }
 
 // CAASet consists of filtered CAA records
@@ -121,21 +166,30 @@ func newCAASet(CAAs []*dns.CAA) *CAASet {
 	return &filtered
 }
 
+// caaResult is the result of the CAA lookup for a single name, made using the
+// DNS server at address server.
 type caaResult struct {
+	name    string
+	server  string
 	records []*dns.CAA
 	err     error
 }
 
-func parseResults(results []caaResult) (*CAASet, []*dns.CAA, error) {
-	// Return first result
-	for _, res := range results {
+// parseResults returns the first result with CAA records, i.e. the one for the
+// name closest to the checked name, and its filtered records. If no result has
+// records, the first result is returned with a nil *CAASet.
+func parseResults(results []caaResult) (*CAASet, *caaResult, error) {
+	for i, res := range results {
 		if res.err != nil {
 			return nil, nil, res.err
 		}
 		if len(res.records) > 0 {
-			return newCAASet(res.records), res.records, nil
+			return newCAASet(res.records), &results[i], nil
 		}
 	}
+	if len(results) > 0 {
+		return nil, &results[0], nil
+	}
 	return nil, nil, nil
 }
 
@@ -148,7 +202,8 @@ func (va *ValidationAuthorityImpl) parallelCAALookup(ctx context.Context, name s
 		// Start the concurrent DNS lookup.
 		wg.Add(1)
 		go func(name string, r *caaResult) {
-			r.records, r.err = va.dnsClient.LookupCAA(ctx, name)
+			r.name = name
+			r.records, r.server, r.err = va.dnsClient.LookupCAA(ctx, name)
 			wg.Done()
 		}(strings.Join(labels[i:], "."), &results[i])
 	}
@@ -157,7 +212,7 @@ func (va *ValidationAuthorityImpl) parallelCAALookup(ctx context.Context, name s
 	return results
 }
 
-func (va *ValidationAuthorityImpl) getCAASet(ctx context.Context, hostname string) (*CAASet, []*dns.CAA, error) {
+func (va *ValidationAuthorityImpl) getCAASet(ctx context.Context, hostname string) (*CAASet, *caaResult, error) {
 	hostname = strings.TrimRight(hostname, ".")
 
 	// See RFC 6844 "Certification Authority Processing" for pseudocode, as
@@ -177,16 +232,17 @@ func (va *ValidationAuthorityImpl) getCAASet(ctx context.Context, hostname strin
 // validates them. If the identifier argument's value has a wildcard prefix then
 // the prefix is stripped and validation will be performed against the base
 // domain, honouring any issueWild CAA records encountered as apppropriate.
-// checkCAARecords returns four values: the first is a bool indicating whether
+// checkCAARecords returns five values: the first is a bool indicating whether
 // CAA records were present after filtering for known/supported CAA tags. The
-// second is a bool indicating whether issuance for the identifier is valid. The
-// unmodified *dns.CAA records that were processed/filtered are returned as the
-// third argument. Any  errors encountered are returned as the fourth return
-// value (or nil).
+// second is a bool indicating whether issuance for the identifier is valid.
+// The third is a record of the decision, including the CAA identity found in
+// the records which authorized issuance, if any. The unmodified *dns.CAA
+// records that were processed/filtered are returned as the fourth argument.
+// Any errors encountered are returned as the fifth return value (or nil).
 func (va *ValidationAuthorityImpl) checkCAARecords(
 	ctx context.Context,
 	identifier core.AcmeIdentifier,
-	params *caaParams) (bool, bool, []*dns.CAA, error) {
+	params *caaParams) (bool, bool, *core.CAAValidationRecord, []*dns.CAA, error) {
 	hostname := strings.ToLower(identifier.Value)
 	// If this is a wildcard name, remove the prefix
 	var wildcard bool
@@ -194,12 +250,45 @@ func (va *ValidationAuthorityImpl) checkCAARecords(
 		hostname = strings.TrimPrefix(identifier.Value, `*.`)
 		wildcard = true
 	}
-	caaSet, records, err := va.getCAASet(ctx, hostname)
+	caaSet, result, err := va.getCAASet(ctx, hostname)
 	if err != nil {
-		return false, false, nil, err
+		return false, false, nil, nil, err
+	}
+	present, valid, matched, identity := va.validateCAASet(caaSet, wildcard, params)
+
+	record := &core.CAAValidationRecord{
+		QueryName: hostname,
+		Identity:  identity,
+		Present:   present,
+		Valid:     valid,
+		CheckedAt: va.clk.Now(),
+	}
+	var records []*dns.CAA
+	if result != nil {
+		records = result.records
+		record.QueryName = result.name
+		record.Resolver = result.server
+		for _, caa := range records {
+			record.RRSet = append(record.RRSet, caaPresentation(caa))
+		}
 	}
-	present, valid := va.validateCAASet(caaSet, wildcard, params)
-	return present, valid, records, nil
+	if matched != nil {
+		record.MatchedRecord = caaPresentation(matched)
+		_, parameters := extractIssuerDomainAndParameters(matched)
+		if len(parameters) > 0 {
+			record.Parameters = parameters
+		}
+	}
+	if !valid {
+		record.Iodef = iodefTargets(records)
+	}
+	return present, valid, record, records, nil
+}
+
+// caaPresentation returns the flag, tag and value of a CAA record in
+// presentation format, e.g. `0 issue "letsencrypt.org"`.
+func caaPresentation(caa *dns.CAA) string {
+	return fmt.Sprintf("%d %s %q", caa.Flag, caa.Tag, caa.Value)
 }
 
 func containsMethod(commaSeparatedMethods, method string) bool {
@@ -211,19 +300,51 @@ func containsMethod(commaSeparatedMethods, method string) bool {
 	return false
 }
 
+// caaIdentity returns the configured CAA identity matching the issuer domain
+// of a CAA record, or nil if there is none.
+func (va *ValidationAuthorityImpl) caaIdentity(issuerDomain string) *cmd.CAAIdentityConfig {
+	issuerDomain = strings.ToLower(issuerDomain)
+	for i := range va.caaIdentities {
+		if va.caaIdentities[i].Domain == issuerDomain {
+			return &va.caaIdentities[i]
+		}
+	}
+	return nil
+}
+
+// identityAccountURIPrefixes returns the VA's account URI prefixes which name
+// accounts a CAA identity may authorize: all of them for an unscoped identity,
+// and those beginning with one of its AccountURIPrefixes for a scoped one.
+func (va *ValidationAuthorityImpl) identityAccountURIPrefixes(identity *cmd.CAAIdentityConfig) []string {
+	if len(identity.AccountURIPrefixes) == 0 {
+		return va.accountURIPrefixes
+	}
+	var prefixes []string
+	for _, prefix := range va.accountURIPrefixes {
+		for _, scope := range identity.AccountURIPrefixes {
+			if strings.HasPrefix(prefix, scope) {
+				prefixes = append(prefixes, prefix)
+				break
+			}
+		}
+	}
+	return prefixes
+}
+
 // validateCAASet checks a provided *CAASet. When the wildcard argument is true
 // this means the CAASet's issueWild records must be validated as well. This
 // function returns two booleans: the first indicates whether the CAASet was
 // empty, the second indicates whether the CAASet is valid for issuance to
-// proceed.
-func (va *ValidationAuthorityImpl) validateCAASet(caaSet *CAASet, wildcard bool, params *caaParams) (present, valid bool) {
+// proceed. If a record naming one of our CAA identities authorized issuance,
+// that record and identity are returned too.
+func (va *ValidationAuthorityImpl) validateCAASet(caaSet *CAASet, wildcard bool, params *caaParams) (present, valid bool, matched *dns.CAA, identity string) {
 	if caaSet == nil {
 		// No CAA records found, can issue
 		va.stats.Inc("CAA.None", 1)
-		return false, true
+		return false, true, nil, ""
 	}
 
-	// Record stats on directives not currently processed.
+	// Iodef records are only used to report denials, see iodefTargets.
 	if len(caaSet.Iodef) > 0 {
 		va.stats.Inc("CAA.WithIodef", 1)
 	}
@@ -231,7 +352,7 @@ func (va *ValidationAuthorityImpl) validateCAASet(caaSet *CAASet, wildcard bool,
 	if caaSet.criticalUnknown() {
 		// Contains unknown critical directives.
 		va.stats.Inc("CAA.UnknownCritical", 1)
-		return true, false
+		return true, false, nil, ""
 	}
 
 	if len(caaSet.Unknown) > 0 {
@@ -244,7 +365,7 @@ func (va *ValidationAuthorityImpl) validateCAASet(caaSet *CAASet, wildcard bool,
 		// non-wildcard identifier, or there is only an iodef or non-critical unknown
 		// directive.)
 		va.stats.Inc("CAA.NoneRelevant", 1)
-		return true, true
+		return true, true, nil, ""
 	}
 
 	// Per RFC 6844 Section 5.3 "issueWild properties MUST be ignored when
@@ -261,12 +382,28 @@ func (va *ValidationAuthorityImpl) validateCAASet(caaSet *CAASet, wildcard bool,
 	// includes the case of the unsatisfiable CAA record value ";", used to
 	// prevent issuance by any CA under any circumstance.
 	//
-	// Our CAA identity must be found in the chosen checkSet.
+	// One of our CAA identities must be found in the chosen checkSet.
 	for _, caa := range records {
 		caaIssuerDomain, caaParameters := extractIssuerDomainAndParameters(caa)
-		if caaIssuerDomain != va.issuerDomain {
+		caaIdentity := va.caaIdentity(caaIssuerDomain)
+		if caaIdentity == nil {
 			continue
 		}
+		// An identity scoped by account URI prefixes only authorizes the
+		// account named by the record's accounturi parameter, which must be
+		// under one of its prefixes. The VA can't tell which of its prefixes
+		// the requesting account was created under otherwise, so a record
+		// without accounturi doesn't authorize any account.
+		prefixes := va.identityAccountURIPrefixes(caaIdentity)
+		if len(caaIdentity.AccountURIPrefixes) > 0 {
+			caaAccountURI, ok := caaParameters["accounturi"]
+			if !ok || !features.Enabled(features.CAAAccountURI) || params.accountURIID == nil {
+				continue
+			}
+			if !checkAccountURI(caaAccountURI, prefixes, *params.accountURIID) {
+				continue
+			}
+		}
 
 		if features.Enabled(features.CAAAccountURI) {
 			// Check the accounturi CAA parameter as defined
@@ -277,7 +414,7 @@ func (va *ValidationAuthorityImpl) validateCAASet(caaSet *CAASet, wildcard bool,
 				if params.accountURIID == nil {
 					continue
 				}
-				if !checkAccountURI(caaAccountURI, va.accountURIPrefixes, *params.accountURIID) {
+				if !checkAccountURI(caaAccountURI, prefixes, *params.accountURIID) {
 					continue
 				}
 			}
@@ -298,12 +435,12 @@ func (va *ValidationAuthorityImpl) validateCAASet(caaSet *CAASet, wildcard bool,
 		}
 
 		va.stats.Inc("CAA.Authorized", 1)
-		return true, true
+		return true, true, caa, caaIdentity.Domain
 	}
 
 	// The list of authorized issuers is non-empty, but we are not in it. Fail.
 	va.stats.Inc("CAA.Unauthorized", 1)
-	return true, false
+	return true, false, nil, ""
 }
 
 // checkAccountURI checks the specified full account URI against the
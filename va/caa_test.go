@@ -2,14 +2,18 @@ package va
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/jmhodges/clock"
 	"github.com/miekg/dns"
 
+	"github.com/letsencrypt/boulder/cmd"
 	"github.com/letsencrypt/boulder/core"
 	"github.com/letsencrypt/boulder/features"
 	"github.com/letsencrypt/boulder/probs"
@@ -36,7 +40,12 @@ func (mock caaMockDNS) LookupMX(_ context.Context, domain string) ([]string, err
 	return nil, nil
 }
 
-func (mock caaMockDNS) LookupCAA(_ context.Context, domain string) ([]*dns.CAA, error) {
+func (mock caaMockDNS) LookupCAA(_ context.Context, domain string) ([]*dns.CAA, string, error) {
+	records, err := caaMockRecords(domain)
+	return records, "127.0.0.1:53", err
+}
+
+func caaMockRecords(domain string) ([]*dns.CAA, error) {
 	var results []*dns.CAA
 	var record dns.CAA
 	switch strings.TrimRight(domain, ".") {
@@ -160,6 +169,37 @@ func (mock caaMockDNS) LookupCAA(_ context.Context, domain string) ([]*dns.CAA,
 		record.Tag = "issuewild"
 		record.Value = "letsencrypt.org"
 		results = append(results, &record)
+	case "old-identity.com":
+		record.Tag = "issue"
+		record.Value = "OldName.example"
+		results = append(results, &record)
+	case "partner-accounturi.com":
+		record.Tag = "issue"
+		record.Value = "partner.example; accounturi=https://acme.partner.example/acct/123"
+		results = append(results, &record)
+	case "partner.com":
+		record.Tag = "issue"
+		record.Value = "partner.example"
+		results = append(results, &record)
+	case "partner-our-accounturi.com":
+		record.Tag = "issue"
+		record.Value = "partner.example; accounturi=https://letsencrypt.org/acct/reg/123"
+		results = append(results, &record)
+	case "reserved-iodef.com", "present-iodef.com":
+		record.Tag = "issue"
+		record.Value = "ca.com"
+		if domain == "present-iodef.com" {
+			record.Value = "letsencrypt.org"
+		}
+		results = append(results, &record)
+		for _, iodef := range []string{
+			"mailto:security@reserved-iodef.com",
+			"https://reserved-iodef.com/iodef",
+			"http://reserved-iodef.com/insecure",
+			"mailto:nobody",
+		} {
+			results = append(results, &dns.CAA{Tag: "iodef", Value: iodef})
+		}
 	}
 	return results, nil
 }
@@ -167,7 +207,7 @@ func (mock caaMockDNS) LookupCAA(_ context.Context, domain string) ([]*dns.CAA,
 func TestCAATimeout(t *testing.T) {
 	va, _ := setup(nil, 0)
 	va.dnsClient = caaMockDNS{}
-	err := va.checkCAA(ctx, core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "caa-timeout.com"}, nil)
+	_, err := va.checkCAA(ctx, core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "caa-timeout.com"}, nil)
 	if err.Type != probs.DNSProblem {
 		t.Errorf("Expected timeout error type %s, got %s", probs.DNSProblem, err.Type)
 	}
@@ -359,7 +399,7 @@ func TestCAAChecking(t *testing.T) {
 		mockLog.Clear()
 		t.Run(caaTest.Name, func(t *testing.T) {
 			ident := core.AcmeIdentifier{Type: "dns", Value: caaTest.Domain}
-			present, valid, _, err := va.checkCAARecords(ctx, ident, params)
+			present, valid, _, _, err := va.checkCAARecords(ctx, ident, params)
 			if err != nil {
 				t.Errorf("checkCAARecords error for %s: %s", caaTest.Domain, err)
 			}
@@ -377,41 +417,41 @@ func TestCAAChecking(t *testing.T) {
 
 	// present-dns-only.com should now be valid even with http-01
 	ident := core.AcmeIdentifier{Type: "dns", Value: "present-dns-only.com"}
-	present, valid, _, err := va.checkCAARecords(ctx, ident, params)
+	present, valid, _, _, err := va.checkCAARecords(ctx, ident, params)
 	test.AssertNotError(t, err, "present-dns-only.com")
 	test.Assert(t, present, "Present should be true")
 	test.Assert(t, valid, "Valid should be true")
 
 	// present-incorrect-accounturi.com should now be also be valid
 	ident = core.AcmeIdentifier{Type: "dns", Value: "present-incorrect-accounturi.com"}
-	present, valid, _, err = va.checkCAARecords(ctx, ident, params)
+	present, valid, _, _, err = va.checkCAARecords(ctx, ident, params)
 	test.AssertNotError(t, err, "present-incorrect-accounturi.com")
 	test.Assert(t, present, "Present should be true")
 	test.Assert(t, valid, "Valid should be true")
 
 	// nil params should be valid, too
-	present, valid, _, err = va.checkCAARecords(ctx, ident, nil)
+	present, valid, _, _, err = va.checkCAARecords(ctx, ident, nil)
 	test.AssertNotError(t, err, "present-dns-only.com")
 	test.Assert(t, present, "Present should be true")
 	test.Assert(t, valid, "Valid should be true")
 
 	ident.Value = "servfail.com"
-	present, valid, _, err = va.checkCAARecords(ctx, ident, nil)
+	present, valid, _, _, err = va.checkCAARecords(ctx, ident, nil)
 	test.AssertError(t, err, "servfail.com")
 	test.Assert(t, !present, "Present should be false")
 	test.Assert(t, !valid, "Valid should be false")
 
-	if _, _, _, err := va.checkCAARecords(ctx, ident, nil); err == nil {
+	if _, _, _, _, err := va.checkCAARecords(ctx, ident, nil); err == nil {
 		t.Errorf("Should have returned error on CAA lookup, but did not: %s", ident.Value)
 	}
 
 	ident.Value = "servfail.present.com"
-	present, valid, _, err = va.checkCAARecords(ctx, ident, nil)
+	present, valid, _, _, err = va.checkCAARecords(ctx, ident, nil)
 	test.AssertError(t, err, "servfail.present.com")
 	test.Assert(t, !present, "Present should be false")
 	test.Assert(t, !valid, "Valid should be false")
 
-	if _, _, _, err := va.checkCAARecords(ctx, ident, nil); err == nil {
+	if _, _, _, _, err := va.checkCAARecords(ctx, ident, nil); err == nil {
 		t.Errorf("Should have returned error on CAA lookup, but did not: %s", ident.Value)
 	}
 }
@@ -434,55 +474,55 @@ func TestCAALogging(t *testing.T) {
 		{
 			Domain:          "reserved.com",
 			ChallengeType:   nil,
-			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for reserved.com, [Present: true, Account ID: unknown, Challenge: unknown, Valid for issuance: false] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issue\",\"Value\":\"ca.com\"}]",
+			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for reserved.com, [Present: true, Account ID: unknown, Challenge: unknown, Valid for issuance: false, CAA identity: none] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issue\",\"Value\":\"ca.com\"}]",
 		},
 		{
 			Domain:          "reserved.com",
 			AccountURIID:    &acctID,
 			ChallengeType:   &httpChal,
-			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for reserved.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: false] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issue\",\"Value\":\"ca.com\"}]",
+			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for reserved.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: false, CAA identity: none] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issue\",\"Value\":\"ca.com\"}]",
 		},
 		{
 			Domain:          "reserved.com",
 			AccountURIID:    &acctID,
 			ChallengeType:   &dnsChal,
-			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for reserved.com, [Present: true, Account ID: 12345, Challenge: dns-01, Valid for issuance: false] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issue\",\"Value\":\"ca.com\"}]",
+			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for reserved.com, [Present: true, Account ID: 12345, Challenge: dns-01, Valid for issuance: false, CAA identity: none] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issue\",\"Value\":\"ca.com\"}]",
 		},
 		{
 			Domain:          "mixedcase.com",
 			AccountURIID:    &acctID,
 			ChallengeType:   &httpChal,
-			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for mixedcase.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: false] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"iSsUe\",\"Value\":\"ca.com\"}]",
+			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for mixedcase.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: false, CAA identity: none] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"iSsUe\",\"Value\":\"ca.com\"}]",
 		},
 		{
 			Domain:          "critical.com",
 			AccountURIID:    &acctID,
 			ChallengeType:   &httpChal,
-			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for critical.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: false] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":1,\"Tag\":\"issue\",\"Value\":\"ca.com\"}]",
+			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for critical.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: false, CAA identity: none] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":1,\"Tag\":\"issue\",\"Value\":\"ca.com\"}]",
 		},
 		{
 			Domain:          "present.com",
 			AccountURIID:    &acctID,
 			ChallengeType:   &httpChal,
-			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for present.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: true] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issue\",\"Value\":\"letsencrypt.org\"}]",
+			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for present.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: true, CAA identity: letsencrypt.org] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issue\",\"Value\":\"letsencrypt.org\"}]",
 		},
 		{
 			Domain:          "multi-crit-present.com",
 			AccountURIID:    &acctID,
 			ChallengeType:   &httpChal,
-			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for multi-crit-present.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: true] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":1,\"Tag\":\"issue\",\"Value\":\"ca.com\"},{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":1,\"Tag\":\"issue\",\"Value\":\"letsencrypt.org\"}]",
+			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for multi-crit-present.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: true, CAA identity: letsencrypt.org] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":1,\"Tag\":\"issue\",\"Value\":\"ca.com\"},{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":1,\"Tag\":\"issue\",\"Value\":\"letsencrypt.org\"}]",
 		},
 		{
 			Domain:          "present-with-parameter.com",
 			AccountURIID:    &acctID,
 			ChallengeType:   &httpChal,
-			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for present-with-parameter.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: true] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issue\",\"Value\":\"  letsencrypt.org  ;foo=bar;baz=bar\"}]",
+			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for present-with-parameter.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: true, CAA identity: letsencrypt.org] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issue\",\"Value\":\"  letsencrypt.org  ;foo=bar;baz=bar\"}]",
 		},
 		{
 			Domain:          "satisfiable-wildcard-override.com",
 			AccountURIID:    &acctID,
 			ChallengeType:   &httpChal,
-			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for satisfiable-wildcard-override.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: false] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issue\",\"Value\":\"ca.com\"},{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issuewild\",\"Value\":\"letsencrypt.org\"}]",
+			ExpectedLogline: "INFO: [AUDIT] Checked CAA records for satisfiable-wildcard-override.com, [Present: true, Account ID: 12345, Challenge: http-01, Valid for issuance: false, CAA identity: none] Records=[{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issue\",\"Value\":\"ca.com\"},{\"Hdr\":{\"Name\":\"\",\"Rrtype\":0,\"Class\":0,\"Ttl\":0,\"Rdlength\":0},\"Flag\":0,\"Tag\":\"issuewild\",\"Value\":\"letsencrypt.org\"}]",
 		},
 	}
 
@@ -495,7 +535,7 @@ func TestCAALogging(t *testing.T) {
 				accountURIID:     tc.AccountURIID,
 				validationMethod: tc.ChallengeType,
 			}
-			_ = va.checkCAA(ctx, core.AcmeIdentifier{Type: core.IdentifierDNS, Value: tc.Domain}, params)
+			_, _ = va.checkCAA(ctx, core.AcmeIdentifier{Type: core.IdentifierDNS, Value: tc.Domain}, params)
 
 			caaLogLines := mockLog.GetAllMatching(`Checked CAA records for`)
 			if len(caaLogLines) != 1 {
@@ -539,7 +579,7 @@ func TestCAAFailure(t *testing.T) {
 	va, _ := setup(hs, 0)
 	va.dnsClient = caaMockDNS{}
 
-	_, prob := va.validate(ctx, dnsi("reserved.com"), chall, core.Authorization{})
+	_, _, _, prob := va.validate(ctx, dnsi("reserved.com"), chall, core.Authorization{})
 	if prob == nil {
 		t.Fatalf("Expected CAA rejection for reserved.com, got success")
 	}
@@ -548,26 +588,35 @@ func TestCAAFailure(t *testing.T) {
 
 func TestParseResults(t *testing.T) {
 	r := []caaResult{}
-	s, records, err := parseResults(r)
+	s, result, err := parseResults(r)
 	test.Assert(t, s == nil, "set is not nil")
 	test.Assert(t, err == nil, "error is not nil")
-	test.Assert(t, records == nil, "records is not nil")
+	test.Assert(t, result == nil, "result is not nil")
 	test.AssertNotError(t, err, "no error should be returned")
-	r = []caaResult{{nil, errors.New("")}, {[]*dns.CAA{{Value: "test"}}, nil}}
-	s, records, err = parseResults(r)
+	r = []caaResult{{records: nil, err: errors.New("")}, {records: []*dns.CAA{{Value: "test"}}}}
+	s, result, err = parseResults(r)
 	test.Assert(t, s == nil, "set is not nil")
 	test.AssertEquals(t, err.Error(), "")
+	test.Assert(t, result == nil, "result is not nil")
 	expected := dns.CAA{Value: "other-test"}
-	test.AssertEquals(t, len(records), 0)
-	r = []caaResult{{[]*dns.CAA{&expected}, nil}, {[]*dns.CAA{{Value: "test"}}, nil}}
-	s, records, err = parseResults(r)
+	r = []caaResult{
+		{name: "a.example.com", server: "1.1.1.1:53", records: []*dns.CAA{&expected}},
+		{name: "example.com", server: "2.2.2.2:53", records: []*dns.CAA{{Value: "test"}}},
+	}
+	s, result, err = parseResults(r)
 	test.AssertEquals(t, len(s.Unknown), 1)
 	test.Assert(t, s.Unknown[0] == &expected, "Incorrect record returned")
 	test.AssertNotError(t, err, "no error should be returned")
-	test.AssertEquals(t, len(records), len(r[0].records))
-	for i, rec := range records {
-		test.AssertEquals(t, rec.String(), r[0].records[i].String())
+	test.Assert(t, result == &r[0], "Incorrect result returned")
+	// Without any records, the result for the checked name is returned
+	r = []caaResult{
+		{name: "a.example.com", server: "1.1.1.1:53"},
+		{name: "example.com", server: "2.2.2.2:53"},
 	}
+	s, result, err = parseResults(r)
+	test.AssertNotError(t, err, "no error should be returned")
+	test.Assert(t, s == nil, "set is not nil")
+	test.Assert(t, result == &r[0], "Incorrect result returned")
 }
 
 func TestCheckAccountURI(t *testing.T) {
@@ -647,3 +696,169 @@ func TestContainsMethod(t *testing.T) {
 	test.AssertEquals(t, containsMethod("abc,xyz,123", "456"), false)
 	test.AssertEquals(t, containsMethod("abc", "123"), false)
 }
+
+func TestIodefTargets(t *testing.T) {
+	va, _ := setup(nil, 0)
+	va.dnsClient = caaMockDNS{}
+
+	method := "http-01"
+	accountID := int64(123)
+	params := &caaParams{accountURIID: &accountID, validationMethod: &method}
+
+	// Allowed issuance has no iodef targets, even with iodef records
+	record, prob := va.checkCAA(ctx, core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "present-iodef.com"}, params)
+	test.Assert(t, prob == nil, "CAA check for present-iodef.com failed")
+	test.AssertEquals(t, len(record.Iodef), 0)
+
+	// Nor does denied issuance without iodef records
+	record, prob = va.checkCAA(ctx, core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "reserved.com"}, params)
+	test.Assert(t, prob != nil, "CAA check for reserved.com succeeded")
+	test.AssertEquals(t, len(record.Iodef), 0)
+
+	record, prob = va.checkCAA(ctx, core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "reserved-iodef.com"}, params)
+	test.Assert(t, prob != nil, "CAA check for reserved-iodef.com succeeded")
+	// Only mailto: addresses and https: URLs are reported to
+	test.AssertDeepEquals(t, record.Iodef, []string{
+		"mailto:security@reserved-iodef.com",
+		"https://reserved-iodef.com/iodef",
+	})
+}
+
+func TestCAAIdentities(t *testing.T) {
+	if err := features.Set(map[string]bool{"CAAAccountURI": true}); err != nil {
+		t.Fatalf("Failed to enable feature: %v", err)
+	}
+	defer features.Reset()
+
+	va, _ := setup(nil, 0)
+	va.dnsClient = caaMockDNS{}
+	va.caaIdentities = []cmd.CAAIdentityConfig{
+		{Domain: "letsencrypt.org"},
+		{Domain: "oldname.example"},
+		{Domain: "partner.example", AccountURIPrefixes: []string{"https://acme.partner.example/acct/"}},
+	}
+	ourPrefixes := []string{"https://letsencrypt.org/acct/reg/"}
+	partnerPrefixes := []string{"https://letsencrypt.org/acct/reg/", "https://acme.partner.example/acct/"}
+
+	accountID := int64(123)
+	testCases := []struct {
+		domain   string
+		prefixes []string
+		params   *caaParams
+		valid    bool
+		identity string
+	}{
+		{"present.com", ourPrefixes, &caaParams{accountURIID: &accountID}, true, "letsencrypt.org"},
+		// Identities are compared case-insensitively
+		{"old-identity.com", ourPrefixes, &caaParams{accountURIID: &accountID}, true, "oldname.example"},
+		{"reserved.com", ourPrefixes, &caaParams{accountURIID: &accountID}, false, ""},
+		// A scoped identity's accounturi is checked against its own prefixes
+		{"partner-accounturi.com", partnerPrefixes, &caaParams{accountURIID: &accountID}, true, "partner.example"},
+		{"partner-our-accounturi.com", partnerPrefixes, &caaParams{accountURIID: &accountID}, false, ""},
+		// A scoped identity authorizes no account without an accounturi
+		{"partner.com", partnerPrefixes, &caaParams{accountURIID: &accountID}, false, ""},
+		{"partner.com", ourPrefixes, &caaParams{accountURIID: &accountID}, false, ""},
+		{"partner.com", partnerPrefixes, &caaParams{}, false, ""},
+		{"partner-accounturi.com", ourPrefixes, &caaParams{accountURIID: &accountID}, false, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.domain, func(t *testing.T) {
+			va.accountURIPrefixes = tc.prefixes
+			ident := core.AcmeIdentifier{Type: core.IdentifierDNS, Value: tc.domain}
+			present, valid, record, _, err := va.checkCAARecords(ctx, ident, tc.params)
+			test.AssertNotError(t, err, "checkCAARecords failed")
+			test.Assert(t, present, "CAA records not present")
+			test.AssertEquals(t, valid, tc.valid)
+			test.AssertEquals(t, record.Identity, tc.identity)
+		})
+	}
+
+	// Without the CAAAccountURI feature accounturi isn't checked, so scoped
+	// identities authorize no account
+	features.Reset()
+	va.accountURIPrefixes = partnerPrefixes
+	ident := core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "partner-accounturi.com"}
+	_, valid, _, _, err := va.checkCAARecords(ctx, ident, &caaParams{accountURIID: &accountID})
+	test.AssertNotError(t, err, "checkCAARecords failed")
+	test.Assert(t, !valid, "Scoped identity authorized issuance without CAAAccountURI")
+}
+
+func TestCAAValidationRecord(t *testing.T) {
+	if err := features.Set(map[string]bool{"CAAValidationMethods": true}); err != nil {
+		t.Fatalf("Failed to enable feature: %v", err)
+	}
+	defer features.Reset()
+
+	va, _ := setup(nil, 0)
+	va.dnsClient = caaMockDNS{}
+	fc := clock.NewFake()
+	fc.Set(time.Date(2018, 7, 14, 1, 2, 3, 0, time.UTC))
+	va.clk = fc
+
+	method := core.ChallengeTypeHTTP01
+	params := &caaParams{validationMethod: &method}
+	testCases := []struct {
+		domain   string
+		expected core.CAAValidationRecord
+	}{
+		{
+			// The records of the closest ancestor with CAA records are recorded,
+			// along with the record which authorized issuance and its parameters
+			domain: "www.present-http-only.com",
+			expected: core.CAAValidationRecord{
+				QueryName:     "present-http-only.com",
+				RRSet:         []string{`0 issue "letsencrypt.org; validationmethods=http-01"`},
+				MatchedRecord: `0 issue "letsencrypt.org; validationmethods=http-01"`,
+				Parameters:    map[string]string{"validationmethods": "http-01"},
+				Identity:      "letsencrypt.org",
+				Resolver:      "127.0.0.1:53",
+				Present:       true,
+				Valid:         true,
+				CheckedAt:     fc.Now(),
+			},
+		},
+		{
+			domain: "critical.com",
+			expected: core.CAAValidationRecord{
+				QueryName: "critical.com",
+				RRSet:     []string{`1 issue "ca.com"`},
+				Resolver:  "127.0.0.1:53",
+				Present:   true,
+				CheckedAt: fc.Now(),
+			},
+		},
+		{
+			domain: "absent.com",
+			expected: core.CAAValidationRecord{
+				QueryName: "absent.com",
+				Resolver:  "127.0.0.1:53",
+				Valid:     true,
+				CheckedAt: fc.Now(),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.domain, func(t *testing.T) {
+			ident := core.AcmeIdentifier{Type: core.IdentifierDNS, Value: tc.domain}
+			record, _ := va.checkCAA(ctx, ident, params)
+			test.AssertDeepEquals(t, *record, tc.expected)
+		})
+	}
+
+	// IsCAAValid returns the record JSON encoded, even when issuance is denied
+	resp, err := va.IsCAAValid(ctx, &vapb.IsCAAValidRequest{
+		Domain:           &testCases[1].domain,
+		ValidationMethod: &method,
+	})
+	test.AssertNotError(t, err, "IsCAAValid failed")
+	test.AssertNotNil(t, resp.Problem, "Expected a CAA problem")
+	var record core.CAAValidationRecord
+	err = json.Unmarshal(resp.CaaRecord, &record)
+	test.AssertNotError(t, err, "Failed to unmarshal CAA record")
+	test.AssertDeepEquals(t, record, testCases[1].expected)
+
+	// A failed lookup doesn't produce a record
+	record2, prob := va.checkCAA(ctx, core.AcmeIdentifier{Type: core.IdentifierDNS, Value: "caa-timeout.com"}, params)
+	test.AssertNotNil(t, prob, "Expected a DNS problem")
+	test.Assert(t, record2 == nil, "Expected no CAA record")
+}
@@ -0,0 +1,96 @@
+package va
+
+import (
+	"net"
+	"time"
+
+	"golang.org/x/net/context"
+
+	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/metrics"
+)
+
+// dialFunc makes a single connection to address, a host:port pair.
+type dialFunc func(ctx context.Context, address string) (net.Conn, error)
+
+// dualStackDial is the dialing strategy shared by HTTP-01 and the TLS based
+// challenges. It connects to the first address of the preferred family of
+// addrs, IPv6 unless ipv4First is set, and if that connection fails falls
+// back to the first address of the other family. When there is an address to
+// fall back to, the first attempt may use at most half of the time left
+// before ctx's deadline so that the fallback has a chance to complete.
+//
+// Only connection failures lead to a fallback, dial must not do anything
+// more than connect. The returned addrRecord holds the address used and any
+// address tried before it, even when an error is returned.
+func dualStackDial(
+	ctx context.Context,
+	hostname string,
+	addrs []net.IP,
+	port string,
+	ipv4First bool,
+	stats metrics.Scope,
+	dial dialFunc,
+) (net.Conn, addrRecord, error) {
+	var addrInfo addrRecord
+
+	// Split the available addresses into v4 and v6 addresses, and pick the
+	// family to try first
+	v4, v6 := availableAddresses(addrs)
+	first, fallback := v6, v4
+	fallbackFamily := "IPv4"
+	if ipv4First {
+		first, fallback = v4, v6
+		fallbackFamily = "IPv6"
+	}
+
+	// If there is at least one address of the first family then try it first
+	if len(first) > 0 {
+		addrInfo.used = first[0]
+		attemptCtx, cancel := firstAttemptContext(ctx, len(fallback) > 0)
+		conn, err := dial(attemptCtx, net.JoinHostPort(first[0].String(), port))
+		cancel()
+
+		// If there is no error, return immediately
+		if err == nil {
+			return conn, addrInfo, nil
+		}
+
+		// Otherwise, we note that we tried an address. There's no point in
+		// falling back once the overall deadline has passed.
+		addrInfo.tried = append(addrInfo.tried, addrInfo.used)
+		if ctx.Err() != nil {
+			return nil, addrInfo, err
+		}
+		stats.Inc(fallbackFamily+"Fallback", 1)
+	}
+
+	// If there are no fallback addresses and we tried an address of the first
+	// family return an error - there's nothing left to try
+	if len(fallback) == 0 && len(addrInfo.tried) > 0 {
+		return nil, addrInfo, berrors.ConnectionFailureError(
+			"Unable to contact %q at %q, no %s addresses to try as fallback",
+			hostname, addrInfo.tried[0], fallbackFamily)
+	} else if len(fallback) == 0 && len(addrInfo.tried) == 0 {
+		// It shouldn't be possible that there are no addresses of either
+		// family but be defensive about it anyway
+		return nil, addrInfo, berrors.ConnectionFailureError("No IP addresses found for %q", hostname)
+	}
+
+	// Otherwise if there are no addresses of the first family, or there was
+	// an error talking to the first one, try the first fallback address
+	addrInfo.used = fallback[0]
+	conn, err := dial(ctx, net.JoinHostPort(fallback[0].String(), port))
+	return conn, addrInfo, err
+}
+
+// firstAttemptContext returns the context for the first connection attempt
+// of dualStackDial. If there's a fallback to try, it is limited to half of
+// the time left before ctx's deadline.
+func firstAttemptContext(ctx context.Context, hasFallback bool) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !hasFallback || !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Until(deadline)/2)
+}
@@ -0,0 +1,125 @@
+package va
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"golang.org/x/net/context"
+
+	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/metrics/mock_metrics"
+	"github.com/letsencrypt/boulder/test"
+)
+
+// fakeDialer records the addresses dialed and the time each attempt had
+// left, failing to connect to the addresses in fail.
+type fakeDialer struct {
+	fail      map[string]bool
+	dialed    []string
+	remaining []time.Duration
+}
+
+func (d *fakeDialer) dial(ctx context.Context, address string) (net.Conn, error) {
+	d.dialed = append(d.dialed, address)
+	if deadline, ok := ctx.Deadline(); ok {
+		d.remaining = append(d.remaining, time.Until(deadline))
+	}
+	if d.fail[address] {
+		return nil, errors.New("connection refused")
+	}
+	client, server := net.Pipe()
+	_ = server.Close()
+	return client, nil
+}
+
+var dualStackAddrs = []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}
+
+func TestDualStackDial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	scope := mock_metrics.NewMockScope(ctrl)
+
+	// IPv6 is tried first and, once it fails, IPv4. Both attempts are recorded.
+	scope.EXPECT().Inc("IPv4Fallback", int64(1))
+	d := &fakeDialer{fail: map[string]bool{"[::1]:80": true}}
+	conn, addrInfo, err := dualStackDial(context.Background(), "example.com", dualStackAddrs, "80", false, scope, d.dial)
+	test.AssertNotError(t, err, "dualStackDial failed")
+	_ = conn.Close()
+	test.AssertDeepEquals(t, d.dialed, []string{"[::1]:80", "127.0.0.1:80"})
+	test.AssertEquals(t, addrInfo.used.String(), "127.0.0.1")
+	test.AssertEquals(t, len(addrInfo.tried), 1)
+	test.AssertEquals(t, addrInfo.tried[0].String(), "::1")
+
+	// A working IPv6 address is used without trying IPv4
+	d = &fakeDialer{}
+	conn, addrInfo, err = dualStackDial(context.Background(), "example.com", dualStackAddrs, "80", false, scope, d.dial)
+	test.AssertNotError(t, err, "dualStackDial failed")
+	_ = conn.Close()
+	test.AssertDeepEquals(t, d.dialed, []string{"[::1]:80"})
+	test.AssertEquals(t, addrInfo.used.String(), "::1")
+	test.AssertEquals(t, len(addrInfo.tried), 0)
+
+	// With ipv4First the order is reversed
+	scope.EXPECT().Inc("IPv6Fallback", int64(1))
+	d = &fakeDialer{fail: map[string]bool{"127.0.0.1:80": true}}
+	conn, addrInfo, err = dualStackDial(context.Background(), "example.com", dualStackAddrs, "80", true, scope, d.dial)
+	test.AssertNotError(t, err, "dualStackDial failed")
+	_ = conn.Close()
+	test.AssertDeepEquals(t, d.dialed, []string{"127.0.0.1:80", "[::1]:80"})
+	test.AssertEquals(t, addrInfo.used.String(), "::1")
+	test.AssertEquals(t, addrInfo.tried[0].String(), "127.0.0.1")
+}
+
+func TestDualStackDialFailures(t *testing.T) {
+	stats := metrics.NewNoopScope()
+
+	// When both families fail the fallback's error is returned
+	d := &fakeDialer{fail: map[string]bool{"[::1]:443": true, "127.0.0.1:443": true}}
+	_, addrInfo, err := dualStackDial(context.Background(), "example.com", dualStackAddrs, "443", false, stats, d.dial)
+	test.AssertEquals(t, err.Error(), "connection refused")
+	test.AssertEquals(t, addrInfo.used.String(), "127.0.0.1")
+	test.AssertEquals(t, addrInfo.tried[0].String(), "::1")
+
+	// With no address to fall back to there's nothing left to try
+	d = &fakeDialer{fail: map[string]bool{"[::1]:443": true}}
+	_, addrInfo, err = dualStackDial(context.Background(), "example.com", []net.IP{net.ParseIP("::1")}, "443", false, stats, d.dial)
+	test.Assert(t, berrors.Is(err, berrors.ConnectionFailure), "Wrong error type")
+	test.AssertEquals(t, err.Error(), `Unable to contact "example.com" at "::1", no IPv4 addresses to try as fallback`)
+	test.AssertEquals(t, addrInfo.used.String(), "::1")
+	test.AssertEquals(t, len(addrInfo.tried), 1)
+
+	_, _, err = dualStackDial(context.Background(), "example.com", nil, "443", false, stats, d.dial)
+	test.AssertEquals(t, err.Error(), `No IP addresses found for "example.com"`)
+
+	// Once the overall deadline has passed there's no fallback
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d = &fakeDialer{fail: map[string]bool{"[::1]:443": true}}
+	_, addrInfo, err = dualStackDial(ctx, "example.com", dualStackAddrs, "443", false, stats, d.dial)
+	test.AssertEquals(t, err.Error(), "connection refused")
+	test.AssertDeepEquals(t, d.dialed, []string{"[::1]:443"})
+	test.AssertEquals(t, len(addrInfo.tried), 1)
+}
+
+func TestDualStackDialDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The first attempt leaves at least half of the time for the fallback
+	d := &fakeDialer{fail: map[string]bool{"[::1]:80": true}}
+	_, _, err := dualStackDial(ctx, "example.com", dualStackAddrs, "80", false, metrics.NewNoopScope(), d.dial)
+	test.AssertNotError(t, err, "dualStackDial failed")
+	test.AssertEquals(t, len(d.remaining), 2)
+	test.Assert(t, d.remaining[0] <= 5*time.Second, "First attempt wasn't limited to half of the deadline")
+	test.Assert(t, d.remaining[1] > 9*time.Second, "Fallback attempt was limited")
+
+	// Without an address to fall back to the whole deadline is used
+	d = &fakeDialer{}
+	_, _, err = dualStackDial(ctx, "example.com", []net.IP{net.ParseIP("::1")}, "80", false, metrics.NewNoopScope(), d.dial)
+	test.AssertNotError(t, err, "dualStackDial failed")
+	test.Assert(t, d.remaining[0] > 9*time.Second, "Single attempt was limited")
+}
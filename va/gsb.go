@@ -6,7 +6,6 @@ import (
 	safebrowsingv4 "github.com/google/safebrowsing"
 	"golang.org/x/net/context"
 
-	"github.com/letsencrypt/boulder/canceled"
 	bgrpc "github.com/letsencrypt/boulder/grpc"
 	vaPB "github.com/letsencrypt/boulder/va/proto"
 )
@@ -37,37 +36,10 @@ func (va *ValidationAuthorityImpl) IsSafeDomain(ctx context.Context, req *vaPB.I
 	return &vaPB.IsDomainSafe{IsSafe: &status}, nil
 }
 
-// isSafeDomain returns true if the VA considers the given domain safe. If the
-// backend errors, we consider the domain safe, so this function never returns
-// error.
+// isSafeDomain returns true if the VA considers the given domain safe, i.e.
+// none of its reputation checks blocks it. If a provider errors, it is treated
+// as not listing the domain, so this function never returns error.
 func (va *ValidationAuthorityImpl) isSafeDomain(ctx context.Context, domain string) bool {
-	stats := va.stats.NewScope("IsSafeDomain")
-	stats.Inc("IsSafeDomain.Requests", 1)
-	if va.safeBrowsing == nil {
-		stats.Inc("IsSafeDomain.Skips", 1)
-		return true
-	}
-
-	list, err := va.safeBrowsing.IsListed(ctx, domain)
-	if canceled.Is(err) {
-		// Sometimes an IsListed request will be canceled because the main
-		// validation failed, causing the parent context to be canceled.
-		stats.Inc("IsSafeDomain.Canceled", 1)
-		return true
-	}
-	if err != nil {
-		stats.Inc("IsSafeDomain.Errors", 1)
-		// In the event of an error checking the GSB status we allow the domain in
-		// question to be treated as safe to avoid coupling the availability of the
-		// VA to the GSB API. This is acceptable for Let's Encrypt because we do not
-		// have a hard commitment to GSB filtering in our CP/CPS.
-		return true
-	}
-	stats.Inc("IsSafeDomain.Successes", 1)
-	if list == "" {
-		stats.Inc("IsSafeDomain.Status.Good", 1)
-		return true
-	}
-	stats.Inc("IsSafeDomain.Status.Bad", 1)
-	return false
+	_, safe := va.checkReputation(ctx, domain, true)
+	return safe
 }
@@ -35,7 +35,7 @@ func TestIsSafeDomain(t *testing.T) {
 		nil,
 		0,
 		"user agent 1.0",
-		"letsencrypt.org",
+		[]cmd.CAAIdentityConfig{{Domain: "letsencrypt.org"}},
 		stats,
 		clock.NewFake(),
 		blog.NewMock(),
@@ -83,7 +83,7 @@ func TestAllowNilInIsSafeDomain(t *testing.T) {
 		nil,
 		0,
 		"user agent 1.0",
-		"letsencrypt.org",
+		[]cmd.CAAIdentityConfig{{Domain: "letsencrypt.org"}},
 		stats,
 		clock.NewFake(),
 		blog.NewMock(),
@@ -0,0 +1,160 @@
+package va
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/letsencrypt/boulder/cmd"
+	berrors "github.com/letsencrypt/boulder/errors"
+)
+
+// Values of HTTP01Policy.HTTPSUpgrade
+const (
+	httpsUpgradeAllow    = "allow"
+	httpsUpgradeSameHost = "same-host"
+	httpsUpgradeDeny     = "deny"
+)
+
+// Values of HTTP01Policy.AddressOrder
+const (
+	addressOrderIPv6First = "ipv6-first"
+	addressOrderIPv4First = "ipv4-first"
+)
+
+// http01Policy holds the rules the VA applies when fetching HTTP-01
+// challenge responses. See cmd.HTTP01PolicyConfig for their meaning.
+type http01Policy struct {
+	maxRedirects    int
+	allowedPorts    []int
+	httpsUpgrade    string
+	ipv4First       bool
+	maxResponseSize int64
+	maxTotalTime    time.Duration
+}
+
+// defaultHTTP01Policy returns the policy used unless the VA is configured
+// with another.
+func defaultHTTP01Policy(httpPort, httpsPort int) http01Policy {
+	return http01Policy{
+		maxRedirects:    maxRedirect,
+		allowedPorts:    []int{httpPort, httpsPort},
+		httpsUpgrade:    httpsUpgradeAllow,
+		maxResponseSize: maxResponseSize,
+	}
+}
+
+// SetHTTP01Policy replaces the VA's HTTP-01 fetch policy with the one
+// configured by c. Unset fields of c take their defaults.
+func (va *ValidationAuthorityImpl) SetHTTP01Policy(c cmd.HTTP01PolicyConfig) error {
+	policy := defaultHTTP01Policy(va.httpPort, va.httpsPort)
+	if c.MaxRedirects != nil {
+		if *c.MaxRedirects < 0 {
+			return fmt.Errorf("negative maxRedirects %d", *c.MaxRedirects)
+		}
+		policy.maxRedirects = *c.MaxRedirects
+	}
+	if len(c.AllowedPorts) > 0 {
+		// The VA only connects to its HTTP and HTTPS ports, so a redirect
+		// to any other port can't be followed.
+		for _, port := range c.AllowedPorts {
+			if port != va.httpPort && port != va.httpsPort {
+				return fmt.Errorf("allowed port %d is neither the HTTP port %d nor the HTTPS port %d",
+					port, va.httpPort, va.httpsPort)
+			}
+		}
+		policy.allowedPorts = c.AllowedPorts
+	}
+	switch c.HTTPSUpgrade {
+	case "", httpsUpgradeAllow:
+	case httpsUpgradeSameHost, httpsUpgradeDeny:
+		policy.httpsUpgrade = c.HTTPSUpgrade
+	default:
+		return fmt.Errorf("unknown httpsUpgrade %q", c.HTTPSUpgrade)
+	}
+	switch c.AddressOrder {
+	case "", addressOrderIPv6First:
+	case addressOrderIPv4First:
+		policy.ipv4First = true
+	default:
+		return fmt.Errorf("unknown addressOrder %q", c.AddressOrder)
+	}
+	if c.MaxResponseSize < 0 {
+		return fmt.Errorf("negative maxResponseSize %d", c.MaxResponseSize)
+	} else if c.MaxResponseSize > 0 {
+		policy.maxResponseSize = c.MaxResponseSize
+	}
+	if c.MaxTotalTime.Duration < 0 {
+		return fmt.Errorf("negative maxTotalTime %s", c.MaxTotalTime.Duration)
+	}
+	policy.maxTotalTime = c.MaxTotalTime.Duration
+	va.http01Policy = policy
+	return nil
+}
+
+// redirectTarget returns the hostname and port a redirect to target will
+// connect to.
+func (va *ValidationAuthorityImpl) redirectTarget(target *url.URL) (string, int, error) {
+	host, portStr, err := net.SplitHostPort(target.Host)
+	if err != nil {
+		// There is no port in the URL, so the scheme's default is used
+		if strings.ToLower(target.Scheme) == "https" {
+			return target.Host, va.httpsPort, nil
+		}
+		return target.Host, va.httpPort, nil
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, berrors.ConnectionFailureError("Invalid port in redirect target %q", portStr)
+	}
+	return host, port, nil
+}
+
+// checkRedirect returns an error if the policy doesn't allow following the
+// redirect, the redirects'th one of the fetch for hostname, to target, which
+// connects to targetHost and targetPort.
+func (p http01Policy) checkRedirect(hostname string, redirects int, target *url.URL, targetHost string, targetPort int) error {
+	if redirects > p.maxRedirects {
+		return berrors.ConnectionFailureError("Too many redirects, only %d are followed", p.maxRedirects)
+	}
+	allowed := false
+	for _, port := range p.allowedPorts {
+		if targetPort == port {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return berrors.ConnectionFailureError(
+			"Invalid port in redirect target. Only ports %s are supported, not %d",
+			joinPorts(p.allowedPorts), targetPort)
+	}
+	if strings.ToLower(target.Scheme) != "https" {
+		return nil
+	}
+	switch p.httpsUpgrade {
+	case httpsUpgradeDeny:
+		return berrors.ConnectionFailureError("Redirects to HTTPS are not followed")
+	case httpsUpgradeSameHost:
+		if !strings.EqualFold(targetHost, hostname) {
+			return berrors.ConnectionFailureError(
+				"Redirects to HTTPS are only followed for %s, not %s", hostname, targetHost)
+		}
+	}
+	return nil
+}
+
+// joinPorts lists ports for an error message, e.g. "80, 443 and 8080".
+func joinPorts(ports []int) string {
+	strs := make([]string, len(ports))
+	for i, port := range ports {
+		strs[i] = strconv.Itoa(port)
+	}
+	if len(strs) < 2 {
+		return strings.Join(strs, "")
+	}
+	return strings.Join(strs[:len(strs)-1], ", ") + " and " + strs[len(strs)-1]
+}
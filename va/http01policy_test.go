@@ -0,0 +1,210 @@
+package va
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestSetHTTP01Policy(t *testing.T) {
+	va, _ := setup(nil, 0)
+	test.AssertDeepEquals(t, va.http01Policy, http01Policy{
+		maxRedirects:    10,
+		allowedPorts:    []int{va.httpPort, va.httpsPort},
+		httpsUpgrade:    httpsUpgradeAllow,
+		maxResponseSize: 128,
+	})
+
+	va.httpPort = 80
+	va.httpsPort = 443
+	zero := 0
+	err := va.SetHTTP01Policy(cmd.HTTP01PolicyConfig{
+		MaxRedirects:    &zero,
+		AllowedPorts:    []int{443},
+		HTTPSUpgrade:    "same-host",
+		AddressOrder:    "ipv4-first",
+		MaxResponseSize: 256,
+		MaxTotalTime:    cmd.ConfigDuration{Duration: 5 * time.Second},
+	})
+	test.AssertNotError(t, err, "SetHTTP01Policy failed")
+	test.AssertDeepEquals(t, va.http01Policy, http01Policy{
+		maxRedirects:    0,
+		allowedPorts:    []int{443},
+		httpsUpgrade:    httpsUpgradeSameHost,
+		ipv4First:       true,
+		maxResponseSize: 256,
+		maxTotalTime:    5 * time.Second,
+	})
+
+	negative := -1
+	for _, c := range []cmd.HTTP01PolicyConfig{
+		{MaxRedirects: &negative},
+		{AllowedPorts: []int{0}},
+		// The VA only connects to its HTTP and HTTPS ports
+		{AllowedPorts: []int{80, 8080}},
+		{HTTPSUpgrade: "sometimes"},
+		{AddressOrder: "ipv5-first"},
+		{MaxResponseSize: -1},
+		{MaxTotalTime: cmd.ConfigDuration{Duration: -time.Second}},
+	} {
+		err := va.SetHTTP01Policy(c)
+		test.AssertError(t, err, fmt.Sprintf("SetHTTP01Policy accepted %#v", c))
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	policy := http01Policy{
+		maxRedirects: 2,
+		allowedPorts: []int{80, 443},
+		httpsUpgrade: httpsUpgradeAllow,
+	}
+	mustParse := func(s string) *url.URL {
+		u, err := url.Parse(s)
+		test.AssertNotError(t, err, "Failed to parse URL")
+		return u
+	}
+	httpsTarget := mustParse("https://other.com/path")
+
+	test.AssertNotError(t, policy.checkRedirect("example.com", 2, httpsTarget, "other.com", 443), "Redirect rejected")
+
+	err := policy.checkRedirect("example.com", 3, httpsTarget, "other.com", 443)
+	test.Assert(t, berrors.Is(err, berrors.ConnectionFailure), "Too many redirects weren't rejected")
+	test.AssertEquals(t, err.Error(), "Too many redirects, only 2 are followed")
+
+	err = policy.checkRedirect("example.com", 1, mustParse("http://other.com:8080/path"), "other.com", 8080)
+	test.AssertEquals(t, err.Error(), "Invalid port in redirect target. Only ports 80 and 443 are supported, not 8080")
+
+	policy.httpsUpgrade = httpsUpgradeSameHost
+	test.AssertNotError(t,
+		policy.checkRedirect("example.com", 1, mustParse("https://Example.com/path"), "Example.com", 443),
+		"Same host HTTPS redirect rejected")
+	err = policy.checkRedirect("example.com", 1, httpsTarget, "other.com", 443)
+	test.AssertEquals(t, err.Error(), "Redirects to HTTPS are only followed for example.com, not other.com")
+	test.AssertNotError(t,
+		policy.checkRedirect("example.com", 1, mustParse("http://other.com/path"), "other.com", 80),
+		"HTTP redirect rejected")
+
+	policy.httpsUpgrade = httpsUpgradeDeny
+	err = policy.checkRedirect("example.com", 1, mustParse("https://example.com/path"), "example.com", 443)
+	test.AssertEquals(t, err.Error(), "Redirects to HTTPS are not followed")
+}
+
+func TestJoinPorts(t *testing.T) {
+	test.AssertEquals(t, joinPorts([]int{80}), "80")
+	test.AssertEquals(t, joinPorts([]int{80, 443}), "80 and 443")
+	test.AssertEquals(t, joinPorts([]int{80, 443, 8080}), "80, 443 and 8080")
+}
+
+func TestHTTP01PolicyRedirectRecords(t *testing.T) {
+	chall := core.HTTPChallenge01()
+	hs := httpSrv(t, expectedToken)
+	defer hs.Close()
+	va, _ := setup(hs, 0)
+
+	// A redirect rejected by the policy is recorded after the hop that sent
+	// it, without any addresses
+	setChallengeToken(&chall, pathRedirectInvalidPort)
+	records, prob := va.validateHTTP01(ctx, dnsi("localhost"), chall)
+	test.AssertNotNil(t, prob, "Redirect to an invalid port wasn't rejected")
+	test.AssertEquals(t, len(records), 2)
+	test.AssertEquals(t, records[0].Hostname, "localhost")
+	test.AssertEquals(t, records[0].AddressUsed.String(), "127.0.0.1")
+	test.AssertEquals(t, records[1].URL, "http://other.valid:8080/path")
+	test.AssertEquals(t, records[1].Hostname, "other.valid")
+	test.AssertEquals(t, records[1].Port, "8080")
+	test.Assert(t, records[1].AddressUsed == nil, "Rejected redirect target has an address")
+
+	zero := 0
+	err := va.SetHTTP01Policy(cmd.HTTP01PolicyConfig{MaxRedirects: &zero})
+	test.AssertNotError(t, err, "SetHTTP01Policy failed")
+	setChallengeToken(&chall, pathMoved)
+	records, prob = va.validateHTTP01(ctx, dnsi("localhost"), chall)
+	test.AssertNotNil(t, prob, "Redirect followed despite maxRedirects of 0")
+	test.Assert(t, strings.HasSuffix(prob.Detail, "Too many redirects, only 0 are followed"),
+		fmt.Sprintf("Unexpected problem detail %q", prob.Detail))
+	test.AssertEquals(t, len(records), 2)
+	test.Assert(t, strings.HasSuffix(records[1].URL, "/"+pathValid), "Rejected redirect target not recorded")
+}
+
+func TestHTTP01PolicyLimits(t *testing.T) {
+	chall := core.HTTPChallenge01()
+	setChallengeToken(&chall, expectedToken)
+	hs := httpSrv(t, expectedToken)
+	defer hs.Close()
+	va, _ := setup(hs, 0)
+
+	// The key authorization response is larger than 16 bytes
+	err := va.SetHTTP01Policy(cmd.HTTP01PolicyConfig{MaxResponseSize: 16})
+	test.AssertNotError(t, err, "SetHTTP01Policy failed")
+	_, prob := va.validateHTTP01(ctx, dnsi("localhost"), chall)
+	test.AssertNotNil(t, prob, "Response larger than maxResponseSize accepted")
+
+	// The server waits 3 seconds before responding
+	err = va.SetHTTP01Policy(cmd.HTTP01PolicyConfig{
+		MaxTotalTime: cmd.ConfigDuration{Duration: 100 * time.Millisecond},
+	})
+	test.AssertNotError(t, err, "SetHTTP01Policy failed")
+	setChallengeToken(&chall, pathWait)
+	started := time.Now()
+	_, prob = va.validateHTTP01(ctx, dnsi("localhost"), chall)
+	test.AssertNotNil(t, prob, "Fetch wasn't limited by maxTotalTime")
+	test.Assert(t, time.Since(started) < 2*time.Second, "Fetch took longer than maxTotalTime")
+}
+
+func TestHTTP01DialerIPv4First(t *testing.T) {
+	chall := core.HTTPChallenge01()
+	hs := httpSrv(t, chall.Token)
+	defer hs.Close()
+	va, _ := setup(hs, 0)
+	err := va.SetHTTP01Policy(cmd.HTTP01PolicyConfig{AddressOrder: "ipv4-first"})
+	test.AssertNotError(t, err, "SetHTTP01Policy failed")
+
+	// The IPv4 address is dialed first, and succeeds
+	addrs, _ := va.getAddrs(context.Background(), "ipv4.and.ipv6.localhost")
+	d := va.newHTTP01Dialer("ipv4.and.ipv6.localhost", va.httpPort, addrs)
+	_, err = d.DialContext(context.Background(), "", "ipv4.and.ipv6.localhost")
+	test.AssertNotError(t, err, "Dial failed")
+	test.AssertEquals(t, d.dialerCount, 1)
+	addrInfo := <-d.addrInfoChan
+	test.AssertEquals(t, addrInfo.used.String(), "127.0.0.1")
+	test.AssertEquals(t, len(addrInfo.tried), 0)
+}
+
+func TestTLSDialIPv4First(t *testing.T) {
+	// A plain TCP listener is enough to see which address was dialed, since
+	// the TLS handshake only happens once connected
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	test.AssertNotError(t, err, "Failed to listen")
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	va, _ := setup(nil, 0)
+	va.tlsPort = l.Addr().(*net.TCPAddr).Port
+	err = va.SetHTTP01Policy(cmd.HTTP01PolicyConfig{AddressOrder: "ipv4-first"})
+	test.AssertNotError(t, err, "SetHTTP01Policy failed")
+
+	// The IPv4 address is dialed first, and succeeds
+	_, _, records, prob := va.tryGetTLSCerts(ctx, dnsi("ipv4.and.ipv6.localhost"), core.TLSALPNChallenge01(), &tls.Config{})
+	test.AssertNotNil(t, prob, "TLS handshake with a plain TCP listener succeeded")
+	test.AssertEquals(t, len(records), 1)
+	test.AssertEquals(t, records[0].AddressUsed.String(), "127.0.0.1")
+	test.AssertEquals(t, len(records[0].AddressesTried), 0)
+}
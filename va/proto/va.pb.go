@@ -76,8 +76,10 @@ func (m *IsCAAValidRequest) GetAccountURIID() int64 {
 
 // If CAA is valid for the requested domain, the problem will be empty
 type IsCAAValidResponse struct {
-	Problem          *core.ProblemDetails `protobuf:"bytes,1,opt,name=problem" json:"problem,omitempty"`
-	XXX_unrecognized []byte               `json:"-"`
+	Problem *core.ProblemDetails `protobuf:"bytes,1,opt,name=problem" json:"problem,omitempty"`
+	// JSON encoding of the core.CAAValidationRecord of the check
+	CaaRecord        []byte `protobuf:"bytes,2,opt,name=caaRecord" json:"caaRecord,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
 }
 
 func (m *IsCAAValidResponse) Reset()                    { *m = IsCAAValidResponse{} }
@@ -92,6 +94,13 @@ func (m *IsCAAValidResponse) GetProblem() *core.ProblemDetails {
 	return nil
 }
 
+func (m *IsCAAValidResponse) GetCaaRecord() []byte {
+	if m != nil {
+		return m.CaaRecord
+	}
+	return nil
+}
+
 type IsSafeDomainRequest struct {
 	Domain           *string `protobuf:"bytes,1,opt,name=domain" json:"domain,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
@@ -185,9 +194,15 @@ func (m *AuthzMeta) GetRegID() int64 {
 }
 
 type ValidationResult struct {
-	Records          []*core.ValidationRecord `protobuf:"bytes,1,rep,name=records" json:"records,omitempty"`
-	Problems         *core.ProblemDetails     `protobuf:"bytes,2,opt,name=problems" json:"problems,omitempty"`
-	XXX_unrecognized []byte                   `json:"-"`
+	Records  []*core.ValidationRecord `protobuf:"bytes,1,rep,name=records" json:"records,omitempty"`
+	Problems *core.ProblemDetails     `protobuf:"bytes,2,opt,name=problems" json:"problems,omitempty"`
+	// JSON encoding of the core.CAAValidationRecord of the CAA check, if one
+	// was made
+	CaaRecord []byte `protobuf:"bytes,3,opt,name=caaRecord" json:"caaRecord,omitempty"`
+	// JSON encoding of the []core.ReputationVerdict of the domain reputation
+	// checks
+	Reputation       []byte `protobuf:"bytes,4,opt,name=reputation" json:"reputation,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
 }
 
 func (m *ValidationResult) Reset()                    { *m = ValidationResult{} }
@@ -209,6 +224,20 @@ func (m *ValidationResult) GetProblems() *core.ProblemDetails {
 	return nil
 }
 
+func (m *ValidationResult) GetCaaRecord() []byte {
+	if m != nil {
+		return m.CaaRecord
+	}
+	return nil
+}
+
+func (m *ValidationResult) GetReputation() []byte {
+	if m != nil {
+		return m.Reputation
+	}
+	return nil
+}
+
 func init() {
 	proto1.RegisterType((*IsCAAValidRequest)(nil), "va.IsCAAValidRequest")
 	proto1.RegisterType((*IsCAAValidResponse)(nil), "va.IsCAAValidResponse")
@@ -391,33 +420,35 @@ var _CAA_serviceDesc = grpc.ServiceDesc{
 func init() { proto1.RegisterFile("va/proto/va.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 437 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x93, 0xcf, 0x6f, 0xd3, 0x30,
-	0x14, 0xc7, 0x9b, 0x44, 0xa5, 0xeb, 0xeb, 0x80, 0xf6, 0xd1, 0x95, 0xa8, 0xe2, 0x50, 0x19, 0x09,
-	0x55, 0x48, 0xcb, 0x46, 0xae, 0x88, 0x43, 0x68, 0x2e, 0x39, 0x4c, 0x9a, 0x8c, 0xe8, 0x81, 0x9b,
-	0x49, 0xbc, 0x35, 0x52, 0x1a, 0x17, 0xdb, 0xcd, 0x01, 0xee, 0x9c, 0xf8, 0xa3, 0x91, 0xed, 0xf4,
-	0x07, 0xdd, 0xd0, 0x6e, 0xf1, 0xf7, 0xfb, 0xb1, 0xde, 0x7b, 0xdf, 0xe7, 0xc0, 0xa8, 0x61, 0x57,
-	0x1b, 0x29, 0xb4, 0xb8, 0x6a, 0x58, 0x64, 0x3f, 0xd0, 0x6f, 0xd8, 0xf4, 0x22, 0x17, 0x92, 0xb7,
-	0x86, 0xf9, 0x74, 0x16, 0xf9, 0x05, 0xa3, 0x4c, 0x2d, 0x92, 0x64, 0xc9, 0xaa, 0xb2, 0xa0, 0xfc,
-	0xc7, 0x96, 0x2b, 0x8d, 0x13, 0x78, 0x56, 0x88, 0x35, 0x2b, 0xeb, 0xd0, 0x9b, 0x79, 0xf3, 0x3e,
-	0x6d, 0x4f, 0xf8, 0x1e, 0x86, 0x8d, 0xe1, 0x98, 0x2e, 0x45, 0x7d, 0xc3, 0xf5, 0x4a, 0x14, 0xa1,
-	0x6f, 0x89, 0x07, 0x3a, 0x12, 0x38, 0x67, 0x79, 0x2e, 0xb6, 0xb5, 0xfe, 0x4a, 0xb3, 0x2c, 0x0d,
-	0x83, 0x99, 0x37, 0x0f, 0xe8, 0x3f, 0x1a, 0x49, 0x01, 0x8f, 0x8b, 0xab, 0x8d, 0xa8, 0x15, 0xc7,
-	0x08, 0x7a, 0x1b, 0x29, 0xbe, 0x57, 0x7c, 0x6d, 0xcb, 0x0f, 0xe2, 0x71, 0x64, 0x1b, 0xbe, 0x75,
-	0x62, 0xca, 0x35, 0x2b, 0x2b, 0x45, 0x77, 0x10, 0xb9, 0x84, 0x57, 0x99, 0xfa, 0xc2, 0xee, 0x78,
-	0x6a, 0xbb, 0x7c, 0x62, 0x08, 0xf2, 0x0e, 0xce, 0x33, 0xe5, 0x50, 0x73, 0xc9, 0x70, 0xa5, 0xbd,
-	0x6e, 0xb9, 0x33, 0xda, 0x9e, 0xc8, 0x6f, 0x0f, 0xc2, 0x5b, 0x2e, 0xef, 0x84, 0x5c, 0x2f, 0xf7,
-	0xc3, 0x3d, 0x95, 0xd0, 0x25, 0xf4, 0xf3, 0x15, 0xab, 0x2a, 0x5e, 0xdf, 0x73, 0x1b, 0xcd, 0x20,
-	0x7e, 0xe9, 0xba, 0x5f, 0xec, 0x64, 0x7a, 0x20, 0xf0, 0x2d, 0x74, 0xd9, 0x56, 0xaf, 0x7e, 0xda,
-	0x74, 0x06, 0xf1, 0xf3, 0xa8, 0x61, 0x51, 0x62, 0x84, 0x1b, 0xae, 0x19, 0x75, 0x1e, 0xf9, 0x00,
-	0xfd, 0xbd, 0x86, 0x2f, 0xc0, 0x2f, 0x8b, 0xb6, 0xa8, 0x5f, 0x16, 0x38, 0x86, 0xae, 0xe4, 0xf7,
-	0x59, 0x6a, 0x8b, 0x05, 0xd4, 0x1d, 0x48, 0x03, 0xc3, 0xe3, 0x9e, 0xd5, 0xb6, 0xd2, 0x78, 0x0d,
-	0x3d, 0xc9, 0x73, 0x21, 0x0b, 0x15, 0x7a, 0xb3, 0x60, 0x3e, 0x88, 0x27, 0xae, 0xb1, 0x63, 0xd0,
-	0xd8, 0x74, 0x87, 0xe1, 0x35, 0x9c, 0xb5, 0x19, 0xab, 0x76, 0x96, 0xc7, 0x37, 0xb1, 0xa7, 0xe2,
-	0x3f, 0x1e, 0xf8, 0xcb, 0x04, 0x3f, 0x9a, 0x88, 0x0f, 0x1b, 0xc1, 0xd7, 0x66, 0xae, 0x47, 0x76,
-	0x34, 0x1d, 0x3a, 0xe3, 0xb0, 0x0d, 0xd2, 0xc1, 0x0c, 0x46, 0x0f, 0x62, 0xc7, 0x37, 0x06, 0xfc,
-	0xdf, 0x36, 0xa6, 0x63, 0xe3, 0x9e, 0x0e, 0x4c, 0x3a, 0x71, 0x0a, 0xc1, 0x22, 0x49, 0xf0, 0x13,
-	0xc0, 0xe1, 0x99, 0xe1, 0x85, 0xab, 0x79, 0xf2, 0xe6, 0xa7, 0x93, 0x53, 0xd9, 0xbd, 0x46, 0xd2,
-	0xf9, 0xdc, 0xfb, 0xd6, 0xb5, 0xff, 0xca, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0xc3, 0x90, 0x4f,
-	0xfe, 0x5a, 0x03, 0x00, 0x00,
+	// 466 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x53, 0x41, 0x6f, 0xd3, 0x30,
+	0x14, 0x6e, 0x12, 0x4a, 0xd7, 0xd7, 0x02, 0xed, 0xa3, 0x2b, 0x51, 0x35, 0xa1, 0xca, 0x48, 0xa8,
+	0x42, 0x5a, 0x37, 0x72, 0x45, 0x1c, 0x42, 0x7b, 0xc9, 0x61, 0xd2, 0x64, 0x44, 0x0f, 0xdc, 0xbc,
+	0xc4, 0x5b, 0x23, 0xa5, 0x71, 0x89, 0x9d, 0x1c, 0xe0, 0xce, 0x89, 0x9f, 0xc3, 0x0f, 0x44, 0xb6,
+	0xd3, 0xa6, 0xeb, 0x80, 0xdd, 0xfc, 0xbe, 0xef, 0xb3, 0xbe, 0xf7, 0xde, 0x67, 0xc3, 0xb0, 0x62,
+	0x17, 0xdb, 0x42, 0x28, 0x71, 0x51, 0xb1, 0xb9, 0x39, 0xa0, 0x5b, 0xb1, 0xc9, 0x69, 0x2c, 0x0a,
+	0x5e, 0x13, 0xfa, 0x68, 0x29, 0xf2, 0x03, 0x86, 0x91, 0x5c, 0x84, 0xe1, 0x8a, 0x65, 0x69, 0x42,
+	0xf9, 0xb7, 0x92, 0x4b, 0x85, 0x63, 0x78, 0x9a, 0x88, 0x0d, 0x4b, 0x73, 0xdf, 0x99, 0x3a, 0xb3,
+	0x2e, 0xad, 0x2b, 0x7c, 0x07, 0x83, 0x4a, 0xeb, 0x98, 0x4a, 0x45, 0x7e, 0xc5, 0xd5, 0x5a, 0x24,
+	0xbe, 0x6b, 0x14, 0x0f, 0x70, 0x24, 0xd0, 0x67, 0x71, 0x2c, 0xca, 0x5c, 0x7d, 0xa1, 0x51, 0xb4,
+	0xf4, 0xbd, 0xa9, 0x33, 0xf3, 0xe8, 0x3d, 0x8c, 0xdc, 0x00, 0x1e, 0x9a, 0xcb, 0xad, 0xc8, 0x25,
+	0xc7, 0x39, 0x74, 0xb6, 0x85, 0xb8, 0xc9, 0xf8, 0xc6, 0xd8, 0xf7, 0x82, 0xd1, 0xdc, 0x34, 0x7c,
+	0x6d, 0xc1, 0x25, 0x57, 0x2c, 0xcd, 0x24, 0xdd, 0x89, 0xf0, 0x0c, 0xba, 0x31, 0x63, 0x94, 0xc7,
+	0xa2, 0xb0, 0xed, 0xf4, 0x69, 0x03, 0x90, 0x73, 0x78, 0x19, 0xc9, 0xcf, 0xec, 0x96, 0x2f, 0xcd,
+	0x0c, 0x8f, 0x8c, 0x48, 0xde, 0x42, 0x3f, 0x92, 0x56, 0xaa, 0x2f, 0x69, 0x5d, 0x6a, 0xae, 0x1b,
+	0xdd, 0x09, 0xad, 0x2b, 0xf2, 0xd3, 0x01, 0xff, 0x9a, 0x17, 0xb7, 0xa2, 0xd8, 0xac, 0xf6, 0xa3,
+	0x3f, 0xb6, 0xbf, 0x73, 0xe8, 0xc6, 0x6b, 0x96, 0x65, 0x3c, 0xbf, 0xe3, 0xa6, 0xd3, 0x5e, 0xf0,
+	0xc2, 0xce, 0xb6, 0xd8, 0xc1, 0xb4, 0x51, 0xe0, 0x1b, 0x68, 0xb3, 0x52, 0xad, 0xbf, 0x9b, 0xdd,
+	0xf5, 0x82, 0x67, 0xf3, 0x8a, 0xcd, 0x43, 0x0d, 0x5c, 0x71, 0xc5, 0xa8, 0xe5, 0xc8, 0x7b, 0xe8,
+	0xee, 0x31, 0x7c, 0x0e, 0x6e, 0x9a, 0xd4, 0xa6, 0x6e, 0x9a, 0xe0, 0x08, 0xda, 0x05, 0xbf, 0x8b,
+	0x96, 0xc6, 0xcc, 0xa3, 0xb6, 0x20, 0xbf, 0x1d, 0x18, 0x1c, 0x36, 0x2d, 0xcb, 0x4c, 0xe1, 0x25,
+	0x74, 0x0a, 0xb3, 0x31, 0xe9, 0x3b, 0x53, 0x6f, 0xd6, 0x0b, 0xc6, 0xb6, 0xb3, 0x43, 0xa1, 0xa6,
+	0xe9, 0x4e, 0x86, 0x97, 0x70, 0x52, 0x47, 0x20, 0x7d, 0xf7, 0x3f, 0x41, 0xed, 0x55, 0xf7, 0x93,
+	0xf2, 0x8e, 0x92, 0xc2, 0xd7, 0x00, 0x05, 0xdf, 0x96, 0xca, 0x98, 0xf9, 0x4f, 0x0c, 0x7d, 0x80,
+	0x04, 0xbf, 0x1c, 0x70, 0x57, 0x21, 0x7e, 0xd0, 0x09, 0x35, 0x81, 0xe2, 0x2b, 0xbd, 0x96, 0xbf,
+	0x44, 0x3c, 0x19, 0x58, 0xa2, 0x09, 0x93, 0xb4, 0x30, 0x82, 0xe1, 0x83, 0xd4, 0xf0, 0x4c, 0x0b,
+	0xff, 0x15, 0xe6, 0x64, 0xa4, 0xd9, 0xe3, 0x75, 0x91, 0x56, 0xb0, 0x04, 0x6f, 0x11, 0x86, 0xf8,
+	0x11, 0xa0, 0x79, 0xc3, 0x78, 0x6a, 0x3d, 0x8f, 0x3e, 0xd4, 0x64, 0x7c, 0x0c, 0xdb, 0xa7, 0x4e,
+	0x5a, 0x9f, 0x3a, 0x5f, 0xdb, 0xe6, 0x23, 0xfe, 0x19, 0x00, 0x58, 0x0a, 0x45, 0xe9, 0xb7, 0x03,
+	0x00, 0x00,
 }
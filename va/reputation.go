@@ -0,0 +1,194 @@
+package va
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/letsencrypt/boulder/canceled"
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+)
+
+// Values of cmd.ReputationConfig.Action
+const (
+	reputationActionBlock = "block"
+	reputationActionLog   = "log"
+)
+
+// gsbProviderName names Google Safe Browsing in reputation verdicts.
+const gsbProviderName = "gsb"
+
+// ReputationProvider is a source of domain reputation. SafeBrowsing is one,
+// and the VA can be configured with others.
+type ReputationProvider interface {
+	// IsListed returns a non-empty reason if the provider considers the
+	// domain bad.
+	IsListed(ctx context.Context, domain string) (reason string, err error)
+}
+
+// reputationCheck is a ReputationProvider configured for use by the VA.
+type reputationCheck struct {
+	name     string
+	provider ReputationProvider
+	// block is true if the provider listing a domain fails validation for it,
+	// rather than only being logged
+	block   bool
+	timeout time.Duration
+}
+
+// SetReputationChecks configures the VA's domain reputation checks, in
+// addition to Google Safe Browsing if the VA was constructed with a client for
+// it.
+func (va *ValidationAuthorityImpl) SetReputationChecks(c cmd.ReputationConfig) error {
+	defaultBlock, err := reputationBlocks(c.Action, true)
+	if err != nil {
+		return err
+	}
+	var checks []reputationCheck
+	names := map[string]bool{gsbProviderName: true}
+	for _, pc := range c.Providers {
+		if pc.Name == "" {
+			return fmt.Errorf("reputation provider with an empty name configured")
+		}
+		if names[pc.Name] {
+			return fmt.Errorf("reputation provider name %q configured more than once", pc.Name)
+		}
+		names[pc.Name] = true
+		block, err := reputationBlocks(pc.Action, defaultBlock)
+		if err != nil {
+			return err
+		}
+		if pc.Timeout.Duration < 0 {
+			return fmt.Errorf("negative timeout for reputation provider %q", pc.Name)
+		}
+		var provider ReputationProvider
+		switch pc.Type {
+		case "blocklist":
+			provider, err = newBlocklistProvider(pc.Name, pc.File, va.log)
+		case "http":
+			provider, err = newHTTPReputationProvider(pc.URL, pc.Timeout.Duration)
+		case "rbl":
+			provider, err = newRBLProvider(pc.Zone, va.dnsClient)
+		default:
+			err = fmt.Errorf("unknown type %q", pc.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("reputation provider %q: %s", pc.Name, err)
+		}
+		checks = append(checks, reputationCheck{
+			name:     pc.Name,
+			provider: provider,
+			block:    block,
+			timeout:  pc.Timeout.Duration,
+		})
+	}
+	va.safeBrowsingBlocks = defaultBlock
+	va.reputationChecks = checks
+	return nil
+}
+
+// allReputationChecks returns Google Safe Browsing, if withGSB is true and
+// there is a client for it, followed by the configured reputation checks.
+func (va *ValidationAuthorityImpl) allReputationChecks(withGSB bool) []reputationCheck {
+	if !withGSB || va.safeBrowsing == nil {
+		return va.reputationChecks
+	}
+	gsb := reputationCheck{
+		name:     gsbProviderName,
+		provider: va.safeBrowsing,
+		block:    va.safeBrowsingBlocks,
+	}
+	return append([]reputationCheck{gsb}, va.reputationChecks...)
+}
+
+// reputationBlocks returns whether action blocks listed domains, or def if
+// action is empty.
+func reputationBlocks(action string, def bool) (bool, error) {
+	switch action {
+	case "":
+		return def, nil
+	case reputationActionBlock:
+		return true, nil
+	case reputationActionLog:
+		return false, nil
+	}
+	return false, fmt.Errorf("unknown reputation action %q", action)
+}
+
+// checkReputation checks domain with all of the VA's reputation providers
+// concurrently, leaving out Google Safe Browsing unless withGSB is true. It
+// returns their verdicts, and false if a provider that blocks listed domains
+// listed it.
+//
+// In the event of an error checking a provider we treat the domain as not
+// listed by it, to avoid coupling the availability of the VA to third-party
+// APIs. This is acceptable for Let's Encrypt because we do not have a hard
+// commitment to this filtering in our CP/CPS.
+func (va *ValidationAuthorityImpl) checkReputation(ctx context.Context, domain string, withGSB bool) ([]core.ReputationVerdict, bool) {
+	stats := va.stats.NewScope("IsSafeDomain")
+	stats.Inc("IsSafeDomain.Requests", 1)
+	checks := va.allReputationChecks(withGSB)
+	if len(checks) == 0 {
+		stats.Inc("IsSafeDomain.Skips", 1)
+		return nil, true
+	}
+
+	verdicts := make([]core.ReputationVerdict, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check reputationCheck) {
+			defer wg.Done()
+			checkCtx := ctx
+			if check.timeout > 0 {
+				var cancel context.CancelFunc
+				checkCtx, cancel = context.WithTimeout(ctx, check.timeout)
+				defer cancel()
+			}
+			reason, err := check.provider.IsListed(checkCtx, domain)
+			verdict := core.ReputationVerdict{
+				Provider:  check.name,
+				CheckedAt: va.clk.Now(),
+			}
+			if canceled.Is(err) {
+				// Sometimes a request will be canceled because the main validation
+				// failed, causing the parent context to be canceled.
+				stats.Inc("IsSafeDomain.Canceled", 1)
+				verdict.Error = err.Error()
+			} else if err != nil {
+				stats.Inc("IsSafeDomain.Errors", 1)
+				va.log.Warningf("Reputation provider %q failed to check %q: %s", check.name, domain, err)
+				verdict.Error = err.Error()
+			} else {
+				stats.Inc("IsSafeDomain.Successes", 1)
+				verdict.Reason = reason
+				verdict.Blocked = reason != "" && check.block
+			}
+			verdicts[i] = verdict
+		}(i, check)
+	}
+	wg.Wait()
+
+	safe := true
+	for _, verdict := range verdicts {
+		if verdict.Reason == "" {
+			continue
+		}
+		action := reputationActionLog
+		if verdict.Blocked {
+			action = reputationActionBlock
+			safe = false
+		}
+		va.log.AuditInfof("Reputation provider %q listed %q (action: %s): %s",
+			verdict.Provider, domain, action, verdict.Reason)
+	}
+	if safe {
+		stats.Inc("IsSafeDomain.Status.Good", 1)
+	} else {
+		stats.Inc("IsSafeDomain.Status.Bad", 1)
+	}
+	return verdicts, safe
+}
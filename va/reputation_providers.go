@@ -0,0 +1,181 @@
+package va
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/letsencrypt/boulder/bdns"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/reloader"
+)
+
+// maxReputationResponseSize limits the size of an HTTP reputation lookup
+// service's response.
+const maxReputationResponseSize = 4096
+
+// defaultHTTPReputationTimeout limits an HTTP reputation lookup when its
+// provider isn't configured with a timeout.
+const defaultHTTPReputationTimeout = 10 * time.Second
+
+// blocklistProvider is a ReputationProvider listing the domains in a file,
+// along with their subdomains. The file is reloaded whenever it changes.
+type blocklistProvider struct {
+	name string
+	log  blog.Logger
+
+	mu      sync.RWMutex
+	domains map[string]bool
+}
+
+func newBlocklistProvider(name, filename string, log blog.Logger) (*blocklistProvider, error) {
+	if filename == "" {
+		return nil, fmt.Errorf("no blocklist file configured")
+	}
+	p := &blocklistProvider{name: name, log: log}
+	_, err := reloader.New(filename, p.load, p.loadError)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *blocklistProvider) load(b []byte) error {
+	hash := sha256.Sum256(b)
+	p.log.Infof("loading reputation blocklist %q, sha256: %s", p.name, hex.EncodeToString(hash[:]))
+	domains := make(map[string]bool)
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains[strings.ToLower(strings.TrimSuffix(line, "."))] = true
+	}
+	p.mu.Lock()
+	p.domains = domains
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *blocklistProvider) loadError(err error) {
+	p.log.AuditErrf("error loading reputation blocklist %q: %s", p.name, err)
+}
+
+// IsListed returns a reason if the domain, or one of its parent domains, is
+// in the blocklist.
+func (p *blocklistProvider) IsListed(_ context.Context, domain string) (string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	name := strings.ToLower(domain)
+	for {
+		if p.domains[name] {
+			return fmt.Sprintf("listed in blocklist as %s", name), nil
+		}
+		i := strings.Index(name, ".")
+		if i < 0 {
+			return "", nil
+		}
+		name = name[i+1:]
+	}
+}
+
+// httpReputationProvider is a ReputationProvider looking domains up with an
+// HTTP JSON service.
+type httpReputationProvider struct {
+	url    *url.URL
+	client *http.Client
+}
+
+// newHTTPReputationProvider returns a provider looking domains up at rawURL,
+// giving up on a lookup after timeout, or defaultHTTPReputationTimeout if it
+// is zero.
+func newHTTPReputationProvider(rawURL string, timeout time.Duration) (*httpReputationProvider, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("lookup URL %q is not an HTTP or HTTPS URL", rawURL)
+	}
+	if timeout == 0 {
+		timeout = defaultHTTPReputationTimeout
+	}
+	return &httpReputationProvider{url: u, client: &http.Client{Timeout: timeout}}, nil
+}
+
+// IsListed requests the provider's URL with the domain in the "domain" query
+// parameter, and returns the reason from the response if it lists the domain.
+func (p *httpReputationProvider) IsListed(ctx context.Context, domain string) (string, error) {
+	u := *p.url
+	query := u.Query()
+	query.Set("domain", domain)
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := p.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("reputation lookup returned HTTP status %d", resp.StatusCode)
+	}
+	var result struct {
+		Listed bool   `json:"listed"`
+		Reason string `json:"reason"`
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxReputationResponseSize)).Decode(&result)
+	if err != nil {
+		return "", fmt.Errorf("invalid reputation lookup response: %s", err)
+	}
+	if !result.Listed {
+		return "", nil
+	}
+	if result.Reason == "" {
+		return "listed by lookup service", nil
+	}
+	return result.Reason, nil
+}
+
+// rblProvider is a ReputationProvider looking domains up in a DNS zone, in
+// the style of a DNS blocklist. A domain is listed if there are TXT records
+// for it under the zone, which give the reason.
+type rblProvider struct {
+	zone      string
+	dnsClient bdns.DNSClient
+}
+
+func newRBLProvider(zone string, dnsClient bdns.DNSClient) (*rblProvider, error) {
+	zone = strings.Trim(zone, ".")
+	if zone == "" {
+		return nil, fmt.Errorf("no zone configured")
+	}
+	return &rblProvider{zone: zone, dnsClient: dnsClient}, nil
+}
+
+func (p *rblProvider) IsListed(ctx context.Context, domain string) (string, error) {
+	txts, _, err := p.dnsClient.LookupTXT(ctx, domain+"."+p.zone)
+	if dnsErr, ok := err.(*bdns.DNSError); ok && dnsErr.NXDomain() {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(txts) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("listed by %s: %s", p.zone, strings.Join(txts, "; ")), nil
+}
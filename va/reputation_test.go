@@ -0,0 +1,257 @@
+package va
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/letsencrypt/boulder/bdns"
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/features"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/test"
+)
+
+// fakeReputationProvider lists the domains in its map, with their values as
+// the reason, and fails to check "errorful.com".
+type fakeReputationProvider map[string]string
+
+func (p fakeReputationProvider) IsListed(_ context.Context, domain string) (string, error) {
+	if domain == "errorful.com" {
+		return "", errors.New("welp")
+	}
+	return p[domain], nil
+}
+
+func writeBlocklist(t *testing.T, contents string) string {
+	f, err := ioutil.TempFile("", "reputation-blocklist")
+	test.AssertNotError(t, err, "Failed to create blocklist file")
+	_, err = f.WriteString(contents)
+	test.AssertNotError(t, err, "Failed to write blocklist file")
+	test.AssertNotError(t, f.Close(), "Failed to close blocklist file")
+	return f.Name()
+}
+
+func TestSetReputationChecks(t *testing.T) {
+	va, _ := setup(nil, 0)
+	blocklist := writeBlocklist(t, "bad.com\n")
+	defer func() { _ = os.Remove(blocklist) }()
+
+	err := va.SetReputationChecks(cmd.ReputationConfig{
+		Action: "log",
+		Providers: []cmd.ReputationProviderConfig{
+			{Name: "local", Type: "blocklist", File: blocklist},
+			{Name: "lookup", Type: "http", URL: "https://reputation.example.com/check", Action: "block"},
+			{Name: "dbl", Type: "rbl", Zone: "rbl.invalid.", Timeout: cmd.ConfigDuration{Duration: time.Second}},
+		},
+	})
+	test.AssertNotError(t, err, "SetReputationChecks failed")
+	test.AssertEquals(t, va.safeBrowsingBlocks, false)
+	test.AssertEquals(t, len(va.reputationChecks), 3)
+	test.AssertEquals(t, va.reputationChecks[0].name, "local")
+	test.AssertEquals(t, va.reputationChecks[0].block, false)
+	test.AssertEquals(t, va.reputationChecks[1].block, true)
+	test.AssertEquals(t, va.reputationChecks[2].provider.(*rblProvider).zone, "rbl.invalid")
+	test.AssertEquals(t, va.reputationChecks[2].timeout, time.Second)
+
+	// Without a Google Safe Browsing client it isn't checked
+	test.AssertEquals(t, len(va.allReputationChecks(true)), 3)
+	va.safeBrowsing = fakeReputationProvider{}
+	checks := va.allReputationChecks(true)
+	test.AssertEquals(t, len(checks), 4)
+	test.AssertEquals(t, checks[0].name, gsbProviderName)
+	test.AssertEquals(t, checks[0].block, false)
+	test.AssertEquals(t, len(va.allReputationChecks(false)), 3)
+
+	for _, c := range []cmd.ReputationConfig{
+		{Action: "ignore"},
+		{Providers: []cmd.ReputationProviderConfig{{Type: "rbl", Zone: "rbl.invalid"}}},
+		{Providers: []cmd.ReputationProviderConfig{{Name: "gsb", Type: "rbl", Zone: "rbl.invalid"}}},
+		{Providers: []cmd.ReputationProviderConfig{
+			{Name: "dbl", Type: "rbl", Zone: "rbl.invalid"},
+			{Name: "dbl", Type: "rbl", Zone: "rbl.invalid"},
+		}},
+		{Providers: []cmd.ReputationProviderConfig{{Name: "dbl", Type: "rbl", Zone: "rbl.invalid", Action: "ignore"}}},
+		{Providers: []cmd.ReputationProviderConfig{{Name: "dbl", Type: "rbl"}}},
+		{Providers: []cmd.ReputationProviderConfig{{Name: "dbl", Type: "rbl", Zone: "rbl.invalid",
+			Timeout: cmd.ConfigDuration{Duration: -time.Second}}}},
+		{Providers: []cmd.ReputationProviderConfig{{Name: "local", Type: "blocklist"}}},
+		{Providers: []cmd.ReputationProviderConfig{{Name: "local", Type: "blocklist", File: "/does/not/exist"}}},
+		{Providers: []cmd.ReputationProviderConfig{{Name: "lookup", Type: "http", URL: "ftp://example.com"}}},
+		{Providers: []cmd.ReputationProviderConfig{{Name: "other", Type: "whois"}}},
+	} {
+		err := va.SetReputationChecks(c)
+		test.AssertError(t, err, fmt.Sprintf("SetReputationChecks accepted %#v", c))
+	}
+}
+
+func TestCheckReputation(t *testing.T) {
+	va, mockLog := setup(nil, 0)
+
+	// Without any providers every domain is safe
+	verdicts, safe := va.checkReputation(ctx, "bad.com", true)
+	test.Assert(t, safe, "Domain unsafe without any providers")
+	test.AssertEquals(t, len(verdicts), 0)
+
+	va.reputationChecks = []reputationCheck{
+		{name: "blocking", provider: fakeReputationProvider{"bad.com": "malware"}, block: true},
+		{name: "logging", provider: fakeReputationProvider{"bad.com": "phishing", "meh.com": "spam"}},
+	}
+
+	verdicts, safe = va.checkReputation(ctx, "bad.com", true)
+	test.Assert(t, !safe, "bad.com was considered safe")
+	test.AssertEquals(t, len(verdicts), 2)
+	test.AssertEquals(t, verdicts[0].Provider, "blocking")
+	test.AssertEquals(t, verdicts[0].Reason, "malware")
+	test.AssertEquals(t, verdicts[0].Blocked, true)
+	test.AssertEquals(t, verdicts[1].Provider, "logging")
+	test.AssertEquals(t, verdicts[1].Reason, "phishing")
+	test.AssertEquals(t, verdicts[1].Blocked, false)
+	test.Assert(t, !verdicts[0].CheckedAt.IsZero(), "Verdict has no check time")
+	test.AssertEquals(t, len(mockLog.GetAllMatching(`Reputation provider "blocking" listed "bad.com" \(action: block\): malware`)), 1)
+	test.AssertEquals(t, len(mockLog.GetAllMatching(`Reputation provider "logging" listed "bad.com" \(action: log\): phishing`)), 1)
+
+	// A listing by a provider that only logs leaves the domain safe
+	verdicts, safe = va.checkReputation(ctx, "meh.com", true)
+	test.Assert(t, safe, "meh.com was considered unsafe")
+	test.AssertEquals(t, verdicts[1].Reason, "spam")
+
+	// Errors are recorded, and treated as the domain not being listed
+	verdicts, safe = va.checkReputation(ctx, "errorful.com", true)
+	test.Assert(t, safe, "errorful.com was considered unsafe")
+	test.AssertEquals(t, verdicts[0].Error, "welp")
+	test.AssertEquals(t, verdicts[0].Reason, "")
+}
+
+func TestReputationAtValidation(t *testing.T) {
+	chall := core.HTTPChallenge01()
+	setChallengeToken(&chall, core.NewToken())
+	hs := httpSrv(t, chall.Token)
+	defer hs.Close()
+	va, _ := setup(hs, 0)
+
+	_ = features.Set(map[string]bool{"VAChecksGSB": true})
+	defer features.Reset()
+
+	va.reputationChecks = []reputationCheck{
+		{name: "logging", provider: fakeReputationProvider{"meh.com": "spam"}},
+		{name: "blocking", provider: fakeReputationProvider{"bad.com": "malware"}, block: true},
+	}
+
+	_, _, reputation, prob := va.validate(ctx, dnsi("bad.com"), chall, core.Authorization{})
+	test.AssertNotNil(t, prob, "Validation succeeded for bad.com")
+	test.Assert(t, strings.Contains(prob.Error(), "unsafe domain"), "Expected an unsafe domain error")
+	test.AssertEquals(t, len(reputation), 2)
+	test.AssertEquals(t, reputation[1].Blocked, true)
+
+	_, _, reputation, prob = va.validate(ctx, dnsi("meh.com"), chall, core.Authorization{})
+	test.Assert(t, prob == nil, fmt.Sprintf("Validation failed for meh.com: %s", prob))
+	test.AssertEquals(t, reputation[0].Reason, "spam")
+	test.AssertEquals(t, reputation[0].Blocked, false)
+
+	// Wildcard names are checked without the wildcard label
+	_, _, reputation, _ = va.validate(ctx, dnsi("*.bad.com"), chall, core.Authorization{})
+	test.AssertEquals(t, reputation[1].Reason, "malware")
+
+	// Without VAChecksGSB the configured checks still run, but Google Safe
+	// Browsing is left to the RA
+	features.Reset()
+	va.safeBrowsing = fakeReputationProvider{"meh.com": "unwanted software"}
+	_, _, _, prob = va.validate(ctx, dnsi("bad.com"), chall, core.Authorization{})
+	test.AssertNotNil(t, prob, "Validation succeeded for bad.com without VAChecksGSB")
+	_, _, reputation, _ = va.validate(ctx, dnsi("meh.com"), chall, core.Authorization{})
+	test.AssertEquals(t, len(reputation), 2)
+	test.AssertEquals(t, reputation[0].Provider, "logging")
+}
+
+func TestBlocklistProvider(t *testing.T) {
+	filename := writeBlocklist(t, "# Comment\n\nBad.com\nworse.net.\n")
+	defer func() { _ = os.Remove(filename) }()
+	p, err := newBlocklistProvider("local", filename, blog.NewMock())
+	test.AssertNotError(t, err, "newBlocklistProvider failed")
+
+	for domain, expected := range map[string]string{
+		"bad.com":         "listed in blocklist as bad.com",
+		"www.BAD.com":     "listed in blocklist as bad.com",
+		"a.b.worse.net":   "listed in blocklist as worse.net",
+		"notbad.com":      "",
+		"bad.com.example": "",
+		"com":             "",
+	} {
+		reason, err := p.IsListed(ctx, domain)
+		test.AssertNotError(t, err, "IsListed failed")
+		test.AssertEquals(t, reason, expected)
+	}
+
+	// Reloading the file replaces the blocklist
+	err = p.load([]byte("notbad.com\n"))
+	test.AssertNotError(t, err, "load failed")
+	reason, _ := p.IsListed(ctx, "bad.com")
+	test.AssertEquals(t, reason, "")
+	reason, _ = p.IsListed(ctx, "notbad.com")
+	test.AssertEquals(t, reason, "listed in blocklist as notbad.com")
+}
+
+func TestHTTPReputationProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("domain") {
+		case "bad.com":
+			fmt.Fprint(w, `{"listed": true, "reason": "phishing"}`)
+		case "vague.com":
+			fmt.Fprint(w, `{"listed": true}`)
+		case "good.com":
+			fmt.Fprint(w, `{"listed": false}`)
+		case "garbled.com":
+			fmt.Fprint(w, `listed`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	p, err := newHTTPReputationProvider(srv.URL+"/check?key=secret", 0)
+	test.AssertNotError(t, err, "newHTTPReputationProvider failed")
+	test.AssertEquals(t, p.client.Timeout, defaultHTTPReputationTimeout)
+	configured, err := newHTTPReputationProvider(srv.URL, time.Second)
+	test.AssertNotError(t, err, "newHTTPReputationProvider failed")
+	test.AssertEquals(t, configured.client.Timeout, time.Second)
+
+	reason, err := p.IsListed(ctx, "bad.com")
+	test.AssertNotError(t, err, "IsListed failed")
+	test.AssertEquals(t, reason, "phishing")
+	reason, err = p.IsListed(ctx, "vague.com")
+	test.AssertNotError(t, err, "IsListed failed")
+	test.AssertEquals(t, reason, "listed by lookup service")
+	reason, err = p.IsListed(ctx, "good.com")
+	test.AssertNotError(t, err, "IsListed failed")
+	test.AssertEquals(t, reason, "")
+
+	_, err = p.IsListed(ctx, "garbled.com")
+	test.AssertError(t, err, "IsListed accepted an invalid response")
+	_, err = p.IsListed(ctx, "errorful.com")
+	test.AssertError(t, err, "IsListed accepted an HTTP 500 response")
+	test.AssertEquals(t, err.Error(), "reputation lookup returned HTTP status 500")
+}
+
+func TestRBLProvider(t *testing.T) {
+	p, err := newRBLProvider("rbl.invalid", &bdns.MockDNSClient{})
+	test.AssertNotError(t, err, "newRBLProvider failed")
+
+	reason, err := p.IsListed(ctx, "listed.com")
+	test.AssertNotError(t, err, "IsListed failed")
+	test.AssertEquals(t, reason, "listed by rbl.invalid: phishing")
+
+	// NXDOMAIN means the domain isn't listed
+	reason, err = p.IsListed(ctx, "unlisted.com")
+	test.AssertNotError(t, err, "IsListed failed")
+	test.AssertEquals(t, reason, "")
+}
@@ -37,11 +37,13 @@ import (
 )
 
 const (
+	// Default number of redirects followed by HTTP-01 fetches
 	maxRedirect      = 10
 	whitespaceCutset = "\n\r\t "
 	// Payload should be ~87 bytes. Since it may be padded by whitespace which we previously
 	// allowed accept up to 128 bytes before rejecting a response
 	// (32 byte b64 encoded token + . + 32 byte b64 encoded key fingerprint)
+	// This is the HTTP-01 policy's default.
 	maxResponseSize = 128
 
 	// ALPN protocol ID for TLS-ALPN-01 challenge
@@ -108,7 +110,7 @@ func initMetrics(stats metrics.Scope) *vaMetrics {
 type ValidationAuthorityImpl struct {
 	log                blog.Logger
 	dnsClient          bdns.DNSClient
-	issuerDomain       string
+	caaIdentities      []cmd.CAAIdentityConfig
 	safeBrowsing       SafeBrowsing
 	httpPort           int
 	httpsPort          int
@@ -119,6 +121,9 @@ type ValidationAuthorityImpl struct {
 	remoteVAs          []RemoteVA
 	maxRemoteFailures  int
 	accountURIPrefixes []string
+	http01Policy       http01Policy
+	safeBrowsingBlocks bool
+	reputationChecks   []reputationCheck
 
 	metrics *vaMetrics
 }
@@ -131,7 +136,7 @@ func NewValidationAuthorityImpl(
 	remoteVAs []RemoteVA,
 	maxRemoteFailures int,
 	userAgent string,
-	issuerDomain string,
+	caaIdentities []cmd.CAAIdentityConfig,
 	stats metrics.Scope,
 	clk clock.Clock,
 	logger blog.Logger,
@@ -151,10 +156,25 @@ func NewValidationAuthorityImpl(
 		return nil, errors.New("no account URI prefixes configured")
 	}
 
+	if len(caaIdentities) == 0 {
+		return nil, errors.New("no CAA identities configured")
+	}
+	// CAA issuer domains are compared case-insensitively.
+	identities := make([]cmd.CAAIdentityConfig, len(caaIdentities))
+	for i, identity := range caaIdentities {
+		if identity.Domain == "" {
+			return nil, errors.New("CAA identity with an empty domain configured")
+		}
+		identities[i] = cmd.CAAIdentityConfig{
+			Domain:             strings.ToLower(identity.Domain),
+			AccountURIPrefixes: identity.AccountURIPrefixes,
+		}
+	}
+
 	return &ValidationAuthorityImpl{
 		log:                logger,
 		dnsClient:          resolver,
-		issuerDomain:       issuerDomain,
+		caaIdentities:      identities,
 		safeBrowsing:       sbc,
 		httpPort:           pc.HTTPPort,
 		httpsPort:          pc.HTTPSPort,
@@ -166,6 +186,8 @@ func NewValidationAuthorityImpl(
 		remoteVAs:          remoteVAs,
 		maxRemoteFailures:  maxRemoteFailures,
 		accountURIPrefixes: accountURIPrefixes,
+		http01Policy:       defaultHTTP01Policy(pc.HTTPPort, pc.HTTPSPort),
+		safeBrowsingBlocks: true,
 	}, nil
 }
 
@@ -213,6 +235,7 @@ type http01Dialer struct {
 	addrs       []net.IP
 	hostname    string
 	port        string
+	ipv4First   bool
 	stats       metrics.Scope
 	dialerCount int
 
@@ -229,10 +252,11 @@ func (d *http01Dialer) realDialer() *net.Dialer {
 	return &net.Dialer{Timeout: singleDialTimeout}
 }
 
-// DialContext processes the IP addresses from the inner validation record, using
-// `realDialer` to make connections as required. For dual-homed hosts an initial
-// IPv6 connection will be made followed by a IPv4 connection if there is a failure
-// with the IPv6 connection.
+// DialContext connects to one of the addresses in `addrs` using
+// `dualStackDial`, with a new `realDialer` for each connection attempt. For
+// dual-homed hosts an initial IPv6 connection will be made followed by a IPv4
+// connection if there is a failure with the IPv6 connection, or the other way
+// around if `ipv4First` is set.
 func (d *http01Dialer) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
 	deadline, ok := ctx.Deadline()
 	if !ok {
@@ -247,50 +271,12 @@ func (d *http01Dialer) DialContext(ctx context.Context, _, _ string) (net.Conn,
 	ctx, cancel := context.WithDeadline(ctx, deadline)
 	defer cancel()
 
-	var realDialer *net.Dialer
-	var addrInfo addrRecord
-
-	// Split the available addresses into v4 and v6 addresses
-	v4, v6 := availableAddresses(d.addrs)
-
-	// If there is at least one IPv6 address then try it first
-	if len(v6) > 0 {
-		address := net.JoinHostPort(v6[0].String(), d.port)
-		addrInfo.used = v6[0]
-		realDialer = d.realDialer()
-		conn, err := realDialer.DialContext(ctx, "tcp", address)
-
-		// If there is no error, return immediately
-		if err == nil {
-			d.addrInfoChan <- addrInfo
-			return conn, err
-		}
-
-		// Otherwise, we note that we tried an address and fall back to trying IPv4
-		addrInfo.tried = append(addrInfo.tried, addrInfo.used)
-		d.stats.Inc("IPv4Fallback", 1)
-	}
-
-	// If there are no IPv4 addresses and we tried an IPv6 address return an
-	// error - there's nothing left to try
-	if len(v4) == 0 && len(addrInfo.tried) > 0 {
-		d.addrInfoChan <- addrInfo
-		return nil,
-			fmt.Errorf("Unable to contact %q at %q, no IPv4 addresses to try as fallback",
-				d.hostname, addrInfo.tried[0])
-	} else if len(v4) == 0 && len(addrInfo.tried) == 0 {
-		// It shouldn't be possible that there are no IPv4 addresses and no previous
-		// attempts at an IPv6 address connection but be defensive about it anyway
-		d.addrInfoChan <- addrInfo
-		return nil, fmt.Errorf("no IP addresses found for %q", d.hostname)
-	}
-
-	// Otherwise if there are no IPv6 addresses, or there was an error
-	// talking to the first IPv6 address, try the first IPv4 address
-	addrInfo.used = v4[0]
+	conn, addrInfo, err := dualStackDial(ctx, d.hostname, d.addrs, d.port, d.ipv4First, d.stats,
+		func(ctx context.Context, address string) (net.Conn, error) {
+			return d.realDialer().DialContext(ctx, "tcp", address)
+		})
 	d.addrInfoChan <- addrInfo
-	realDialer = d.realDialer()
-	return realDialer.DialContext(ctx, "tcp", net.JoinHostPort(v4[0].String(), d.port))
+	return conn, err
 }
 
 // availableAddresses takes a ValidationRecord and splits the AddressesResolved
@@ -313,6 +299,7 @@ func (va *ValidationAuthorityImpl) newHTTP01Dialer(host string, port int, addrs
 		hostname:     host,
 		port:         strconv.Itoa(port),
 		addrs:        addrs,
+		ipv4First:    va.http01Policy.ipv4First,
 		stats:        va.stats,
 		addrInfoChan: make(chan addrRecord, 1),
 	}
@@ -320,8 +307,18 @@ func (va *ValidationAuthorityImpl) newHTTP01Dialer(host string, port int, addrs
 
 // Validation methods
 
+// fetchHTTP fetches the given path from the identifier's host, following
+// redirects as allowed by the VA's HTTP-01 policy. It returns a validation
+// record for every hop of the fetch, including a redirect target that the
+// policy rejected or that couldn't be resolved.
 func (va *ValidationAuthorityImpl) fetchHTTP(ctx context.Context, identifier core.AcmeIdentifier, path string, useTLS bool, input core.Challenge) ([]byte, []core.ValidationRecord, *probs.ProblemDetails) {
 	challenge := input
+	policy := va.http01Policy
+	if policy.maxTotalTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, policy.maxTotalTime)
+		defer cancel()
+	}
 
 	host := identifier.Value
 	scheme := "http"
@@ -397,13 +394,7 @@ func (va *ValidationAuthorityImpl) fetchHTTP(ctx context.Context, identifier cor
 	// do many more things to satisfy misunderstandings around HTTP.
 	httpRequest.Header.Set("Accept", "*/*")
 
-	numRedirects := 0
 	logRedirect := func(req *http.Request, via []*http.Request) error {
-		if numRedirects >= maxRedirect {
-			return fmt.Errorf("Too many redirects")
-		}
-		numRedirects++
-
 		// Set Accept header for mod_security (see the other place the header is
 		// set)
 		req.Header.Set("Accept", "*/*")
@@ -411,24 +402,9 @@ func (va *ValidationAuthorityImpl) fetchHTTP(ctx context.Context, identifier cor
 			req.Header["User-Agent"] = []string{va.userAgent}
 		}
 
-		urlHost = req.URL.Host
-		reqHost := req.URL.Host
-		var reqPort int
-		if h, p, err := net.SplitHostPort(reqHost); err == nil {
-			reqHost = h
-			reqPort, err = strconv.Atoi(p)
-			if err != nil {
-				return err
-			}
-			if reqPort != va.httpPort && reqPort != va.httpsPort {
-				return berrors.ConnectionFailureError(
-					"Invalid port in redirect target. Only ports %d and %d are supported, not %d",
-					va.httpPort, va.httpsPort, reqPort)
-			}
-		} else if strings.ToLower(req.URL.Scheme) == "https" {
-			reqPort = va.httpsPort
-		} else {
-			reqPort = va.httpPort
+		reqHost, reqPort, err := va.redirectTarget(req.URL)
+		if err != nil {
+			return err
 		}
 
 		// Since we've used dialer.DialContext we need to drain the address info
@@ -439,19 +415,29 @@ func (va *ValidationAuthorityImpl) fetchHTTP(ctx context.Context, identifier cor
 		record.AddressUsed, record.AddressesTried = addrInfo.used, addrInfo.tried
 		validationRecords = append(validationRecords, record)
 
-		// Update base record host, port, and URL for next dial. If there isn't
+		// Start a new base record for the redirect target. If there isn't
 		// another redirect this will be used by the parent scope to construct
 		// the final record.
-		baseRecord.Hostname = reqHost
-		baseRecord.Port = strconv.Itoa(reqPort)
-		baseRecord.URL = req.URL.String()
+		baseRecord = core.ValidationRecord{
+			Hostname: reqHost,
+			Port:     strconv.Itoa(reqPort),
+			URL:      req.URL.String(),
+		}
+
+		// If the policy rejects the redirect, or the target can't be resolved,
+		// we won't call dialer.DialContext again and the parent scope will
+		// block waiting for something from dialer.addrInfoChan, so we put an
+		// empty addrRecord struct in the channel. The target is then recorded
+		// without any addresses.
+		err = policy.checkRedirect(host, len(via), req.URL, reqHost, reqPort)
+		if err != nil {
+			dialer.addrInfoChan <- addrRecord{}
+			return err
+		}
 
 		// Resolve new hostname and construct a new dialer
 		addrs, prob := va.getAddrs(ctx, reqHost)
 		if prob != nil {
-			// Since we won't call dialer.DialContext again the parent scope
-			// will block waiting for something from dialer.addrInfoChan so
-			// we put an empty addrRecord struct in the channel.
 			dialer.addrInfoChan <- addrRecord{}
 			return prob
 		}
@@ -478,7 +464,7 @@ func (va *ValidationAuthorityImpl) fetchHTTP(ctx context.Context, identifier cor
 		return nil, validationRecords, detailedError(err)
 	}
 
-	body, err := ioutil.ReadAll(&io.LimitedReader{R: httpResponse.Body, N: maxResponseSize})
+	body, err := ioutil.ReadAll(&io.LimitedReader{R: httpResponse.Body, N: policy.maxResponseSize})
 	closeErr := httpResponse.Body.Close()
 	if err == nil {
 		err = closeErr
@@ -488,8 +474,8 @@ func (va *ValidationAuthorityImpl) fetchHTTP(ctx context.Context, identifier cor
 		return nil, validationRecords, probs.Unauthorized("Error reading HTTP response body: %v", err)
 	}
 	// io.LimitedReader will silently truncate a Reader so if the
-	// resulting payload is the same size as maxResponseSize fail
-	if len(body) >= maxResponseSize {
+	// resulting payload is the same size as the policy's maxResponseSize fail
+	if int64(len(body)) >= policy.maxResponseSize {
 		return nil, validationRecords, probs.Unauthorized("Invalid response from %s: \"%s\"", url, body)
 	}
 
@@ -532,49 +518,22 @@ func (va *ValidationAuthorityImpl) tryGetTLSCerts(ctx context.Context,
 	}
 	thisRecord := &validationRecords[0]
 
-	// Split the available addresses into v4 and v6 addresses
-	v4, v6 := availableAddresses(allAddrs)
-	addresses := append(v4, v6...)
-
-	// This shouldn't happen, but be defensive about it anyway
-	if len(addresses) < 1 {
-		return nil, nil, validationRecords, probs.Malformed("no IP addresses found for %q", identifier.Value)
-	}
-
-	// If there is at least one IPv6 address then try it first
-	if len(v6) > 0 {
-		address := net.JoinHostPort(v6[0].String(), thisRecord.Port)
-		thisRecord.AddressUsed = v6[0]
-
-		certs, cs, err := va.getTLSCerts(ctx, address, identifier, challenge, tlsConfig)
-
-		// If there is no error, return immediately
-		if err == nil {
-			return certs, cs, validationRecords, err
-		}
-
-		// Otherwise, we note that we tried an address and fall back to trying IPv4
-		thisRecord.AddressesTried = append(thisRecord.AddressesTried, thisRecord.AddressUsed)
-		va.stats.Inc("IPv4Fallback", 1)
-	}
-
-	// If there are no IPv4 addresses and we tried an IPv6 address return
-	// an error - there's nothing left to try
-	if len(v4) == 0 && len(thisRecord.AddressesTried) > 0 {
-		return nil, nil, validationRecords, probs.Malformed("Unable to contact %q at %q, no IPv4 addresses to try as fallback",
-			thisRecord.Hostname, thisRecord.AddressesTried[0])
-	} else if len(v4) == 0 && len(thisRecord.AddressesTried) == 0 {
-		// It shouldn't be possible that there are no IPv4 addresses and no previous
-		// attempts at an IPv6 address connection but be defensive about it anyway
-		return nil, nil, validationRecords, probs.Malformed("No IP addresses found for %q", thisRecord.Hostname)
+	// Connect using the same dual-stack strategy and address order as
+	// HTTP-01. The TLS handshake only happens once connected, so its failures
+	// don't cause a fallback to another address.
+	dialer := &net.Dialer{Timeout: singleDialTimeout}
+	conn, addrInfo, err := dualStackDial(ctx, identifier.Value, allAddrs, thisRecord.Port, va.http01Policy.ipv4First, va.stats,
+		func(ctx context.Context, address string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp", address)
+		})
+	thisRecord.AddressUsed, thisRecord.AddressesTried = addrInfo.used, addrInfo.tried
+	if err != nil {
+		va.log.Infof("%s connection failure for %s. err=[%#v] errStr=[%s]", challenge.Type, identifier, err, err)
+		return nil, nil, validationRecords, detailedError(err)
 	}
 
-	// Otherwise if there are no IPv6 addresses, or there was an error
-	// talking to the first IPv6 address, try the first IPv4 address
-	thisRecord.AddressUsed = v4[0]
-	certs, cs, err := va.getTLSCerts(ctx, net.JoinHostPort(v4[0].String(), thisRecord.Port),
-		identifier, challenge, tlsConfig)
-	return certs, cs, validationRecords, err
+	certs, cs, problem := va.getTLSCerts(ctx, conn, identifier, challenge, tlsConfig)
+	return certs, cs, validationRecords, problem
 }
 
 func (va *ValidationAuthorityImpl) validateTLSSNI01WithZName(ctx context.Context, identifier core.AcmeIdentifier, challenge core.Challenge, zName string) ([]core.ValidationRecord, *probs.ProblemDetails) {
@@ -599,17 +558,19 @@ func (va *ValidationAuthorityImpl) validateTLSSNI01WithZName(ctx context.Context
 	return validationRecords, problem
 }
 
+// getTLSCerts completes a TLS handshake over netConn, which it closes, and
+// returns the certificates presented.
 func (va *ValidationAuthorityImpl) getTLSCerts(
 	ctx context.Context,
-	hostPort string,
+	netConn net.Conn,
 	identifier core.AcmeIdentifier,
 	challenge core.Challenge,
 	config *tls.Config,
 ) ([]*x509.Certificate, *tls.ConnectionState, *probs.ProblemDetails) {
-	va.log.Info(fmt.Sprintf("%s [%s] Attempting to validate for %s %s", challenge.Type, identifier, hostPort, config.ServerName))
+	va.log.Info(fmt.Sprintf("%s [%s] Attempting to validate for %s %s", challenge.Type, identifier, netConn.RemoteAddr(), config.ServerName))
 	// We expect a self-signed challenge certificate, do not verify it here.
 	config.InsecureSkipVerify = true
-	conn, err := tlsDial(ctx, hostPort, config)
+	conn, err := tlsHandshake(ctx, netConn, config)
 
 	if err != nil {
 		va.log.Infof("%s connection failure for %s. err=[%#v] errStr=[%s]", challenge.Type, identifier, err, err)
@@ -633,26 +594,23 @@ func (va *ValidationAuthorityImpl) getTLSCerts(
 	return certs, &cs, nil
 }
 
-// tlsDial does the equivalent of tls.Dial, but obeying a context. Once
-// tls.DialContextWithDialer is available, switch to that.
-func tlsDial(ctx context.Context, hostPort string, config *tls.Config) (*tls.Conn, error) {
+// tlsHandshake does the equivalent of tls.Client followed by a handshake,
+// but obeying a context. netConn is closed if the handshake fails.
+func tlsHandshake(ctx context.Context, netConn net.Conn, config *tls.Config) (*tls.Conn, error) {
 	ctx, cancel := context.WithTimeout(ctx, singleDialTimeout)
 	defer cancel()
-	dialer := &net.Dialer{}
-	netConn, err := dialer.DialContext(ctx, "tcp", hostPort)
-	if err != nil {
-		return nil, err
-	}
 	conn := tls.Client(netConn, config)
-	errChan := make(chan error)
+	errChan := make(chan error, 1)
 	go func() {
 		errChan <- conn.Handshake()
 	}()
 	select {
 	case <-ctx.Done():
+		_ = netConn.Close()
 		return nil, ctx.Err()
 	case err := <-errChan:
 		if err != nil {
+			_ = netConn.Close()
 			return nil, err
 		}
 	}
@@ -868,15 +826,16 @@ func (va *ValidationAuthorityImpl) validateDNS01(ctx context.Context, identifier
 }
 
 // validate performs a challenge validation and, in parallel,
-// checks CAA and GSB for the identifier. If any of those steps fails, it
-// returns a ProblemDetails plus the validation records created during the
-// validation attempt.
+// checks CAA and the domain's reputation for the identifier. If any of those
+// steps fails, it returns a ProblemDetails plus the validation records created
+// during the validation attempt. Once the CAA and reputation checks have
+// completed, their records are returned too.
 func (va *ValidationAuthorityImpl) validate(
 	ctx context.Context,
 	identifier core.AcmeIdentifier,
 	challenge core.Challenge,
 	authz core.Authorization,
-) ([]core.ValidationRecord, *probs.ProblemDetails) {
+) ([]core.ValidationRecord, *core.CAAValidationRecord, []core.ReputationVerdict, *probs.ProblemDetails) {
 
 	// If the identifier is a wildcard domain we need to validate the base
 	// domain by removing the "*." wildcard prefix. We create a separate
@@ -889,17 +848,27 @@ func (va *ValidationAuthorityImpl) validate(
 
 	// va.checkCAA accepts wildcard identifiers and handles them appropriately so
 	// we can dispatch `checkCAA` with the provided `identifier` instead of
-	// `baseIdentifier`
+	// `baseIdentifier`. caaRecord and reputation are only read after the
+	// goroutines have sent their results on ch.
+	var caaRecord *core.CAAValidationRecord
+	var reputation []core.ReputationVerdict
 	ch := make(chan *probs.ProblemDetails, 2)
 	go func() {
 		params := &caaParams{
 			accountURIID:     &authz.RegistrationID,
 			validationMethod: &challenge.Type,
 		}
-		ch <- va.checkCAA(ctx, identifier, params)
+		var prob *probs.ProblemDetails
+		caaRecord, prob = va.checkCAA(ctx, identifier, params)
+		ch <- prob
 	}()
 	go func() {
-		if features.Enabled(features.VAChecksGSB) && !va.isSafeDomain(ctx, baseIdentifier.Value) {
+		// The configured reputation checks always run, but Google Safe
+		// Browsing is only checked here with the VAChecksGSB feature.
+		// Without it, the RA checks it before creating authorizations.
+		var safe bool
+		reputation, safe = va.checkReputation(ctx, baseIdentifier.Value, features.Enabled(features.VAChecksGSB))
+		if !safe {
 			ch <- probs.Unauthorized("%q was considered an unsafe domain by a third-party API",
 				baseIdentifier.Value)
 		} else {
@@ -910,15 +879,18 @@ func (va *ValidationAuthorityImpl) validate(
 	// TODO(#1292): send into another goroutine
 	validationRecords, err := va.validateChallenge(ctx, baseIdentifier, challenge)
 	if err != nil {
-		return validationRecords, err
+		return validationRecords, nil, nil, err
 	}
 
+	// Wait for both checks so that their records are complete, returning the
+	// first problem found.
+	var prob *probs.ProblemDetails
 	for i := 0; i < cap(ch); i++ {
-		if extraProblem := <-ch; extraProblem != nil {
-			return validationRecords, extraProblem
+		if extraProblem := <-ch; extraProblem != nil && prob == nil {
+			prob = extraProblem
 		}
 	}
-	return validationRecords, nil
+	return validationRecords, caaRecord, reputation, prob
 }
 
 func (va *ValidationAuthorityImpl) validateChallenge(ctx context.Context, identifier core.AcmeIdentifier, challenge core.Challenge) ([]core.ValidationRecord, *probs.ProblemDetails) {
@@ -943,7 +915,7 @@ func (va *ValidationAuthorityImpl) performRemoteValidation(ctx context.Context,
 	errors := make(chan error, len(va.remoteVAs))
 	for _, remoteVA := range va.remoteVAs {
 		go func(rva RemoteVA) {
-			_, err := rva.PerformValidation(ctx, domain, challenge, authz)
+			_, _, _, err := rva.PerformValidation(ctx, domain, challenge, authz)
 			if err != nil {
 				// returned error can be a nil *probs.ProblemDetails which breaks the
 				// err != nil check so do a slightly more complicated unwrap check to
@@ -1011,8 +983,9 @@ func (va *ValidationAuthorityImpl) performRemoteValidation(ctx context.Context,
 }
 
 // PerformValidation validates the given challenge. It always returns a list of
-// validation records, even when it also returns an error.
-func (va *ValidationAuthorityImpl) PerformValidation(ctx context.Context, domain string, challenge core.Challenge, authz core.Authorization) ([]core.ValidationRecord, error) {
+// validation records, even when it also returns an error. If the CAA and
+// reputation checks completed, their records are returned as well.
+func (va *ValidationAuthorityImpl) PerformValidation(ctx context.Context, domain string, challenge core.Challenge, authz core.Authorization) ([]core.ValidationRecord, *core.CAAValidationRecord, []core.ReputationVerdict, error) {
 	logEvent := verificationRequestEvent{
 		ID:          authz.ID,
 		Requester:   authz.RegistrationID,
@@ -1027,10 +1000,12 @@ func (va *ValidationAuthorityImpl) PerformValidation(ctx context.Context, domain
 		go va.performRemoteValidation(ctx, domain, challenge, authz, remoteError)
 	}
 
-	records, prob := va.validate(ctx, core.AcmeIdentifier{Type: "dns", Value: domain}, challenge, authz)
+	records, caaRecord, reputation, prob := va.validate(ctx, core.AcmeIdentifier{Type: "dns", Value: domain}, challenge, authz)
 
 	logEvent.ValidationRecords = records
 	challenge.ValidationRecord = records
+	challenge.CAARecord = caaRecord
+	challenge.Reputation = reputation
 
 	// Check for malformed ValidationRecords
 	if !challenge.RecordsSane() && prob == nil {
@@ -1074,8 +1049,8 @@ func (va *ValidationAuthorityImpl) PerformValidation(ctx context.Context, domain
 		// non-nil interface value containing a nil pointer, rather than a nil
 		// interface value. See, e.g.
 		// https://stackoverflow.com/questions/29138591/hiding-nil-values-understanding-why-golang-fails-here
-		return records, nil
+		return records, caaRecord, reputation, nil
 	}
 
-	return records, prob
+	return records, caaRecord, reputation, prob
 }
@@ -920,7 +920,7 @@ func TestGSBAtValidation(t *testing.T) {
 	sbc.EXPECT().IsListed(gomock.Any(), "errorful.com").Return("", fmt.Errorf("welp"))
 	va.safeBrowsing = sbc
 
-	_, prob := va.validate(ctx, dnsi("bad.com"), chall, core.Authorization{})
+	_, _, _, prob := va.validate(ctx, dnsi("bad.com"), chall, core.Authorization{})
 	if prob == nil {
 		t.Fatalf("Expected rejection for bad.com, got success")
 	}
@@ -928,12 +928,12 @@ func TestGSBAtValidation(t *testing.T) {
 		t.Errorf("Got error %q, expected an unsafe domain error.", prob.Error())
 	}
 
-	_, prob = va.validate(ctx, dnsi("errorful.com"), chall, core.Authorization{})
+	_, _, _, prob = va.validate(ctx, dnsi("errorful.com"), chall, core.Authorization{})
 	if prob != nil {
 		t.Fatalf("Expected success for errorful.com, got error")
 	}
 
-	_, prob = va.validate(ctx, dnsi("good.com"), chall, core.Authorization{})
+	_, _, _, prob = va.validate(ctx, dnsi("good.com"), chall, core.Authorization{})
 	if prob != nil {
 		t.Fatalf("Expected success for good.com, got %s", prob)
 	}
@@ -1043,7 +1043,7 @@ func TestPerformValidationInvalid(t *testing.T) {
 	va, _ := setup(nil, 0)
 
 	chalDNS := createChallenge(core.ChallengeTypeDNS01)
-	_, prob := va.PerformValidation(context.Background(), "foo.com", chalDNS, core.Authorization{})
+	_, _, _, prob := va.PerformValidation(context.Background(), "foo.com", chalDNS, core.Authorization{})
 	test.Assert(t, prob != nil, "validation succeeded")
 
 	samples := test.CountHistogramSamples(va.metrics.validationTime.With(prometheus.Labels{
@@ -1060,7 +1060,7 @@ func TestDNSValidationEmpty(t *testing.T) {
 	va, _ := setup(nil, 0)
 
 	chalDNS := createChallenge(core.ChallengeTypeDNS01)
-	_, prob := va.PerformValidation(
+	_, _, _, prob := va.PerformValidation(
 		context.Background(),
 		"empty-txts.com",
 		chalDNS,
@@ -1081,7 +1081,7 @@ func TestDNSValidationWrong(t *testing.T) {
 	va, _ := setup(nil, 0)
 
 	chalDNS := createChallenge(core.ChallengeTypeDNS01)
-	_, prob := va.PerformValidation(
+	_, _, _, prob := va.PerformValidation(
 		context.Background(),
 		"wrong-dns01.com",
 		chalDNS,
@@ -1096,7 +1096,7 @@ func TestDNSValidationWrongMany(t *testing.T) {
 	va, _ := setup(nil, 0)
 
 	chalDNS := createChallenge(core.ChallengeTypeDNS01)
-	_, prob := va.PerformValidation(
+	_, _, _, prob := va.PerformValidation(
 		context.Background(),
 		"wrong-many-dns01.com",
 		chalDNS,
@@ -1111,7 +1111,7 @@ func TestDNSValidationWrongLong(t *testing.T) {
 	va, _ := setup(nil, 0)
 
 	chalDNS := createChallenge(core.ChallengeTypeDNS01)
-	_, prob := va.PerformValidation(
+	_, _, _, prob := va.PerformValidation(
 		context.Background(),
 		"long-dns01.com",
 		chalDNS,
@@ -1129,8 +1129,10 @@ func TestPerformValidationValid(t *testing.T) {
 	chalDNS := core.DNSChallenge01()
 	chalDNS.Token = expectedToken
 	chalDNS.ProvidedKeyAuthorization = expectedKeyAuthorization
-	_, prob := va.PerformValidation(context.Background(), "good-dns01.com", chalDNS, core.Authorization{})
+	_, caaRecord, _, prob := va.PerformValidation(context.Background(), "good-dns01.com", chalDNS, core.Authorization{})
 	test.Assert(t, prob == nil, fmt.Sprintf("validation failed: %#v", prob))
+	test.AssertNotNil(t, caaRecord, "PerformValidation didn't return a CAA record")
+	test.AssertEquals(t, caaRecord.QueryName, "good-dns01.com")
 
 	samples := test.CountHistogramSamples(va.metrics.validationTime.With(prometheus.Labels{
 		"type":        "dns-01",
@@ -1159,7 +1161,7 @@ func TestPerformValidationWildcard(t *testing.T) {
 	chalDNS.Token = expectedToken
 	chalDNS.ProvidedKeyAuthorization = expectedKeyAuthorization
 	// perform a validation for a wildcard name
-	_, prob := va.PerformValidation(context.Background(), "*.good-dns01.com", chalDNS, core.Authorization{})
+	_, _, _, prob := va.PerformValidation(context.Background(), "*.good-dns01.com", chalDNS, core.Authorization{})
 	test.Assert(t, prob == nil, fmt.Sprintf("validation failed: %#v", prob))
 
 	samples := test.CountHistogramSamples(va.metrics.validationTime.With(prometheus.Labels{
@@ -1330,7 +1332,7 @@ func setup(srv *httptest.Server, maxRemoteFailures int) (*ValidationAuthorityImp
 		nil,
 		maxRemoteFailures,
 		"user agent 1.0",
-		"letsencrypt.org",
+		[]cmd.CAAIdentityConfig{{Domain: "letsencrypt.org"}},
 		metrics.NewNoopScope(),
 		clock.Default(),
 		logger,
@@ -1629,7 +1631,7 @@ func TestPerformRemoteValidation(t *testing.T) {
 	ms.mu.Unlock()
 
 	// Both local and remotes working, should succeed
-	_, err := localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
+	_, _, _, err := localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
 	if err != nil {
 		t.Errorf("PerformValidation failed: %s", err)
 	}
@@ -1638,7 +1640,7 @@ func TestPerformRemoteValidation(t *testing.T) {
 	ms.mu.Lock()
 	delete(ms.allowedUAs, "local")
 	ms.mu.Unlock()
-	_, err = localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
+	_, _, _, err = localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
 	if err == nil {
 		t.Error("PerformValidation didn't fail when local validation failed")
 	}
@@ -1649,7 +1651,7 @@ func TestPerformRemoteValidation(t *testing.T) {
 	ms.allowedUAs["local"] = struct{}{}
 	delete(ms.allowedUAs, "remote 1")
 	ms.mu.Unlock()
-	_, err = localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
+	_, _, _, err = localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
 	if err == nil {
 		t.Error("PerformValidation didn't fail when one 'remote' validation failed")
 	}
@@ -1669,7 +1671,7 @@ func TestPerformRemoteValidation(t *testing.T) {
 		{remoteVA1, "remote 1"},
 		{remoteVA2, "remote 2"},
 	}
-	_, err = localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
+	_, _, _, err = localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
 	if err != nil {
 		t.Errorf("PerformValidation failed when one 'remote' validation failed but maxRemoteFailures is 1: %s", err)
 	}
@@ -1678,7 +1680,7 @@ func TestPerformRemoteValidation(t *testing.T) {
 	ms.mu.Lock()
 	delete(ms.allowedUAs, "remote 2")
 	ms.mu.Unlock()
-	_, err = localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
+	_, _, _, err = localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
 	if err == nil {
 		t.Error("PerformValidation didn't fail when both 'remote' validations failed")
 	}
@@ -1689,7 +1691,7 @@ func TestPerformRemoteValidation(t *testing.T) {
 	ms.mu.Unlock()
 	remoteVA2.userAgent = "slow remote"
 	s := time.Now()
-	_, err = localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
+	_, _, _, err = localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
 	if err != nil {
 		t.Errorf("PerformValidation failed when one 'remote' validation failed but maxRemoteFailures is 1: %s", err)
 	}
@@ -1709,7 +1711,7 @@ func TestPerformRemoteValidation(t *testing.T) {
 		{remoteVA2, "remote 2"},
 	}
 	s = time.Now()
-	_, err = localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
+	_, _, _, err = localVA.PerformValidation(context.Background(), "localhost", chall, core.Authorization{})
 	if err == nil {
 		t.Error("PerformValidation didn't fail when two validations failed")
 	}
@@ -1732,8 +1734,8 @@ func (b *brokenRemoteVA) PerformValidation(
 	_ context.Context,
 	_ string,
 	_ core.Challenge,
-	_ core.Authorization) ([]core.ValidationRecord, error) {
-	return nil, brokenRemoteVAError
+	_ core.Authorization) ([]core.ValidationRecord, *core.CAAValidationRecord, []core.ReputationVerdict, error) {
+	return nil, nil, nil, brokenRemoteVAError
 }
 
 // IsSafeDomain returns brokenRemoteVAError unconditionally
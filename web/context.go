@@ -3,6 +3,7 @@ package web
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -105,3 +106,17 @@ func GetClientAddr(r *http.Request) string {
 	}
 	return r.RemoteAddr
 }
+
+// RequesterIP returns the IP address of the client making r, taken from the
+// X-Real-IP header set by our proxy or else from the remote end of the TCP
+// connection, or nil if neither is an IP address.
+func RequesterIP(r *http.Request) net.IP {
+	if ip := net.ParseIP(r.Header.Get("X-Real-IP")); ip != nil {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
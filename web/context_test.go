@@ -28,3 +28,18 @@ func TestLogCode(t *testing.T) {
 	test.AssertEquals(t, 1, len(mockLog.GetAllMatching(`"Code":201`)))
 	test.AssertEquals(t, 1, len(mockLog.GetAllMatching(`"Latency":0\.`)))
 }
+
+func TestRequesterIP(t *testing.T) {
+	req, err := http.NewRequest("GET", "/", &bytes.Reader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.RemoteAddr = "10.0.0.1:4000"
+	test.AssertEquals(t, RequesterIP(req).String(), "10.0.0.1")
+	req.Header.Set("X-Real-IP", "2001:db8::1")
+	test.AssertEquals(t, RequesterIP(req).String(), "2001:db8::1")
+
+	req.Header.Del("X-Real-IP")
+	req.RemoteAddr = "example.com:4000"
+	test.Assert(t, RequesterIP(req) == nil, "Expected no IP for a hostname")
+}
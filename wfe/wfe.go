@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -26,6 +27,7 @@ import (
 	"github.com/letsencrypt/boulder/features"
 	"github.com/letsencrypt/boulder/goodkey"
 	blog "github.com/letsencrypt/boulder/log"
+	bmail "github.com/letsencrypt/boulder/mail"
 	"github.com/letsencrypt/boulder/metrics"
 	"github.com/letsencrypt/boulder/metrics/measured_http"
 	"github.com/letsencrypt/boulder/nonce"
@@ -52,6 +54,9 @@ const (
 	issuerPath     = "/acme/issuer-cert"
 	buildIDPath    = "/build"
 	rolloverPath   = "/acme/key-change"
+	// unsubscribePath isn't part of ACME. It is linked from the emails sent
+	// by Boulder's mailers.
+	unsubscribePath = "/unsubscribe"
 )
 
 // WebFrontEndImpl provides all the logic for Boulder's web-facing interface,
@@ -98,6 +103,18 @@ type WebFrontEndImpl struct {
 	AcceptRevocationReason bool
 	AllowAuthzDeactivation bool
 
+	// AuthzDebug enables authz debug output: the CAA records and reputation
+	// verdicts of an authz's challenges are included when it is requested
+	// with the "debug" query parameter. Authz GETs are unauthenticated, so
+	// the output omits the resolvers queried and provider errors.
+	AuthzDebug bool
+
+	// Suppressions and UnsubscribeKey, if both set, enable the unsubscribe
+	// endpoint, which suppresses the address in a link made with
+	// mail.UnsubscribeToken and the same key.
+	Suppressions   bmail.SuppressionList
+	UnsubscribeKey []byte
+
 	csrSignatureAlgs *prometheus.CounterVec
 }
 
@@ -204,6 +221,9 @@ func (wfe *WebFrontEndImpl) HandleFunc(mux *http.ServeMux, pattern string, h web
 			}
 			ctx, cancel := context.WithTimeout(ctx, timeout)
 			// TODO(riking): add request context using WithValue
+			if ip := web.RequesterIP(request); ip != nil {
+				ctx = core.WithRequestIP(ctx, ip)
+			}
 
 			// Call the wrapped handler.
 			h(ctx, logEvent, response, request)
@@ -285,6 +305,9 @@ func (wfe *WebFrontEndImpl) Handler() http.Handler {
 	wfe.HandleFunc(m, issuerPath, wfe.Issuer, "GET")
 	wfe.HandleFunc(m, buildIDPath, wfe.BuildID, "GET")
 	wfe.HandleFunc(m, rolloverPath, wfe.KeyRollover, "POST")
+	if wfe.Suppressions != nil && wfe.UnsubscribeKey != nil {
+		wfe.HandleFunc(m, unsubscribePath, wfe.Unsubscribe, "GET", "POST")
+	}
 
 	// We don't use our special HandleFunc for "/" because it matches everything,
 	// meaning we can wind up returning 405 when we mean to return 404. See
@@ -998,6 +1021,10 @@ func (wfe *WebFrontEndImpl) prepChallengeForDisplay(request *http.Request, authz
 	if features.Enabled(features.ForceConsistentStatus) && authz.Status == core.StatusInvalid {
 		challenge.Status = authz.Status
 	}
+
+	// CAA records and reputation verdicts are only shown in authz debug output
+	challenge.CAARecord = nil
+	challenge.Reputation = nil
 }
 
 // prepAuthorizationForDisplay takes a core.Authorization and prepares it for
@@ -1252,8 +1279,20 @@ func (wfe *WebFrontEndImpl) Authorization(ctx context.Context, logEvent *web.Req
 		}
 	}
 
+	// Keep the CAA records and reputation verdicts of the challenges for
+	// authz debug output, since preparing the authz for display removes them.
+	var debugChallenges []core.Challenge
+	if wfe.AuthzDebug && request.URL.Query().Get("debug") != "" {
+		debugChallenges = make([]core.Challenge, len(authz.Challenges))
+		copy(debugChallenges, authz.Challenges)
+	}
+
 	wfe.prepAuthorizationForDisplay(request, &authz)
 
+	for i, challenge := range debugChallenges {
+		authz.Challenges[i].CAARecord, authz.Challenges[i].Reputation = publicDebugInfo(challenge)
+	}
+
 	response.Header().Add("Link", link(web.RelativeEndpoint(request, newCertPath), "next"))
 
 	err = wfe.writeJsonResponse(response, logEvent, http.StatusOK, authz)
@@ -1264,6 +1303,24 @@ func (wfe *WebFrontEndImpl) Authorization(ctx context.Context, logEvent *web.Req
 	}
 }
 
+// publicDebugInfo returns copies of a challenge's CAA record and reputation
+// verdicts for authz debug output, without the resolver queried or the errors
+// of reputation providers, which describe our infrastructure.
+func publicDebugInfo(challenge core.Challenge) (*core.CAAValidationRecord, []core.ReputationVerdict) {
+	var caaRecord *core.CAAValidationRecord
+	if challenge.CAARecord != nil {
+		record := *challenge.CAARecord
+		record.Resolver = ""
+		caaRecord = &record
+	}
+	var verdicts []core.ReputationVerdict
+	for _, verdict := range challenge.Reputation {
+		verdict.Error = ""
+		verdicts = append(verdicts, verdict)
+	}
+	return caaRecord, verdicts
+}
+
 var allHex = regexp.MustCompile("^[0-9a-f]+$")
 
 // Certificate is used by clients to request a copy of their current certificate, or to
@@ -1327,6 +1384,46 @@ func (wfe *WebFrontEndImpl) BuildID(ctx context.Context, logEvent *web.RequestEv
 	}
 }
 
+// Unsubscribe suppresses the email address of a signed unsubscribe link, so
+// that Boulder's mailers stop sending to it. Mail clients supporting one-click
+// unsubscription (RFC 8058) POST to the link. A GET only shows a form which
+// POSTs to it, so that link scanners fetching every URL in a mail don't
+// unsubscribe anyone.
+func (wfe *WebFrontEndImpl) Unsubscribe(ctx context.Context, logEvent *web.RequestEvent, response http.ResponseWriter, request *http.Request) {
+	token := request.FormValue("token")
+	email, err := bmail.VerifyUnsubscribeToken(wfe.UnsubscribeKey, token)
+	if err != nil {
+		wfe.sendError(response, logEvent, probs.Malformed("Invalid unsubscribe link"), err)
+		return
+	}
+
+	if request.Method != "POST" {
+		response.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(response, `<html>
+		<body>
+			<form method="POST">
+				<input type="hidden" name="token" value="%s">
+				Stop sending emails to %s?
+				<input type="submit" value="Unsubscribe">
+			</form>
+		</body>
+	</html>
+	`, html.EscapeString(token), html.EscapeString(email))
+		return
+	}
+
+	err = wfe.Suppressions.AddEmailSuppression(ctx, email, bmail.SuppressionUnsubscribe)
+	if err != nil {
+		wfe.sendError(response, logEvent, probs.ServerInternal("Failed to unsubscribe"), err)
+		return
+	}
+	wfe.stats.Inc("Unsubscribes", 1)
+	response.Header().Set("Content-Type", "text/plain")
+	if _, err := fmt.Fprintf(response, "%s has been unsubscribed.\n", email); err != nil {
+		wfe.log.Warningf("Could not write response: %s", err)
+	}
+}
+
 // Options responds to an HTTP OPTIONS request.
 func (wfe *WebFrontEndImpl) Options(response http.ResponseWriter, request *http.Request, methodsStr string, methodsMap map[string]bool) {
 	// Every OPTIONS request gets an Allow header with a list of supported methods.
@@ -29,6 +29,7 @@ import (
 	"github.com/letsencrypt/boulder/features"
 	"github.com/letsencrypt/boulder/goodkey"
 	blog "github.com/letsencrypt/boulder/log"
+	bmail "github.com/letsencrypt/boulder/mail"
 	"github.com/letsencrypt/boulder/metrics"
 	"github.com/letsencrypt/boulder/mocks"
 	"github.com/letsencrypt/boulder/nonce"
@@ -268,15 +269,15 @@ func (ra *MockRegistrationAuthority) FinalizeOrder(ctx context.Context, _ *rapb.
 
 type mockPA struct{}
 
-func (pa *mockPA) ChallengesFor(identifier core.AcmeIdentifier, registrationID int64, revalidation bool) (challenges []core.Challenge, combinations [][]int, err error) {
+func (pa *mockPA) ChallengesFor(identifier core.AcmeIdentifier, reg core.Registration, revalidation bool) (challenges []core.Challenge, combinations [][]int, err error) {
 	return
 }
 
-func (pa *mockPA) WillingToIssue(id core.AcmeIdentifier) error {
+func (pa *mockPA) WillingToIssue(id core.AcmeIdentifier, regID int64) error {
 	return nil
 }
 
-func (pa *mockPA) WillingToIssueWildcard(id core.AcmeIdentifier) error {
+func (pa *mockPA) WillingToIssueWildcard(id core.AcmeIdentifier, regID int64) error {
 	return nil
 }
 
@@ -284,6 +285,14 @@ func (pa *mockPA) ChallengeTypeEnabled(t string, registrationID int64) bool {
 	return true
 }
 
+func (pa *mockPA) AccountAllowsChallengeType(t string, reg core.Registration) bool {
+	return true
+}
+
+func (pa *mockPA) ContactSchemeAllowed(scheme string) bool {
+	return scheme == "mailto"
+}
+
 func makeBody(s string) io.ReadCloser {
 	return ioutil.NopCloser(strings.NewReader(s))
 }
@@ -1783,6 +1792,69 @@ func TestAuthorizationChallengeNamespace(t *testing.T) {
 	responseWriter.Body.Reset()
 }
 
+// mockSAWithDebugRecords returns authorizations whose challenge has a CAA
+// record and reputation verdicts.
+type mockSAWithDebugRecords struct {
+	core.StorageGetter
+}
+
+func (msa mockSAWithDebugRecords) GetAuthorization(ctx context.Context, id string) (core.Authorization, error) {
+	authz, err := msa.StorageGetter.GetAuthorization(ctx, id)
+	if err != nil {
+		return authz, err
+	}
+	authz.Challenges[0].CAARecord = &core.CAAValidationRecord{
+		QueryName: "not-an-example.com",
+		Resolver:  "127.0.0.1:53",
+		Valid:     true,
+	}
+	authz.Challenges[0].Reputation = []core.ReputationVerdict{
+		{Provider: "gsb", Error: "dial tcp 10.0.0.1:443: i/o timeout"},
+	}
+	return authz, nil
+}
+
+// TestAuthorizationDebug tests that the CAA records and reputation verdicts
+// of challenges are only shown in authz debug output.
+func TestAuthorizationDebug(t *testing.T) {
+	wfe, fc := setupWFE(t)
+	wfe.SA = mockSAWithDebugRecords{mocks.NewStorageAuthority(fc)}
+
+	getAuthz := func(url string) core.Authorization {
+		responseWriter := httptest.NewRecorder()
+		wfe.Authorization(ctx, newRequestEvent(), responseWriter, &http.Request{
+			Method: "GET",
+			URL:    mustParseURL(url),
+		})
+		test.AssertEquals(t, responseWriter.Code, http.StatusOK)
+		var authz core.Authorization
+		err := json.Unmarshal(responseWriter.Body.Bytes(), &authz)
+		test.AssertNotError(t, err, "Couldn't unmarshal returned authorization object")
+		test.AssertEquals(t, len(authz.Challenges), 1)
+		return authz
+	}
+
+	// Without AuthzDebug, the debug parameter is ignored
+	authz := getAuthz("valid?debug=1")
+	test.Assert(t, authz.Challenges[0].CAARecord == nil, "CAA record shown without AuthzDebug")
+	test.Assert(t, authz.Challenges[0].Reputation == nil, "Reputation verdicts shown without AuthzDebug")
+
+	wfe.AuthzDebug = true
+	authz = getAuthz("valid")
+	test.Assert(t, authz.Challenges[0].CAARecord == nil, "CAA record shown without the debug parameter")
+	test.Assert(t, authz.Challenges[0].Reputation == nil, "Reputation verdicts shown without the debug parameter")
+
+	authz = getAuthz("valid?debug=1")
+	test.Assert(t, authz.Challenges[0].CAARecord != nil, "CAA record not shown in debug output")
+	test.AssertEquals(t, authz.Challenges[0].CAARecord.QueryName, "not-an-example.com")
+	test.AssertEquals(t, len(authz.Challenges[0].Reputation), 1)
+	test.AssertEquals(t, authz.Challenges[0].Reputation[0].Provider, "gsb")
+	// Resolvers and provider errors describe our infrastructure, so they
+	// aren't shown to unauthenticated requesters
+	test.AssertEquals(t, authz.Challenges[0].CAARecord.Resolver, "")
+	test.AssertEquals(t, authz.Challenges[0].Reputation[0].Error, "")
+}
+
 func contains(s []string, e string) bool {
 	for _, a := range s {
 		if a == e {
@@ -2468,3 +2540,56 @@ func TestNewCertificateSCTError(t *testing.T) {
 		responseWriter.Body.String(),
 		`{"type":"`+probs.V1ErrorNS+`serverInternal","detail":"Error creating new cert :: Unable to meet CA SCT embedding requirements","status":500}`)
 }
+
+type recordingSuppressions struct {
+	mocks.StorageAuthority
+	added map[string]string
+}
+
+func (rs *recordingSuppressions) AddEmailSuppression(_ context.Context, email, reason string) error {
+	rs.added[email] = reason
+	return nil
+}
+
+func TestUnsubscribe(t *testing.T) {
+	wfe, _ := setupWFE(t)
+	key := []byte("secret")
+	token, err := bmail.UnsubscribeToken(key, "Recv@Email.com")
+	test.AssertNotError(t, err, "UnsubscribeToken failed")
+
+	// The endpoint only exists if it is configured
+	rw := httptest.NewRecorder()
+	wfe.Handler().ServeHTTP(rw, httptest.NewRequest("POST", "/unsubscribe?token="+token, nil))
+	test.AssertEquals(t, rw.Code, http.StatusNotFound)
+
+	suppressions := &recordingSuppressions{added: map[string]string{}}
+	wfe.Suppressions = suppressions
+	wfe.UnsubscribeKey = key
+	handler := wfe.Handler()
+
+	// A GET only shows a form
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/unsubscribe?token="+token, nil))
+	test.AssertEquals(t, rw.Code, http.StatusOK)
+	test.Assert(t, strings.Contains(rw.Body.String(), `<form method="POST">`), "GET didn't return a form")
+	test.Assert(t, strings.Contains(rw.Body.String(), "recv@email.com"), "GET didn't show the address")
+	test.AssertEquals(t, len(suppressions.added), 0)
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("POST", "/unsubscribe?token=x"+token, nil))
+	test.AssertEquals(t, rw.Code, http.StatusBadRequest)
+	test.AssertEquals(t, len(suppressions.added), 0)
+
+	// The form POSTs the token in the body, and one-click unsubscription in
+	// the query
+	form := httptest.NewRequest("POST", "/unsubscribe", strings.NewReader("token="+token))
+	form.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	oneClick := httptest.NewRequest("POST", "/unsubscribe?token="+token, strings.NewReader("List-Unsubscribe=One-Click"))
+	oneClick.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for _, req := range []*http.Request{form, oneClick} {
+		rw = httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		test.AssertEquals(t, rw.Code, http.StatusOK)
+		test.AssertDeepEquals(t, suppressions.added, map[string]string{"recv@email.com": bmail.SuppressionUnsubscribe})
+	}
+}
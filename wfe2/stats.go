@@ -15,6 +15,9 @@ type wfe2Stats struct {
 	// csrSignatureAlgs counts the signature algorithms in use for order
 	// finalization CSRs
 	csrSignatureAlgs *prometheus.CounterVec
+	// unsubscribes counts email addresses suppressed through unsubscribe
+	// links
+	unsubscribes prometheus.Counter
 }
 
 func initStats(scope metrics.Scope) wfe2Stats {
@@ -43,9 +46,17 @@ func initStats(scope metrics.Scope) wfe2Stats {
 	)
 	scope.MustRegister(csrSignatureAlgs)
 
+	unsubscribes := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "unsubscribes",
+			Help: "Number of email addresses suppressed through unsubscribe links",
+		})
+	scope.MustRegister(unsubscribes)
+
 	return wfe2Stats{
 		httpErrorCount:   httpErrorCount,
 		joseErrorCount:   joseErrorCount,
 		csrSignatureAlgs: csrSignatureAlgs,
+		unsubscribes:     unsubscribes,
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"html"
 	"net"
 	"net/http"
 	"regexp"
@@ -28,6 +29,7 @@ import (
 	"github.com/letsencrypt/boulder/goodkey"
 	bgrpc "github.com/letsencrypt/boulder/grpc"
 	blog "github.com/letsencrypt/boulder/log"
+	bmail "github.com/letsencrypt/boulder/mail"
 	"github.com/letsencrypt/boulder/metrics"
 	"github.com/letsencrypt/boulder/metrics/measured_http"
 	"github.com/letsencrypt/boulder/nonce"
@@ -57,6 +59,10 @@ const (
 	newOrderPath      = "/acme/new-order"
 	orderPath         = "/acme/order/"
 	finalizeOrderPath = "/acme/finalize/"
+
+	// unsubscribePath isn't part of ACME. It is linked from the emails sent
+	// by Boulder's mailers.
+	unsubscribePath = "/unsubscribe"
 )
 
 // WebFrontEndImpl provides all the logic for Boulder's web-facing interface,
@@ -110,6 +116,18 @@ type WebFrontEndImpl struct {
 
 	AcceptRevocationReason bool
 	AllowAuthzDeactivation bool
+
+	// AuthzDebug enables authz debug output: the CAA records and reputation
+	// verdicts of an authz's challenges are included when it is requested
+	// with the "debug" query parameter. Authz GETs are unauthenticated, so
+	// the output omits the resolvers queried and provider errors.
+	AuthzDebug bool
+
+	// Suppressions and UnsubscribeKey, if both set, enable the unsubscribe
+	// endpoint, which suppresses the address in a link made with
+	// mail.UnsubscribeToken and the same key.
+	Suppressions   bmail.SuppressionList
+	UnsubscribeKey []byte
 }
 
 // NewWebFrontEndImpl constructs a web service for Boulder
@@ -210,6 +228,9 @@ func (wfe *WebFrontEndImpl) HandleFunc(mux *http.ServeMux, pattern string, h web
 			}
 			ctx, cancel := context.WithTimeout(ctx, timeout)
 			// TODO(riking): add request context using WithValue
+			if ip := web.RequesterIP(request); ip != nil {
+				ctx = core.WithRequestIP(ctx, ip)
+			}
 
 			// Call the wrapped handler.
 			h(ctx, logEvent, response, request)
@@ -311,6 +332,9 @@ func (wfe *WebFrontEndImpl) Handler() http.Handler {
 	wfe.HandleFunc(m, newOrderPath, wfe.NewOrder, "POST")
 	wfe.HandleFunc(m, orderPath, wfe.GetOrder, "GET")
 	wfe.HandleFunc(m, finalizeOrderPath, wfe.FinalizeOrder, "POST")
+	if wfe.Suppressions != nil && wfe.UnsubscribeKey != nil {
+		wfe.HandleFunc(m, unsubscribePath, wfe.Unsubscribe, "GET", "POST")
+	}
 	// We don't use our special HandleFunc for "/" because it matches everything,
 	// meaning we can wind up returning 405 when we mean to return 404. See
 	// https://github.com/letsencrypt/boulder/issues/717
@@ -878,6 +902,10 @@ func (wfe *WebFrontEndImpl) prepChallengeForDisplay(request *http.Request, authz
 	if features.Enabled(features.ForceConsistentStatus) && authz.Status == core.StatusInvalid {
 		challenge.Status = authz.Status
 	}
+
+	// CAA records and reputation verdicts are only shown in authz debug output
+	challenge.CAARecord = nil
+	challenge.Reputation = nil
 }
 
 // prepAuthorizationForDisplay takes a core.Authorization and prepares it for
@@ -1023,11 +1051,15 @@ func (wfe *WebFrontEndImpl) Account(
 		return
 	}
 
-	// Only the Contact and Status fields of an account may be updated this way.
-	// For key updates clients should be using the key change endpoint.
+	// Only the Contact, Status and ChallengeTypes fields of an account may be
+	// updated this way. For key updates clients should be using the key change
+	// endpoint. ChallengeTypes is a Boulder extension: it restricts the challenge
+	// types used for the account's authorizations, and an empty list removes the
+	// restriction.
 	var accountUpdateRequest struct {
-		Contact *[]string       `json:"contact"`
-		Status  core.AcmeStatus `json:"status"`
+		Contact        *[]string       `json:"contact"`
+		Status         core.AcmeStatus `json:"status"`
+		ChallengeTypes *[]string       `json:"challengeTypes"`
 	}
 
 	err = json.Unmarshal(body, &accountUpdateRequest)
@@ -1039,8 +1071,9 @@ func (wfe *WebFrontEndImpl) Account(
 	// Copy over the fields from the request to the registration object used for
 	// the RA updates.
 	update := core.Registration{
-		Contact: accountUpdateRequest.Contact,
-		Status:  accountUpdateRequest.Status,
+		Contact:        accountUpdateRequest.Contact,
+		Status:         accountUpdateRequest.Status,
+		ChallengeTypes: accountUpdateRequest.ChallengeTypes,
 	}
 
 	// People *will* POST their full accounts to this endpoint, including
@@ -1165,8 +1198,20 @@ func (wfe *WebFrontEndImpl) Authorization(ctx context.Context, logEvent *web.Req
 		}
 	}
 
+	// Keep the CAA records and reputation verdicts of the challenges for
+	// authz debug output, since preparing the authz for display removes them.
+	var debugChallenges []core.Challenge
+	if wfe.AuthzDebug && request.URL.Query().Get("debug") != "" {
+		debugChallenges = make([]core.Challenge, len(authz.Challenges))
+		copy(debugChallenges, authz.Challenges)
+	}
+
 	wfe.prepAuthorizationForDisplay(request, &authz)
 
+	for i, challenge := range debugChallenges {
+		authz.Challenges[i].CAARecord, authz.Challenges[i].Reputation = publicDebugInfo(challenge)
+	}
+
 	err = wfe.writeJsonResponse(response, logEvent, http.StatusOK, authz)
 	if err != nil {
 		// InternalServerError because this is a failure to decode from our DB.
@@ -1175,6 +1220,24 @@ func (wfe *WebFrontEndImpl) Authorization(ctx context.Context, logEvent *web.Req
 	}
 }
 
+// publicDebugInfo returns copies of a challenge's CAA record and reputation
+// verdicts for authz debug output, without the resolver queried or the errors
+// of reputation providers, which describe our infrastructure.
+func publicDebugInfo(challenge core.Challenge) (*core.CAAValidationRecord, []core.ReputationVerdict) {
+	var caaRecord *core.CAAValidationRecord
+	if challenge.CAARecord != nil {
+		record := *challenge.CAARecord
+		record.Resolver = ""
+		caaRecord = &record
+	}
+	var verdicts []core.ReputationVerdict
+	for _, verdict := range challenge.Reputation {
+		verdict.Error = ""
+		verdicts = append(verdicts, verdict)
+	}
+	return caaRecord, verdicts
+}
+
 var allHex = regexp.MustCompile("^[0-9a-f]+$")
 
 // Certificate is used by clients to request a copy of their current certificate, or to
@@ -1289,6 +1352,46 @@ func (wfe *WebFrontEndImpl) BuildID(ctx context.Context, logEvent *web.RequestEv
 	}
 }
 
+// Unsubscribe suppresses the email address of a signed unsubscribe link, so
+// that Boulder's mailers stop sending to it. Mail clients supporting one-click
+// unsubscription (RFC 8058) POST to the link. A GET only shows a form which
+// POSTs to it, so that link scanners fetching every URL in a mail don't
+// unsubscribe anyone.
+func (wfe *WebFrontEndImpl) Unsubscribe(ctx context.Context, logEvent *web.RequestEvent, response http.ResponseWriter, request *http.Request) {
+	token := request.FormValue("token")
+	email, err := bmail.VerifyUnsubscribeToken(wfe.UnsubscribeKey, token)
+	if err != nil {
+		wfe.sendError(response, logEvent, probs.Malformed("Invalid unsubscribe link"), err)
+		return
+	}
+
+	if request.Method != "POST" {
+		response.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(response, `<html>
+		<body>
+			<form method="POST">
+				<input type="hidden" name="token" value="%s">
+				Stop sending emails to %s?
+				<input type="submit" value="Unsubscribe">
+			</form>
+		</body>
+	</html>
+	`, html.EscapeString(token), html.EscapeString(email))
+		return
+	}
+
+	err = wfe.Suppressions.AddEmailSuppression(ctx, email, bmail.SuppressionUnsubscribe)
+	if err != nil {
+		wfe.sendError(response, logEvent, probs.ServerInternal("Failed to unsubscribe"), err)
+		return
+	}
+	wfe.stats.unsubscribes.Inc()
+	response.Header().Set("Content-Type", "text/plain")
+	if _, err := fmt.Fprintf(response, "%s has been unsubscribed.\n", email); err != nil {
+		wfe.log.Warningf("Could not write response: %s", err)
+	}
+}
+
 // Options responds to an HTTP OPTIONS request.
 func (wfe *WebFrontEndImpl) Options(response http.ResponseWriter, request *http.Request, methodsStr string, methodsMap map[string]bool) {
 	// Every OPTIONS request gets an Allow header with a list of supported methods.
@@ -1468,6 +1571,7 @@ type orderJSON struct {
 	Finalize       string                `json:"finalize"`
 	Certificate    string                `json:"certificate,omitempty"`
 	Error          *probs.ProblemDetails `json:"error,omitempty"`
+	Profile        string                `json:"profile,omitempty"`
 }
 
 // orderToOrderJSON converts a *corepb.Order instance into an orderJSON struct
@@ -1487,6 +1591,7 @@ func (wfe *WebFrontEndImpl) orderToOrderJSON(request *http.Request, order *corep
 		Identifiers:    idents,
 		Authorizations: make([]string, len(order.Authorizations)),
 		Finalize:       finalizeURL,
+		Profile:        order.GetProfile(),
 	}
 	// If there is an order error, prefix its type with the V2 namespace
 	if order.Error != nil {
@@ -1529,6 +1634,9 @@ func (wfe *WebFrontEndImpl) NewOrder(
 	var newOrderRequest struct {
 		Identifiers         []core.AcmeIdentifier `json:"identifiers"`
 		NotBefore, NotAfter string
+		// Profile is the name of the issuance profile to use for the order's
+		// certificate, or empty for the default
+		Profile string `json:"profile"`
 	}
 	err := json.Unmarshal(body, &newOrderRequest)
 	if err != nil {
@@ -1561,10 +1669,14 @@ func (wfe *WebFrontEndImpl) NewOrder(
 		names[i] = ident.Value
 	}
 
-	order, err := wfe.RA.NewOrder(ctx, &rapb.NewOrderRequest{
+	orderReq := &rapb.NewOrderRequest{
 		RegistrationID: &acct.ID,
 		Names:          names,
-	})
+	}
+	if newOrderRequest.Profile != "" {
+		orderReq.Profile = &newOrderRequest.Profile
+	}
+	order, err := wfe.RA.NewOrder(ctx, orderReq)
 	if err != nil {
 		wfe.sendError(response, logEvent, web.ProblemDetailsForError(err, "Error creating new order"), err)
 		return
@@ -29,6 +29,7 @@ import (
 	berrors "github.com/letsencrypt/boulder/errors"
 	"github.com/letsencrypt/boulder/goodkey"
 	blog "github.com/letsencrypt/boulder/log"
+	bmail "github.com/letsencrypt/boulder/mail"
 	"github.com/letsencrypt/boulder/metrics"
 	"github.com/letsencrypt/boulder/mocks"
 	"github.com/letsencrypt/boulder/nonce"
@@ -226,6 +227,9 @@ func (ra *MockRegistrationAuthority) UpdateRegistration(ctx context.Context, acc
 	if !keysMatch {
 		acct.Key = updated.Key
 	}
+	if updated.ChallengeTypes != nil {
+		acct.ChallengeTypes = updated.ChallengeTypes
+	}
 	return acct, nil
 }
 
@@ -265,6 +269,7 @@ func (ra *MockRegistrationAuthority) NewOrder(ctx context.Context, req *rapb.New
 		Names:          req.Names,
 		Status:         &status,
 		Authorizations: []string{"hello"},
+		Profile:        req.Profile,
 	}, nil
 }
 
@@ -280,11 +285,11 @@ func (pa *mockPA) ChallengesFor(identifier core.AcmeIdentifier) (challenges []co
 	return
 }
 
-func (pa *mockPA) WillingToIssue(id core.AcmeIdentifier) error {
+func (pa *mockPA) WillingToIssue(id core.AcmeIdentifier, regID int64) error {
 	return nil
 }
 
-func (pa *mockPA) WillingToIssueWildcard(id core.AcmeIdentifier) error {
+func (pa *mockPA) WillingToIssueWildcard(id core.AcmeIdentifier, regID int64) error {
 	return nil
 }
 
@@ -1391,6 +1396,69 @@ func TestAuthorizationChallengeNamespace(t *testing.T) {
 	responseWriter.Body.Reset()
 }
 
+// mockSAWithDebugRecords returns authorizations whose challenge has a CAA
+// record and reputation verdicts.
+type mockSAWithDebugRecords struct {
+	core.StorageGetter
+}
+
+func (msa mockSAWithDebugRecords) GetAuthorization(ctx context.Context, id string) (core.Authorization, error) {
+	authz, err := msa.StorageGetter.GetAuthorization(ctx, id)
+	if err != nil {
+		return authz, err
+	}
+	authz.Challenges[0].CAARecord = &core.CAAValidationRecord{
+		QueryName: "not-an-example.com",
+		Resolver:  "127.0.0.1:53",
+		Valid:     true,
+	}
+	authz.Challenges[0].Reputation = []core.ReputationVerdict{
+		{Provider: "gsb", Error: "dial tcp 10.0.0.1:443: i/o timeout"},
+	}
+	return authz, nil
+}
+
+// TestAuthorizationDebug tests that the CAA records and reputation verdicts
+// of challenges are only shown in authz debug output.
+func TestAuthorizationDebug(t *testing.T) {
+	wfe, fc := setupWFE(t)
+	wfe.SA = mockSAWithDebugRecords{mocks.NewStorageAuthority(fc)}
+
+	getAuthz := func(url string) core.Authorization {
+		responseWriter := httptest.NewRecorder()
+		wfe.Authorization(ctx, newRequestEvent(), responseWriter, &http.Request{
+			Method: "GET",
+			URL:    mustParseURL(url),
+		})
+		test.AssertEquals(t, responseWriter.Code, http.StatusOK)
+		var authz core.Authorization
+		err := json.Unmarshal(responseWriter.Body.Bytes(), &authz)
+		test.AssertNotError(t, err, "Couldn't unmarshal returned authorization object")
+		test.AssertEquals(t, len(authz.Challenges), 1)
+		return authz
+	}
+
+	// Without AuthzDebug, the debug parameter is ignored
+	authz := getAuthz("valid?debug=1")
+	test.Assert(t, authz.Challenges[0].CAARecord == nil, "CAA record shown without AuthzDebug")
+	test.Assert(t, authz.Challenges[0].Reputation == nil, "Reputation verdicts shown without AuthzDebug")
+
+	wfe.AuthzDebug = true
+	authz = getAuthz("valid")
+	test.Assert(t, authz.Challenges[0].CAARecord == nil, "CAA record shown without the debug parameter")
+	test.Assert(t, authz.Challenges[0].Reputation == nil, "Reputation verdicts shown without the debug parameter")
+
+	authz = getAuthz("valid?debug=1")
+	test.Assert(t, authz.Challenges[0].CAARecord != nil, "CAA record not shown in debug output")
+	test.AssertEquals(t, authz.Challenges[0].CAARecord.QueryName, "not-an-example.com")
+	test.AssertEquals(t, len(authz.Challenges[0].Reputation), 1)
+	test.AssertEquals(t, authz.Challenges[0].Reputation[0].Provider, "gsb")
+	// Resolvers and provider errors describe our infrastructure, so they
+	// aren't shown to unauthenticated requesters
+	test.AssertEquals(t, authz.Challenges[0].CAARecord.Resolver, "")
+	test.AssertEquals(t, authz.Challenges[0].Reputation[0].Error, "")
+}
+
 func contains(s []string, e string) bool {
 	for _, a := range s {
 		if a == e {
@@ -1469,6 +1537,30 @@ func TestAccount(t *testing.T) {
 	responseWriter.Body.Reset()
 }
 
+func TestAccountChallengeTypes(t *testing.T) {
+	wfe, _ := setupWFE(t)
+	responseWriter := httptest.NewRecorder()
+
+	// An update with challenge types is passed to the RA, and the account's
+	// challenge preferences are returned
+	payload := `{"challengeTypes":["dns-01"]}`
+	_, _, body := signRequestKeyID(t, 1, nil, "http://localhost/1", payload, wfe.nonceService)
+	wfe.Account(ctx, newRequestEvent(), responseWriter, makePostRequestWithPath("1", body))
+	test.AssertEquals(t, responseWriter.Code, http.StatusOK)
+	var acct core.Registration
+	err := json.Unmarshal(responseWriter.Body.Bytes(), &acct)
+	test.AssertNotError(t, err, "Couldn't unmarshal returned account object")
+	test.AssertDeepEquals(t, acct.ChallengeTypes, &[]string{core.ChallengeTypeDNS01})
+	responseWriter.Body.Reset()
+
+	// Accounts without challenge preferences don't include the field
+	payload = `{}`
+	_, _, body = signRequestKeyID(t, 1, nil, "http://localhost/1", payload, wfe.nonceService)
+	wfe.Account(ctx, newRequestEvent(), responseWriter, makePostRequestWithPath("1", body))
+	test.AssertEquals(t, responseWriter.Code, http.StatusOK)
+	test.AssertNotContains(t, responseWriter.Body.String(), "challengeTypes")
+}
+
 func TestIssuer(t *testing.T) {
 	wfe, _ := setupWFE(t)
 	wfe.IssuerCert = []byte{0, 0, 1}
@@ -1858,6 +1950,23 @@ func TestNewOrder(t *testing.T) {
 						"finalize": "http://localhost/acme/finalize/1/1"
 					}`,
 		},
+		{
+			Name:    "POST, good payload with a profile",
+			Request: signAndPost(t, targetPath, signedURL, `{"identifiers":[{"type": "dns", "value": "not-example.com"}], "profile": "shortLived"}`, 1, wfe.nonceService),
+			ExpectedBody: `
+					{
+						"status": "pending",
+						"expires": "1970-01-01T00:00:00Z",
+						"identifiers": [
+							{ "type": "dns", "value": "not-example.com"}
+						],
+						"authorizations": [
+							"http://localhost/acme/authz/hello"
+						],
+						"finalize": "http://localhost/acme/finalize/1/1",
+						"profile": "shortLived"
+					}`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2584,3 +2693,55 @@ func TestFinalizeSCTError(t *testing.T) {
 		responseWriter.Body.String(),
 		`{"type":"`+probs.V2ErrorNS+`serverInternal","detail":"Error finalizing order :: Unable to meet CA SCT embedding requirements","status":500}`)
 }
+
+type recordingSuppressions struct {
+	mocks.StorageAuthority
+	added map[string]string
+}
+
+func (rs *recordingSuppressions) AddEmailSuppression(_ context.Context, email, reason string) error {
+	rs.added[email] = reason
+	return nil
+}
+
+func TestUnsubscribe(t *testing.T) {
+	wfe, _ := setupWFE(t)
+	key := []byte("secret")
+	token, err := bmail.UnsubscribeToken(key, "Recv@Email.com")
+	test.AssertNotError(t, err, "UnsubscribeToken failed")
+
+	// The endpoint only exists if it is configured
+	rw := httptest.NewRecorder()
+	wfe.Handler().ServeHTTP(rw, httptest.NewRequest("POST", "/unsubscribe?token="+token, nil))
+	test.AssertEquals(t, rw.Code, http.StatusNotFound)
+
+	suppressions := &recordingSuppressions{added: map[string]string{}}
+	wfe.Suppressions = suppressions
+	wfe.UnsubscribeKey = key
+	handler := wfe.Handler()
+
+	// A GET only shows a form
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/unsubscribe?token="+token, nil))
+	test.AssertEquals(t, rw.Code, http.StatusOK)
+	test.Assert(t, strings.Contains(rw.Body.String(), `<form method="POST">`), "GET didn't return a form")
+	test.AssertEquals(t, len(suppressions.added), 0)
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("POST", "/unsubscribe?token=x"+token, nil))
+	test.AssertEquals(t, rw.Code, http.StatusBadRequest)
+	test.AssertEquals(t, len(suppressions.added), 0)
+
+	// The form POSTs the token in the body, and one-click unsubscription in
+	// the query
+	form := httptest.NewRequest("POST", "/unsubscribe", strings.NewReader("token="+token))
+	form.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	oneClick := httptest.NewRequest("POST", "/unsubscribe?token="+token, strings.NewReader("List-Unsubscribe=One-Click"))
+	oneClick.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for _, req := range []*http.Request{form, oneClick} {
+		rw = httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		test.AssertEquals(t, rw.Code, http.StatusOK)
+		test.AssertDeepEquals(t, suppressions.added, map[string]string{"recv@email.com": bmail.SuppressionUnsubscribe})
+	}
+}